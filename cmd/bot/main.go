@@ -22,6 +22,7 @@ import (
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler"
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/webapp"
 	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/database"
+	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/telegramapi"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
 	"github.com/8thgencore/dory-reminder-bot/internal/usecase"
 	"github.com/8thgencore/dory-reminder-bot/pkg/logger"
@@ -63,7 +64,7 @@ func run() error {
 	defer database.CloseDatabase(db, log)
 
 	reminderUc := usecase.NewReminderUsecase(repository.NewReminderRepository(db))
-	chatUc := usecase.NewChatUsecase(repository.NewChatRepository(db))
+	chatUc := usecase.NewChatUsecase(repository.NewChatRepository(db), reminderUc)
 	memberUc := usecase.NewMemberUsecase(repository.NewMemberRepository(db))
 
 	h := handler.NewHandler(bot, reminderUc, chatUc, memberUc, cfg.WebApp)
@@ -74,7 +75,10 @@ func run() error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	scheduler := telegram.NewScheduler(bot, reminderUc, chatUc)
+	// Рассылка идёт через лимиты Bot API: пачка напоминаний в 09:00 не должна упираться в 429.
+	scheduler := telegram.NewScheduler(telegramapi.NewLimiter(bot), reminderUc, chatUc, cfg.Delivery)
+	// Правки расписания будят планировщик, чтобы срабатывания не ждали сверки с базой.
+	reminderUc.SetWaker(scheduler)
 	go scheduler.Run(ctx)
 
 	srv := startWebApp(ctx, cfg, bot, reminderUc, chatUc, memberUc, log)
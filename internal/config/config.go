@@ -25,6 +25,7 @@ type Config struct {
 	Telegram TelegramConfig
 	Database DatabaseConfig
 	WebApp   WebAppConfig
+	Delivery DeliveryConfig
 	// ProxyURL — необязательный исходящий прокси для Bot API (http, https или socks5).
 	//
 	// Тега env-required здесь быть не должно: cleanenv считает поле обязательным
@@ -45,6 +46,15 @@ type DatabaseConfig struct {
 	Path string `env:"DB_PATH" env-default:"data/reminders.db"`
 }
 
+// DeliveryConfig описывает повторную отправку напоминаний, которые не удалось доставить.
+type DeliveryConfig struct {
+	// RetryDeadline — сколько после срабатывания повторяются попытки отправить напоминание
+	// при сетевых сбоях и ошибках Telegram, прежде чем оно будет брошено; 0 — не повторять.
+	RetryDeadline time.Duration `env:"DELIVERY_RETRY_DEADLINE" env-default:"1h"`
+	// HistoryRetention — сколько хранится история доставок; 0 — бессрочно.
+	HistoryRetention time.Duration `env:"DELIVERY_HISTORY_RETENTION" env-default:"720h"`
+}
+
 // WebAppConfig описывает настройки Telegram Mini App.
 type WebAppConfig struct {
 	// Enabled включает HTTP-сервер Mini App.
@@ -78,6 +88,13 @@ func (c *Config) Validate() error {
 		return errors.New("TELEGRAM_TOKEN is required")
 	}
 
+	if c.Delivery.RetryDeadline < 0 {
+		return errors.New("DELIVERY_RETRY_DEADLINE must not be negative")
+	}
+	if c.Delivery.HistoryRetention < 0 {
+		return errors.New("DELIVERY_HISTORY_RETENTION must not be negative")
+	}
+
 	if c.WebApp.Enabled && c.WebApp.PublicURL == "" {
 		return errors.New("WEBAPP_PUBLIC_URL is required when WEBAPP_ENABLED=true: " +
 			"Telegram opens Mini Apps only over a public HTTPS URL")
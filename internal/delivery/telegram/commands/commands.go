@@ -20,7 +20,10 @@ func SetCommands(bot *tele.Bot, log *slog.Logger, withWebApp bool) {
 		{Text: "delete", Description: "Удалить напоминание"},
 		{Text: "pause", Description: "Поставить на паузу"},
 		{Text: "resume", Description: "Возобновить"},
+		{Text: "skip", Description: "Пропустить ближайшее срабатывание"},
+		{Text: "history", Description: "История доставок"},
 		{Text: "timezone", Description: "Установить часовой пояс"},
+		{Text: "quiet", Description: "Тихие часы"},
 	}
 
 	if withWebApp {
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	tele "gopkg.in/telebot.v4"
+)
+
+type reminderAcknowledger interface {
+	Acknowledge(ctx context.Context, id, chatID, userID int64, userName string,
+		at time.Time) (*domain.Acknowledgement, error)
+	ArmFollowUps(ctx context.Context, parentID int64, trigger domain.FollowTrigger, at time.Time) (int, error)
+}
+
+type ackChats interface {
+	Location(ctx context.Context, chatID int64) *time.Location
+}
+
+// messageEditor — часть API бота для правки первого сообщения доставки: «Готово»
+// могли нажать под повтором, а отметку нужно оставить и под исходным сообщением.
+type messageEditor interface {
+	Edit(msg tele.Editable, what any, opts ...any) (*tele.Message, error)
+}
+
+// AckCommands обрабатывает кнопку «Готово» под напоминаниями, ждущими подтверждения.
+type AckCommands struct {
+	ReminderUsecase reminderAcknowledger
+	ChatUsecase     ackChats
+	Editor          messageEditor
+	nowFunc         func() time.Time
+}
+
+// NewAckCommands создает новый экземпляр AckCommands.
+func NewAckCommands(reminderUc reminderAcknowledger, chatUc ackChats, editor messageEditor) *AckCommands {
+	return &AckCommands{ReminderUsecase: reminderUc, ChatUsecase: chatUc, Editor: editor, nowFunc: time.Now}
+}
+
+// HandleAckCallback отмечает доставку выполненной и дописывает к сообщениям, кто и когда
+// это сделал. Повторное нажатие — например, под более ранним повтором — только
+// показывает уже записанную отметку.
+func (ac *AckCommands) HandleAckCallback(c tele.Context) error {
+	ctx := context.Background()
+	chatID := c.Chat().ID
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(c.Callback().Data), "ack_"), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	a, err := ac.ReminderUsecase.Acknowledge(ctx, id, chatID, c.Sender().ID, senderName(c.Sender()), ac.nowFunc())
+	switch {
+	case errors.Is(err, domain.ErrAlreadyAcknowledged):
+	case errors.Is(err, repository.ErrAckNotFound):
+		return c.Send(texts.AckNotFound)
+	case err != nil:
+		slog.Error("Failed to acknowledge reminder", "chat_id", chatID, "ack_id", id, "error", err)
+		return c.Send(texts.ErrAcknowledge)
+	default:
+		slog.Info("Reminder acknowledged", "chat_id", chatID, "ack_id", id, "user_id", c.Sender().ID)
+		ac.armFollowUps(ctx, a)
+	}
+
+	// В личном чате подтвердить может только его владелец — имя там лишнее.
+	by := ""
+	if c.Chat().Type != tele.ChatPrivate {
+		by = a.AckedByName
+	}
+	line := texts.AckedLine(by, ui.FormatTime(a.AckedAt, ac.ChatUsecase.Location(ctx, chatID)))
+
+	if err == nil && a.MessageID != 0 && a.MessageID != c.Message().ID {
+		// Исходное сообщение могло прийти с опозданием и другим оформлением, но текст
+		// напоминания в нём тот же.
+		original := tele.StoredMessage{MessageID: strconv.Itoa(a.MessageID), ChatID: chatID}
+		if _, err := ac.Editor.Edit(original, texts.ReminderPrefix+a.Text+line); err != nil {
+			slog.Warn("Failed to mark original reminder as done", "chat_id", chatID, "ack_id", id, "error", err)
+		}
+	}
+
+	return c.Edit(c.Message().Text + line)
+}
+
+// armFollowUps назначает продолжения, ждущие подтверждения напоминания. Отметку
+// это не отменяет: сбой только записывается в журнал.
+func (ac *AckCommands) armFollowUps(ctx context.Context, a *domain.Acknowledgement) {
+	if _, err := ac.ReminderUsecase.ArmFollowUps(ctx, a.ReminderID, domain.FollowOnAck, a.AckedAt); err != nil {
+		slog.Error("Failed to arm follow-ups", "reminder_id", a.ReminderID, "ack_id", a.ID, "error", err)
+	}
+}
+
+// senderName возвращает имя пользователя для отметки о выполнении.
+func senderName(u *tele.User) string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+
+	return strconv.FormatInt(u.ID, 10)
+}
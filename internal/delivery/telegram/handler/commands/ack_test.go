@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+type ackStub struct {
+	ack *domain.Acknowledgement
+	// armed — моменты, от которых назначались продолжения.
+	armed []time.Time
+}
+
+func (s *ackStub) Acknowledge(
+	_ context.Context,
+	id, chatID, userID int64,
+	userName string,
+	at time.Time,
+) (*domain.Acknowledgement, error) {
+	if s.ack == nil || s.ack.ID != id || s.ack.ChatID != chatID {
+		return nil, repository.ErrAckNotFound
+	}
+	if s.ack.Acknowledged() {
+		copied := *s.ack
+		return &copied, domain.ErrAlreadyAcknowledged
+	}
+	s.ack.AckedBy, s.ack.AckedByName, s.ack.AckedAt = userID, userName, at.UTC()
+	copied := *s.ack
+
+	return &copied, nil
+}
+
+func (s *ackStub) ArmFollowUps(
+	_ context.Context,
+	parentID int64,
+	trigger domain.FollowTrigger,
+	at time.Time,
+) (int, error) {
+	if parentID == s.ack.ReminderID && trigger == domain.FollowOnAck {
+		s.armed = append(s.armed, at)
+	}
+
+	return 0, nil
+}
+
+type ackLocationStub struct{ loc *time.Location }
+
+func (s ackLocationStub) Location(context.Context, int64) *time.Location { return s.loc }
+
+type editorStub struct {
+	edited map[string]string
+}
+
+func (s *editorStub) Edit(msg tele.Editable, what any, _ ...any) (*tele.Message, error) {
+	id, _ := msg.MessageSig()
+	s.edited[id] = what.(string)
+
+	return &tele.Message{}, nil
+}
+
+type ackContext struct {
+	reminderCommandContext
+	callback *tele.Callback
+	sender   *tele.User
+	edits    []string
+}
+
+func (c *ackContext) Callback() *tele.Callback { return c.callback }
+func (c *ackContext) Sender() *tele.User       { return c.sender }
+func (c *ackContext) Edit(what any, _ ...any) error {
+	c.edits = append(c.edits, what.(string))
+	return nil
+}
+
+func TestHandleAckCallback(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	now := time.Date(2026, time.March, 10, 18, 5, 0, 0, moscow)
+
+	stub := &ackStub{ack: &domain.Acknowledgement{
+		ID: 3, ReminderID: 5, ChatID: -42, Text: "закрыть смену", MessageID: 10,
+	}}
+	editor := &editorStub{edited: map[string]string{}}
+	handler := NewAckCommands(stub, ackLocationStub{loc: moscow}, editor)
+	handler.nowFunc = func() time.Time { return now }
+
+	press := func(messageID int, text string) *ackContext {
+		ctx := &ackContext{
+			reminderCommandContext: reminderCommandContext{
+				chat:    &tele.Chat{ID: -42, Type: tele.ChatGroup},
+				message: &tele.Message{ID: messageID, Text: text},
+			},
+			callback: &tele.Callback{Data: "\fack_3"},
+			sender:   &tele.User{ID: 7, FirstName: "Анна", LastName: "Петрова"},
+		}
+		require.NoError(t, handler.HandleAckCallback(ctx))
+
+		return ctx
+	}
+
+	nag := texts.ReminderNag("закрыть смену", 1, 3)
+	ctx := press(11, nag)
+	done := "\n\n✅ Выполнено: Анна Петрова, 10.03.2026 в 18:05"
+	assert.Equal(t, []string{nag + done}, ctx.edits)
+	assert.Equal(t, texts.ReminderPrefix+"закрыть смену"+done, editor.edited["10"])
+	assert.Equal(t, int64(7), stub.ack.AckedBy)
+	assert.Equal(t, []time.Time{now.UTC()}, stub.armed)
+
+	// Кнопка под другим повтором показывает уже записанную отметку.
+	editor.edited = map[string]string{}
+	handler.nowFunc = func() time.Time { return now.Add(time.Hour) }
+	ctx = press(12, nag)
+	assert.Equal(t, []string{nag + done}, ctx.edits)
+	assert.Empty(t, editor.edited)
+	assert.Len(t, stub.armed, 1, "повторное нажатие продолжения не назначает")
+
+	stub.ack = nil
+	ctx = press(12, nag)
+	assert.Empty(t, ctx.edits)
+	assert.Equal(t, []string{texts.AckNotFound}, ctx.sent)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	tele "gopkg.in/telebot.v4"
+)
+
+// historyEntries — сколько последних попыток показывает /history: длинная история
+// не поместилась бы в одно сообщение.
+const historyEntries = 20
+
+type historyReminders interface {
+	ListHistory(ctx context.Context, chatID int64, limit int) ([]*domain.DeliveryAttempt, error)
+}
+
+type historyChats interface {
+	Location(ctx context.Context, chatID int64) *time.Location
+}
+
+// HistoryCommands содержит обработчик команды /history.
+type HistoryCommands struct {
+	ReminderUsecase historyReminders
+	ChatUsecase     historyChats
+}
+
+// NewHistoryCommands создает новый экземпляр HistoryCommands.
+func NewHistoryCommands(reminderUc historyReminders, chatUc historyChats) *HistoryCommands {
+	return &HistoryCommands{ReminderUsecase: reminderUc, ChatUsecase: chatUc}
+}
+
+// OnHistory обрабатывает команду /history: последние попытки доставки в чат — отправленные,
+// отложенные до повтора и брошенные, от новых к старым.
+func (hc *HistoryCommands) OnHistory(c tele.Context) error {
+	ctx := context.Background()
+	chatID := c.Chat().ID
+
+	attempts, err := hc.ReminderUsecase.ListHistory(ctx, chatID, historyEntries)
+	if err != nil {
+		slog.Error("Failed to list delivery history", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrGetHistory)
+	}
+	if len(attempts) == 0 {
+		return c.Send(texts.HistoryEmpty)
+	}
+
+	loc := hc.ChatUsecase.Location(ctx, chatID)
+	lines := make([]string, 0, len(attempts)+1)
+	lines = append(lines, texts.HistoryHeader)
+	for _, a := range attempts {
+		lines = append(lines, ui.FormatAttempt(a, loc))
+	}
+
+	return c.Send(strings.Join(lines, "\n"))
+}
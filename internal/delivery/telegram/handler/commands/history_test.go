@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+type historyStub struct {
+	attempts []*domain.DeliveryAttempt
+	chatID   int64
+	limit    int
+}
+
+func (s *historyStub) ListHistory(_ context.Context, chatID int64, limit int) ([]*domain.DeliveryAttempt, error) {
+	s.chatID, s.limit = chatID, limit
+
+	return s.attempts, nil
+}
+
+func TestOnHistory(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	scheduled := time.Date(2026, time.March, 10, 6, 0, 0, 0, time.UTC)
+
+	send := func(t *testing.T, stub *historyStub) string {
+		t.Helper()
+		ctx := &reminderCommandContext{chat: &tele.Chat{ID: -42}, message: &tele.Message{}}
+		require.NoError(t, NewHistoryCommands(stub, ackLocationStub{loc: moscow}).OnHistory(ctx))
+		require.Len(t, ctx.sent, 1)
+		assert.Equal(t, int64(-42), stub.chatID)
+		assert.Equal(t, historyEntries, stub.limit)
+
+		return ctx.sent[0]
+	}
+
+	assert.Equal(t, texts.HistoryEmpty, send(t, &historyStub{}))
+
+	got := send(t, &historyStub{attempts: []*domain.DeliveryAttempt{
+		{Text: "⏰ Напоминание: планёрка", ScheduledAt: scheduled, AttemptedAt: scheduled, Outcome: domain.OutcomeSent},
+		{
+			Text: "⏰ Напоминание: планёрка", ScheduledAt: scheduled, AttemptedAt: scheduled,
+			Outcome: domain.OutcomeRetry, ErrorClass: "server",
+		},
+	}})
+	assert.Equal(t, texts.HistoryHeader+"\n"+
+		"✅ 10.03 09:00 — ⏰ Напоминание: планёрка\n"+
+		"🔁 10.03 09:00, не отправлено: сбой на стороне Telegram, будет повтор — ⏰ Напоминание: планёрка", got)
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	tele "gopkg.in/telebot.v4"
+)
+
+type locationChats interface {
+	chatCreator
+	SetGeo(ctx context.Context, chatID int64, geo domain.GeoPoint) error
+	Location(ctx context.Context, chatID int64) *time.Location
+}
+
+// chatCreator создаёт строку чата, если её ещё нет.
+type chatCreator interface {
+	GetOrCreateChat(ctx context.Context, chatID int64, chatType, title, username string) (*domain.Chat, error)
+	Get(ctx context.Context, chatID int64) (*domain.Chat, error)
+}
+
+// LocationCommands сохраняет присланную в чат геопозицию: по ней считаются
+// напоминания на восход и закат.
+type LocationCommands struct {
+	ChatUsecase locationChats
+	nowFunc     func() time.Time
+}
+
+// NewLocationCommands создает новый экземпляр LocationCommands.
+func NewLocationCommands(chatUc locationChats) *LocationCommands {
+	return &LocationCommands{ChatUsecase: chatUc, nowFunc: time.Now}
+}
+
+// OnLocation запоминает координаты из геопозиции и показывает восход и закат на сегодня.
+func (lc *LocationCommands) OnLocation(c tele.Context) error {
+	point := c.Message().Location
+	if point == nil {
+		return nil
+	}
+	ctx := context.Background()
+	chatID := c.Chat().ID
+
+	if err := ensureChat(ctx, c, lc.ChatUsecase); err != nil {
+		slog.Error("Failed to upsert chat", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrSetLocation)
+	}
+
+	geo := domain.GeoPoint{Latitude: float64(point.Lat), Longitude: float64(point.Lng)}
+	if err := lc.ChatUsecase.SetGeo(ctx, chatID, geo); err != nil {
+		slog.Error("Failed to set chat location", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrSetLocation)
+	}
+	slog.Info("Chat location set", "chat_id", chatID)
+
+	loc := lc.ChatUsecase.Location(ctx, chatID)
+
+	return c.Send(texts.LocationSaved + ui.FormatSunTimes(geo, lc.nowFunc(), loc))
+}
+
+// ensureChat создаёт строку chats, если сообщение пришло раньше любого другого
+// сообщения чата: иначе настройку некуда сохранить.
+func ensureChat(ctx context.Context, c tele.Context, chats chatCreator) error {
+	chatID := c.Chat().ID
+	_, err := chats.Get(ctx, chatID)
+	if !errors.Is(err, repository.ErrChatNotFound) {
+		return nil
+	}
+
+	name := c.Chat().Title
+	if name == "" {
+		name = c.Chat().FirstName
+	}
+	_, err = chats.GetOrCreateChat(ctx, chatID, string(c.Chat().Type), name, c.Chat().Username)
+
+	return err
+}
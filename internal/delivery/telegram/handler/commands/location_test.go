@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+type locationChatsStub struct {
+	quietChatsStub
+	loc *time.Location
+	geo *domain.GeoPoint
+}
+
+func (s *locationChatsStub) SetGeo(_ context.Context, _ int64, geo domain.GeoPoint) error {
+	if err := geo.Validate(); err != nil {
+		return err
+	}
+	s.geo = &geo
+
+	return nil
+}
+
+func (s *locationChatsStub) Location(context.Context, int64) *time.Location {
+	return s.loc
+}
+
+func TestOnLocation(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	chats := &locationChatsStub{loc: moscow}
+	handler := NewLocationCommands(chats)
+	handler.nowFunc = func() time.Time { return time.Date(2025, time.June, 21, 12, 0, 0, 0, moscow) }
+
+	ctx := &reminderCommandContext{
+		chat:    &tele.Chat{ID: 42, Type: tele.ChatPrivate, FirstName: "Дарья"},
+		message: &tele.Message{Location: &tele.Location{Lat: 55.7558, Lng: 37.6173}},
+	}
+	require.NoError(t, handler.OnLocation(ctx))
+
+	assert.True(t, chats.created, "the chat row must exist before the location is saved")
+	require.NotNil(t, chats.geo)
+	assert.InDelta(t, 55.7558, chats.geo.Latitude, 1e-4)
+	assert.InDelta(t, 37.6173, chats.geo.Longitude, 1e-4)
+	require.Len(t, ctx.sent, 1)
+	assert.Equal(t, texts.LocationSaved+"восход в 03:45, закат в 21:18", ctx.sent[0])
+}
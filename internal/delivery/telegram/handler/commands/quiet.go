@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/8thgencore/dory-reminder-bot/pkg/validator"
+	tele "gopkg.in/telebot.v4"
+)
+
+type quietChats interface {
+	chatCreator
+	SetQuietHours(ctx context.Context, chatID int64, quiet domain.QuietHours) error
+}
+
+// QuietCommands содержит обработчик команды /quiet.
+type QuietCommands struct {
+	ChatUsecase quietChats
+}
+
+// NewQuietCommands создает новый экземпляр QuietCommands.
+func NewQuietCommands(chatUc quietChats) *QuietCommands {
+	return &QuietCommands{ChatUsecase: chatUc}
+}
+
+// OnQuiet обрабатывает команду /quiet: без параметров показывает тихие часы чата,
+// «/quiet 23:00-08:00 [тихо]» задаёт их, «/quiet off» — отключает.
+func (qc *QuietCommands) OnQuiet(c tele.Context) error {
+	ctx := context.Background()
+	chatID := c.Chat().ID
+
+	payload := strings.TrimSpace(c.Message().Payload)
+	if payload == "" {
+		chat, err := qc.ChatUsecase.Get(ctx, chatID)
+		if err != nil && !errors.Is(err, repository.ErrChatNotFound) {
+			slog.Error("Failed to load chat quiet hours", "chat_id", chatID, "error", err)
+			return c.Send(texts.ErrCheckSettings)
+		}
+		var quiet domain.QuietHours
+		if chat != nil {
+			quiet = chat.Quiet
+		}
+
+		return c.Send(texts.QuietHoursCurrent + ui.FormatQuietHours(quiet) + "\n\n" + texts.QuietUsage)
+	}
+
+	quiet, ok := parseQuietHours(payload)
+	if !ok {
+		return c.Send(texts.QuietUsage)
+	}
+
+	if err := ensureChat(ctx, c, qc.ChatUsecase); err != nil {
+		slog.Error("Failed to upsert chat", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrSetQuietHours)
+	}
+
+	if err := qc.ChatUsecase.SetQuietHours(ctx, chatID, quiet); err != nil {
+		slog.Error("Failed to set quiet hours", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrSetQuietHours)
+	}
+	if !quiet.Enabled() {
+		return c.Send(texts.QuietHoursOff)
+	}
+
+	return c.Send(texts.QuietHoursSet + ui.FormatQuietHours(quiet))
+}
+
+// parseQuietHours разбирает параметры /quiet: окно ЧЧ:ММ-ЧЧ:ММ, которое может
+// переходить через полночь, и необязательный режим; «off» отключает тихие часы.
+func parseQuietHours(payload string) (domain.QuietHours, bool) {
+	fields := strings.Fields(strings.ToLower(payload))
+	if len(fields) == 1 && (fields[0] == "off" || fields[0] == "выкл") {
+		return domain.QuietHours{}, true
+	}
+	if len(fields) > 2 {
+		return domain.QuietHours{}, false
+	}
+
+	from, to, ok := strings.Cut(strings.ReplaceAll(fields[0], "–", "-"), "-")
+	if !ok || !validator.IsTime(from) || !validator.IsTime(to) || from == to {
+		return domain.QuietHours{}, false
+	}
+	start, _ := time.Parse("15:04", from)
+	end, _ := time.Parse("15:04", to)
+	quiet := domain.QuietHours{
+		Start: start.Hour()*60 + start.Minute(),
+		End:   end.Hour()*60 + end.Minute(),
+	}
+
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "тихо", "silent":
+			quiet.Mode = domain.QuietSilent
+		case "отложить", "defer":
+			quiet.Mode = domain.QuietDefer
+		default:
+			return domain.QuietHours{}, false
+		}
+	}
+
+	return quiet, true
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+type quietChatsStub struct {
+	chat    *domain.Chat
+	created bool
+}
+
+func (s *quietChatsStub) GetOrCreateChat(
+	_ context.Context,
+	chatID int64,
+	chatType, title, username string,
+) (*domain.Chat, error) {
+	s.created = true
+	s.chat = &domain.Chat{ID: chatID, Type: chatType, Name: title, Username: username}
+
+	return s.chat, nil
+}
+
+func (s *quietChatsStub) Get(context.Context, int64) (*domain.Chat, error) {
+	if s.chat == nil {
+		return nil, repository.ErrChatNotFound
+	}
+
+	return s.chat, nil
+}
+
+func (s *quietChatsStub) SetQuietHours(_ context.Context, _ int64, quiet domain.QuietHours) error {
+	if err := quiet.Validate(); err != nil {
+		return err
+	}
+	s.chat.Quiet = quiet
+
+	return nil
+}
+
+func TestOnQuiet(t *testing.T) {
+	send := func(t *testing.T, chats *quietChatsStub, payload string) string {
+		t.Helper()
+		ctx := &reminderCommandContext{
+			chat:    &tele.Chat{ID: 42, Type: tele.ChatGroup, Title: "Команда"},
+			message: &tele.Message{Payload: payload},
+		}
+		require.NoError(t, NewQuietCommands(chats).OnQuiet(ctx))
+		require.Len(t, ctx.sent, 1)
+
+		return ctx.sent[0]
+	}
+
+	t.Run("установка в новом чате", func(t *testing.T) {
+		chats := &quietChatsStub{}
+		got := send(t, chats, "23:00-08:00")
+
+		assert.Equal(t, texts.QuietHoursSet+"23:00–08:00, напоминания откладываются до конца", got)
+		assert.True(t, chats.created)
+		assert.Equal(t, "Команда", chats.chat.Name)
+		assert.Equal(t, domain.QuietHours{Start: 23 * 60, End: 8 * 60}, chats.chat.Quiet)
+	})
+
+	t.Run("без звука и отключение", func(t *testing.T) {
+		chats := &quietChatsStub{chat: &domain.Chat{ID: 42, Name: "Команда"}}
+
+		got := send(t, chats, "13:00–14:30 тихо")
+		assert.Equal(t, texts.QuietHoursSet+"13:00–14:30, напоминания приходят без звука", got)
+		assert.False(t, chats.created)
+		assert.Equal(t, domain.QuietHours{Start: 13 * 60, End: 14*60 + 30, Mode: domain.QuietSilent}, chats.chat.Quiet)
+
+		got = send(t, chats, "")
+		assert.Contains(t, got, texts.QuietHoursCurrent+"13:00–14:30, напоминания приходят без звука")
+
+		assert.Equal(t, texts.QuietHoursOff, send(t, chats, "off"))
+		assert.False(t, chats.chat.Quiet.Enabled())
+	})
+
+	for _, payload := range []string{"23:00", "23:00-23:00", "25:00-08:00", "23:00-08:00 громко"} {
+		t.Run("ошибка формата "+payload, func(t *testing.T) {
+			chats := &quietChatsStub{}
+			assert.Equal(t, texts.QuietUsage, send(t, chats, payload))
+			assert.Nil(t, chats.chat)
+		})
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/quickadd"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/8thgencore/dory-reminder-bot/pkg/validator"
 	tele "gopkg.in/telebot.v4"
 )
@@ -19,30 +23,39 @@ import (
 const remindersPerPage = 10
 
 type reminderCommands interface {
+	AddReminder(ctx context.Context, reminder *domain.Reminder) error
 	ListReminders(ctx context.Context, chatID int64) ([]*domain.Reminder, error)
 	EditReminder(ctx context.Context, reminder *domain.Reminder) error
 	DeleteReminder(ctx context.Context, id int64) error
 	PauseReminder(ctx context.Context, id int64) error
 	ResumeReminder(ctx context.Context, id int64) error
+	SkipNext(ctx context.Context, reminder *domain.Reminder, now time.Time, loc *time.Location) error
 }
 
 type reminderChats interface {
 	Get(ctx context.Context, chatID int64) (*domain.Chat, error)
 	HasTimezone(ctx context.Context, chatID int64) (bool, error)
 	Location(ctx context.Context, chatID int64) *time.Location
+	Calendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error)
+	Geo(ctx context.Context, chatID int64) (*domain.GeoPoint, error)
 }
 
 // ReminderCRUD содержит обработчики CRUD операций с напоминаниями
 type ReminderCRUD struct {
 	Usecase     reminderCommands
 	ChatUsecase reminderChats
+	// SessionManager хранит напоминание из /add с текстом, пока его не подтвердят.
+	SessionManager *session.Manager
+	nowFunc        func() time.Time
 }
 
 // NewReminderCRUD создает новый экземпляр ReminderCRUD
-func NewReminderCRUD(reminderUc reminderCommands, chatUc reminderChats) *ReminderCRUD {
+func NewReminderCRUD(reminderUc reminderCommands, chatUc reminderChats, sessionMgr *session.Manager) *ReminderCRUD {
 	return &ReminderCRUD{
-		Usecase:     reminderUc,
-		ChatUsecase: chatUc,
+		Usecase:        reminderUc,
+		ChatUsecase:    chatUc,
+		SessionManager: sessionMgr,
+		nowFunc:        time.Now,
 	}
 }
 
@@ -74,13 +87,83 @@ func (rc *ReminderCRUD) OnAdd(c tele.Context) error {
 	if !hasTZ {
 		return c.Send(texts.TimezoneRequired)
 	}
-	if c.Message().Payload != "" {
-		return c.Send(texts.AddViaWizardOnly)
+	if phrase := strings.TrimSpace(c.Message().Payload); phrase != "" {
+		return rc.previewPhrase(c, phrase)
 	}
 
 	return c.Send(texts.HelpAdd, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, ui.GetAddMenu())
 }
 
+// previewPhrase разбирает напоминание, записанное фразой после /add, и показывает, как
+// бот его понял. Сохраняется оно только по кнопке — см. HandleAddPhraseCallback.
+func (rc *ReminderCRUD) previewPhrase(c tele.Context, phrase string) error {
+	ctx := context.Background()
+	chatID := c.Chat().ID
+	loc := rc.ChatUsecase.Location(ctx, chatID)
+	now := rc.nowFunc()
+
+	rem, _, err := quickadd.Parse(phrase, now.In(loc))
+	if errors.Is(err, domain.ErrEmptyText) {
+		return c.Send(texts.AddPhraseNoText)
+	}
+	if errors.Is(err, quickadd.ErrTodayPassed) {
+		return c.Send(texts.AddPhraseToday)
+	}
+	if err != nil {
+		return c.Send(texts.AddPhraseNotUnderstood)
+	}
+	rem.ChatID = chatID
+	rem.CreatedAt, rem.UpdatedAt = now.UTC(), now.UTC()
+	rem.Normalize()
+	if err := rem.Validate(); err != nil {
+		slog.Debug("Parsed phrase is not a valid reminder", "chat_id", chatID, "error", err)
+		return c.Send(texts.AddPhraseNotUnderstood)
+	}
+
+	rc.SessionManager.Set(&session.AddReminderSession{
+		UserID: c.Sender().ID,
+		ChatID: chatID,
+		Step:   session.StepConfirm,
+		Draft:  rem,
+	})
+
+	return c.Send(
+		texts.AddPhraseConfirm(rem.Text, ui.FormatTime(rem.NextTime, loc), ui.FormatRepeat(rem, loc)),
+		ui.AddPhraseMenu(),
+	)
+}
+
+// HandleAddPhraseCallback сохраняет или отменяет напоминание, разобранное из фразы.
+// Кнопки отвечают только автору: черновик хранится в его сессии.
+func (rc *ReminderCRUD) HandleAddPhraseCallback(c tele.Context) error {
+	chatID, userID := c.Chat().ID, c.Sender().ID
+	sess := rc.SessionManager.Get(chatID, userID)
+	if sess == nil || sess.Step != session.StepConfirm || sess.Draft == nil {
+		return c.Send(texts.AddPhraseExpired)
+	}
+	rc.SessionManager.Delete(chatID, userID)
+
+	// Удаляем сообщение с кнопками
+	if err := c.Delete(); err != nil {
+		slog.Warn("Failed to delete phrase confirmation message", "error", err)
+	}
+
+	if strings.TrimSpace(c.Callback().Data) != "addphrase_save" {
+		return c.Send(texts.AddPhraseCanceled)
+	}
+	// Черновик разобран в момент /add: пока автор думал, его время могло пройти.
+	if !sess.Draft.NextTime.After(rc.nowFunc()) {
+		return c.Send(texts.AddPhrasePassed)
+	}
+	if err := rc.Usecase.AddReminder(context.Background(), sess.Draft); err != nil {
+		slog.Error("Failed to create reminder from phrase", "chat_id", chatID, "error", err)
+		return c.Send(texts.ErrCreateReminder)
+	}
+	slog.Info("Reminder created from phrase", "chat_id", chatID, "reminder_id", sess.Draft.ID)
+
+	return c.Send(texts.ReminderCreated)
+}
+
 // OnList обрабатывает команду /list
 func (rc *ReminderCRUD) OnList(c tele.Context) error {
 	reminders, err := rc.getReminders(c.Chat().ID)
@@ -124,23 +207,35 @@ func (rc *ReminderCRUD) OnList(c tele.Context) error {
 		fmt.Fprintf(&builder, "🕐 *Часовой пояс:* %s\n\n", ui.EscapeMarkdownV2(ch.Timezone))
 	}
 
+	// Продолжения ссылаются на родителя по номеру в списке, а не по ID.
+	numbers := make(map[int64]int, len(reminders))
+	for i, r := range reminders {
+		numbers[r.ID] = i + 1
+	}
+
 	for i := start; i < end; i++ {
 		r := reminders[i]
 
 		status := ui.FormatStatus(r.Paused)
-		timeStr := ui.EscapeMarkdownV2(ui.FormatTime(r.NextTime, loc))
-		repeatStr := ui.EscapeMarkdownV2(ui.FormatRepeat(r))
+		timeStr := "📅 " + ui.EscapeMarkdownV2(ui.FormatReminderTime(r, loc))
+		repeatStr := "🔁 " + ui.EscapeMarkdownV2(ui.FormatRepeat(r, r.Location(loc)))
+		if r.IsFollowUp() {
+			repeatStr = "🔗 " + ui.EscapeMarkdownV2(ui.FormatFollowUp(r, numbers[r.ParentID]))
+			if r.Waiting {
+				timeStr = "⏳ ждёт срабатывания"
+			}
+		}
 
 		fmt.Fprintf(&builder, "*%d\\.* %s\n", i+1, ui.EscapeMarkdownV2(r.Text))
 
 		// Отображаем статус только если напоминание приостановлено
 		if status != "" {
-			fmt.Fprintf(&builder, "   %s \\| 📅 %s\n", ui.EscapeMarkdownV2(status), timeStr)
+			fmt.Fprintf(&builder, "   %s \\| %s\n", ui.EscapeMarkdownV2(status), timeStr)
 		} else {
-			fmt.Fprintf(&builder, "   📅 %s\n", timeStr)
+			fmt.Fprintf(&builder, "   %s\n", timeStr)
 		}
 
-		fmt.Fprintf(&builder, "   🔁 %s\n", repeatStr)
+		fmt.Fprintf(&builder, "   %s\n", repeatStr)
 		builder.WriteString("\n")
 	}
 
@@ -205,15 +300,29 @@ func (rc *ReminderCRUD) OnEdit(c tele.Context) error {
 	}
 
 	if newTime != "" {
-		nextTime, err := nextTimeAtClock(
-			newTime,
-			rem.NextTime,
-			rc.ChatUsecase.Location(context.Background(), c.Chat().ID),
-		)
+		// Время вводится по зоне напоминания — той же, в которой оно показано в /list.
+		loc := rem.Location(rc.ChatUsecase.Location(context.Background(), c.Chat().ID))
+		nextTime, err := nextTimeAtClock(newTime, rem.NextTime, loc)
 		if err != nil {
 			return c.Send(texts.ErrUpdateReminder)
 		}
 		rem.NextTime = nextTime
+		// /edit задаёт одно время: прежний список времён в течение дня и окно случайного
+		// времени больше не действуют.
+		rem.Times = nil
+		if rem.HasRandomWindow() {
+			rem.WindowStart, rem.WindowEnd = 0, 0
+		}
+		if !rem.StartTime.IsZero() {
+			// Время срабатываний правила RRULE берётся из DTSTART: без сдвига следующее
+			// срабатывание вернулось бы к прежнему времени.
+			rem.StartTime = withClockOf(rem.StartTime, nextTime, loc)
+		}
+		if !rem.ShiftedFrom.IsZero() {
+			// Срабатывание, перенесённое на рабочий день, остаётся привязанным к своему
+			// дню по расписанию — меняется только время.
+			rem.ShiftedFrom = withClockOf(rem.ShiftedFrom, nextTime, loc)
+		}
 	}
 	if newText != "" {
 		rem.Text = newText
@@ -226,6 +335,13 @@ func (rc *ReminderCRUD) OnEdit(c tele.Context) error {
 	return c.Send(texts.ReminderUpdated)
 }
 
+// withClockOf возвращает день day со временем суток clock в поясе loc.
+func withClockOf(day, clock time.Time, loc *time.Location) time.Time {
+	d, c := day.In(loc), clock.In(loc)
+
+	return time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), 0, 0, loc).UTC()
+}
+
 func nextTimeAtClock(value string, base time.Time, loc *time.Location) (time.Time, error) {
 	clock, err := time.ParseInLocation("15:04", value, loc)
 	if err != nil {
@@ -259,24 +375,35 @@ func (rc *ReminderCRUD) handleReminderAction(
 	errMsg, successMsg string,
 	do func(remID int64) error,
 ) error {
+	rem, failure := rc.reminderByNumber(c)
+	if rem == nil {
+		return c.Send(failure)
+	}
+
+	if err := do(rem.ID); err != nil {
+		return c.Send(errMsg)
+	}
+
+	return c.Send(successMsg)
+}
+
+// reminderByNumber находит напоминание по номеру из аргумента команды. Если найти не
+// удалось, возвращает nil и текст ошибки для пользователя.
+func (rc *ReminderCRUD) reminderByNumber(c tele.Context) (*domain.Reminder, string) {
 	num, err := getReminderNumber(strings.TrimSpace(c.Message().Payload))
 	if err != nil {
-		return c.Send(texts.ErrWrongNumber)
+		return nil, texts.ErrWrongNumber
 	}
 
 	reminders, err := rc.getReminders(c.Chat().ID)
 	if err != nil {
-		return c.Send(texts.ErrGetReminders)
+		return nil, texts.ErrGetReminders
 	}
 	if num > len(reminders) {
-		return c.Send(texts.ErrNoSuchReminder)
-	}
-
-	if err := do(reminders[num-1].ID); err != nil {
-		return c.Send(errMsg)
+		return nil, texts.ErrNoSuchReminder
 	}
 
-	return c.Send(successMsg)
+	return reminders[num-1], ""
 }
 
 // OnDelete обрабатывает команду /delete
@@ -299,3 +426,41 @@ func (rc *ReminderCRUD) OnResume(c tele.Context) error {
 		return rc.Usecase.ResumeReminder(context.Background(), remID)
 	})
 }
+
+// OnSkip обрабатывает команду /skip: ближайшее срабатывание повторяющегося напоминания
+// пропускается, а расписание серии остаётся прежним.
+func (rc *ReminderCRUD) OnSkip(c tele.Context) error {
+	rem, failure := rc.reminderByNumber(c)
+	if rem == nil {
+		return c.Send(failure)
+	}
+	if rem.Repeat == domain.RepeatNone {
+		return c.Send(texts.ErrSkipOneTime)
+	}
+
+	if rem.WorkdayPolicy != domain.WorkdayAny {
+		calendar, err := rc.ChatUsecase.Calendar(context.Background(), c.Chat().ID)
+		if err != nil {
+			return c.Send(texts.ErrSkipReminder)
+		}
+		rem.Calendar = calendar
+	}
+	if rem.Repeat == domain.RepeatSolar {
+		geo, err := rc.ChatUsecase.Geo(context.Background(), c.Chat().ID)
+		if err != nil {
+			return c.Send(texts.ErrSkipReminder)
+		}
+		rem.Geo = geo
+	}
+
+	loc := rem.Location(rc.ChatUsecase.Location(context.Background(), c.Chat().ID))
+	err := rc.Usecase.SkipNext(context.Background(), rem, rc.nowFunc(), loc)
+	if errors.Is(err, scheduling.ErrSeriesEnded) {
+		return c.Send(texts.ErrSkipLast)
+	}
+	if err != nil {
+		return c.Send(texts.ErrSkipReminder)
+	}
+
+	return c.Send(texts.ReminderSkipped + ui.FormatTime(rem.NextTime, loc))
+}
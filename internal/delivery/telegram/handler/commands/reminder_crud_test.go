@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	tele "gopkg.in/telebot.v4"
@@ -56,7 +59,16 @@ func TestNextTimeAtClockUsesChatTimezoneAndCalendarDays(t *testing.T) {
 
 type reminderCommandsStub struct {
 	reminders []*domain.Reminder
+	added     *domain.Reminder
 	edited    *domain.Reminder
+	skipped   *domain.Reminder
+	skippedAt time.Time
+	skipErr   error
+}
+
+func (s *reminderCommandsStub) AddReminder(_ context.Context, reminder *domain.Reminder) error {
+	s.added = reminder
+	return nil
 }
 
 func (s *reminderCommandsStub) ListReminders(context.Context, int64) ([]*domain.Reminder, error) {
@@ -72,6 +84,22 @@ func (s *reminderCommandsStub) DeleteReminder(context.Context, int64) error { re
 func (s *reminderCommandsStub) PauseReminder(context.Context, int64) error  { return nil }
 func (s *reminderCommandsStub) ResumeReminder(context.Context, int64) error { return nil }
 
+// SkipNext имитирует пропуск недельной серии: следующее срабатывание — через неделю.
+func (s *reminderCommandsStub) SkipNext(
+	_ context.Context,
+	reminder *domain.Reminder,
+	now time.Time,
+	_ *time.Location,
+) error {
+	if s.skipErr != nil {
+		return s.skipErr
+	}
+	s.skipped, s.skippedAt = reminder, now
+	reminder.NextTime = reminder.NextTime.AddDate(0, 0, 7)
+
+	return nil
+}
+
 type reminderChatsStub struct {
 	loc *time.Location
 }
@@ -88,15 +116,27 @@ func (s *reminderChatsStub) Location(context.Context, int64) *time.Location {
 	return s.loc
 }
 
+func (s *reminderChatsStub) Calendar(_ context.Context, chatID int64) (*domain.BusinessCalendar, error) {
+	return &domain.BusinessCalendar{ChatID: chatID}, nil
+}
+
+func (s *reminderChatsStub) Geo(context.Context, int64) (*domain.GeoPoint, error) {
+	return nil, nil
+}
+
 type reminderCommandContext struct {
 	tele.Context
-	chat    *tele.Chat
-	message *tele.Message
-	sent    []string
+	chat     *tele.Chat
+	message  *tele.Message
+	callback *tele.Callback
+	sent     []string
 }
 
-func (c *reminderCommandContext) Chat() *tele.Chat       { return c.chat }
-func (c *reminderCommandContext) Message() *tele.Message { return c.message }
+func (c *reminderCommandContext) Chat() *tele.Chat         { return c.chat }
+func (c *reminderCommandContext) Message() *tele.Message   { return c.message }
+func (c *reminderCommandContext) Callback() *tele.Callback { return c.callback }
+func (c *reminderCommandContext) Sender() *tele.User       { return &tele.User{ID: 7} }
+func (c *reminderCommandContext) Delete() error            { return nil }
 func (c *reminderCommandContext) Send(message any, _ ...any) error {
 	c.sent = append(c.sent, message.(string))
 	return nil
@@ -112,7 +152,7 @@ func TestOnEditInterpretsTimeInChatTimezone(t *testing.T) {
 		Text:     "старый текст",
 		NextTime: time.Date(2026, time.July, 31, 7, 0, 0, 0, time.UTC),
 	}}}
-	handler := NewReminderCRUD(service, &reminderChatsStub{loc: loc})
+	handler := NewReminderCRUD(service, &reminderChatsStub{loc: loc}, session.NewSessionManager())
 	ctx := &reminderCommandContext{
 		chat:    &tele.Chat{ID: 42},
 		message: &tele.Message{Payload: "1 09:00 новый текст"},
@@ -123,3 +163,139 @@ func TestOnEditInterpretsTimeInChatTimezone(t *testing.T) {
 	assert.Equal(t, "новый текст", service.edited.Text)
 	assert.Equal(t, time.Date(2026, time.August, 1, 6, 0, 0, 0, time.UTC), service.edited.NextTime)
 }
+
+func TestOnSkip(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	weekly := func() *domain.Reminder {
+		return &domain.Reminder{
+			ID:         1,
+			ChatID:     42,
+			Text:       "планёрка",
+			NextTime:   time.Date(2026, time.July, 28, 7, 0, 0, 0, time.UTC),
+			Repeat:     domain.RepeatEveryWeek,
+			RepeatDays: []int{int(time.Tuesday)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		reminder *domain.Reminder
+		payload  string
+		skipErr  error
+		want     string
+	}{
+		{
+			name:     "пропуск ближайшего",
+			reminder: weekly(),
+			payload:  "1",
+			want:     texts.ReminderSkipped + "04.08.2026 в 10:00",
+		},
+		{
+			name:     "разовое напоминание",
+			reminder: &domain.Reminder{ID: 1, ChatID: 42, Text: "звонок", NextTime: time.Now(), Repeat: domain.RepeatNone},
+			payload:  "1",
+			want:     texts.ErrSkipOneTime,
+		},
+		{
+			name:     "последнее срабатывание серии",
+			reminder: weekly(),
+			payload:  "1",
+			skipErr:  scheduling.ErrSeriesEnded,
+			want:     texts.ErrSkipLast,
+		},
+		{name: "несуществующий номер", reminder: weekly(), payload: "2", want: texts.ErrNoSuchReminder},
+		{name: "некорректный номер", reminder: weekly(), payload: "abc", want: texts.ErrWrongNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &reminderCommandsStub{reminders: []*domain.Reminder{tt.reminder}, skipErr: tt.skipErr}
+			handler := NewReminderCRUD(service, &reminderChatsStub{loc: loc}, session.NewSessionManager())
+			now := time.Date(2026, time.July, 27, 9, 0, 0, 0, time.UTC)
+			handler.nowFunc = func() time.Time { return now }
+			ctx := &reminderCommandContext{
+				chat:    &tele.Chat{ID: 42},
+				message: &tele.Message{Payload: tt.payload},
+			}
+
+			require.NoError(t, handler.OnSkip(ctx))
+			require.Len(t, ctx.sent, 1)
+			assert.Equal(t, tt.want, ctx.sent[0])
+			if service.skipped != nil {
+				assert.Equal(t, now, service.skippedAt, "the skip uses the handler clock")
+			}
+		})
+	}
+}
+
+func TestOnAddPhraseConfirmsBeforeSaving(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	service := &reminderCommandsStub{}
+	handler := NewReminderCRUD(service, &reminderChatsStub{loc: loc}, session.NewSessionManager())
+	handler.nowFunc = func() time.Time { return time.Date(2026, time.June, 10, 11, 0, 0, 0, time.UTC) }
+	ctx := &reminderCommandContext{
+		chat:    &tele.Chat{ID: 42},
+		message: &tele.Message{Payload: "завтра в 9 купить молоко"},
+	}
+
+	require.NoError(t, handler.OnAdd(ctx))
+	require.Len(t, ctx.sent, 1)
+	assert.Equal(t, texts.AddPhraseConfirm("купить молоко", "11.06.2026 в 09:00", "разово"), ctx.sent[0])
+	assert.Nil(t, service.added, "nothing is saved until confirmed")
+
+	ctx.callback = &tele.Callback{Data: "\faddphrase_save"}
+	require.NoError(t, handler.HandleAddPhraseCallback(ctx))
+	require.NotNil(t, service.added)
+	assert.Equal(t, int64(42), service.added.ChatID)
+	assert.Equal(t, time.Date(2026, time.June, 11, 6, 0, 0, 0, time.UTC), service.added.NextTime)
+	assert.Equal(t, texts.ReminderCreated, ctx.sent[len(ctx.sent)-1])
+
+	require.NoError(t, handler.HandleAddPhraseCallback(ctx))
+	assert.Equal(t, texts.AddPhraseExpired, ctx.sent[len(ctx.sent)-1], "a draft is saved only once")
+}
+
+func TestOnAddPhraseRejectsPassedTime(t *testing.T) {
+	now := time.Date(2026, time.June, 10, 11, 0, 0, 0, time.UTC)
+	service := &reminderCommandsStub{}
+	handler := NewReminderCRUD(service, &reminderChatsStub{loc: time.UTC}, session.NewSessionManager())
+	handler.nowFunc = func() time.Time { return now }
+
+	t.Run("сегодня, но время уже прошло", func(t *testing.T) {
+		ctx := &reminderCommandContext{
+			chat:    &tele.Chat{ID: 42},
+			message: &tele.Message{Payload: "сегодня в 9 позвонить"},
+		}
+		require.NoError(t, handler.OnAdd(ctx))
+		assert.Equal(t, []string{texts.AddPhraseToday}, ctx.sent)
+	})
+
+	t.Run("подтверждение после срабатывания", func(t *testing.T) {
+		ctx := &reminderCommandContext{chat: &tele.Chat{ID: 42}, message: &tele.Message{Payload: "в 11:30 созвон"}}
+		require.NoError(t, handler.OnAdd(ctx))
+
+		handler.nowFunc = func() time.Time { return now.Add(time.Hour) }
+		ctx.callback = &tele.Callback{Data: "\faddphrase_save"}
+		require.NoError(t, handler.HandleAddPhraseCallback(ctx))
+		assert.Equal(t, texts.AddPhrasePassed, ctx.sent[len(ctx.sent)-1])
+	})
+	assert.Nil(t, service.added)
+}
+
+func TestOnAddPhraseRejectsUnknownSchedule(t *testing.T) {
+	service := &reminderCommandsStub{}
+	handler := NewReminderCRUD(service, &reminderChatsStub{loc: time.UTC}, session.NewSessionManager())
+
+	for payload, want := range map[string]string{
+		"купить молоко": texts.AddPhraseNotUnderstood,
+		"завтра в 9":    texts.AddPhraseNoText,
+	} {
+		ctx := &reminderCommandContext{chat: &tele.Chat{ID: 42}, message: &tele.Message{Payload: payload}}
+		require.NoError(t, handler.OnAdd(ctx))
+		assert.Equal(t, []string{want}, ctx.sent, payload)
+	}
+	assert.Nil(t, service.added)
+}
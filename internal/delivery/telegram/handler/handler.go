@@ -38,8 +38,13 @@ type Handler struct {
 	BasicCommands     *commands.BasicCommands
 	ReminderCRUD      *commands.ReminderCRUD
 	WebAppCommands    *commands.WebAppCommands
+	QuietCommands     *commands.QuietCommands
+	LocationCommands  *commands.LocationCommands
+	AckCommands       *commands.AckCommands
+	HistoryCommands   *commands.HistoryCommands
 	AddReminderWizard *wizards.AddReminderWizard
 	TimezoneWizard    *wizards.TimezoneWizard
+	SnoozeWizard      *wizards.SnoozeWizard
 }
 
 // NewHandler создает новый Handler для работы с напоминаниями
@@ -58,10 +63,15 @@ func NewHandler(bot *tele.Bot, reminderUc usecase.ReminderUsecase,
 		ChatUC:            chatUc,
 		MemberUC:          memberUc,
 		BasicCommands:     commands.NewBasicCommands(chatUc, ui.GetMainMenu),
-		ReminderCRUD:      commands.NewReminderCRUD(reminderUc, chatUc),
+		ReminderCRUD:      commands.NewReminderCRUD(reminderUc, chatUc, sessionMgr),
 		WebAppCommands:    commands.NewWebAppCommands(webAppCfg, botName),
+		QuietCommands:     commands.NewQuietCommands(chatUc),
+		LocationCommands:  commands.NewLocationCommands(chatUc),
+		AckCommands:       commands.NewAckCommands(reminderUc, chatUc, bot),
+		HistoryCommands:   commands.NewHistoryCommands(reminderUc, chatUc),
 		AddReminderWizard: wizards.NewAddReminderWizard(reminderUc, sessionMgr, chatUc, botName),
 		TimezoneWizard:    wizards.NewTimezoneWizard(chatUc, sessionMgr, ui.GetMainMenu, botName),
+		SnoozeWizard:      wizards.NewSnoozeWizard(reminderUc, sessionMgr, chatUc, botName),
 	}
 
 	return h
@@ -80,9 +90,14 @@ func (h *Handler) Register() {
 	h.Bot.Handle("/delete", h.ReminderCRUD.OnDelete)
 	h.Bot.Handle("/pause", h.ReminderCRUD.OnPause)
 	h.Bot.Handle("/resume", h.ReminderCRUD.OnResume)
+	h.Bot.Handle("/skip", h.ReminderCRUD.OnSkip)
+	h.Bot.Handle("/history", h.HistoryCommands.OnHistory)
 
 	// Настройка часового пояса
 	h.Bot.Handle("/timezone", h.TimezoneWizard.OnTimezone)
+	h.Bot.Handle("/quiet", h.QuietCommands.OnQuiet)
+	// Геопозиция для напоминаний на восход и закат
+	h.Bot.Handle(tele.OnLocation, h.LocationCommands.OnLocation)
 
 	// Telegram Mini App
 	h.Bot.Handle("/app", h.onApp)
@@ -105,6 +120,8 @@ func (h *Handler) Register() {
 		{ui.BtnMonth, wizards.ReminderTypeMonth},
 		{ui.BtnYear, wizards.ReminderTypeYear},
 		{ui.BtnDate, wizards.ReminderTypeDate},
+		{ui.BtnInterval, wizards.ReminderTypeInterval},
+		{ui.BtnPeriod, wizards.ReminderTypePeriod},
 	}
 	for _, b := range reminderTypeButtons {
 		h.Bot.Handle(b.btn, h.withCallbackAck(func(c tele.Context) error {
@@ -182,6 +199,9 @@ func (h *Handler) onText(c tele.Context) error {
 	if sess != nil && sess.Step == session.StepTimezone {
 		return h.TimezoneWizard.HandleTimezoneText(c, h.BotName)
 	}
+	if sess != nil && sess.Step == session.StepSnooze {
+		return h.SnoozeWizard.HandleSnoozeText(c, h.BotName)
+	}
 	if sess != nil && (sess.Step == session.StepTime || sess.Step == session.StepText ||
 		sess.Step == session.StepInterval || sess.Step == session.StepDate) {
 		return h.AddReminderWizard.HandleAddWizardText(c, h.BotName)
@@ -302,6 +322,24 @@ func (h *Handler) onCallback(c tele.Context) error {
 	if strings.HasPrefix(callbackData, "weekday_") {
 		return h.AddReminderWizard.HandleWeekdayCallback(c)
 	}
+	if strings.HasPrefix(callbackData, "month_") {
+		return h.AddReminderWizard.HandleMonthModeCallback(c)
+	}
+	if strings.HasPrefix(callbackData, "interval_") {
+		return h.AddReminderWizard.HandleIntervalCallback(c)
+	}
+	if strings.HasPrefix(callbackData, "snooze_") {
+		return h.SnoozeWizard.HandleSnoozeCallback(c)
+	}
+	if strings.HasPrefix(callbackData, "addphrase_") {
+		return h.ReminderCRUD.HandleAddPhraseCallback(c)
+	}
+	if strings.HasPrefix(callbackData, "tzrebase_") {
+		return h.TimezoneWizard.HandleTimezoneRebaseCallback(c)
+	}
+	if strings.HasPrefix(callbackData, "ack_") {
+		return h.AckCommands.HandleAckCallback(c)
+	}
 
 	return nil
 }
@@ -3,9 +3,19 @@
 package texts
 
 const (
-	PromptToday    = "Во сколько напомнить сегодня? (например, 15:00)"
-	PromptTomorrow = "Во сколько напомнить завтра? (например, 15:00)"
-	PromptEveryDay = "Во сколько напоминать каждый день? (например, 09:00)"
+	PromptToday    = "Во сколько напомнить сегодня? (например, 15:00 или случайно в окне 15:00-17:00)"
+	PromptTomorrow = "Во сколько напомнить завтра? (например, 15:00 или случайно в окне 15:00-17:00)"
+	PromptEveryDay = "Во сколько напоминать каждый день? (09:00, несколько через запятую или окно 10:00-12:00)"
 	PromptWeek     = "В какой день недели? (например: понедельник)"
 	PromptUnknown  = "Неизвестный тип напоминания"
+
+	PromptMonth        = "Введите число месяца от 1 до 31 или выберите вариант ниже"
+	PromptMonthOrdinal = "Какой по счёту день недели в месяце?"
+	PromptMonthWeekday = "Какой день недели? (например: вторник)"
+
+	PromptInterval     = "Как часто напоминать? (например: 30 мин, 2 ч или 1 ч 30 мин)"
+	PromptWindow       = "В какие часы? Введите окно, например 10:00-18:00, или выберите вариант ниже"
+	PromptIntervalDays = "В какие дни?"
+
+	PromptPeriod = "Как часто повторять? (например: 2 недели, 3 месяца или 2 года)"
 )
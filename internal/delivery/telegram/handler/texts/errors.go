@@ -12,8 +12,21 @@ const (
 	ErrUpdateReminder = "Ошибка при обновлении напоминания"
 	ErrCreateReminder = "Ошибка при создании напоминания"
 	ErrUnknownDay     = "Ошибка: неверный день недели."
+	ErrUnknownMonth   = "Ошибка: неизвестный вариант ежемесячного повтора."
+	ErrUnknownWindow  = "Ошибка: неизвестный вариант интервального повтора."
 	ErrSetTimezone    = "Ошибка при установке часового пояса"
+	ErrRebaseInstant  = "Часовой пояс не изменён: у некоторых напоминаний время задано по часам " +
+		"(несколько времён, окно или cron). Выберите «Сохранить время по часам»"
+	ErrSetQuietHours  = "Ошибка при установке тихих часов"
+	ErrSetLocation    = "Ошибка при сохранении геопозиции"
+	ErrSnooze         = "Не удалось отложить напоминание"
+	ErrAcknowledge    = "Не удалось отметить напоминание выполненным"
+	ErrGetHistory     = "Ошибка при получении истории доставок"
 	ErrDeleteReminder = "Ошибка при удалении напоминания"
 	ErrPauseReminder  = "Ошибка при постановке напоминания на паузу"
 	ErrResumeReminder = "Ошибка при возобновлении напоминания"
+	ErrSkipReminder   = "Ошибка при пропуске срабатывания"
+	ErrSkipOneTime    = "Разовое напоминание нельзя пропустить — удалите его командой /delete"
+	ErrSkipLast       = "Это последнее срабатывание серии — чтобы его отменить, " +
+		"удалите напоминание командой /delete"
 )
@@ -12,23 +12,34 @@ const (
 		"• **Раз в месяц** - в выбранное число месяца\n" +
 		"• **Раз в несколько дней** - с указанным интервалом\n" +
 		"• **Раз в год** - в указанную дату\n" +
+		"• **Раз в N недель, месяцев или лет** - например, раз в 2 недели или раз в квартал\n" +
 		"• **Выбрать дату** - разовое напоминание в конкретную дату\n\n" +
 		"*Примеры:*\n" +
 		"• Сегодня → 15:00 → Позвонить маме\n" +
-		"• Ежедневно → 09:00 → Принять таблетку"
+		"• Ежедневно → 09:00 → Принять таблетку\n\n" +
+		"*Одной фразой:* напишите расписание и текст сразу после команды — бот покажет, как понял, " +
+		"и сохранит после подтверждения:\n" +
+		"• `/add завтра в 9 купить молоко`\n" +
+		"• `/add каждый понедельник в 10:30 планёрка`\n" +
+		"• `/add через 2 часа позвонить`\n" +
+		"• `/add every other Friday at 5pm retro` — можно и по-английски"
 
 	// HelpManage содержит справку по управлению напоминаниями
 	HelpManage = "⚙️ *Управление напоминаниями*\n\n" +
 		"*Команды:*\n" +
 		"• `/delete <номер>` - удалить напоминание\n" +
 		"• `/pause <номер>` - поставить на паузу\n" +
-		"• `/resume <номер>` - возобновить напоминание\n\n" +
+		"• `/resume <номер>` - возобновить напоминание\n" +
+		"• `/skip <номер>` - пропустить ближайшее срабатывание\n" +
+		"• `/history` - последние доставки: что бот отправил и что не смог\n\n" +
 		"*Примеры:*\n" +
 		"• `/delete 2` - удалить напоминание №2\n" +
 		"• `/pause 1` - поставить на паузу напоминание №1\n" +
-		"• `/resume 1` - возобновить напоминание №1\n\n" +
+		"• `/resume 1` - возобновить напоминание №1\n" +
+		"• `/skip 3` - пропустить ближайшую встречу, не меняя расписание\n\n" +
 		"*Примечания:*\n" +
 		"• Номера напоминаний можно посмотреть командой `/list`\n" +
 		"• На паузе напоминания не срабатывают, но сохраняются\n" +
+		"• Пропуск касается только одного срабатывания: следующие придут по расписанию\n" +
 		"• Удалённые напоминания восстановить нельзя"
 )
@@ -2,6 +2,11 @@
 
 package texts
 
+import (
+	"strconv"
+	"strings"
+)
+
 // Все тексты, отправляемые пользователю, вынесены сюда.
 
 const (
@@ -18,8 +23,13 @@ const (
 /delete - удалить напоминание
 /pause - поставить на паузу
 /resume - возобновить напоминание
+/skip - пропустить ближайшее срабатывание
+/history - история доставок
 /timezone - установить часовой пояс
-/app - открыть приложение`
+/quiet - тихие часы
+/app - открыть приложение
+
+Отправьте геопозицию, чтобы ставить напоминания на восход и закат.`
 	SetTimezonePrompt = "🌍 Введите ваш часовой пояс в формате IANA (например, Europe/Moscow, " +
 		"America/New_York, Asia/Tokyo):"
 	UnknownTimezone = "❌ Неизвестный или невалидный часовой пояс. Введите в формате IANA, например: " +
@@ -38,15 +48,129 @@ const (
 	ReminderDeleted   = "🗑️ Напоминание удалено!"
 	ReminderPaused    = "⏸️ Напоминание поставлено на паузу!"
 	ReminderResumed   = "▶️ Напоминание возобновлено!"
+	ReminderSkipped   = "⏭️ Ближайшее срабатывание пропущено. Следующее — "
 	RemindersHeader   = "📋 *Ваши напоминания*"
 	ReminderPrefix    = "⏰ Напоминание: "
 	TimezoneRequired  = "⚠️ Сначала установите часовой пояс командой /timezone"
-	AddViaWizardOnly  = "Для создания напоминания используйте мастер через /add без параметров."
 	EditUsage         = "Формат: /edit <номер> <новый текст> или /edit <номер> <время> <новый текст>"
 	WebAppUnavailable = "Веб-приложение сейчас недоступно. Используйте команды бота: /add, /list."
 	WebAppOpenPrivate = "Управляйте напоминаниями в удобном интерфейсе:"
 	WebAppOpenGroup   = "Управляйте напоминаниями этого чата:"
 	WebAppButton      = "📱 Открыть приложение"
+
+	// Тихие часы.
+	QuietHoursCurrent = "🌙 Тихие часы: "
+	QuietHoursSet     = "🌙 Тихие часы установлены: "
+	QuietHoursOff     = "🔔 Тихие часы отключены"
+	QuietUsage        = "Формат: /quiet 23:00-08:00 — отложить напоминания до конца тихих часов, " +
+		"/quiet 23:00-08:00 тихо — присылать без звука, /quiet off — отключить"
+
+	// Геопозиция для напоминаний на восход и закат.
+	LocationSaved = "📍 Геопозиция сохранена — по ней считаются напоминания на восход и закат. " +
+		"Создать такое напоминание можно в приложении: /app\n\nСегодня: "
+
+	// Напоминание одной фразой: /add завтра в 9 купить молоко.
+	AddPhraseNotUnderstood = "Не получилось разобрать, когда напомнить. Начните с даты, времени или повтора:\n" +
+		"/add завтра в 9 купить молоко\n" +
+		"/add каждый понедельник в 10:30 планёрка\n" +
+		"/add через 2 часа позвонить\n" +
+		"/add 15 июня в 18:00 день рождения\n\n" +
+		"Или отправьте /add без текста — откроется мастер."
+	AddPhraseNoText   = "Добавьте после времени, о чём напомнить: /add завтра в 9 купить молоко"
+	AddPhraseCanceled = "Напоминание не создано"
+	AddPhraseExpired  = "Подтверждение устарело — отправьте /add ещё раз"
+	AddPhraseToday    = "Это время сегодня уже прошло — укажите другое или напишите «завтра»"
+	AddPhrasePassed   = "Время напоминания уже прошло — отправьте /add ещё раз"
+
+	// Смена часового пояса.
+	TimezoneRebasePrompt = "Как пересчитать напоминания под новый часовой пояс?\n\n" +
+		"🕘 По часам — 09:00 останется 09:00 уже по новому времени.\n" +
+		"⏱ По моменту — напоминания сработают в тот же момент, что и раньше, но на часах будет другое время."
+	TimezoneRebaseExpired = "Выбор устарел — отправьте /timezone ещё раз"
+
+	// Отложенные напоминания.
+	SnoozePrompt = "Когда напомнить снова? Введите через сколько (30 мин, 2 ч) или время (18:30)"
+	SnoozedUntil = "💤 Напомню снова "
+
+	// Подтверждение выполнения.
+	AckNotFound = "Это напоминание больше не ждёт подтверждения"
+
+	// История доставок.
+	HistoryHeader = "🕓 Последние доставки:"
+	HistoryEmpty  = "Доставок пока не было"
 )
 
 // Функции для генерации динамических текстов можно добавить ниже.
+
+const (
+	lateReminderPrefix  = "⏰ Напоминание с опозданием (должно было прийти "
+	missedSummaryMarker = "\n\n⚠️ Пока бот был недоступен, пропущено срабатываний: "
+)
+
+// ReminderLate — напоминание, доставленное позже порога чата; due — исходное время
+// в поясе чата.
+func ReminderLate(text, due string) string {
+	return lateReminderPrefix + due + "): " + text
+}
+
+// ReminderMissedSummary заменяет несколько срабатываний, пропущенных за время простоя,
+// одним сообщением. atLeast — пропущено не меньше count: перечисление было ограничено.
+func ReminderMissedSummary(text string, count int, atLeast bool, first, last string) string {
+	amount := strconv.Itoa(count)
+	if atLeast {
+		amount = "не меньше " + amount
+	}
+
+	return ReminderPrefix + text + missedSummaryMarker + amount + " — с " + first + " по " + last + "."
+}
+
+// AddPhraseConfirm — разобранное из фразы напоминание перед сохранением; when — первое
+// срабатывание, repeat — описание повтора.
+func AddPhraseConfirm(text, when, repeat string) string {
+	return "Создать напоминание?\n\n📝 " + text + "\n🕐 " + when + "\n🔁 " + repeat
+}
+
+// TimezoneRebased — итог смены часового пояса: сколько напоминаний пересчитано по часам.
+func TimezoneRebased(tz string, n int) string {
+	return TimezoneSet + tz + "\nПересчитано напоминаний: " + strconv.Itoa(n)
+}
+
+// ReminderNag — повтор неподтверждённого напоминания; n — номер повтора из max.
+func ReminderNag(text string, n, max int) string {
+	return "🔁 Напоминание (повтор " + strconv.Itoa(n) + " из " + strconv.Itoa(max) + "): " + text
+}
+
+// ReminderNotice — предупреждение перед срабатыванием; when — «через 3 дня».
+func ReminderNotice(text, when string) string {
+	return "🔔 Напоминание " + when + ": " + text
+}
+
+// AckedLine дописывается к доставке после нажатия «Готово»; by — имя подтвердившего,
+// пустое в личном чате, at — время в поясе чата.
+func AckedLine(by, at string) string {
+	if by == "" {
+		return "\n\n✅ Выполнено " + at
+	}
+
+	return "\n\n✅ Выполнено: " + by + ", " + at
+}
+
+// ReminderTextFrom извлекает текст напоминания из доставленного сообщения в любом
+// из оформлений планировщика. Разовое напоминание удаляется ещё до отправки, и кроме
+// сообщения текст взять неоткуда.
+func ReminderTextFrom(message string) (string, bool) {
+	if rest, ok := strings.CutPrefix(message, lateReminderPrefix); ok {
+		_, text, ok := strings.Cut(rest, "): ")
+		return text, ok && text != ""
+	}
+
+	text, ok := strings.CutPrefix(message, ReminderPrefix)
+	if !ok {
+		return "", false
+	}
+	if i := strings.LastIndex(text, missedSummaryMarker); i >= 0 {
+		text = text[:i]
+	}
+
+	return text, text != ""
+}
@@ -4,10 +4,14 @@ package texts
 
 const (
 	ValidateEnterTime         = "Пожалуйста, введите время в формате 15:00"
+	ValidateEnterTimes        = "Пожалуйста, введите время в формате 15:00, список через запятую или окно 10:00-12:00"
 	ValidateEnterText         = "Пожалуйста, введите текст напоминания"
 	ValidateEnterInterval     = "Пожалуйста, введите интервал в днях (целое число > 0)"
 	ValidateEnterDate         = "Пожалуйста, введите дату старта в формате ДД.ММ.ГГГГ"
 	ValidateEnterMonth        = "Пожалуйста, введите число месяца от 1 до 31"
+	ValidateEnterIntervalTime = "Пожалуйста, введите интервал от 5 минут до 24 часов (например, 30 мин или 2 ч)"
+	ValidateEnterPeriod       = "Пожалуйста, введите число и единицу: недели, месяцы или годы (например, 2 недели)"
+	ValidateEnterWindow       = "Пожалуйста, введите окно в формате 10:00-18:00 в пределах одного дня"
 	ValidateEnterWeekday      = "Пожалуйста, введите день недели (например, понедельник)"
 	ValidateEnterDateDDMM     = "Пожалуйста, введите дату в формате ДД.ММ (например, 13.06)"
 	ValidateEnterDateDDMMYYYY = "Пожалуйста, введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ"
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+)
+
+// monthPrepositional — месяцы в предложном падеже: «в январе».
+var monthPrepositional = [...]string{
+	"январе", "феврале", "марте", "апреле", "мае", "июне",
+	"июле", "августе", "сентябре", "октябре", "ноябре", "декабре",
+}
+
+// formatCron описывает выражение cron словами: «по будням в 09:00 (cron 0 9 * * 1-5)».
+// Выражение, которое коротко не пересказать, показывается как есть.
+func formatCron(expr string) string {
+	raw := "по cron " + expr
+	c, err := scheduling.ParseCron(expr)
+	if err != nil {
+		return raw
+	}
+	days, ok := cronDaysLabel(c)
+	if !ok {
+		return raw
+	}
+	clock, ok := cronClockLabel(c)
+	if !ok {
+		return raw
+	}
+
+	return fmt.Sprintf("%s %s (cron %s)", days, clock, expr)
+}
+
+// cronDaysLabel описывает, в какие дни срабатывает выражение.
+func cronDaysLabel(c *scheduling.Cron) (string, bool) {
+	allMonthDays, allWeekdays := len(c.DaysOfMonth) == 31, len(c.Weekdays) == 7
+	// Со звёздочкой в одном из полей день должен подойти по обоим, иначе — по любому.
+	both := c.AnyDayOfMonth || c.AnyWeekday
+
+	var label string
+	switch {
+	case both && allMonthDays && allWeekdays, !both && (allMonthDays || allWeekdays):
+		label = repeatDaily
+	case both && allMonthDays:
+		label = cronWeekdaysLabel(c.Weekdays)
+	case both && allWeekdays:
+		label = cronMonthDaysLabel(c.DaysOfMonth)
+	case both:
+		return "", false
+	default:
+		label = cronMonthDaysLabel(c.DaysOfMonth) + " и " + cronWeekdaysLabel(c.Weekdays)
+	}
+
+	if len(c.Months) < len(monthPrepositional) {
+		months := make([]string, len(c.Months))
+		for i, m := range c.Months {
+			months[i] = monthPrepositional[m-1]
+		}
+		label += " в " + joinAnd(months)
+	}
+
+	return label, true
+}
+
+func cronWeekdaysLabel(days []int) string {
+	switch {
+	case isWorkweek(days):
+		return "по будням"
+	case slices.Equal(days, []int{0, 6}):
+		return "по выходным"
+	}
+
+	return fmt.Sprintf("по дням недели (%s)", weekdayList(days))
+}
+
+func cronMonthDaysLabel(days []int) string {
+	labels := make([]string, len(days))
+	for i, d := range days {
+		labels[i] = strconv.Itoa(d) + "-го"
+	}
+
+	return joinAnd(labels) + " числа"
+}
+
+// cronClockLabel описывает время срабатывания: несколько времён списком, частые —
+// шагом и окном: «каждые 15 мин с 09:00 до 17:45».
+func cronClockLabel(c *scheduling.Cron) (string, bool) {
+	if len(c.Hours)*len(c.Minutes) <= 4 {
+		var clocks []int
+		for _, h := range c.Hours {
+			for _, m := range c.Minutes {
+				clocks = append(clocks, h*60+m)
+			}
+		}
+
+		return "в " + FormatTimes(clocks), true
+	}
+
+	step, ok := evenStep(c.Minutes)
+	if !ok {
+		return "", false
+	}
+	label := "каждый час"
+	if step < 60 {
+		label = "каждые " + durationLabel(step)
+	}
+	first, last := c.Hours[0], c.Hours[len(c.Hours)-1]
+	if last-first+1 != len(c.Hours) {
+		return "", false
+	}
+	if len(c.Hours) < 24 || c.Minutes[0] != 0 {
+		label += fmt.Sprintf(" с %s до %s", clockLabel(first*60+c.Minutes[0]),
+			clockLabel(last*60+c.Minutes[len(c.Minutes)-1]))
+	}
+
+	return label, true
+}
+
+// evenStep возвращает шаг минут, если они идут через равные промежутки и повторяются
+// каждый час одинаково: 0,15,30,45 — шаг 15, одна минута — шаг 60.
+func evenStep(minutes []int) (int, bool) {
+	step := 60
+	if len(minutes) > 1 {
+		step = minutes[1] - minutes[0]
+	}
+	if 60%step != 0 || len(minutes) != 60/step {
+		return 0, false
+	}
+	for i, m := range minutes {
+		if m != minutes[0]+i*step {
+			return 0, false
+		}
+	}
+
+	return step, true
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// followTriggerLabels — события родителя в родительном падеже: «после подтверждения».
+var followTriggerLabels = map[domain.FollowTrigger]string{
+	domain.FollowOnDelivery: "доставки",
+	domain.FollowOnAck:      "подтверждения",
+}
+
+// FormatFollowUp описывает продолжение цепочки: «через 3 дня после подтверждения №2».
+// parent — номер родителя в списке чата, 0 — родителя уже нет.
+func FormatFollowUp(r *domain.Reminder, parent int) string {
+	when := "сразу"
+	if r.FollowOffset > 0 {
+		when = "через " + LeadLabel(r.FollowOffset)
+	}
+
+	event := "после удалённого напоминания"
+	if parent > 0 {
+		event = fmt.Sprintf("после %s №%d", followTriggerLabels[r.FollowOn], parent)
+	}
+
+	return when + " " + event + formatLeads(r.LeadMinutes) + formatNag(r)
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,8 +23,145 @@ var weekdayNames = [...]string{
 	"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота",
 }
 
-// FormatRepeat форматирует режим повтора напоминания для отображения.
-func FormatRepeat(r *domain.Reminder) string {
+// FormatRepeat форматирует режим повтора напоминания для отображения. Дата окончания
+// серии показывается в часовом поясе loc.
+func FormatRepeat(r *domain.Reminder, loc *time.Location) string {
+	repeat := formatRepeatKind(r)
+	if len(r.Times) > 0 {
+		repeat = fmt.Sprintf("%s в %s", repeat, FormatTimes(r.Times))
+	}
+	if r.HasRandomWindow() {
+		repeat = fmt.Sprintf("%s в случайное время с %s до %s",
+			repeat, clockLabel(r.WindowStart), clockLabel(r.WindowEnd))
+	}
+
+	return repeat + formatWorkdays(r.WorkdayPolicy) + formatEnd(r, loc) + formatLeads(r.LeadMinutes) + formatNag(r)
+}
+
+// formatLeads описывает предупреждения перед срабатыванием: «, заранее за 3 дня и 1 час».
+func formatLeads(leads []int) string {
+	if len(leads) == 0 {
+		return ""
+	}
+	labels := make([]string, len(leads))
+	for i, lead := range leads {
+		labels[i] = LeadLabel(lead)
+	}
+
+	return ", заранее за " + joinAnd(labels)
+}
+
+// LeadLabel записывает отступ предупреждения в самых крупных целых единицах:
+// «3 дня», «2 часа», «90 минут».
+func LeadLabel(minutes int) string {
+	switch {
+	case minutes%domain.MinutesPerDay == 0:
+		days := minutes / domain.MinutesPerDay
+		return fmt.Sprintf("%d %s", days, pluralForm(days, unitDays))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d %s", minutes/60, pluralForm(minutes/60, unitHours))
+	}
+
+	return fmt.Sprintf("%d %s", minutes, pluralForm(minutes, unitMinutes))
+}
+
+// formatNag описывает режим подтверждения: «, до подтверждения: каждые 15 мин, максимум 3 раза».
+func formatNag(r *domain.Reminder) string {
+	if r.NagEveryMinutes == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(", до подтверждения: каждые %s, максимум %d %s",
+		durationLabel(r.NagEveryMinutes), r.NagMax, pluralForm(r.NagMax, unitTimes))
+}
+
+// formatWorkdays описывает режим «только по рабочим дням».
+func formatWorkdays(policy domain.WorkdayPolicy) string {
+	switch policy {
+	case domain.WorkdaySkip:
+		return ", только по рабочим дням"
+	case domain.WorkdayPrevious:
+		return ", с нерабочих дней — на предыдущий рабочий"
+	case domain.WorkdayNext:
+		return ", с нерабочих дней — на следующий рабочий"
+	case domain.WorkdayAny:
+	}
+
+	return ""
+}
+
+// formatEnd описывает условия окончания серии: «, до 07.08.2026, ещё 3 раза».
+func formatEnd(r *domain.Reminder, loc *time.Location) string {
+	var b strings.Builder
+	if !r.EndsAt.IsZero() {
+		if loc == nil {
+			loc = time.UTC
+		}
+		fmt.Fprintf(&b, ", до %s", r.EndsAt.In(loc).Format("02.01.2006"))
+	}
+	if r.RemainingCount > 0 {
+		fmt.Fprintf(&b, ", ещё %d %s", r.RemainingCount, pluralForm(r.RemainingCount, unitTimes))
+	}
+
+	return b.String()
+}
+
+// formatInterval описывает интервальный повтор: «каждые 2 ч с 10:00 до 18:00 по будням».
+func formatInterval(r *domain.Reminder) string {
+	var b strings.Builder
+	b.WriteString("каждые " + durationLabel(r.IntervalMinutes))
+	if r.HasWindow() {
+		fmt.Fprintf(&b, " с %s до %s", clockLabel(r.WindowStart), clockLabel(r.WindowEnd))
+	}
+	switch {
+	case isWorkweek(r.RepeatDays):
+		b.WriteString(" по будням")
+	case len(r.RepeatDays) > 0:
+		fmt.Fprintf(&b, " (%s)", weekdayList(r.RepeatDays))
+	}
+
+	return b.String()
+}
+
+// durationLabel записывает длительность в минутах: «2 ч 30 мин», «45 мин».
+func durationLabel(total int) string {
+	hours, minutes := total/60, total%60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%d ч %d мин", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d ч", hours)
+	}
+
+	return fmt.Sprintf("%d мин", minutes)
+}
+
+// clockLabel переводит минуты от полуночи в ЧЧ:ММ.
+func clockLabel(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// FormatTimes перечисляет времена срабатывания в течение дня: «09:00, 13:00 и 21:00».
+func FormatTimes(times []int) string {
+	clocks := make([]string, len(times))
+	for i, m := range times {
+		clocks[i] = clockLabel(m)
+	}
+
+	return joinAnd(clocks)
+}
+
+// joinAnd перечисляет элементы через запятую, последний — через «и».
+func joinAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " и " + items[len(items)-1]
+}
+
+// formatRepeatKind описывает тип повтора без учёта времён срабатывания.
+func formatRepeatKind(r *domain.Reminder) string {
 	switch r.Repeat {
 	case domain.RepeatNone:
 		return repeatOnce
@@ -32,32 +170,142 @@ func FormatRepeat(r *domain.Reminder) string {
 		return repeatDaily
 
 	case domain.RepeatEveryWeek:
+		weekly := stepLabel(r.RepeatStep(), repeatWeekly, unitWeeks)
 		// Напоминание может повторяться в нескольких днях недели: через Mini App
 		// их выбирают списком, и показать нужно все.
 		if names := weekdayList(r.RepeatDays); names != "" {
-			return fmt.Sprintf("%s (%s)", repeatWeekly, names)
+			return fmt.Sprintf("%s (%s)", weekly, names)
 		}
 
-		return repeatWeekly
+		return weekly
 
 	case domain.RepeatEveryMonth:
+		monthly := stepLabel(r.RepeatStep(), repeatMonthly, unitMonths)
+		if r.MonthOrdinal != 0 {
+			return fmt.Sprintf("%s (%s)", monthly, monthWeekdayLabel(r.MonthOrdinal, r.RepeatDays))
+		}
+		if len(r.RepeatDays) > 0 && r.RepeatDays[0] == domain.LastMonthDay {
+			return fmt.Sprintf("%s (в последний день)", monthly)
+		}
 		if len(r.RepeatDays) > 0 {
-			return fmt.Sprintf("%s (%d-го числа)", repeatMonthly, r.RepeatDays[0])
+			return fmt.Sprintf("%s (%d-го числа)", monthly, r.RepeatDays[0])
 		}
 
-		return repeatMonthly
+		return monthly
 
 	case domain.RepeatEveryYear:
-		return repeatYearly
+		return stepLabel(r.RepeatStep(), repeatYearly, unitYears)
 
 	case domain.RepeatEveryNDays:
 		return fmt.Sprintf("каждые %d дней", r.RepeatEvery)
 
+	case domain.RepeatRRule:
+		return fmt.Sprintf("по правилу %s", r.RRule)
+
+	case domain.RepeatInterval:
+		return formatInterval(r)
+
+	case domain.RepeatCron:
+		return formatCron(r.Cron)
+
+	case domain.RepeatSolar:
+		return formatSolar(r)
+
 	default:
 		return "-"
 	}
 }
 
+// Формы единиц шага для «раз в N …»: после 1, после 2–4 и после 5–20.
+var (
+	unitWeeks  = [3]string{"неделю", "недели", "недель"}
+	unitMonths = [3]string{"месяц", "месяца", "месяцев"}
+	unitYears  = [3]string{"год", "года", "лет"}
+	unitTimes  = [3]string{"раз", "раза", "раз"}
+	// Винительный падеж: «через 1 минуту», «за 3 дня».
+	unitDays    = [3]string{"день", "дня", "дней"}
+	unitHours   = [3]string{"час", "часа", "часов"}
+	unitMinutes = [3]string{"минуту", "минуты", "минут"}
+)
+
+// stepLabel описывает повтор с шагом: «раз в 2 недели», «раз в 5 лет». Шаг 1 —
+// это обычное «еженедельно» и т. п., его передают в single.
+func stepLabel(step int, single string, forms [3]string) string {
+	if step <= 1 {
+		return single
+	}
+
+	return fmt.Sprintf("раз в %d %s", step, pluralForm(step, forms))
+}
+
+// pluralForm выбирает форму существительного после числа n по правилам русского языка.
+func pluralForm(n int, forms [3]string) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return forms[0]
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return forms[1]
+	default:
+		return forms[2]
+	}
+}
+
+// Порядковые числительные в винительном падеже: «во второй вторник», «в последнюю
+// пятницу», «в первое воскресенье». Индекс 0 — для MonthOrdinalLast.
+var (
+	ordinalsMasculine = [...]string{"последний", "первый", "второй", "третий", "четвёртый"}
+	ordinalsFeminine  = [...]string{"последнюю", "первую", "вторую", "третью", "четвёртую"}
+	ordinalsNeuter    = [...]string{"последнее", "первое", "второе", "третье", "четвёртое"}
+)
+
+// weekdayAccusative — дни недели в винительном падеже по индексу time.Weekday.
+var weekdayAccusative = [...]string{
+	"воскресенье", "понедельник", "вторник", "среду", "четверг", "пятницу", "субботу",
+}
+
+// monthWeekdayLabel описывает режим «N-й день недели месяца».
+func monthWeekdayLabel(ordinal int, days []int) string {
+	idx := ordinal
+	if ordinal == domain.MonthOrdinalLast {
+		idx = 0
+	}
+	if idx < 0 || idx >= len(ordinalsMasculine) {
+		return "-"
+	}
+
+	var phrase string
+	switch {
+	case isWorkweek(days):
+		phrase = ordinalsMasculine[idx] + " рабочий день"
+	case len(days) == 1 && days[0] >= 0 && days[0] < len(weekdayAccusative):
+		ordinals := ordinalsMasculine
+		switch time.Weekday(days[0]) {
+		case time.Wednesday, time.Friday, time.Saturday:
+			ordinals = ordinalsFeminine
+		case time.Sunday:
+			ordinals = ordinalsNeuter
+		case time.Monday, time.Tuesday, time.Thursday:
+		}
+		phrase = ordinals[idx] + " " + weekdayAccusative[days[0]]
+	default:
+		phrase = fmt.Sprintf("%s из дней: %s", ordinalsMasculine[idx], weekdayList(days))
+	}
+
+	// «во второй», но «в первый»: иначе получается труднопроизносимое «в вторник».
+	if strings.HasPrefix(phrase, "втор") {
+		return "во " + phrase
+	}
+
+	return "в " + phrase
+}
+
+// isWorkweek сообщает, совпадает ли набор дней с понедельником–пятницей.
+func isWorkweek(days []int) bool {
+	sorted := slices.Sorted(slices.Values(days))
+
+	return slices.Equal(sorted, []int{1, 2, 3, 4, 5})
+}
+
 // FormatStatus форматирует статус напоминания
 func FormatStatus(paused bool) string {
 	if paused {
@@ -73,6 +321,18 @@ func FormatTime(nextTime time.Time, loc *time.Location) string {
 	return nextTime.In(loc).Format("02.01.2006 в 15:04")
 }
 
+// FormatReminderTime показывает ближайшее срабатывание в зоне напоминания, а если она
+// отличается от зоны чата, — ещё и по времени чата.
+func FormatReminderTime(r *domain.Reminder, chat *time.Location) string {
+	loc := r.Location(chat)
+	local := FormatTime(r.NextTime, loc)
+	if loc.String() == chat.String() {
+		return local
+	}
+
+	return fmt.Sprintf("%s (%s), в чате %s", local, loc, FormatTime(r.NextTime, chat))
+}
+
 // weekdayList собирает названия дней недели через запятую.
 func weekdayList(days []int) string {
 	names := make([]string, 0, len(days))
@@ -84,3 +344,16 @@ func weekdayList(days []int) string {
 
 	return strings.Join(names, ", ")
 }
+
+// FormatQuietHours описывает тихие часы чата: «23:00–08:00, без звука».
+func FormatQuietHours(q domain.QuietHours) string {
+	if !q.Enabled() {
+		return "не заданы"
+	}
+	mode := "напоминания откладываются до конца"
+	if q.Mode == domain.QuietSilent {
+		mode = "напоминания приходят без звука"
+	}
+
+	return clockLabel(q.Start) + "–" + clockLabel(q.End) + ", " + mode
+}
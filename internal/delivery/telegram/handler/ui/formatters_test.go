@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatRepeat_EndDateInChatTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	r := &domain.Reminder{
+		Repeat: domain.RepeatEveryDay,
+		EndsAt: time.Date(2026, time.August, 7, 23, 59, 0, 0, loc).UTC(),
+	}
+
+	assert.Equal(t, "ежедневно, до 07.08.2026", FormatRepeat(r, loc))
+}
+
+func TestFormatRepeat_Workdays(t *testing.T) {
+	r := &domain.Reminder{Repeat: domain.RepeatEveryDay, WorkdayPolicy: domain.WorkdaySkip}
+	assert.Equal(t, "ежедневно, только по рабочим дням", FormatRepeat(r, time.UTC))
+
+	r.WorkdayPolicy = domain.WorkdayNext
+	assert.Equal(t, "ежедневно, с нерабочих дней — на следующий рабочий", FormatRepeat(r, time.UTC))
+}
+
+func TestFormatReminderTime(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	r := &domain.Reminder{NextTime: time.Date(2026, time.June, 12, 13, 0, 0, 0, time.UTC)}
+
+	assert.Equal(t, "12.06.2026 в 16:00", FormatReminderTime(r, moscow))
+
+	r.Timezone = "America/New_York"
+	assert.Equal(t, "12.06.2026 в 09:00 (America/New_York), в чате 12.06.2026 в 16:00", FormatReminderTime(r, moscow))
+
+	r.Timezone = "Europe/Moscow"
+	assert.Equal(t, "12.06.2026 в 16:00", FormatReminderTime(r, moscow), "the same zone is not repeated")
+}
+
+func TestFormatRepeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		reminder domain.Reminder
+		want     string
+	}{
+		{
+			name:     "число месяца",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryMonth, RepeatDays: []int{15}},
+			want:     "ежемесячно (15-го числа)",
+		},
+		{
+			name: "случайное время в окне",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatEveryDay, WindowStart: 10 * 60, WindowEnd: 12*60 + 30,
+			},
+			want: "ежедневно в случайное время с 10:00 до 12:30",
+		},
+		{
+			name:     "последний день месяца",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryMonth, RepeatDays: []int{domain.LastMonthDay}},
+			want:     "ежемесячно (в последний день)",
+		},
+		{
+			name:     "второй вторник",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryMonth, MonthOrdinal: 2, RepeatDays: []int{2}},
+			want:     "ежемесячно (во второй вторник)",
+		},
+		{
+			name: "последняя пятница",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatEveryMonth, MonthOrdinal: domain.MonthOrdinalLast, RepeatDays: []int{5},
+			},
+			want: "ежемесячно (в последнюю пятницу)",
+		},
+		{
+			name:     "первое воскресенье",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryMonth, MonthOrdinal: 1, RepeatDays: []int{0}},
+			want:     "ежемесячно (в первое воскресенье)",
+		},
+		{
+			name: "последний рабочий день",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatEveryMonth, MonthOrdinal: domain.MonthOrdinalLast, RepeatDays: []int{1, 2, 3, 4, 5},
+			},
+			want: "ежемесячно (в последний рабочий день)",
+		},
+		{
+			name:     "несколько раз в день",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryDay, Times: []int{9 * 60, 13 * 60, 21*60 + 30}},
+			want:     "ежедневно в 09:00, 13:00 и 21:30",
+		},
+		{
+			name: "по будням дважды в день",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatEveryWeek, RepeatDays: []int{1, 5}, Times: []int{8 * 60, 20 * 60},
+			},
+			want: "еженедельно (понедельник, пятница) в 08:00 и 20:00",
+		},
+		{
+			name: "каждые 2 часа в окне по будням",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatInterval, IntervalMinutes: 120, WindowStart: 10 * 60, WindowEnd: 18 * 60,
+				RepeatDays: []int{1, 2, 3, 4, 5},
+			},
+			want: "каждые 2 ч с 10:00 до 18:00 по будням",
+		},
+		{
+			name:     "каждые полтора часа",
+			reminder: domain.Reminder{Repeat: domain.RepeatInterval, IntervalMinutes: 90},
+			want:     "каждые 1 ч 30 мин",
+		},
+		{
+			name:     "каждые 30 минут по выходным",
+			reminder: domain.Reminder{Repeat: domain.RepeatInterval, IntervalMinutes: 30, RepeatDays: []int{6, 0}},
+			want:     "каждые 30 мин (суббота, воскресенье)",
+		},
+		{
+			name:     "cron по будням",
+			reminder: domain.Reminder{Repeat: domain.RepeatCron, Cron: "0 9 * * 1-5"},
+			want:     "по будням в 09:00 (cron 0 9 * * 1-5)",
+		},
+		{
+			name:     "cron с шагом в рабочие часы",
+			reminder: domain.Reminder{Repeat: domain.RepeatCron, Cron: "*/15 9-17 * * *"},
+			want:     "ежедневно каждые 15 мин с 09:00 до 17:45 (cron */15 9-17 * * *)",
+		},
+		{
+			name:     "cron по числам в отдельные месяцы",
+			reminder: domain.Reminder{Repeat: domain.RepeatCron, Cron: "0 8 1,15 jan,jul *"},
+			want:     "1-го и 15-го числа в январе и июле в 08:00 (cron 0 8 1,15 jan,jul *)",
+		},
+		{
+			name:     "cron по числу или дню недели",
+			reminder: domain.Reminder{Repeat: domain.RepeatCron, Cron: "0 9 1 * mon"},
+			want:     "1-го числа и по дням недели (понедельник) в 09:00 (cron 0 9 1 * mon)",
+		},
+		{
+			name: "за полчаса до заката",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatSolar, SolarEvent: domain.SolarSunset, SolarOffset: -30,
+			},
+			want: "каждый день за 30 мин до заката",
+		},
+		{
+			name: "через час после восхода",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatSolar, SolarEvent: domain.SolarSunrise, SolarOffset: 60,
+			},
+			want: "каждый день через 1 ч после восхода",
+		},
+		{
+			name:     "в вечерние сумерки",
+			reminder: domain.Reminder{Repeat: domain.RepeatSolar, SolarEvent: domain.SolarDusk},
+			want:     "каждый день в вечерние сумерки",
+		},
+		{
+			name:     "cron, который не пересказать",
+			reminder: domain.Reminder{Repeat: domain.RepeatCron, Cron: "0 1,5,9,13,17 * * *"},
+			want:     "по cron 0 1,5,9,13,17 * * *",
+		},
+		{
+			name:     "раз в 2 недели",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryWeek, RepeatEvery: 2, RepeatDays: []int{1, 4}},
+			want:     "раз в 2 недели (понедельник, четверг)",
+		},
+		{
+			name:     "раз в 3 месяца",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryMonth, RepeatEvery: 3, RepeatDays: []int{15}},
+			want:     "раз в 3 месяца (15-го числа)",
+		},
+		{
+			name:     "раз в 5 лет",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryYear, RepeatEvery: 5},
+			want:     "раз в 5 лет",
+		},
+		{
+			name:     "раз в 21 неделю",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryWeek, RepeatEvery: 21},
+			want:     "раз в 21 неделю",
+		},
+		{
+			name: "до даты, ещё 3 раза",
+			reminder: domain.Reminder{
+				Repeat: domain.RepeatEveryDay, Times: []int{9 * 60, 21 * 60},
+				EndsAt: time.Date(2026, time.August, 7, 23, 59, 0, 0, time.UTC), RemainingCount: 3,
+			},
+			want: "ежедневно в 09:00 и 21:00, до 07.08.2026, ещё 3 раза",
+		},
+		{
+			name:     "ещё 5 раз",
+			reminder: domain.Reminder{Repeat: domain.RepeatInterval, IntervalMinutes: 8 * 60, RemainingCount: 5},
+			want:     "каждые 8 ч, ещё 5 раз",
+		},
+		{
+			name:     "до подтверждения",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryDay, NagEveryMinutes: 90, NagMax: 3},
+			want:     "ежедневно, до подтверждения: каждые 1 ч 30 мин, максимум 3 раза",
+		},
+		{
+			name:     "с предупреждениями",
+			reminder: domain.Reminder{Repeat: domain.RepeatEveryDay, LeadMinutes: []int{2 * 24 * 60, 90, 60}},
+			want:     "ежедневно, заранее за 2 дня, 90 минут и 1 час",
+		},
+		{
+			name:     "правило RRULE",
+			reminder: domain.Reminder{Repeat: domain.RepeatRRule, RRule: "FREQ=MONTHLY;BYDAY=2TU"},
+			want:     "по правилу FREQ=MONTHLY;BYDAY=2TU",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatRepeat(&tt.reminder, time.UTC))
+		})
+	}
+}
+
+func TestFormatSunTimes(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	summer := time.Date(2025, time.June, 21, 12, 0, 0, 0, moscow)
+	winter := time.Date(2025, time.December, 21, 12, 0, 0, 0, moscow)
+
+	assert.Equal(t, "восход в 03:45, закат в 21:18",
+		FormatSunTimes(domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}, summer, moscow))
+
+	tromso := domain.GeoPoint{Latitude: 69.6492, Longitude: 18.9553}
+	assert.Equal(t, "полярный день — солнце не заходит", FormatSunTimes(tromso, summer, moscow))
+	assert.Equal(t, "полярная ночь — солнце не всходит", FormatSunTimes(tromso, winter, moscow))
+}
+
+func TestFormatFollowUp(t *testing.T) {
+	r := &domain.Reminder{ParentID: 5, FollowOffset: 3 * domain.MinutesPerDay, FollowOn: domain.FollowOnAck}
+	assert.Equal(t, "через 3 дня после подтверждения №2", FormatFollowUp(r, 2))
+
+	r.FollowOffset, r.FollowOn = 0, domain.FollowOnDelivery
+	assert.Equal(t, "сразу после доставки №2", FormatFollowUp(r, 2))
+
+	r.LeadMinutes = []int{60}
+	assert.Equal(t, "сразу после удалённого напоминания, заранее за 1 час", FormatFollowUp(r, 0))
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/telegramapi"
+)
+
+// historyTextLimit ограничивает текст сообщения в строке истории: нужна подсказка,
+// какое это напоминание, а не всё сообщение целиком.
+const historyTextLimit = 60
+
+var errorClassLabels = map[string]string{
+	telegramapi.ClassFlood:       "лимит запросов Telegram",
+	telegramapi.ClassThrottled:   "очередь рассылки бота",
+	telegramapi.ClassServer:      "сбой на стороне Telegram",
+	telegramapi.ClassNetwork:     "нет связи с Telegram",
+	telegramapi.ClassUnavailable: "бот удалён из чата",
+	telegramapi.ClassMigrated:    "группа стала супергруппой",
+	telegramapi.ClassRejected:    "Telegram отклонил сообщение",
+}
+
+// FormatAttempt описывает попытку доставки одной строкой: итог, время по расписанию
+// в поясе чата, задержку отправки и первую строку сообщения.
+func FormatAttempt(a *domain.DeliveryAttempt, loc *time.Location) string {
+	var b strings.Builder
+	switch a.Outcome {
+	case domain.OutcomeSent:
+		b.WriteString("✅ ")
+	case domain.OutcomeRetry:
+		b.WriteString("🔁 ")
+	default:
+		b.WriteString("❌ ")
+	}
+	b.WriteString(a.ScheduledAt.In(loc).Format("02.01 15:04"))
+
+	switch {
+	case a.Outcome != domain.OutcomeSent:
+		reason := errorClassLabels[a.ErrorClass]
+		if reason == "" {
+			reason = "ошибка отправки"
+		}
+		b.WriteString(", не отправлено: " + reason)
+		if a.Outcome == domain.OutcomeRetry {
+			b.WriteString(", будет повтор")
+		}
+	case a.AttemptedAt.Sub(a.ScheduledAt) >= time.Minute:
+		b.WriteString(", отправлено " + a.AttemptedAt.In(loc).Format("02.01 15:04"))
+	}
+
+	text, _, _ := strings.Cut(a.Text, "\n")
+	if runes := []rune(text); len(runes) > historyTextLimit {
+		text = string(runes[:historyTextLimit-1]) + "…"
+	}
+
+	return b.String() + " — " + text
+}
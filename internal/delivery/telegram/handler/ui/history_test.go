@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/telegramapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAttempt(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	scheduled := time.Date(2026, time.June, 2, 6, 0, 0, 0, time.UTC)
+	attempt := func(outcome domain.DeliveryOutcome, delay time.Duration, class string) *domain.DeliveryAttempt {
+		return &domain.DeliveryAttempt{
+			Text: "⏰ Напоминание: полить цветы", ScheduledAt: scheduled,
+			AttemptedAt: scheduled.Add(delay), Outcome: outcome, ErrorClass: class,
+		}
+	}
+
+	assert.Equal(t, "✅ 02.06 09:00 — ⏰ Напоминание: полить цветы",
+		FormatAttempt(attempt(domain.OutcomeSent, 20*time.Second, ""), loc))
+	assert.Equal(t, "✅ 02.06 09:00, отправлено 02.06 09:05 — ⏰ Напоминание: полить цветы",
+		FormatAttempt(attempt(domain.OutcomeSent, 5*time.Minute, ""), loc))
+	assert.Equal(t, "🔁 02.06 09:00, не отправлено: нет связи с Telegram, будет повтор — ⏰ Напоминание: полить цветы",
+		FormatAttempt(attempt(domain.OutcomeRetry, 0, telegramapi.ClassNetwork), loc))
+	assert.Equal(t, "❌ 02.06 09:00, не отправлено: бот удалён из чата — ⏰ Напоминание: полить цветы",
+		FormatAttempt(attempt(domain.OutcomeFailed, 0, telegramapi.ClassUnavailable), loc))
+
+	long := attempt(domain.OutcomeSent, 0, "")
+	long.Text = strings.Repeat("а", 100) + "\nвторая строка"
+	assert.Equal(t, "✅ 02.06 09:00 — "+strings.Repeat("а", 59)+"…", FormatAttempt(long, loc))
+}
@@ -2,7 +2,12 @@
 
 package ui
 
-import tele "gopkg.in/telebot.v4"
+import (
+	"strconv"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+)
 
 var (
 	AddMenu     = &tele.ReplyMarkup{}
@@ -15,6 +20,8 @@ var (
 	btnMonth    = AddMenu.Data("Раз в месяц", "add_month")
 	btnYear     = AddMenu.Data("Раз в год", "add_year")
 	btnDate     = AddMenu.Data("Выбрать дату", "add_date")
+	btnInterval = AddMenu.Data("Каждые N минут или часов", "add_interval")
+	btnPeriod   = AddMenu.Data("Раз в N недель, месяцев или лет", "add_period")
 
 	// Help menu buttons
 	btnHelpAdd    = MainMenu.Data("➕ Добавить напоминание", "help_add")
@@ -28,6 +35,8 @@ func init() {
 		AddMenu.Row(btnEveryDay, btnWeek),
 		AddMenu.Row(btnMonth, btnYear),
 		AddMenu.Row(btnNDays),
+		AddMenu.Row(btnPeriod),
+		AddMenu.Row(btnInterval),
 		AddMenu.Row(btnDate),
 	)
 
@@ -65,6 +74,73 @@ func WeekdaysMenu() *tele.ReplyMarkup {
 	return m
 }
 
+// MonthModeMenu возвращает inline-меню особых вариантов ежемесячного повтора
+func MonthModeMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	btnLast := m.Data("Последний день", "month_last")
+	btnLastWorkday := m.Data("Последний рабочий день", "month_lastwork")
+	btnNth := m.Data("N-й день недели", "month_nth")
+	m.Inline(
+		m.Row(btnLast, btnLastWorkday),
+		m.Row(btnNth),
+	)
+
+	return m
+}
+
+// MonthOrdinalMenu возвращает inline-меню выбора номера дня недели в месяце
+func MonthOrdinalMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	btnFirst := m.Data("Первый", "month_ord_1")
+	btnSecond := m.Data("Второй", "month_ord_2")
+	btnThird := m.Data("Третий", "month_ord_3")
+	btnFourth := m.Data("Четвёртый", "month_ord_4")
+	btnLast := m.Data("Последний", "month_ord_-1")
+	m.Inline(
+		m.Row(btnFirst, btnSecond, btnThird, btnFourth),
+		m.Row(btnLast),
+	)
+
+	return m
+}
+
+// IntervalWindowMenu возвращает inline-меню окна интервального повтора
+func IntervalWindowMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	m.Inline(m.Row(m.Data("Весь день", "interval_allday")))
+
+	return m
+}
+
+// IntervalDaysMenu возвращает inline-меню дней недели интервального повтора
+func IntervalDaysMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	btnAll := m.Data("Каждый день", "interval_days_all")
+	btnWork := m.Data("По будням", "interval_days_work")
+	m.Inline(m.Row(btnAll, btnWork))
+
+	return m
+}
+
+// AddPhraseMenu возвращает кнопки подтверждения напоминания, разобранного из фразы
+func AddPhraseMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	m.Inline(m.Row(m.Data("✅ Сохранить", "addphrase_save"), m.Data("✖️ Отмена", "addphrase_cancel")))
+
+	return m
+}
+
+// TimezoneRebaseMenu возвращает выбор, как пересчитать напоминания при смене часового пояса
+func TimezoneRebaseMenu() *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	m.Inline(
+		m.Row(m.Data("🕘 Сохранить время по часам", "tzrebase_wall")),
+		m.Row(m.Data("⏱ Сохранить момент срабатывания", "tzrebase_instant")),
+	)
+
+	return m
+}
+
 // Кнопки для обработчиков
 var (
 	BtnToday    = &btnToday
@@ -75,8 +151,41 @@ var (
 	BtnMonth    = &btnMonth
 	BtnYear     = &btnYear
 	BtnDate     = &btnDate
+	BtnInterval = &btnInterval
+	BtnPeriod   = &btnPeriod
 
 	BtnHelpAdd    = &btnHelpAdd
 	BtnHelpList   = &btnHelpList
 	BtnHelpManage = &btnHelpManage
 )
+
+// Часы, на которые откладывают кнопки «Вечером» и «Завтра утром», по поясу чата.
+const (
+	SnoozeEveningHour = 19
+	SnoozeMorningHour = 9
+)
+
+// SnoozeMenu возвращает inline-меню под доставленным напоминанием. «Вечером» предлагается,
+// только пока вечер ещё не наступил; now — в поясе чата.
+func SnoozeMenu(now time.Time) *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	first := m.Row(m.Data("10 мин", "snooze_10m"), m.Data("1 час", "snooze_1h"))
+	if now.Hour() < SnoozeEveningHour {
+		first = append(first, m.Data("Вечером", "snooze_evening"))
+	}
+	m.Inline(
+		first,
+		m.Row(m.Data("Завтра утром", "snooze_morning"), m.Data("Другое время…", "snooze_custom")),
+	)
+
+	return m
+}
+
+// AckMenu возвращает кнопку «Готово» под доставкой, ждущей подтверждения; ackID —
+// идентификатор этой доставки.
+func AckMenu(ackID int64) *tele.ReplyMarkup {
+	m := &tele.ReplyMarkup{}
+	m.Inline(m.Row(m.Data("✅ Готово", "ack_"+strconv.FormatInt(ackID, 10))))
+
+	return m
+}
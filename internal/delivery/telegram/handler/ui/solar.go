@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+)
+
+// solarEventLabels — события в родительном падеже («до заката») и в предложном
+// с предлогом («на закате»).
+var solarEventLabels = map[domain.SolarEvent][2]string{
+	domain.SolarSunrise: {"восхода", "на восходе"},
+	domain.SolarSunset:  {"заката", "на закате"},
+	domain.SolarDawn:    {"утренних сумерек", "в утренние сумерки"},
+	domain.SolarDusk:    {"вечерних сумерек", "в вечерние сумерки"},
+}
+
+// formatSolar описывает солнечный повтор: «каждый день за 30 мин до заката».
+func formatSolar(r *domain.Reminder) string {
+	labels, ok := solarEventLabels[r.SolarEvent]
+	if !ok {
+		return "-"
+	}
+
+	switch {
+	case r.SolarOffset < 0:
+		return fmt.Sprintf("каждый день за %s до %s", durationLabel(-r.SolarOffset), labels[0])
+	case r.SolarOffset > 0:
+		return fmt.Sprintf("каждый день через %s после %s", durationLabel(r.SolarOffset), labels[0])
+	}
+
+	return "каждый день " + labels[1]
+}
+
+// FormatSunTimes описывает восход и закат в сутки now по часам loc: «восход в 03:44,
+// закат в 21:18». В полярный день и полярную ночь пишет, что солнце не заходит
+// или не всходит.
+func FormatSunTimes(geo domain.GeoPoint, now time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	day := now.In(loc)
+
+	sunrise, hasSunrise := scheduling.SolarTime(domain.SolarSunrise, geo, day)
+	sunset, hasSunset := scheduling.SolarTime(domain.SolarSunset, geo, day)
+	switch {
+	case hasSunrise && hasSunset:
+		return fmt.Sprintf("восход в %s, закат в %s", sunrise.In(loc).Format("15:04"), sunset.In(loc).Format("15:04"))
+	case scheduling.PolarDay(geo, day):
+		return "полярный день — солнце не заходит"
+	case !hasSunrise && !hasSunset:
+		return "полярная ночь — солнце не всходит"
+	case hasSunrise:
+		return fmt.Sprintf("восход в %s, заката нет", sunrise.In(loc).Format("15:04"))
+	}
+
+	return fmt.Sprintf("восхода нет, закат в %s", sunset.In(loc).Format("15:04"))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +29,10 @@ const (
 	ReminderTypeMonth    = "month"
 	ReminderTypeYear     = "year"
 	ReminderTypeDate     = "date"
+	ReminderTypeInterval = "interval"
+	// ReminderTypePeriod — промежуточный тип «раз в N недель, месяцев или лет»: после
+	// ввода шага мастер переключается на ReminderTypeWeek, ReminderTypeMonth или ReminderTypeYear.
+	ReminderTypePeriod = "period"
 )
 
 type reminderCreator interface {
@@ -91,12 +97,24 @@ func (w *AddReminderWizard) HandleAddTypeCallback(c tele.Context, typ string) er
 	chatID := c.Chat().ID
 	sess := w.getSession(chatID, userID)
 	sess.Type = typ
+	sess.Every = 0 // шаг задаётся только через «Раз в N недель, месяцев или лет»
 
 	// Удаляем сообщение с кнопками
 	if err := c.Delete(); err != nil {
 		slog.Warn("Failed to delete message with buttons", "error", err)
 	}
 
+	return w.promptFirstStep(c, sess)
+}
+
+// promptFirstStep задаёт первый вопрос мастера для типа sess.Type.
+func (w *AddReminderWizard) promptFirstStep(c tele.Context, sess *session.AddReminderSession) error {
+	typ := sess.Type
+	if typ == ReminderTypePeriod {
+		sess.Step = session.StepInterval
+		w.updateSession(sess)
+		return c.Send(withGroupHint(c, w.BotName, texts.PromptPeriod))
+	}
 	if typ == ReminderTypeWeek {
 		sess.Step = session.StepInterval
 		w.updateSession(sess)
@@ -105,7 +123,12 @@ func (w *AddReminderWizard) HandleAddTypeCallback(c tele.Context, typ string) er
 	if typ == ReminderTypeMonth {
 		sess.Step = session.StepInterval
 		w.updateSession(sess)
-		return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterMonth))
+		return c.Send(withGroupHint(c, w.BotName, texts.PromptMonth), ui.MonthModeMenu())
+	}
+	if typ == ReminderTypeInterval {
+		sess.Step = session.StepInterval
+		w.updateSession(sess)
+		return c.Send(withGroupHint(c, w.BotName, texts.PromptInterval))
 	}
 	if typ == ReminderTypeYear {
 		sess.Step = session.StepInterval
@@ -161,6 +184,8 @@ func (w *AddReminderWizard) HandleAddWizardText(c tele.Context, botName string)
 		return w.handleStepTextWithText(c, sess, text)
 	case session.StepDate:
 		return w.handleStepDateWithText(c, sess, text)
+	case session.StepWindow:
+		return w.handleStepWindowWithText(c, sess, text)
 	}
 
 	slog.Warn("[HandleAddWizardText] unknown step", "step", sess.Step, "type", sess.Type)
@@ -172,7 +197,23 @@ func (w *AddReminderWizard) HandleAddWizardText(c tele.Context, botName string)
 func (w *AddReminderWizard) handleStepTimeWithText(c tele.Context, sess *session.AddReminderSession,
 	text string,
 ) error {
-	if !validator.IsTime(text) {
+	// Окно «10:00-12:00» вместо времени: каждое срабатывание — в случайную минуту внутри него.
+	if start, end, ok := parseWindow(text); ok {
+		sess.WindowStart, sess.WindowEnd = start, end
+		sess.Time = ""
+		sess.Step = session.StepText
+		w.updateSession(sess)
+
+		return c.Send(texts.ValidateEnterText)
+	}
+
+	times, ok := validator.ParseTimes(text)
+	if !ok {
+		return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterTimes))
+	}
+	// Разовое напоминание удаляется после первой отправки, поэтому несколько времён
+	// имеют смысл только для повторяющихся.
+	if len(times) > 1 && !isRepeating(sess.Type) {
 		return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterTime))
 	}
 	sess.Time = text
@@ -200,6 +241,18 @@ func (w *AddReminderWizard) handleStepIntervalWithText(c tele.Context, sess *ses
 
 		return c.Send(withGroupHint(c, w.BotName, texts.PromptEveryDay))
 	case ReminderTypeMonth:
+		if sess.Ordinal != 0 {
+			weekday, ok := parseWeekday(text)
+			if !ok {
+				return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterWeekday))
+			}
+			sess.Weekdays = []int{weekday}
+			sess.Step = session.StepTime
+			w.updateSession(sess)
+			slog.Debug("[handleStepInterval]", "set_month_weekday", weekday, "ordinal", sess.Ordinal)
+
+			return c.Send(withGroupHint(c, w.BotName, texts.PromptEveryDay))
+		}
 		n, ok := validator.ParseDayOfMonth(text)
 		if !ok {
 			return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterMonth))
@@ -220,6 +273,27 @@ func (w *AddReminderWizard) handleStepIntervalWithText(c tele.Context, sess *ses
 		slog.Debug("[handleStepInterval]", "set_year_date", text, "next_step", "StepTime")
 
 		return c.Send(withGroupHint(c, w.BotName, texts.PromptEveryDay))
+	case ReminderTypeInterval:
+		minutes, ok := parseIntervalMinutes(text)
+		if !ok {
+			return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterIntervalTime))
+		}
+		sess.Interval = minutes
+		sess.Step = session.StepWindow
+		w.updateSession(sess)
+		slog.Debug("[handleStepInterval]", "set_interval_minutes", minutes, "next_step", "StepWindow")
+
+		return c.Send(withGroupHint(c, w.BotName, texts.PromptWindow), ui.IntervalWindowMenu())
+	case ReminderTypePeriod:
+		typ, every, ok := parsePeriod(text)
+		if !ok {
+			return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterPeriod))
+		}
+		sess.Type = typ
+		sess.Every = every
+		slog.Debug("[handleStepInterval]", "set_period", every, "type", typ)
+
+		return w.promptFirstStep(c, sess)
 	case ReminderTypeNDays:
 		n, ok := validator.ParseInterval(text)
 		if !ok {
@@ -236,6 +310,21 @@ func (w *AddReminderWizard) handleStepIntervalWithText(c tele.Context, sess *ses
 	return nil
 }
 
+// handleStepWindowWithText принимает окно интервального повтора вида 10:00-18:00.
+func (w *AddReminderWizard) handleStepWindowWithText(c tele.Context, sess *session.AddReminderSession,
+	text string,
+) error {
+	start, end, ok := parseWindow(text)
+	if !ok {
+		return c.Send(withGroupHint(c, w.BotName, texts.ValidateEnterWindow))
+	}
+	sess.WindowStart, sess.WindowEnd = start, end
+	sess.Step = session.StepWeekdays
+	w.updateSession(sess)
+
+	return c.Send(texts.PromptIntervalDays, ui.IntervalDaysMenu())
+}
+
 func (w *AddReminderWizard) handleStepTextWithText(c tele.Context, sess *session.AddReminderSession,
 	text string,
 ) error {
@@ -317,12 +406,32 @@ func (w *AddReminderWizard) createReminderFromSession(sess *session.AddReminderS
 
 	loc := w.ChatUsecase.Location(ctx, sess.ChatID)
 
-	t, err := time.ParseInLocation("15:04", sess.Time, loc)
-	if err != nil {
-		slog.Warn("[createReminderFromSession] failed to parse time", "sess.Time", sess.Time, "err", err)
-		return err
+	if sess.Type == ReminderTypeInterval {
+		rem := convertSessionToReminder(sess, time.Time{})
+		nextTime, err := scheduling.NextInterval(now.In(loc), rem)
+		if err != nil {
+			slog.Warn("[createReminderFromSession] failed to calculate next time", "type", sess.Type, "err", err)
+			return err
+		}
+		rem.NextTime = nextTime.UTC()
+
+		return w.addReminder(ctx, rem)
+	}
+	if sess.WindowEnd != 0 {
+		return w.createWindowReminder(ctx, sess, now, loc)
 	}
 
+	times, ok := validator.ParseTimes(sess.Time)
+	if !ok {
+		slog.Warn("[createReminderFromSession] failed to parse time", "sess.Time", sess.Time)
+		return fmt.Errorf("%w: %q is not a valid HH:MM time", scheduling.ErrInvalidDate, sess.Time)
+	}
+
+	// Ближайший подходящий день ищется по последнему времени: если сегодня прошло
+	// только утреннее срабатывание, дневное и вечернее ещё должны прийти сегодня.
+	last := times[len(times)-1]
+	t := time.Date(0, time.January, 1, last/60, last%60, 0, 0, loc)
+
 	nextTime, err := w.calcNextTime(sess, now.In(loc), t, loc)
 	if err != nil {
 		slog.Warn("[createReminderFromSession] failed to calculate next time", "type", sess.Type, "err", err)
@@ -330,16 +439,47 @@ func (w *AddReminderWizard) createReminderFromSession(sess *session.AddReminderS
 	}
 
 	rem := convertSessionToReminder(sess, nextTime)
+	if len(times) > 1 {
+		rem.Times = times
+		rem.NextTime = scheduling.EarliestTimeInDay(nextTime, now, times).UTC()
+	}
 
-	slog.Debug("[createReminderFromSession] final reminder",
+	return w.addReminder(ctx, rem)
+}
+
+// createWindowReminder создаёт напоминание со случайным временем в окне. День ищется
+// по концу окна: окно, которое ещё не закончилось, успевает сработать сегодня.
+func (w *AddReminderWizard) createWindowReminder(
+	ctx context.Context,
+	sess *session.AddReminderSession,
+	now time.Time,
+	loc *time.Location,
+) error {
+	end := time.Date(0, time.January, 1, sess.WindowEnd/60, sess.WindowEnd%60, 0, 0, loc)
+	day, err := w.calcNextTime(sess, now.In(loc), end, loc)
+	if err != nil {
+		slog.Warn("[createWindowReminder] failed to calculate next time", "type", sess.Type, "err", err)
+		return err
+	}
+
+	rem := convertSessionToReminder(sess, day)
+	rem.WindowStart, rem.WindowEnd = sess.WindowStart, sess.WindowEnd
+	rem.NextTime = scheduling.FirstWindowTime(rem, day, now, loc)
+
+	return w.addReminder(ctx, rem)
+}
+
+// addReminder сохраняет собранное мастером напоминание.
+func (w *AddReminderWizard) addReminder(ctx context.Context, rem *domain.Reminder) error {
+	slog.Debug("[addReminder] final reminder",
 		"chatID", rem.ChatID, "nextTime", rem.NextTime, "repeat", rem.Repeat)
 
 	if err := w.ReminderUsecase.AddReminder(ctx, rem); err != nil {
-		slog.Error("[createReminderFromSession] failed to add reminder", "error", err, "chatID", rem.ChatID)
+		slog.Error("[addReminder] failed to add reminder", "error", err, "chatID", rem.ChatID)
 		return err
 	}
 
-	slog.Info("[createReminderFromSession] reminder created", "reminderID", rem.ID, "chatID", rem.ChatID)
+	slog.Info("[addReminder] reminder created", "reminderID", rem.ID, "chatID", rem.ChatID)
 
 	return nil
 }
@@ -360,6 +500,10 @@ func (w *AddReminderWizard) calcNextTime(
 	case ReminderTypeWeek:
 		return scheduling.NextWeekday(now, t, sess.Interval)
 	case ReminderTypeMonth:
+		if sess.Ordinal != 0 {
+			return scheduling.NextMonthWeekday(now, t, sess.Ordinal, sess.Weekdays)
+		}
+
 		return scheduling.NextMonthDay(now, t, sess.Interval)
 	case ReminderTypeYear:
 		return scheduling.NextYearDay(now, t, sess.Date)
@@ -377,6 +521,78 @@ func (w *AddReminderWizard) calcNextTime(
 	return time.Time{}, fmt.Errorf("unknown reminder type %q", sess.Type)
 }
 
+// parseIntervalMinutes разбирает интервал вида «30 мин», «2 ч», «1 ч 30 мин» или просто
+// число минут. Допустимы значения от domain.MinIntervalMinutes до суток.
+func parseIntervalMinutes(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n >= domain.MinIntervalMinutes && n <= domain.MaxIntervalMinutes
+	}
+
+	m := intervalPattern.FindStringSubmatch(s)
+	if m == nil || (m[1] == "" && m[2] == "") {
+		return 0, false
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	total := hours*60 + minutes
+
+	return total, total >= domain.MinIntervalMinutes && total <= domain.MaxIntervalMinutes
+}
+
+// intervalPattern — часы и минуты интервала: «2 ч», «2 часа», «1ч 30м», «45 минут».
+var intervalPattern = regexp.MustCompile(`^(?:(\d{1,2})\s*ч[а-я]*\.?)?\s*(?:(\d{1,4})\s*м[а-я]*\.?)?$`)
+
+// parsePeriod разбирает шаг вида «2 недели», «3 мес», «2 года» и возвращает тип мастера,
+// к которому он относится.
+func parsePeriod(s string) (string, int, bool) {
+	m := periodPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 || n > domain.MaxRepeatEvery {
+		return "", 0, false
+	}
+
+	switch {
+	case strings.HasPrefix(m[2], "нед"):
+		return ReminderTypeWeek, n, true
+	case strings.HasPrefix(m[2], "мес"):
+		return ReminderTypeMonth, n, true
+	default:
+		return ReminderTypeYear, n, true
+	}
+}
+
+// periodPattern — число и единица шага: «2 недели», «3 мес.», «1 год», «5 лет».
+var periodPattern = regexp.MustCompile(`^(\d{1,3})\s*(нед[а-я]*|мес[а-я]*|год[а-я]*|лет)\.?$`)
+
+// parseWindow разбирает окно ЧЧ:ММ-ЧЧ:ММ в минуты от полуночи.
+func parseWindow(s string) (int, int, bool) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(s, "–", "-"), "-")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || !validator.IsTime(from) || !validator.IsTime(to) {
+		return 0, 0, false
+	}
+
+	start, _ := time.Parse("15:04", from)
+	end, _ := time.Parse("15:04", to)
+	startMin, endMin := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+
+	return startMin, endMin, startMin < endMin
+}
+
+// isRepeating сообщает, повторяется ли напоминание выбранного в мастере типа.
+func isRepeating(typ string) bool {
+	switch typ {
+	case ReminderTypeToday, ReminderTypeTomorrow, ReminderTypeDate:
+		return false
+	}
+
+	return true
+}
+
 func parseWeekday(s string) (int, bool) {
 	const (
 		sunday    = "воскресенье"
@@ -411,7 +627,8 @@ func (w *AddReminderWizard) HandleWeekdayCallback(c tele.Context) error {
 		slog.Info("Session state", "type", sess.Type, "step", sess.Step)
 	}
 
-	if sess == nil || sess.Type != ReminderTypeWeek || sess.Step != session.StepInterval {
+	monthWeekday := sess.Type == ReminderTypeMonth && sess.Ordinal != 0
+	if sess.Type != ReminderTypeWeek && !monthWeekday || sess.Step != session.StepInterval {
 		return c.Send(texts.ErrUnknownDay)
 	}
 
@@ -425,7 +642,11 @@ func (w *AddReminderWizard) HandleWeekdayCallback(c tele.Context) error {
 		return c.Send(texts.ErrUnknownDay)
 	}
 
-	sess.Interval = weekday
+	if monthWeekday {
+		sess.Weekdays = []int{weekday}
+	} else {
+		sess.Interval = weekday
+	}
 	sess.Step = session.StepTime
 	w.updateSession(sess)
 
@@ -437,6 +658,99 @@ func (w *AddReminderWizard) HandleWeekdayCallback(c tele.Context) error {
 	return c.Send(texts.PromptEveryDay)
 }
 
+// HandleMonthModeCallback обрабатывает inline-кнопки особых вариантов ежемесячного повтора:
+// последний день, последний рабочий день и N-й день недели месяца.
+func (w *AddReminderWizard) HandleMonthModeCallback(c tele.Context) error {
+	data := strings.TrimSpace(c.Callback().Data)
+	slog.Info("HandleMonthModeCallback", "callback_data", data)
+
+	sess := w.getSession(c.Chat().ID, c.Sender().ID)
+	if sess.Type != ReminderTypeMonth || sess.Step != session.StepInterval {
+		return c.Send(texts.ErrUnknownMonth)
+	}
+
+	var (
+		prompt = texts.PromptEveryDay
+		markup *tele.ReplyMarkup
+	)
+
+	switch data {
+	case "month_last":
+		sess.Interval = domain.LastMonthDay
+		sess.Step = session.StepTime
+	case "month_lastwork":
+		sess.Ordinal = domain.MonthOrdinalLast
+		sess.Weekdays = []int{1, 2, 3, 4, 5}
+		sess.Step = session.StepTime
+	case "month_nth":
+		prompt, markup = texts.PromptMonthOrdinal, ui.MonthOrdinalMenu()
+	default:
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(data, "month_ord_"))
+		if !strings.HasPrefix(data, "month_ord_") || err != nil ||
+			(ordinal != domain.MonthOrdinalLast && (ordinal < 1 || ordinal > domain.MaxMonthOrdinal)) {
+			return c.Send(texts.ErrUnknownMonth)
+		}
+		sess.Ordinal = ordinal
+		prompt, markup = texts.PromptMonthWeekday, ui.WeekdaysMenu()
+	}
+	w.updateSession(sess)
+
+	// Удаляем сообщение с кнопками
+	if err := c.Delete(); err != nil {
+		slog.Warn("Failed to delete month mode buttons message", "error", err)
+	}
+
+	if markup != nil {
+		return c.Send(prompt, markup)
+	}
+
+	return c.Send(prompt)
+}
+
+// HandleIntervalCallback обрабатывает inline-кнопки интервального повтора: окно «весь день»
+// и выбор дней недели.
+func (w *AddReminderWizard) HandleIntervalCallback(c tele.Context) error {
+	data := strings.TrimSpace(c.Callback().Data)
+	slog.Info("HandleIntervalCallback", "callback_data", data)
+
+	sess := w.getSession(c.Chat().ID, c.Sender().ID)
+	if sess.Type != ReminderTypeInterval {
+		return c.Send(texts.ErrUnknownWindow)
+	}
+
+	var (
+		prompt = texts.ValidateEnterText
+		markup *tele.ReplyMarkup
+	)
+
+	switch {
+	case data == "interval_allday" && sess.Step == session.StepWindow:
+		sess.WindowStart, sess.WindowEnd = 0, 0
+		sess.Step = session.StepWeekdays
+		prompt, markup = texts.PromptIntervalDays, ui.IntervalDaysMenu()
+	case data == "interval_days_all" && sess.Step == session.StepWeekdays:
+		sess.Weekdays = nil
+		sess.Step = session.StepText
+	case data == "interval_days_work" && sess.Step == session.StepWeekdays:
+		sess.Weekdays = []int{1, 2, 3, 4, 5}
+		sess.Step = session.StepText
+	default:
+		return c.Send(texts.ErrUnknownWindow)
+	}
+	w.updateSession(sess)
+
+	// Удаляем сообщение с кнопками
+	if err := c.Delete(); err != nil {
+		slog.Warn("Failed to delete interval buttons message", "error", err)
+	}
+
+	if markup != nil {
+		return c.Send(prompt, markup)
+	}
+
+	return c.Send(prompt)
+}
+
 // convertSessionToReminder собирает доменное напоминание из состояния мастера.
 //
 // sess.Interval переиспользуется под разные смыслы в зависимости от типа: день недели,
@@ -459,14 +773,26 @@ func convertSessionToReminder(sess *session.AddReminderSession, nextTime time.Ti
 	case ReminderTypeWeek:
 		rem.Repeat = domain.RepeatEveryWeek
 		rem.RepeatDays = []int{sess.Interval}
+		rem.RepeatEvery = sess.Every
 	case ReminderTypeMonth:
 		rem.Repeat = domain.RepeatEveryMonth
 		rem.RepeatDays = []int{sess.Interval}
+		rem.RepeatEvery = sess.Every
+		if sess.Ordinal != 0 {
+			rem.MonthOrdinal = sess.Ordinal
+			rem.RepeatDays = slices.Clone(sess.Weekdays)
+		}
 	case ReminderTypeYear:
 		rem.Repeat = domain.RepeatEveryYear
+		rem.RepeatEvery = sess.Every
 	case ReminderTypeNDays:
 		rem.Repeat = domain.RepeatEveryNDays
 		rem.RepeatEvery = sess.Interval
+	case ReminderTypeInterval:
+		rem.Repeat = domain.RepeatInterval
+		rem.IntervalMinutes = sess.Interval
+		rem.WindowStart, rem.WindowEnd = sess.WindowStart, sess.WindowEnd
+		rem.RepeatDays = slices.Clone(sess.Weekdays)
 	case ReminderTypeToday, ReminderTypeTomorrow, ReminderTypeDate:
 		rem.Repeat = domain.RepeatNone
 	}
@@ -8,13 +8,17 @@ import (
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	tele "gopkg.in/telebot.v4"
 )
 
 // Mock usecase implementations
-type mockReminderUsecase struct{}
+type mockReminderUsecase struct {
+	added *domain.Reminder
+}
 
 func (m *mockReminderUsecase) AddReminder(ctx context.Context, r *domain.Reminder) error {
+	m.added = r
 	return nil
 }
 
@@ -248,6 +252,284 @@ func TestAddWizard_MonthFlow(t *testing.T) {
 	assert.Contains(t, c3.sendCalls[len(c3.sendCalls)-1], "Напоминание создано")
 }
 
+// TestAddWizard_SeveralTimesPerDay проверяет ввод нескольких времён через запятую
+func TestAddWizard_SeveralTimesPerDay(t *testing.T) {
+	sessionMgr := session.NewSessionManager()
+	reminders := &mockReminderUsecase{}
+	wizard := NewAddReminderWizard(reminders, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+
+	sessionMgr.Set(&session.AddReminderSession{
+		UserID: 1, ChatID: 1, Type: "everyday", Step: session.StepTime,
+	})
+
+	c := &mockContext{text: "21:00, 09:00, 13:00"}
+	require.NoError(t, wizard.HandleAddWizardText(c, "reminder_bot"))
+	assert.Equal(t, session.StepText, sessionMgr.Get(1, 1).Step)
+
+	c2 := &mockContext{text: "Выпить таблетку"}
+	require.NoError(t, wizard.HandleAddWizardText(c2, "reminder_bot"))
+	assert.Contains(t, c2.sendCalls[len(c2.sendCalls)-1], "Напоминание создано")
+
+	require.NotNil(t, reminders.added)
+	assert.Equal(t, []int{9 * 60, 13 * 60, 21 * 60}, reminders.added.Times)
+	loc := (&mockChatUsecase{}).Location(context.Background(), 1)
+	assert.Contains(t, reminders.added.Times, reminders.added.NextTime.In(loc).Hour()*60)
+
+	t.Run("разовое напоминание принимает одно время", func(t *testing.T) {
+		sessionMgr.Set(&session.AddReminderSession{
+			UserID: 1, ChatID: 1, Type: "today", Step: session.StepTime,
+		})
+		c := &mockContext{text: "09:00, 13:00"}
+		require.NoError(t, wizard.HandleAddWizardText(c, "reminder_bot"))
+		assert.Equal(t, session.StepTime, sessionMgr.Get(1, 1).Step)
+		assert.Contains(t, c.sendCalls[len(c.sendCalls)-1], "формате 15:00")
+	})
+}
+
+// TestAddWizard_RandomWindow проверяет «каждый день когда-нибудь между 10:00 и 12:00».
+func TestAddWizard_RandomWindow(t *testing.T) {
+	sessionMgr := session.NewSessionManager()
+	reminders := &mockReminderUsecase{}
+	wizard := NewAddReminderWizard(reminders, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+
+	sessionMgr.Set(&session.AddReminderSession{
+		UserID: 1, ChatID: 1, Type: "everyday", Step: session.StepTime,
+	})
+
+	c := &mockContext{text: "10:00-12:00"}
+	require.NoError(t, wizard.HandleAddWizardText(c, "reminder_bot"))
+	assert.Equal(t, session.StepText, sessionMgr.Get(1, 1).Step)
+
+	c2 := &mockContext{text: "Записать настроение"}
+	require.NoError(t, wizard.HandleAddWizardText(c2, "reminder_bot"))
+	assert.Contains(t, c2.sendCalls[len(c2.sendCalls)-1], "Напоминание создано")
+
+	require.NotNil(t, reminders.added)
+	assert.True(t, reminders.added.HasRandomWindow())
+	assert.Equal(t, 10*60, reminders.added.WindowStart)
+	assert.Equal(t, 12*60, reminders.added.WindowEnd)
+
+	loc := (&mockChatUsecase{}).Location(context.Background(), 1)
+	local := reminders.added.NextTime.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	assert.GreaterOrEqual(t, minutes, 10*60)
+	assert.LessOrEqual(t, minutes, 12*60)
+	assert.True(t, reminders.added.NextTime.After(time.Now()))
+}
+
+// TestAddWizard_IntervalFlow проверяет сценарий «каждые 2 часа с 10:00 до 18:00 по будням»
+func TestAddWizard_IntervalFlow(t *testing.T) {
+	sessionMgr := session.NewSessionManager()
+	reminders := &mockReminderUsecase{}
+	wizard := NewAddReminderWizard(reminders, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+
+	c := &mockContext{}
+	require.NoError(t, wizard.HandleAddTypeCallback(c, ReminderTypeInterval))
+	assert.Contains(t, c.sendCalls[len(c.sendCalls)-1], "Как часто")
+
+	c2 := &mockContext{text: "2 ч"}
+	require.NoError(t, wizard.HandleAddWizardText(c2, "reminder_bot"))
+	sess := sessionMgr.Get(1, 1)
+	assert.Equal(t, 120, sess.Interval)
+	assert.Equal(t, session.StepWindow, sess.Step)
+
+	c3 := &mockContext{text: "10:00-18:00"}
+	require.NoError(t, wizard.HandleAddWizardText(c3, "reminder_bot"))
+	sess = sessionMgr.Get(1, 1)
+	assert.Equal(t, 10*60, sess.WindowStart)
+	assert.Equal(t, 18*60, sess.WindowEnd)
+	assert.Equal(t, session.StepWeekdays, sess.Step)
+
+	c4 := &mockContext{callback: &tele.Callback{Data: "interval_days_work"}}
+	require.NoError(t, wizard.HandleIntervalCallback(c4))
+	assert.Equal(t, session.StepText, sessionMgr.Get(1, 1).Step)
+
+	c5 := &mockContext{text: "Размяться"}
+	require.NoError(t, wizard.HandleAddWizardText(c5, "reminder_bot"))
+	assert.Contains(t, c5.sendCalls[len(c5.sendCalls)-1], "Напоминание создано")
+
+	require.NotNil(t, reminders.added)
+	assert.Equal(t, domain.RepeatInterval, reminders.added.Repeat)
+	assert.Equal(t, 120, reminders.added.IntervalMinutes)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, reminders.added.RepeatDays)
+	assert.True(t, reminders.added.NextTime.After(time.Now()))
+}
+
+func TestAddWizard_PeriodFlow(t *testing.T) {
+	sessionMgr := session.NewSessionManager()
+	reminders := &mockReminderUsecase{}
+	wizard := NewAddReminderWizard(reminders, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+
+	c := &mockContext{}
+	require.NoError(t, wizard.HandleAddTypeCallback(c, ReminderTypePeriod))
+	assert.Contains(t, c.sendCalls[len(c.sendCalls)-1], "Как часто повторять")
+
+	c2 := &mockContext{text: "2 недели"}
+	require.NoError(t, wizard.HandleAddWizardText(c2, "reminder_bot"))
+	sess := sessionMgr.Get(1, 1)
+	assert.Equal(t, ReminderTypeWeek, sess.Type)
+	assert.Equal(t, 2, sess.Every)
+	assert.Equal(t, session.StepInterval, sess.Step)
+	assert.Contains(t, c2.sendCalls[len(c2.sendCalls)-1], "день недели")
+
+	for _, text := range []string{"четверг", "10:00", "Ревью спринта"} {
+		require.NoError(t, wizard.HandleAddWizardText(&mockContext{text: text}, "reminder_bot"))
+	}
+
+	require.NotNil(t, reminders.added)
+	assert.Equal(t, domain.RepeatEveryWeek, reminders.added.Repeat)
+	assert.Equal(t, 2, reminders.added.RepeatEvery)
+	assert.Equal(t, []int{4}, reminders.added.RepeatDays)
+	assert.Equal(t, time.Thursday, reminders.added.NextTime.In(time.FixedZone("MSK", 3*60*60)).Weekday())
+}
+
+// TestAddWizard_ParsePeriod проверяет разбор шага «раз в N недель/месяцев/лет»
+func TestAddWizard_ParsePeriod(t *testing.T) {
+	tests := []struct {
+		input string
+		typ   string
+		every int
+		ok    bool
+	}{
+		{"2 недели", ReminderTypeWeek, 2, true},
+		{"1 неделю", ReminderTypeWeek, 1, true},
+		{"3 месяца", ReminderTypeMonth, 3, true},
+		{"6 мес.", ReminderTypeMonth, 6, true},
+		{"2 года", ReminderTypeYear, 2, true},
+		{"5 лет", ReminderTypeYear, 5, true},
+		{"0 недель", "", 0, false},
+		{"2 дня", "", 0, false},
+		{"недели", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			typ, every, ok := parsePeriod(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.typ, typ)
+				assert.Equal(t, tt.every, every)
+			}
+		})
+	}
+}
+
+// TestAddWizard_ParseIntervalMinutes проверяет разбор интервала
+func TestAddWizard_ParseIntervalMinutes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		ok       bool
+	}{
+		{"30", 30, true},
+		{"30 мин", 30, true},
+		{"45 минут", 45, true},
+		{"2 ч", 120, true},
+		{"2 часа", 120, true},
+		{"1ч 30м", 90, true},
+		{"1 ч 30 мин", 90, true},
+		{"24 ч", 24 * 60, true},
+		{"1 мин", 1, false},
+		{"25 ч", 25 * 60, false},
+		{"", 0, false},
+		{"часто", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, ok := parseIntervalMinutes(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestAddWizard_ParseWindow проверяет разбор окна интервального повтора
+func TestAddWizard_ParseWindow(t *testing.T) {
+	start, end, ok := parseWindow("10:00 - 18:30")
+	assert.True(t, ok)
+	assert.Equal(t, 10*60, start)
+	assert.Equal(t, 18*60+30, end)
+
+	for _, s := range []string{"18:00-10:00", "10:00", "10-18", "10:00-24:00"} {
+		_, _, ok := parseWindow(s)
+		assert.False(t, ok, "expected %q to be invalid", s)
+	}
+}
+
+// TestAddWizard_MonthModeCallback проверяет особые варианты ежемесячного повтора
+func TestAddWizard_MonthModeCallback(t *testing.T) {
+	sessionMgr := session.NewSessionManager()
+	wizard := NewAddReminderWizard(&mockReminderUsecase{}, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+
+	monthSession := func() {
+		sessionMgr.Set(&session.AddReminderSession{
+			UserID: 1, ChatID: 1, Type: "month", Step: session.StepInterval,
+		})
+	}
+
+	t.Run("последний день", func(t *testing.T) {
+		monthSession()
+		c := &mockContext{callback: &tele.Callback{Data: "month_last"}}
+		require.NoError(t, wizard.HandleMonthModeCallback(c))
+
+		sess := sessionMgr.Get(1, 1)
+		assert.Equal(t, domain.LastMonthDay, sess.Interval)
+		assert.Equal(t, session.StepTime, sess.Step)
+
+		rem := convertSessionToReminder(sess, time.Now())
+		assert.Equal(t, []int{domain.LastMonthDay}, rem.RepeatDays)
+		assert.Zero(t, rem.MonthOrdinal)
+	})
+
+	t.Run("последний рабочий день", func(t *testing.T) {
+		monthSession()
+		c := &mockContext{callback: &tele.Callback{Data: "month_lastwork"}}
+		require.NoError(t, wizard.HandleMonthModeCallback(c))
+
+		sess := sessionMgr.Get(1, 1)
+		assert.Equal(t, session.StepTime, sess.Step)
+
+		rem := convertSessionToReminder(sess, time.Now())
+		assert.Equal(t, domain.MonthOrdinalLast, rem.MonthOrdinal)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, rem.RepeatDays)
+	})
+
+	t.Run("второй вторник", func(t *testing.T) {
+		monthSession()
+		c := &mockContext{callback: &tele.Callback{Data: "month_nth"}}
+		require.NoError(t, wizard.HandleMonthModeCallback(c))
+		assert.Contains(t, c.sendCalls[len(c.sendCalls)-1], "по счёту")
+
+		c2 := &mockContext{callback: &tele.Callback{Data: "month_ord_2"}}
+		require.NoError(t, wizard.HandleMonthModeCallback(c2))
+		assert.Equal(t, 2, sessionMgr.Get(1, 1).Ordinal)
+
+		c3 := &mockContext{callback: &tele.Callback{Data: "weekday_2"}}
+		require.NoError(t, wizard.HandleWeekdayCallback(c3))
+
+		sess := sessionMgr.Get(1, 1)
+		assert.Equal(t, []int{2}, sess.Weekdays)
+		assert.Equal(t, session.StepTime, sess.Step)
+
+		c4 := &mockContext{text: "10:00"}
+		require.NoError(t, wizard.HandleAddWizardText(c4, "reminder_bot"))
+		c5 := &mockContext{text: "Планёрка"}
+		require.NoError(t, wizard.HandleAddWizardText(c5, "reminder_bot"))
+		assert.Contains(t, c5.sendCalls[len(c5.sendCalls)-1], "Напоминание создано")
+	})
+
+	t.Run("неизвестный номер", func(t *testing.T) {
+		monthSession()
+		c := &mockContext{callback: &tele.Callback{Data: "month_ord_5"}}
+		require.NoError(t, wizard.HandleMonthModeCallback(c))
+		assert.Contains(t, c.sendCalls[len(c.sendCalls)-1], "неизвестный вариант")
+		assert.Zero(t, sessionMgr.Get(1, 1).Ordinal)
+	})
+}
+
 // TestAddWizard_YearFlow проверяет сценарий добавления ежегодного напоминания
 func TestAddWizard_YearFlow(t *testing.T) {
 	sessionMgr := session.NewSessionManager()
@@ -438,6 +720,7 @@ func TestAddWizard_HandleAddTypeCallback(t *testing.T) {
 		{"month", "month", "число месяца"},
 		{"year", "year", "дату в формате"},
 		{"date", "date", "дату и время в формате"},
+		{"period", "period", "Как часто повторять"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,154 @@
+package wizards
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+	"github.com/8thgencore/dory-reminder-bot/pkg/validator"
+	tele "gopkg.in/telebot.v4"
+)
+
+// snoozeCustom — кнопка «Другое время…»: время спрашивается отдельным сообщением.
+const snoozeCustom = "custom"
+
+// SnoozeWizard откладывает доставленное напоминание кнопками под ним.
+//
+// Отложенное срабатывание — отдельное разовое напоминание с тем же текстом: NextTime
+// повторяющейся серии оно не трогает, а разового к моменту нажатия уже нет — планировщик
+// удаляет его до отправки.
+type SnoozeWizard struct {
+	ReminderUsecase reminderCreator
+	SessionManager  *session.Manager
+	ChatUsecase     chatLocationProvider
+	BotName         string
+	nowFunc         func() time.Time
+}
+
+// NewSnoozeWizard создает новый экземпляр мастера отложенных напоминаний.
+func NewSnoozeWizard(
+	reminderUc reminderCreator,
+	sessionMgr *session.Manager,
+	chatUc chatLocationProvider,
+	botName string,
+) *SnoozeWizard {
+	return &SnoozeWizard{
+		ReminderUsecase: reminderUc,
+		SessionManager:  sessionMgr,
+		ChatUsecase:     chatUc,
+		BotName:         botName,
+		nowFunc:         time.Now,
+	}
+}
+
+// HandleSnoozeCallback обрабатывает кнопки ui.SnoozeMenu.
+func (sw *SnoozeWizard) HandleSnoozeCallback(c tele.Context) error {
+	preset := strings.TrimPrefix(strings.TrimSpace(c.Callback().Data), "snooze_")
+
+	text, ok := "", c.Message() != nil
+	if ok {
+		text, ok = texts.ReminderTextFrom(c.Message().Text)
+	}
+	if !ok {
+		slog.Warn("Failed to recover reminder text for snooze", "chat_id", c.Chat().ID)
+		return c.Send(texts.ErrSnooze)
+	}
+
+	if preset == snoozeCustom {
+		sw.SessionManager.Set(&session.AddReminderSession{
+			UserID: c.Sender().ID,
+			ChatID: c.Chat().ID,
+			Step:   session.StepSnooze,
+			Text:   text,
+		})
+
+		return c.Send(withGroupHint(c, sw.BotName, texts.SnoozePrompt))
+	}
+
+	loc := sw.ChatUsecase.Location(context.Background(), c.Chat().ID)
+	at, ok := snoozePresetTime(preset, sw.nowFunc().In(loc))
+	if !ok {
+		return c.Send(texts.ErrSnooze)
+	}
+
+	return sw.snooze(c, text, at, loc)
+}
+
+// HandleSnoozeText принимает своё время после кнопки «Другое время…».
+func (sw *SnoozeWizard) HandleSnoozeText(c tele.Context, botName string) error {
+	sess := sw.SessionManager.Get(c.Chat().ID, c.Sender().ID)
+	if sess == nil || sess.Step != session.StepSnooze {
+		return nil
+	}
+
+	input := strings.TrimSpace(strings.ReplaceAll(c.Text(), "@"+botName, ""))
+	loc := sw.ChatUsecase.Location(context.Background(), c.Chat().ID)
+	at, ok := snoozeInputTime(input, sw.nowFunc().In(loc))
+	if !ok {
+		return c.Send(withGroupHint(c, sw.BotName, texts.SnoozePrompt))
+	}
+
+	sw.SessionManager.Delete(sess.ChatID, sess.UserID)
+
+	return sw.snooze(c, sess.Text, at, loc)
+}
+
+// snooze создаёт разовое напоминание с текстом text на время at.
+func (sw *SnoozeWizard) snooze(c tele.Context, text string, at time.Time, loc *time.Location) error {
+	now := sw.nowFunc().UTC()
+	rem := &domain.Reminder{
+		ChatID:    c.Chat().ID,
+		Text:      text,
+		NextTime:  at.UTC(),
+		Repeat:    domain.RepeatNone,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := sw.ReminderUsecase.AddReminder(context.Background(), rem); err != nil {
+		slog.Error("Failed to snooze reminder", "chat_id", rem.ChatID, "error", err)
+		return c.Send(texts.ErrSnooze)
+	}
+	slog.Info("Reminder snoozed", "chat_id", rem.ChatID, "reminder_id", rem.ID, "until", rem.NextTime)
+
+	return c.Send(texts.SnoozedUntil + ui.FormatTime(rem.NextTime, loc))
+}
+
+// snoozePresetTime вычисляет время для кнопки отложенного напоминания; now — в поясе чата.
+func snoozePresetTime(preset string, now time.Time) (time.Time, bool) {
+	evening := time.Date(0, time.January, 1, ui.SnoozeEveningHour, 0, 0, 0, time.UTC)
+	morning := time.Date(0, time.January, 1, ui.SnoozeMorningHour, 0, 0, 0, time.UTC)
+
+	switch preset {
+	case "10m":
+		return now.Add(10 * time.Minute), true
+	case "1h":
+		return now.Add(time.Hour), true
+	case "evening":
+		// Кнопку могли нажать уже после наступления вечера — тогда ближайший вечер завтра.
+		return scheduling.NextToday(now, evening), true
+	case "morning":
+		return scheduling.NextTomorrow(now, morning), true
+	}
+
+	return time.Time{}, false
+}
+
+// snoozeInputTime разбирает своё время: через сколько («30 мин», «2 ч») или во сколько
+// («18:30» — ближайшее такое время); now — в поясе чата.
+func snoozeInputTime(input string, now time.Time) (time.Time, bool) {
+	if validator.IsTime(input) {
+		t, _ := time.Parse("15:04", input)
+		return scheduling.NextToday(now, t), true
+	}
+	if minutes, ok := parseIntervalMinutes(input); ok {
+		return now.Add(time.Duration(minutes) * time.Minute), true
+	}
+
+	return time.Time{}, false
+}
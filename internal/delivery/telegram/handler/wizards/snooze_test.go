@@ -0,0 +1,110 @@
+package wizards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+func TestSnoozeWizard_Presets(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	now := time.Date(2026, time.March, 10, 20, 15, 0, 0, moscow)
+
+	tests := []struct {
+		preset  string
+		message string
+		want    time.Time
+	}{
+		{"10m", texts.ReminderPrefix + "выпить воды", now.Add(10 * time.Minute)},
+		{"1h", texts.ReminderLate("выпить воды", "10.03.2026 в 18:00"), now.Add(time.Hour)},
+		{
+			"evening",
+			texts.ReminderMissedSummary("выпить воды", 3, false, "10.03.2026 в 18:00", "10.03.2026 в 20:00"),
+			time.Date(2026, time.March, 11, 19, 0, 0, 0, moscow),
+		},
+		{"morning", texts.ReminderPrefix + "выпить воды", time.Date(2026, time.March, 11, 9, 0, 0, 0, moscow)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			reminders := &mockReminderUsecase{}
+			wizard := NewSnoozeWizard(reminders, session.NewSessionManager(), &mockChatUsecase{}, "reminder_bot")
+			wizard.nowFunc = func() time.Time { return now }
+			ctx := &mockContext{
+				callback: &tele.Callback{Data: "\fsnooze_" + tt.preset},
+				message:  &tele.Message{Text: tt.message},
+			}
+
+			require.NoError(t, wizard.HandleSnoozeCallback(ctx))
+			require.NotNil(t, reminders.added)
+			assert.Equal(t, "выпить воды", reminders.added.Text)
+			assert.Equal(t, domain.RepeatNone, reminders.added.Repeat)
+			assert.Equal(t, tt.want.UTC(), reminders.added.NextTime)
+			require.Len(t, ctx.sendCalls, 1)
+			assert.Contains(t, ctx.sendCalls[0], texts.SnoozedUntil)
+		})
+	}
+}
+
+func TestSnoozeWizard_CustomTime(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	now := time.Date(2026, time.March, 10, 20, 15, 0, 0, moscow)
+
+	reminders := &mockReminderUsecase{}
+	sessionMgr := session.NewSessionManager()
+	wizard := NewSnoozeWizard(reminders, sessionMgr, &mockChatUsecase{}, "reminder_bot")
+	wizard.nowFunc = func() time.Time { return now }
+
+	ctx := &mockContext{
+		callback: &tele.Callback{Data: "\fsnooze_custom"},
+		message:  &tele.Message{Text: texts.ReminderPrefix + "позвонить маме"},
+	}
+	require.NoError(t, wizard.HandleSnoozeCallback(ctx))
+	assert.Nil(t, reminders.added)
+	assert.Contains(t, ctx.sendCalls[0], texts.SnoozePrompt)
+	require.NotNil(t, sessionMgr.Get(1, 1))
+	assert.Equal(t, session.StepSnooze, sessionMgr.Get(1, 1).Step)
+
+	ctx = &mockContext{text: "завтра"}
+	require.NoError(t, wizard.HandleSnoozeText(ctx, "reminder_bot"))
+	assert.Nil(t, reminders.added, "unparsable input must ask again")
+
+	ctx = &mockContext{text: "08:30 @reminder_bot"}
+	require.NoError(t, wizard.HandleSnoozeText(ctx, "reminder_bot"))
+	require.NotNil(t, reminders.added)
+	assert.Equal(t, "позвонить маме", reminders.added.Text)
+	assert.Equal(t, time.Date(2026, time.March, 11, 8, 30, 0, 0, moscow).UTC(), reminders.added.NextTime)
+	assert.Nil(t, sessionMgr.Get(1, 1))
+}
+
+func TestSnoozeInputTime(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	got, ok := snoozeInputTime("45 мин", now)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(45*time.Minute), got)
+
+	got, ok = snoozeInputTime("18:30", now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, time.March, 10, 18, 30, 0, 0, time.UTC), got)
+
+	_, ok = snoozeInputTime("вечером", now)
+	assert.False(t, ok)
+}
+
+func TestReminderTextFrom(t *testing.T) {
+	text, ok := texts.ReminderTextFrom(texts.ReminderLate("созвон (важно): в 10", "10.03.2026 в 09:00"))
+	require.True(t, ok)
+	assert.Equal(t, "созвон (важно): в 10", text)
+
+	_, ok = texts.ReminderTextFrom("Напоминание создано!")
+	assert.False(t, ok)
+}
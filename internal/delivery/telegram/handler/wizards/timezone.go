@@ -2,18 +2,21 @@ package wizards
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strings"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/session"
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/8thgencore/dory-reminder-bot/pkg/timezone"
 	tele "gopkg.in/telebot.v4"
 )
 
 type timezoneUsecase interface {
 	HasTimezone(ctx context.Context, chatID int64) (bool, error)
-	SetTimezone(ctx context.Context, chatID int64, timezone string) error
+	SetTimezone(ctx context.Context, chatID int64, timezone string, mode domain.TimezoneRebase) (int, error)
 }
 
 // TimezoneWizard обрабатывает мастер настройки часового пояса
@@ -71,8 +74,19 @@ func (tw *TimezoneWizard) HandleTimezoneText(c tele.Context, botName string) err
 		hadTimezone = false // считаем что таймзоны не было
 	}
 
-	err = tw.ChatUsecase.SetTimezone(context.Background(), chatID, tz)
-	if err != nil {
+	// При смене пояса спрашиваем, как пересчитать уже созданные напоминания
+	if hadTimezone {
+		tw.SessionManager.Set(&session.AddReminderSession{
+			UserID:   userID,
+			ChatID:   chatID,
+			Step:     session.StepTimezoneRebase,
+			Timezone: tz,
+		})
+
+		return c.Send(texts.TimezoneRebasePrompt, ui.TimezoneRebaseMenu())
+	}
+
+	if _, err := tw.ChatUsecase.SetTimezone(context.Background(), chatID, tz, domain.RebaseWallClock); err != nil {
 		slog.Error("Failed to set custom timezone", "user_id", userID, "chat_id", chatID, "timezone", tz, "error", err)
 		return c.Send(texts.ErrSetTimezone)
 	}
@@ -81,13 +95,41 @@ func (tw *TimezoneWizard) HandleTimezoneText(c tele.Context, botName string) err
 
 	slog.Info("Custom timezone set", "chat_id", chatID, "timezone", tz)
 
-	// Показываем сообщение об успешной установке
-	successMsg := texts.TimezoneSet + tz
-
 	// Приветственное сообщение показываем только при первой установке
-	if !hadTimezone {
-		return c.Send(successMsg+"\n\n"+texts.HelpMainMenu, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, tw.GetMainMenu())
+	return c.Send(texts.TimezoneSet+tz+"\n\n"+texts.HelpMainMenu,
+		&tele.SendOptions{ParseMode: tele.ModeMarkdown}, tw.GetMainMenu())
+}
+
+// HandleTimezoneRebaseCallback меняет часовой пояс чата, пересчитывая напоминания
+// выбранным способом. Кнопки отвечают только автору: новый пояс хранится в его сессии.
+func (tw *TimezoneWizard) HandleTimezoneRebaseCallback(c tele.Context) error {
+	chatID, userID := c.Chat().ID, c.Sender().ID
+	sess := tw.SessionManager.Get(chatID, userID)
+	if sess == nil || sess.Step != session.StepTimezoneRebase || sess.Timezone == "" {
+		return c.Send(texts.TimezoneRebaseExpired)
+	}
+	tw.SessionManager.Delete(chatID, userID)
+
+	// Удаляем сообщение с кнопками
+	if err := c.Delete(); err != nil {
+		slog.Warn("Failed to delete timezone rebase message", "error", err)
+	}
+
+	mode := domain.RebaseWallClock
+	if strings.TrimSpace(c.Callback().Data) == "tzrebase_instant" {
+		mode = domain.RebaseInstant
+	}
+
+	rebased, err := tw.ChatUsecase.SetTimezone(context.Background(), chatID, sess.Timezone, mode)
+	if err != nil {
+		slog.Error("Failed to set custom timezone", "user_id", userID, "chat_id", chatID,
+			"timezone", sess.Timezone, "error", err)
+		if errors.Is(err, domain.ErrInstantRebase) {
+			return c.Send(texts.ErrRebaseInstant)
+		}
+		return c.Send(texts.ErrSetTimezone)
 	}
+	slog.Info("Custom timezone set", "chat_id", chatID, "timezone", sess.Timezone, "rebased", rebased)
 
-	return c.Send(successMsg)
+	return c.Send(texts.TimezoneRebased(sess.Timezone, rebased))
 }
@@ -2,11 +2,14 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/8thgencore/dory-reminder-bot/internal/config"
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/texts"
+	"github.com/8thgencore/dory-reminder-bot/internal/delivery/telegram/handler/ui"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/telegramapi"
 	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
@@ -14,13 +17,24 @@ import (
 )
 
 const (
-	// tickInterval — как часто проверяются наступившие напоминания.
-	tickInterval = 30 * time.Second
+	// resyncInterval — как часто ближайшие срабатывания сверяются с базой. Обычно о них
+	// сообщает ReminderUsecase, сверка страхует от правок в обход него.
+	resyncInterval = 5 * time.Minute
+	// resyncLimit — сколько ближайших пробуждений читается из базы за одну сверку.
+	resyncLimit = 500
+	// retryDelay — через сколько повторяется работа, сорванная ошибкой базы.
+	retryDelay = 30 * time.Second
 	// sendConcurrency ограничивает число одновременных отправок: один недоступный чат
 	// не должен задерживать всю пачку на таймаут HTTP-клиента.
 	sendConcurrency = 8
-	// batchTimeout ограничивает обработку одной пачки, чтобы тики не наслаивались.
+	// batchTimeout ограничивает обработку одной пачки: пока она идёт, планировщик не видит
+	// новых срабатываний.
 	batchTimeout = 25 * time.Second
+	// maxMissedInSummary ограничивает перечисление пропущенных срабатываний для сводки:
+	// интервальное напоминание за сутки простоя набирает их сотни.
+	maxMissedInSummary = 1000
+	// pruneInterval — как часто из истории доставок удаляются записи старше срока хранения.
+	pruneInterval = time.Hour
 )
 
 // sender — часть API бота, нужная планировщику. Интерфейс позволяет тестировать доставку
@@ -31,138 +45,763 @@ type sender interface {
 
 type reminderScheduler interface {
 	ListDue(ctx context.Context, now time.Time) ([]*domain.Reminder, error)
+	ListWakeups(ctx context.Context, limit int) ([]domain.Wakeup, error)
 	EditReminder(ctx context.Context, reminder *domain.Reminder) error
-	DeleteReminder(ctx context.Context, id int64) error
+	AdvanceReminder(ctx context.Context, reminder *domain.Reminder, d *domain.Delivery) error
+	FinishReminder(ctx context.Context, id int64, d *domain.Delivery) error
+	ArmFollowUps(ctx context.Context, parentID int64, trigger domain.FollowTrigger, at time.Time) (int, error)
 	PauseReminder(ctx context.Context, id int64) error
+	ListExceptions(ctx context.Context, reminderID int64) ([]domain.OccurrenceException, error)
+	UpdateDelivery(ctx context.Context, d *domain.Delivery) error
+	ListPendingDeliveries(ctx context.Context, now time.Time) ([]*domain.Delivery, error)
+	CreateAck(ctx context.Context, a *domain.Acknowledgement) error
+	UpdateAck(ctx context.Context, a *domain.Acknowledgement) error
+	GetAck(ctx context.Context, id, chatID int64) (*domain.Acknowledgement, error)
+	ListDueAcks(ctx context.Context, now time.Time) ([]*domain.Acknowledgement, error)
+	RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt) error
+	PruneHistory(ctx context.Context, before time.Time) (int64, error)
 }
 
 type schedulerChats interface {
 	Location(ctx context.Context, chatID int64) *time.Location
+	CatchUp(ctx context.Context, chatID int64) domain.CatchUp
+	QuietHours(ctx context.Context, chatID int64) domain.QuietHours
+	Calendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error)
+	Geo(ctx context.Context, chatID int64) (*domain.GeoPoint, error)
 	SetAvailable(ctx context.Context, chatID int64, available bool) error
 }
 
-// Scheduler рассылает наступившие напоминания и переносит их на следующий раз.
+// Scheduler переносит наступившие напоминания на следующий раз, ставит их доставку
+// в очередь и рассылает её, повторяя при временных ошибках Telegram.
+//
+// Базу планировщик не опрашивает: ближайшие срабатывания, повторы и попытки доставки
+// он держит в куче в памяти и спит до первого из них. Об изменениях расписания сообщает
+// ReminderUsecase через WakeAt, а раз в resyncInterval куча сверяется с базой.
 type Scheduler struct {
 	bot     sender
 	uc      reminderScheduler
 	chatUc  schedulerChats
 	nowFunc func() time.Time
+	// retryDeadline — сколько после постановки в очередь повторяются попытки доставки.
+	retryDeadline time.Duration
+	// historyRetention — срок хранения истории доставок; prunedAt — когда её чистили.
+	historyRetention time.Duration
+	prunedAt         time.Time
+	// wakeups — когда проснуться; wake будит Run, чтобы тот пересчитал таймер.
+	wakeups *wakeQueue
+	wake    chan struct{}
 }
 
 // NewScheduler создает планировщик напоминаний.
-func NewScheduler(bot sender, uc reminderScheduler, chatUc schedulerChats) *Scheduler {
-	return &Scheduler{bot: bot, uc: uc, chatUc: chatUc, nowFunc: time.Now}
+func NewScheduler(bot sender, uc reminderScheduler, chatUc schedulerChats, cfg config.DeliveryConfig) *Scheduler {
+	return &Scheduler{
+		bot:              bot,
+		uc:               uc,
+		chatUc:           chatUc,
+		nowFunc:          time.Now,
+		retryDeadline:    cfg.RetryDeadline,
+		historyRetention: cfg.HistoryRetention,
+		wakeups:          newWakeQueue(),
+		wake:             make(chan struct{}, 1),
+	}
 }
 
-// Run опрашивает базу до отмены контекста. Вызов блокирующий.
+// Run обрабатывает срабатывания до отмены контекста. Вызов блокирующий.
 func (s *Scheduler) Run(ctx context.Context) {
-	ticker := time.NewTicker(tickInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
-	slog.Info("Reminder scheduler started", "interval", tickInterval)
+	slog.Info("Reminder scheduler started", "resync", resyncInterval)
 
+	var resyncAt time.Time
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("Reminder scheduler stopped")
 			return
-		case <-ticker.C:
-			s.deliverDue(ctx)
+		case <-s.wake:
+		case <-timer.C:
+		}
+
+		now := s.nowFunc()
+		if !now.Before(resyncAt) {
+			resyncAt = s.resync(ctx, now)
+		}
+		if s.wakeups.popDue(now) > 0 && !s.deliverDue(ctx) {
+			resyncAt = now.Add(retryDelay)
+		}
+
+		next := resyncAt
+		if at, ok := s.wakeups.next(); ok && at.Before(next) {
+			next = at
 		}
+		timer.Reset(next.Sub(s.nowFunc()))
 	}
 }
 
-func (s *Scheduler) deliverDue(ctx context.Context) {
+// WakeAt сообщает, что напоминание id нужно обработать в момент at; нулевое at снимает
+// его с очереди. Реализует usecase.Waker.
+func (s *Scheduler) WakeAt(id int64, at time.Time) {
+	s.schedule(domain.WakeReminder, id, at)
+}
+
+// schedule назначает записи время пробуждения и будит Run, чтобы тот пересчитал таймер.
+func (s *Scheduler) schedule(kind domain.WakeupKind, id int64, at time.Time) {
+	s.wakeups.set(wakeKey{kind: kind, id: id}, at)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// retryLater повторяет работу над записью через retryDelay: ошибка базы оставила её
+// наступившей, а из очереди она уже снята.
+func (s *Scheduler) retryLater(kind domain.WakeupKind, id int64) {
+	s.schedule(kind, id, s.nowFunc().Add(retryDelay))
+}
+
+// resync сверяет очередь с базой и чистит историю доставок. Возвращает время следующей
+// сверки: если база вернула не всё, то не позже последнего прочитанного пробуждения.
+func (s *Scheduler) resync(ctx context.Context, now time.Time) time.Time {
 	ctx, cancel := context.WithTimeout(ctx, batchTimeout)
 	defer cancel()
 
-	now := s.nowFunc()
+	s.pruneHistory(ctx, now)
 
+	wakeups, err := s.uc.ListWakeups(ctx, resyncLimit)
+	if err != nil {
+		slog.Error("Failed to list upcoming wakeups", "error", err)
+		return now.Add(retryDelay)
+	}
+	for _, w := range wakeups {
+		s.wakeups.merge(wakeKey{kind: w.Kind, id: w.ID}, w.At)
+	}
+
+	next := now.Add(resyncInterval)
+	if len(wakeups) == resyncLimit {
+		// Всё, что раньше последнего прочитанного, уже в очереди. Просроченное же
+		// разберёт deliverDue целиком, и перечитывать его чаще retryDelay незачем.
+		last := wakeups[len(wakeups)-1].At
+		if floor := now.Add(retryDelay); last.Before(floor) {
+			last = floor
+		}
+		if last.Before(next) {
+			next = last
+		}
+	}
+
+	return next
+}
+
+// deliverDue обрабатывает всё наступившее. Возвращает false, если прочитать его
+// из базы не удалось.
+func (s *Scheduler) deliverDue(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, batchTimeout)
+	defer cancel()
+
+	now := s.nowFunc()
 	reminders, err := s.uc.ListDue(ctx, now)
 	if err != nil {
 		slog.Error("Failed to list due reminders", "error", err)
-		return
+		return false
 	}
-	if len(reminders) == 0 {
-		return
+	// Повторы неподтверждённых доставок идут в той же пачке: ошибка их чтения
+	// не должна задерживать сами напоминания.
+	acks, acksErr := s.uc.ListDueAcks(ctx, now)
+	if acksErr != nil {
+		slog.Error("Failed to list due acknowledgements", "error", acksErr)
+	}
+	if len(reminders) > 0 || len(acks) > 0 {
+		slog.Info("Processing due reminders", "count", len(reminders), "nags", len(acks))
 	}
-	slog.Info("Processing due reminders", "count", len(reminders))
 
 	var wg sync.WaitGroup
 	slots := make(chan struct{}, sendConcurrency)
-
-	for _, r := range reminders {
+	run := func(fn func()) {
 		wg.Add(1)
-		go func(r *domain.Reminder) {
+		go func() {
 			defer wg.Done()
 			slots <- struct{}{}
 			defer func() { <-slots }()
 
-			s.deliverOne(ctx, r, now)
-		}(r)
+			fn()
+		}()
 	}
 
+	for _, r := range reminders {
+		run(func() { s.deliverOne(ctx, r, now) })
+	}
+	for _, a := range acks {
+		run(func() { s.nagOne(ctx, a, now) })
+	}
+	wg.Wait()
+
+	// Очередь разбирается после постановки: только что перенесённые напоминания
+	// уходят в той же пачке, а вместе с ними — повторы прежних неудачных отправок.
+	deliveries, err := s.uc.ListPendingDeliveries(ctx, now)
+	if err != nil {
+		slog.Error("Failed to list pending deliveries", "error", err)
+		return false
+	}
+	for _, d := range fairOrder(deliveries) {
+		run(func() { s.sendDelivery(ctx, d, now) })
+	}
 	wg.Wait()
+
+	return acksErr == nil
 }
 
-// deliverOne переносит напоминание и только потом отправляет его.
+// fairOrder чередует доставки разных чатов, сохраняя порядок внутри каждого: чат,
+// которому в 09:00 причитается сотня сообщений, не оттесняет остальных в конец пачки.
+func fairOrder(deliveries []*domain.Delivery) []*domain.Delivery {
+	var chats []int64
+	byChat := make(map[int64][]*domain.Delivery)
+	for _, d := range deliveries {
+		if _, ok := byChat[d.ChatID]; !ok {
+			chats = append(chats, d.ChatID)
+		}
+		byChat[d.ChatID] = append(byChat[d.ChatID], d)
+	}
+
+	ordered := make([]*domain.Delivery, 0, len(deliveries))
+	for round := 0; len(ordered) < len(deliveries); round++ {
+		for _, chatID := range chats {
+			if queue := byChat[chatID]; round < len(queue) {
+				ordered = append(ordered, queue[round])
+			}
+		}
+	}
+
+	return ordered
+}
+
+// deliverOne переносит напоминание и в той же транзакции ставит его доставку в очередь.
 //
-// Порядок принципиален: при обратном порядке падение записи в базу оставляло бы next_time
-// в прошлом, и пользователь получал бы одно и то же напоминание каждые 30 секунд.
-// Ценой этого выбора становится пропуск напоминания, если отправка не удалась, — что
-// заметно лучше бесконечного потока дублей.
+// Отправка не может идти первой: падение записи в базу оставляло бы next_time в прошлом,
+// и пользователь получал бы одно и то же напоминание при каждом пробуждении. А раз доставка
+// записана вместе с переносом, неудачная отправка её не теряет — sendDelivery повторит.
 func (s *Scheduler) deliverOne(ctx context.Context, r *domain.Reminder, now time.Time) {
 	if r.Paused {
 		return
 	}
+	if r.NextTime.After(now) {
+		// В выборку напоминание попало ради предупреждения: само срабатывание ещё впереди.
+		s.deliverNotice(ctx, r, now)
+		return
+	}
+	if !s.loadSchedule(ctx, r) {
+		s.retryLater(domain.WakeReminder, r.ID)
+		return
+	}
 
-	text := r.Text
+	// Тихие часы — настройка чата и считаются по его зоне, а расписание — по зоне
+	// напоминания.
+	chatLoc := s.chatUc.Location(ctx, r.ChatID)
+	loc := r.Location(chatLoc)
 
-	if r.Repeat == domain.RepeatNone {
-		if err := s.uc.DeleteReminder(ctx, r.ID); err != nil {
-			slog.Error("Failed to delete one-time reminder", "reminder_id", r.ID, "error", err)
+	silent := false
+	if quiet := s.chatUc.QuietHours(ctx, r.ChatID); quiet.Contains(now.In(chatLoc)) {
+		if quiet.Mode == domain.QuietDefer && s.deferQuiet(ctx, r, quiet, now, chatLoc) {
 			return
 		}
-	} else {
+		silent = true
+	}
+
+	due := r.NextTime
+	// Текст собирается до переноса: сводке нужны срабатывания, начиная с текущего.
+	message, send := s.message(ctx, r, now, loc)
+	var d *domain.Delivery
+	if send {
+		d = s.newDelivery(r, domain.DeliveryReminder, due, message, silent, now)
+	}
+
+	if !s.reschedule(ctx, r, now, loc, d) {
+		s.retryLater(domain.WakeReminder, r.ID)
+		return
+	}
+	switch {
+	case d == nil:
+		slog.Info("Late reminder skipped by chat policy",
+			"chat_id", r.ChatID, "reminder_id", r.ID, "late", now.Sub(due))
+	case d.ID == 0:
+		slog.Warn("Reminder is already queued", "chat_id", r.ChatID, "reminder_id", r.ID, "due", due)
+	default:
+		s.startAck(ctx, r, d, now)
+	}
+}
+
+// deliverNotice отправляет предупреждение перед срабатыванием. Как и напоминание, оно
+// сначала записывается — ReminderUsecase при сохранении назначает следующее, — и только
+// потом отправляется. Предупреждение, опоздавшее дольше порога чата, не отправляется:
+// после простоя «через 3 дня» было бы уже неправдой.
+func (s *Scheduler) deliverNotice(ctx context.Context, r *domain.Reminder, now time.Time) {
+	var d *domain.Delivery
+	lead, at, ok := scheduling.DueNotice(r, now)
+	late := now.Sub(at)
+	stale := ok && late >= domain.MinCatchUpAfter && late >= s.chatUc.CatchUp(ctx, r.ChatID).Threshold()
+	if ok && !stale {
+		// Предупреждение не откладывается на конец тихих часов — к тому времени оно устарело бы.
 		loc := s.chatUc.Location(ctx, r.ChatID)
+		silent := s.chatUc.QuietHours(ctx, r.ChatID).Contains(now.In(loc))
+		message := texts.ReminderNotice(r.Text, "через "+ui.LeadLabel(lead))
+		d = s.newDelivery(r, domain.DeliveryNotice, at, message, silent, now)
+	}
 
-		next, err := scheduling.Advance(r, now, loc)
-		if err != nil {
-			slog.Error("Failed to compute next time, pausing reminder",
-				"reminder_id", r.ID, "repeat", r.Repeat, "error", err)
-			// Пересчитать время не удалось — ставим на паузу, иначе напоминание
-			// останется навсегда просроченным и будет отправляться каждый тик.
-			if err := s.uc.PauseReminder(ctx, r.ID); err != nil {
-				slog.Error("Failed to pause broken reminder", "reminder_id", r.ID, "error", err)
-			}
+	r.UpdatedAt = now
+	if err := s.uc.AdvanceReminder(ctx, r, d); err != nil {
+		slog.Error("Failed to plan next notice", "reminder_id", r.ID, "error", err)
+		s.retryLater(domain.WakeReminder, r.ID)
+		return
+	}
+	if stale {
+		slog.Info("Late notice skipped", "chat_id", r.ChatID, "reminder_id", r.ID, "late", late)
+	}
+}
+
+// newDelivery готовит доставку text в чат напоминания r; occurrence — срабатывание или
+// предупреждение по расписанию, ради которого она отправляется.
+func (s *Scheduler) newDelivery(
+	r *domain.Reminder,
+	kind domain.DeliveryKind,
+	occurrence time.Time,
+	text string,
+	silent bool,
+	now time.Time,
+) *domain.Delivery {
+	return &domain.Delivery{
+		ReminderID:    r.ID,
+		ChatID:        r.ChatID,
+		Kind:          kind,
+		Occurrence:    occurrence.UTC(),
+		Text:          text,
+		Silent:        silent,
+		NextAttemptAt: now.UTC(),
+		Deadline:      now.Add(s.retryDeadline).UTC(),
+		CreatedAt:     now.UTC(),
+	}
+}
+
+// startAck начинает ожидание подтверждения доставки d напоминания r. Если запись
+// не удалась, напоминание уходит как обычное, с кнопками «отложить».
+func (s *Scheduler) startAck(ctx context.Context, r *domain.Reminder, d *domain.Delivery, now time.Time) {
+	if r.NagEveryMinutes == 0 {
+		return
+	}
 
+	// Запись создаётся до отправки: её ID нужен кнопке «Готово» под сообщением.
+	// Повторы начнутся, только когда сообщение уйдёт, — см. ackDelivered.
+	ack := domain.NewAcknowledgement(r, 0, now)
+	if err := s.uc.CreateAck(ctx, ack); err != nil {
+		slog.Error("Failed to start acknowledgement", "reminder_id", r.ID, "error", err)
+		return
+	}
+	d.AckID = ack.ID
+	if !s.saveDelivery(ctx, d) {
+		// Без ссылки из доставки кнопки «Готово» не будет — повторять незачем.
+		ack.NextNagAt = time.Time{}
+		s.saveAck(ctx, ack)
+	}
+}
+
+// sendDelivery отправляет доставку из очереди. После временной ошибки назначается
+// следующая попытка, после остальных — и после RetryDeadline — доставка бросается.
+func (s *Scheduler) sendDelivery(ctx context.Context, d *domain.Delivery, now time.Time) {
+	if d.Expire(now) {
+		slog.Warn("Delivery expired before it was sent", "chat_id", d.ChatID, "reminder_id", d.ReminderID,
+			"kind", d.Kind, "deadline", d.Deadline)
+		s.saveDelivery(ctx, d)
+		s.recordAttempt(ctx, d.Attempt(now), 0, nil)
+		s.cancelAck(ctx, d)
+		return
+	}
+
+	// Под напоминанием — кнопки «отложить», а в режиме подтверждения — «Готово».
+	var sendOpts []any
+	switch {
+	case d.AckID != 0:
+		sendOpts = append(sendOpts, ui.AckMenu(d.AckID))
+	case d.Kind == domain.DeliveryReminder:
+		sendOpts = append(sendOpts, ui.SnoozeMenu(now.In(s.chatUc.Location(ctx, d.ChatID))))
+	}
+	if d.Silent {
+		sendOpts = append(sendOpts, &tele.SendOptions{DisableNotification: true})
+	}
+
+	msg, err := s.bot.Send(&tele.Chat{ID: d.ChatID}, d.Text, sendOpts...)
+	if err != nil {
+		s.deliveryFailed(ctx, d, err, now)
+		return
+	}
+	slog.Info("Reminder sent", "chat_id", d.ChatID, "reminder_id", d.ReminderID, "kind", d.Kind,
+		"attempt", d.Attempts+1)
+
+	// Если отметка не запишется, через retryDelay сообщение уйдёт ещё раз: дубль
+	// лучше потерянного напоминания.
+	d.Sent(now)
+	s.saveDelivery(ctx, d)
+	s.recordAttempt(ctx, d.Attempt(now), msg.ID, nil)
+	if d.AckID != 0 {
+		s.ackDelivered(ctx, d, msg.ID, now)
+	}
+	if d.Kind == domain.DeliveryReminder {
+		s.armFollowUps(ctx, d.ReminderID, now)
+	}
+}
+
+// armFollowUps назначает продолжения, ждущие доставки напоминания parentID.
+func (s *Scheduler) armFollowUps(ctx context.Context, parentID int64, now time.Time) {
+	n, err := s.uc.ArmFollowUps(ctx, parentID, domain.FollowOnDelivery, now)
+	if err != nil {
+		slog.Error("Failed to arm follow-ups", "reminder_id", parentID, "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("Follow-ups armed", "reminder_id", parentID, "count", n)
+	}
+}
+
+// deliveryFailed назначает повтор доставки после временной ошибки или бросает её.
+// Отправку, которую отложил свой лимит, неудачей не считает: попытка просто переносится.
+func (s *Scheduler) deliveryFailed(ctx context.Context, d *domain.Delivery, err error, now time.Time) {
+	if wait, ok := telegramapi.Throttled(err); ok {
+		if d.Postpone(now.Add(wait), err.Error()) {
+			slog.Debug("Delivery throttled", "chat_id", d.ChatID, "reminder_id", d.ReminderID,
+				"next_attempt_at", d.NextAttemptAt)
+			s.saveDelivery(ctx, d)
 			return
 		}
+		slog.Warn("Delivery throttled past its deadline", "chat_id", d.ChatID, "reminder_id", d.ReminderID,
+			"deadline", d.Deadline)
+		s.saveDelivery(ctx, d)
+		s.recordAttempt(ctx, d.Attempt(now), 0, err)
+		s.cancelAck(ctx, d)
+		return
+	}
 
-		r.NextTime = next
-		r.UpdatedAt = now
-		if err := s.uc.EditReminder(ctx, r); err != nil {
-			slog.Error("Failed to reschedule reminder", "reminder_id", r.ID, "error", err)
+	retryAfter, _ := telegramapi.RetryAfter(err)
+	if !telegramapi.IsTemporary(err) {
+		d.Fail(err.Error())
+	} else if d.Retry(now, retryAfter, err.Error()) {
+		slog.Warn("Failed to send reminder, will retry",
+			"chat_id", d.ChatID, "reminder_id", d.ReminderID, "attempt", d.Attempts,
+			"next_attempt_at", d.NextAttemptAt, "error", err)
+		s.saveDelivery(ctx, d)
+		s.recordAttempt(ctx, d.Attempt(now), 0, err)
+		return
+	}
+
+	s.sendFailed(ctx, d.ChatID, err, "reminder_id", d.ReminderID, "attempts", d.Attempts)
+	s.saveDelivery(ctx, d)
+	s.recordAttempt(ctx, d.Attempt(now), 0, err)
+	s.cancelAck(ctx, d)
+}
+
+// cancelAck снимает повторы с брошенной доставки d в режиме подтверждения.
+func (s *Scheduler) cancelAck(ctx context.Context, d *domain.Delivery) {
+	if d.AckID != 0 {
+		// Сообщение до чата не дошло, повторять его незачем: пустая запись без
+		// next_nag_at уйдёт вместе со старыми доставками.
+		s.saveAck(ctx, &domain.Acknowledgement{ID: d.AckID})
+	}
+}
+
+// ackDelivered запоминает отправленное сообщение доставки в режиме подтверждения
+// и запускает повторы от момента отправки.
+func (s *Scheduler) ackDelivered(ctx context.Context, d *domain.Delivery, messageID int, now time.Time) {
+	ack, err := s.uc.GetAck(ctx, d.AckID, d.ChatID)
+	if err != nil {
+		slog.Error("Failed to load acknowledgement", "ack_id", d.AckID, "error", err)
+		return
+	}
+	ack.Delivered(messageID, now)
+	s.saveAck(ctx, ack)
+}
+
+// saveDelivery сохраняет доставку и назначает планировщику её следующую попытку.
+func (s *Scheduler) saveDelivery(ctx context.Context, d *domain.Delivery) bool {
+	if err := s.uc.UpdateDelivery(ctx, d); err != nil {
+		slog.Error("Failed to update delivery", "delivery_id", d.ID, "error", err)
+		s.retryLater(domain.WakeDelivery, d.ID)
+		return false
+	}
+	s.schedule(domain.WakeDelivery, d.ID, d.NextAttemptAt)
+
+	return true
+}
+
+// nagOne повторяет доставку, которую так и не подтвердили. Повтор, как и напоминание,
+// сначала записывается и только потом отправляется.
+func (s *Scheduler) nagOne(ctx context.Context, a *domain.Acknowledgement, now time.Time) {
+	loc := s.chatUc.Location(ctx, a.ChatID)
+
+	sendOpts := []any{ui.AckMenu(a.ID)}
+	if quiet := s.chatUc.QuietHours(ctx, a.ChatID); quiet.Contains(now.In(loc)) {
+		if quiet.Mode == domain.QuietDefer {
+			a.NextNagAt = quiet.EndAfter(now.In(loc)).UTC()
+			s.saveAck(ctx, a)
 			return
 		}
-		slog.Info("Reminder rescheduled", "reminder_id", r.ID, "next_time", next)
+		sendOpts = append(sendOpts, &tele.SendOptions{DisableNotification: true})
 	}
 
-	if _, err := s.bot.Send(&tele.Chat{ID: r.ChatID}, texts.ReminderPrefix+text); err != nil {
-		if telegramapi.IsBotUnavailable(err) {
-			if stateErr := s.chatUc.SetAvailable(ctx, r.ChatID, false); stateErr != nil {
-				slog.Error(
-					"Failed to freeze unavailable chat",
-					"chat_id", r.ChatID,
-					"error", stateErr,
-				)
-			} else {
-				slog.Warn("Telegram bot is unavailable in chat", "chat_id", r.ChatID, "error", err)
-			}
-			return
+	scheduled := a.NextNagAt
+	a.Nagged(now)
+	if !s.saveAck(ctx, a) {
+		return
+	}
+
+	message := texts.ReminderNag(a.Text, a.Nags, a.NagMax)
+	attempt := &domain.DeliveryAttempt{
+		ReminderID:  a.ReminderID,
+		ChatID:      a.ChatID,
+		Kind:        domain.DeliveryNag,
+		Text:        message,
+		ScheduledAt: scheduled,
+		AttemptedAt: now.UTC(),
+		Outcome:     domain.OutcomeSent,
+	}
+	msg, err := s.bot.Send(&tele.Chat{ID: a.ChatID}, message, sendOpts...)
+	if err != nil {
+		// Повтор не ставится в очередь: следующий придёт по расписанию повторов.
+		attempt.Outcome, attempt.Error = domain.OutcomeFailed, err.Error()
+		s.recordAttempt(ctx, attempt, 0, err)
+		s.sendFailed(ctx, a.ChatID, err, "ack_id", a.ID)
+		return
+	}
+	s.recordAttempt(ctx, attempt, msg.ID, nil)
+	slog.Info("Reminder nag sent", "chat_id", a.ChatID, "ack_id", a.ID, "nag", a.Nags)
+}
+
+// recordAttempt пишет попытку в историю доставок; err — ошибка Telegram, если она была.
+// История вспомогательная: ошибка записи только логируется.
+func (s *Scheduler) recordAttempt(ctx context.Context, a *domain.DeliveryAttempt, messageID int, err error) {
+	a.MessageID = messageID
+	a.ErrorClass = telegramapi.ErrorClass(err)
+	if recordErr := s.uc.RecordAttempt(ctx, a); recordErr != nil {
+		slog.Error("Failed to record delivery attempt",
+			"chat_id", a.ChatID, "reminder_id", a.ReminderID, "error", recordErr)
+	}
+}
+
+// pruneHistory раз в pruneInterval удаляет историю доставок старше срока хранения.
+func (s *Scheduler) pruneHistory(ctx context.Context, now time.Time) {
+	if s.historyRetention <= 0 || now.Sub(s.prunedAt) < pruneInterval {
+		return
+	}
+	s.prunedAt = now
+
+	deleted, err := s.uc.PruneHistory(ctx, now.Add(-s.historyRetention))
+	if err != nil {
+		slog.Error("Failed to prune delivery history", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Info("Pruned delivery history", "deleted", deleted)
+	}
+}
+
+// saveAck сохраняет ожидание подтверждения и назначает планировщику следующий повтор.
+func (s *Scheduler) saveAck(ctx context.Context, a *domain.Acknowledgement) bool {
+	if err := s.uc.UpdateAck(ctx, a); err != nil {
+		slog.Error("Failed to update acknowledgement", "ack_id", a.ID, "error", err)
+		s.retryLater(domain.WakeAck, a.ID)
+		return false
+	}
+	s.schedule(domain.WakeAck, a.ID, a.NextNagAt)
+
+	return true
+}
+
+// sendFailed разбирает ошибку отправки: чат, где бот больше недоступен, замораживается.
+func (s *Scheduler) sendFailed(ctx context.Context, chatID int64, err error, attrs ...any) {
+	if telegramapi.IsBotUnavailable(err) {
+		if stateErr := s.chatUc.SetAvailable(ctx, chatID, false); stateErr != nil {
+			slog.Error(
+				"Failed to freeze unavailable chat",
+				"chat_id", chatID,
+				"error", stateErr,
+			)
+		} else {
+			slog.Warn("Telegram bot is unavailable in chat", "chat_id", chatID, "error", err)
 		}
-		slog.Error("Failed to send reminder", "chat_id", r.ChatID, "reminder_id", r.ID, "error", err)
 		return
 	}
-	slog.Info("Reminder sent", "chat_id", r.ChatID, "reminder_id", r.ID)
+	slog.Error("Failed to send reminder", append([]any{"chat_id", chatID, "error", err}, attrs...)...)
+}
+
+// message собирает текст доставки. Напоминание, опоздавшее дольше порога чата, — обычно
+// после простоя бота — оформляется по политике чата; send=false — отправлять не нужно.
+func (s *Scheduler) message(
+	ctx context.Context,
+	r *domain.Reminder,
+	now time.Time,
+	loc *time.Location,
+) (text string, send bool) {
+	late := now.Sub(r.NextTime)
+	// Порог не бывает меньше минуты — обычные доставки обходятся без чтения настроек чата.
+	if late < domain.MinCatchUpAfter {
+		return texts.ReminderPrefix + r.Text, true
+	}
+	catchUp := s.chatUc.CatchUp(ctx, r.ChatID)
+	if late < catchUp.Threshold() {
+		return texts.ReminderPrefix + r.Text, true
+	}
+
+	switch catchUp.Policy {
+	case domain.CatchUpSkip:
+		return "", false
+	case domain.CatchUpSummary:
+		missed := scheduling.Missed(r, now, loc, maxMissedInSummary)
+		if len(missed) > 1 {
+			return texts.ReminderMissedSummary(r.Text, len(missed), len(missed) == maxMissedInSummary,
+				ui.FormatTime(missed[0], loc), ui.FormatTime(missed[len(missed)-1], loc)), true
+		}
+	}
+
+	return texts.ReminderLate(r.Text, ui.FormatTime(r.NextTime, loc)), true
+}
+
+// deferQuiet откладывает доставку до конца тихих часов чата. Серия продолжает шагать от
+// времени по расписанию — оно сохраняется в ShiftedFrom, как при переносе на рабочий день,
+// а срабатывания, наступившие в тихие часы, сливаются в одно.
+//
+// Возвращает false, если откладывать нельзя: серия заканчивается раньше тихих часов,
+// и остаётся отправить напоминание без звука.
+func (s *Scheduler) deferQuiet(
+	ctx context.Context,
+	r *domain.Reminder,
+	quiet domain.QuietHours,
+	now time.Time,
+	loc *time.Location,
+) bool {
+	end := quiet.EndAfter(now.In(loc)).UTC()
+	if !r.EndsAt.IsZero() && end.After(r.EndsAt) {
+		return false
+	}
+
+	if r.Repeat != domain.RepeatNone {
+		r.ShiftedFrom = scheduling.SeriesTime(r)
+	}
+	r.NextTime = end
+	r.UpdatedAt = now
+	if err := s.uc.EditReminder(ctx, r); err != nil {
+		// Напоминание остаётся просроченным, через retryDelay попробуем снова — шуметь
+		// в тихие часы из-за ошибки базы не стоит.
+		slog.Error("Failed to defer reminder past quiet hours", "reminder_id", r.ID, "error", err)
+		s.retryLater(domain.WakeReminder, r.ID)
+		return true
+	}
+	slog.Info("Reminder deferred past quiet hours", "chat_id", r.ChatID, "reminder_id", r.ID, "until", end)
+
+	return true
+}
+
+// loadSchedule подгружает в повторяющееся напоминание исключения, календарь и координаты
+// чата, без которых следующее срабатывание вычислилось бы неверно. Возвращает false, если
+// их прочитать не удалось: напоминание остаётся просроченным, и deliverOne повторит позже.
+func (s *Scheduler) loadSchedule(ctx context.Context, r *domain.Reminder) bool {
+	if r.Repeat == domain.RepeatNone {
+		return true
+	}
+
+	exceptions, err := s.uc.ListExceptions(ctx, r.ID)
+	if err != nil {
+		// Без исключений следующим стало бы пропущенное срабатывание.
+		slog.Error("Failed to load reminder exceptions", "reminder_id", r.ID, "error", err)
+		return false
+	}
+	r.Exceptions = exceptions
+
+	if r.WorkdayPolicy != domain.WorkdayAny {
+		calendar, err := s.chatUc.Calendar(ctx, r.ChatID)
+		if err != nil {
+			// Без календаря праздник стал бы обычным рабочим днём.
+			slog.Error("Failed to load chat calendar", "chat_id", r.ChatID, "reminder_id", r.ID, "error", err)
+			return false
+		}
+		r.Calendar = calendar
+	}
+
+	if r.Repeat == domain.RepeatSolar {
+		geo, err := s.chatUc.Geo(ctx, r.ChatID)
+		if err != nil {
+			slog.Error("Failed to load chat location", "chat_id", r.ChatID, "reminder_id", r.ID, "error", err)
+			return false
+		}
+		r.Geo = geo
+	}
+
+	return true
+}
+
+// reschedule сдвигает повторяющееся напоминание на следующий раз или удаляет завершённое
+// и вместе с этим ставит в очередь доставку d, если она есть. Возвращает false, если
+// изменение не сохранилось: тогда и в очередь ничего не попало.
+func (s *Scheduler) reschedule(
+	ctx context.Context,
+	r *domain.Reminder,
+	now time.Time,
+	loc *time.Location,
+	d *domain.Delivery,
+) bool {
+	if r.Repeat == domain.RepeatNone && r.IsFollowUp() {
+		// Продолжение не удаляется: оно снова ждёт следующего срабатывания родителя.
+		r.Waiting = true
+		r.UpdatedAt = now
+
+		return s.advance(ctx, r, d)
+	}
+	if r.Repeat == domain.RepeatNone {
+		return s.finish(ctx, r, d)
+	}
+
+	next, err := scheduling.AdvanceOccurrence(r, now, loc)
+	if errors.Is(err, scheduling.ErrSeriesEnded) {
+		// Последнее срабатывание серии доставляется как разовое напоминание.
+		return s.finish(ctx, r, d)
+	}
+	if err != nil {
+		slog.Error("Failed to compute next time, pausing reminder",
+			"reminder_id", r.ID, "repeat", r.Repeat, "error", err)
+		// Пересчитать время не удалось — ставим на паузу, иначе напоминание
+		// останется навсегда просроченным и будет отправляться при каждом пробуждении.
+		if err := s.uc.PauseReminder(ctx, r.ID); err != nil {
+			slog.Error("Failed to pause broken reminder", "reminder_id", r.ID, "error", err)
+		}
+
+		return false
+	}
+
+	next.Apply(r)
+	if r.RemainingCount > 0 {
+		// Текущее срабатывание сейчас уйдёт в чат и из остатка выбывает.
+		r.RemainingCount--
+	}
+	r.UpdatedAt = now
+	if !s.advance(ctx, r, d) {
+		return false
+	}
+	slog.Info("Reminder rescheduled", "reminder_id", r.ID, "next_time", next.Time)
+
+	return true
+}
+
+// advance сохраняет сдвинутое напоминание вместе с постановкой d в очередь.
+func (s *Scheduler) advance(ctx context.Context, r *domain.Reminder, d *domain.Delivery) bool {
+	if err := s.uc.AdvanceReminder(ctx, r, d); err != nil {
+		slog.Error("Failed to reschedule reminder", "reminder_id", r.ID, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// finish удаляет напоминание, у которого больше не будет срабатываний.
+func (s *Scheduler) finish(ctx context.Context, r *domain.Reminder, d *domain.Delivery) bool {
+	if err := s.uc.FinishReminder(ctx, r.ID, d); err != nil {
+		slog.Error("Failed to delete finished reminder", "reminder_id", r.ID, "error", err)
+		return false
+	}
+
+	return true
 }
@@ -3,11 +3,16 @@ package telegram
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/8thgencore/dory-reminder-bot/internal/config"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/infrastructure/telegramapi"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	tele "gopkg.in/telebot.v4"
@@ -18,27 +23,52 @@ import (
 type sentMessage struct {
 	chatID int64
 	text   string
+	silent bool
+	snooze bool
+	// ackID — доставка, на которую ссылается кнопка «Готово», если она есть.
+	ackID string
 }
 
 type stubSender struct {
 	mu   sync.Mutex
 	sent []sentMessage
 	err  error
+	// failures — ошибки для очередных попыток отправки, по одной на попытку.
+	failures []error
 }
 
-func (s *stubSender) Send(to tele.Recipient, what any, _ ...any) (*tele.Message, error) {
+func (s *stubSender) Send(to tele.Recipient, what any, opts ...any) (*tele.Message, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if s.err != nil {
 		return nil, s.err
 	}
+	if len(s.failures) > 0 {
+		err := s.failures[0]
+		s.failures = s.failures[1:]
+		return nil, err
+	}
 
 	chat, _ := to.(*tele.Chat)
 	text, _ := what.(string)
-	s.sent = append(s.sent, sentMessage{chatID: chat.ID, text: text})
+	msg := sentMessage{chatID: chat.ID, text: text}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case *tele.SendOptions:
+			msg.silent = o.DisableNotification
+		case *tele.ReplyMarkup:
+			if len(o.InlineKeyboard) == 0 {
+				break
+			}
+			unique := o.InlineKeyboard[0][0].Unique
+			msg.snooze = strings.HasPrefix(unique, "snooze_")
+			msg.ackID, _ = strings.CutPrefix(unique, "ack_")
+		}
+	}
+	s.sent = append(s.sent, msg)
 
-	return &tele.Message{}, nil
+	return &tele.Message{ID: len(s.sent)}, nil
 }
 
 func (s *stubSender) messages() []sentMessage {
@@ -54,13 +84,30 @@ type stubReminderUC struct {
 	reminders map[int64]*domain.Reminder
 	editErr   error
 	deleteErr error
-	edits     int
-	deletes   int
-	pauses    int
+	// exceptions — исключения по ID напоминания; exceptionsErr ломает их чтение.
+	exceptions    map[int64][]domain.OccurrenceException
+	exceptionsErr error
+	// acks — доставки, ждущие подтверждения, по ID.
+	acks map[int64]*domain.Acknowledgement
+	// deliveries — очередь доставок по ID.
+	deliveries map[int64]*domain.Delivery
+	// history — записанные попытки доставки; prunedBefore — граница последней чистки.
+	history      []*domain.DeliveryAttempt
+	prunedBefore time.Time
+	// now — часы, по которым EditReminder, как и ReminderUsecase, назначает следующее
+	// предупреждение; nil — предупреждения не планируются.
+	now     func() time.Time
+	edits   int
+	deletes int
+	pauses  int
 }
 
 func newStubReminderUC(reminders ...*domain.Reminder) *stubReminderUC {
-	s := &stubReminderUC{reminders: make(map[int64]*domain.Reminder)}
+	s := &stubReminderUC{
+		reminders:  make(map[int64]*domain.Reminder),
+		acks:       make(map[int64]*domain.Acknowledgement),
+		deliveries: make(map[int64]*domain.Delivery),
+	}
 	for _, r := range reminders {
 		s.reminders[r.ID] = r
 	}
@@ -74,7 +121,8 @@ func (s *stubReminderUC) ListDue(_ context.Context, now time.Time) ([]*domain.Re
 
 	var due []*domain.Reminder
 	for _, r := range s.reminders {
-		if !r.Paused && !r.NextTime.After(now) {
+		notice := !r.NoticeAt.IsZero() && !r.NoticeAt.After(now)
+		if !r.Paused && !r.Waiting && (!r.NextTime.After(now) || notice) {
 			copied := *r
 			due = append(due, &copied)
 		}
@@ -83,21 +131,60 @@ func (s *stubReminderUC) ListDue(_ context.Context, now time.Time) ([]*domain.Re
 	return due, nil
 }
 
+func (s *stubReminderUC) ListWakeups(_ context.Context, limit int) ([]domain.Wakeup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var wakeups []domain.Wakeup
+	for _, r := range s.reminders {
+		if at := r.WakeAt(); !at.IsZero() {
+			wakeups = append(wakeups, domain.Wakeup{Kind: domain.WakeReminder, ID: r.ID, At: at})
+		}
+	}
+	for _, d := range s.deliveries {
+		if !d.NextAttemptAt.IsZero() {
+			wakeups = append(wakeups, domain.Wakeup{Kind: domain.WakeDelivery, ID: d.ID, At: d.NextAttemptAt})
+		}
+	}
+	slices.SortFunc(wakeups, func(a, b domain.Wakeup) int { return a.At.Compare(b.At) })
+
+	return wakeups[:min(len(wakeups), limit)], nil
+}
+
 func (s *stubReminderUC) EditReminder(_ context.Context, r *domain.Reminder) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.save(r)
+}
+
+func (s *stubReminderUC) AdvanceReminder(_ context.Context, r *domain.Reminder, d *domain.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.save(r); err != nil {
+		return err
+	}
+	s.enqueue(d)
+
+	return nil
+}
+
+func (s *stubReminderUC) save(r *domain.Reminder) error {
 	s.edits++
 	if s.editErr != nil {
 		return s.editErr
 	}
+	if s.now != nil {
+		r.NoticeAt = scheduling.NextNotice(r, s.now())
+	}
 	copied := *r
 	s.reminders[r.ID] = &copied
 
 	return nil
 }
 
-func (s *stubReminderUC) DeleteReminder(_ context.Context, id int64) error {
+func (s *stubReminderUC) FinishReminder(_ context.Context, id int64, d *domain.Delivery) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -106,10 +193,86 @@ func (s *stubReminderUC) DeleteReminder(_ context.Context, id int64) error {
 		return s.deleteErr
 	}
 	delete(s.reminders, id)
+	s.enqueue(d)
 
 	return nil
 }
 
+// ArmFollowUps назначает ждущие продолжения так же, как ReminderUsecase: продолжения
+// удалённого родителя отвязываются.
+func (s *stubReminderUC) ArmFollowUps(
+	_ context.Context,
+	parentID int64,
+	trigger domain.FollowTrigger,
+	at time.Time,
+) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, parentExists := s.reminders[parentID]
+	armed := 0
+	for _, r := range s.reminders {
+		if r.ParentID != parentID || !r.Waiting || r.FollowOn != trigger {
+			continue
+		}
+		r.Arm(at)
+		if !parentExists {
+			r.ParentID = 0
+		}
+		armed++
+	}
+
+	return armed, nil
+}
+
+// enqueue повторяет дедупликацию репозитория: срабатывание ставится в очередь один раз.
+func (s *stubReminderUC) enqueue(d *domain.Delivery) {
+	if d == nil {
+		return
+	}
+	for _, queued := range s.deliveries {
+		if queued.ReminderID == d.ReminderID && queued.Kind == d.Kind && queued.Occurrence.Equal(d.Occurrence) {
+			return
+		}
+	}
+	d.ID = int64(len(s.deliveries) + 1)
+	copied := *d
+	s.deliveries[d.ID] = &copied
+}
+
+func (s *stubReminderUC) UpdateDelivery(_ context.Context, d *domain.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *d
+	s.deliveries[d.ID] = &copied
+
+	return nil
+}
+
+func (s *stubReminderUC) ListPendingDeliveries(_ context.Context, now time.Time) ([]*domain.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []*domain.Delivery
+	for _, d := range s.deliveries {
+		if !d.NextAttemptAt.IsZero() && !d.NextAttemptAt.After(now) {
+			copied := *d
+			pending = append(pending, &copied)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *domain.Delivery) int { return int(a.ID - b.ID) })
+
+	return pending, nil
+}
+
+func (s *stubReminderUC) delivery(id int64) *domain.Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deliveries[id]
+}
+
 func (s *stubReminderUC) PauseReminder(_ context.Context, id int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -122,6 +285,91 @@ func (s *stubReminderUC) PauseReminder(_ context.Context, id int64) error {
 	return nil
 }
 
+func (s *stubReminderUC) ListExceptions(_ context.Context, id int64) ([]domain.OccurrenceException, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.exceptions[id], s.exceptionsErr
+}
+
+func (s *stubReminderUC) CreateAck(_ context.Context, a *domain.Acknowledgement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a.ID = int64(len(s.acks) + 1)
+	copied := *a
+	s.acks[a.ID] = &copied
+
+	return nil
+}
+
+func (s *stubReminderUC) UpdateAck(_ context.Context, a *domain.Acknowledgement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *a
+	s.acks[a.ID] = &copied
+
+	return nil
+}
+
+func (s *stubReminderUC) ListDueAcks(_ context.Context, now time.Time) ([]*domain.Acknowledgement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*domain.Acknowledgement
+	for _, a := range s.acks {
+		if !a.Acknowledged() && a.MessageID != 0 && !a.NextNagAt.IsZero() && !a.NextNagAt.After(now) {
+			copied := *a
+			due = append(due, &copied)
+		}
+	}
+
+	return due, nil
+}
+
+func (s *stubReminderUC) GetAck(_ context.Context, id, _ int64) (*domain.Acknowledgement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *s.acks[id]
+
+	return &copied, nil
+}
+
+func (s *stubReminderUC) RecordAttempt(_ context.Context, a *domain.DeliveryAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *a
+	s.history = append(s.history, &copied)
+
+	return nil
+}
+
+func (s *stubReminderUC) PruneHistory(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prunedBefore = before
+
+	return 0, nil
+}
+
+func (s *stubReminderUC) attempts() []*domain.DeliveryAttempt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.history)
+}
+
+func (s *stubReminderUC) ack(id int64) *domain.Acknowledgement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.acks[id]
+}
+
 func (s *stubReminderUC) get(id int64) *domain.Reminder {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -138,6 +386,10 @@ func (s *stubReminderUC) counts() (edits, deletes, pauses int) {
 
 type stubChatUC struct {
 	loc             *time.Location
+	catchUp         domain.CatchUp
+	quiet           domain.QuietHours
+	calendar        *domain.BusinessCalendar
+	geo             *domain.GeoPoint
 	availabilitySet bool
 	availableChatID int64
 	available       bool
@@ -151,6 +403,26 @@ func (s *stubChatUC) Location(context.Context, int64) *time.Location {
 	return s.loc
 }
 
+func (s *stubChatUC) CatchUp(context.Context, int64) domain.CatchUp {
+	return s.catchUp
+}
+
+func (s *stubChatUC) QuietHours(context.Context, int64) domain.QuietHours {
+	return s.quiet
+}
+
+func (s *stubChatUC) Calendar(_ context.Context, chatID int64) (*domain.BusinessCalendar, error) {
+	if s.calendar == nil {
+		return &domain.BusinessCalendar{ChatID: chatID}, nil
+	}
+
+	return s.calendar, nil
+}
+
+func (s *stubChatUC) Geo(context.Context, int64) (*domain.GeoPoint, error) {
+	return s.geo, nil
+}
+
 func (s *stubChatUC) SetAvailable(_ context.Context, chatID int64, available bool) error {
 	s.availabilitySet = true
 	s.availableChatID = chatID
@@ -160,6 +432,8 @@ func (s *stubChatUC) SetAvailable(_ context.Context, chatID int64, available boo
 
 // --- Тесты ----------------------------------------------------------------
 
+var testDelivery = config.DeliveryConfig{RetryDeadline: time.Hour}
+
 func berlin(t *testing.T) *time.Location {
 	t.Helper()
 	loc, err := time.LoadLocation("Europe/Berlin")
@@ -182,7 +456,7 @@ func TestDeliverDue_SendsAndReschedulesRepeating(t *testing.T) {
 
 	uc := newStubReminderUC(rem)
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{loc: loc})
+	s := NewScheduler(bot, uc, &stubChatUC{loc: loc}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -191,6 +465,7 @@ func TestDeliverDue_SendsAndReschedulesRepeating(t *testing.T) {
 	require.Len(t, sent, 1)
 	assert.Equal(t, int64(100), sent[0].chatID)
 	assert.Contains(t, sent[0].text, "выпить воды")
+	assert.True(t, sent[0].snooze, "delivered reminder must offer snooze buttons")
 
 	stored := uc.get(1)
 	require.NotNil(t, stored)
@@ -199,6 +474,30 @@ func TestDeliverDue_SendsAndReschedulesRepeating(t *testing.T) {
 	assert.Equal(t, 11, stored.NextTime.In(loc).Day())
 }
 
+func TestDeliverDue_ReschedulesSolarByChatLocation(t *testing.T) {
+	now := time.Date(2025, time.June, 21, 17, 48, 30, 0, time.UTC)
+	rem := &domain.Reminder{
+		ID: 1, ChatID: 100, Text: "полить грядки",
+		NextTime: now.Truncate(time.Minute), Repeat: domain.RepeatSolar,
+		SolarEvent: domain.SolarSunset, SolarOffset: -30,
+	}
+
+	uc := newStubReminderUC(rem)
+	bot := &stubSender{}
+	moscow := &domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	s := NewScheduler(bot, uc, &stubChatUC{geo: moscow}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	require.Len(t, bot.messages(), 1)
+	stored := uc.get(1)
+	require.NotNil(t, stored)
+	assert.False(t, stored.Paused)
+	// Закат 22 июня в Москве — 18:18 UTC, напоминание за полчаса до него.
+	assert.WithinDuration(t, time.Date(2025, time.June, 22, 17, 48, 0, 0, time.UTC), stored.NextTime, 2*time.Minute)
+}
+
 func TestDeliverDue_DeletesOneTimeReminder(t *testing.T) {
 	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
 
@@ -207,7 +506,7 @@ func TestDeliverDue_DeletesOneTimeReminder(t *testing.T) {
 		NextTime: now.Add(-time.Minute), Repeat: domain.RepeatNone,
 	})
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -216,6 +515,157 @@ func TestDeliverDue_DeletesOneTimeReminder(t *testing.T) {
 	assert.Nil(t, uc.get(1), "one-time reminder must be removed after firing")
 }
 
+// Последнее срабатывание правила с COUNT уходит пользователю, а само напоминание
+// удаляется, а не ставится на паузу как битое.
+func TestDeliverDue_ArmsFollowUps(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+	parent := &domain.Reminder{
+		ID: 1, ChatID: 100, Text: "полить рассаду",
+		NextTime: now.Truncate(time.Minute), Repeat: domain.RepeatEveryDay,
+	}
+	followUp := &domain.Reminder{
+		ID: 2, ChatID: 100, Text: "проверить поддон", NextTime: parent.NextTime,
+		ParentID: 1, FollowOffset: 60, Waiting: true,
+	}
+
+	uc := newStubReminderUC(parent, followUp)
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{loc: time.UTC}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	require.Len(t, bot.messages(), 1, "ждущее продолжение не отправляется вместе с родителем")
+	armed := uc.get(2)
+	require.NotNil(t, armed)
+	assert.False(t, armed.Waiting)
+	assert.Equal(t, now.Add(time.Hour), armed.NextTime)
+
+	now = now.Add(time.Hour)
+	s.deliverDue(context.Background())
+
+	sent := bot.messages()
+	require.Len(t, sent, 2)
+	assert.Contains(t, sent[1].text, "проверить поддон")
+	stored := uc.get(2)
+	require.NotNil(t, stored, "сработавшее продолжение не удаляется")
+	assert.True(t, stored.Waiting)
+	assert.True(t, stored.WakeAt().IsZero())
+}
+
+func TestDeliverDue_DeletesFinishedRRuleSeries(t *testing.T) {
+	now := time.Date(2025, time.June, 12, 9, 0, 30, 0, time.UTC)
+
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "три дня подряд",
+		NextTime:  time.Date(2025, time.June, 12, 9, 0, 0, 0, time.UTC),
+		StartTime: time.Date(2025, time.June, 10, 9, 0, 0, 0, time.UTC),
+		Repeat:    domain.RepeatRRule, RRule: "FREQ=DAILY;COUNT=3",
+	})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	assert.Len(t, bot.messages(), 1)
+	assert.Nil(t, uc.get(1))
+	_, _, pauses := uc.counts()
+	assert.Zero(t, pauses)
+}
+
+// Счётчик оставшихся срабатываний уменьшается с каждой отправкой, а последняя
+// отправка завершает серию так же, как у разового напоминания.
+func TestDeliverDue_CountsDownRemainingOccurrences(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "антибиотик",
+		NextTime: now.Add(-30 * time.Second), Repeat: domain.RepeatInterval, IntervalMinutes: 8 * 60,
+		RemainingCount: 2,
+	})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	stored := uc.get(1)
+	require.NotNil(t, stored)
+	assert.Equal(t, 1, stored.RemainingCount)
+
+	now = stored.NextTime.Add(30 * time.Second)
+	s.deliverDue(context.Background())
+
+	assert.Len(t, bot.messages(), 2)
+	assert.Nil(t, uc.get(1), "series must be removed after its last occurrence")
+}
+
+func TestDeliverDue_DeletesSeriesPastEndDate(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "до конца недели",
+		NextTime: now.Add(-30 * time.Second), Repeat: domain.RepeatEveryDay,
+		EndsAt: time.Date(2025, time.June, 10, 23, 59, 0, 0, time.UTC),
+	})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	assert.Len(t, bot.messages(), 1)
+	assert.Nil(t, uc.get(1))
+}
+
+// Пропущенное срабатывание перешагивается, а перенесённое наступает в новое время,
+// после чего серия возвращается к расписанию.
+func TestDeliverDue_HonoursOccurrenceExceptions(t *testing.T) {
+	loc := berlin(t)
+	tuesday := func(day, hour int) time.Time { return time.Date(2025, time.June, day, hour, 0, 0, 0, loc).UTC() }
+	now := tuesday(3, 10).Add(30 * time.Second)
+
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "планёрка",
+		NextTime: tuesday(3, 10), Repeat: domain.RepeatEveryWeek, RepeatDays: []int{int(time.Tuesday)},
+	})
+	uc.exceptions = map[int64][]domain.OccurrenceException{1: {
+		{ReminderID: 1, Occurrence: tuesday(10, 10)},
+		{ReminderID: 1, Occurrence: tuesday(17, 10), MovedTo: tuesday(18, 15)},
+	}}
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{loc: loc}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+	assert.Equal(t, tuesday(18, 15), uc.get(1).NextTime)
+
+	now = tuesday(18, 15).Add(30 * time.Second)
+	s.deliverDue(context.Background())
+	assert.Equal(t, tuesday(24, 10), uc.get(1).NextTime)
+	assert.Len(t, bot.messages(), 2)
+}
+
+func TestDeliverDue_DoesNotSendWhenExceptionsFail(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "ежедневное",
+		NextTime: now.Add(-time.Minute), Repeat: domain.RepeatEveryDay,
+	})
+	uc.exceptionsErr = errors.New("database is locked")
+
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	assert.Empty(t, bot.messages())
+	assert.Equal(t, now.Add(-time.Minute), uc.get(1).NextTime)
+}
+
 // Ключевая проверка: если запись в базу упала, напоминание не должно уйти
 // пользователю — иначе оно будет приходить каждые 30 секунд, пока база не оживёт.
 func TestDeliverDue_DoesNotSendWhenRescheduleFails(t *testing.T) {
@@ -228,7 +678,7 @@ func TestDeliverDue_DoesNotSendWhenRescheduleFails(t *testing.T) {
 	uc.editErr = errors.New("database is locked")
 
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -246,7 +696,7 @@ func TestDeliverDue_DoesNotSendWhenDeleteFails(t *testing.T) {
 	uc.deleteErr = errors.New("database is locked")
 
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -265,7 +715,7 @@ func TestDeliverDue_PausesReminderWithBrokenRepeat(t *testing.T) {
 	})
 
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -284,7 +734,7 @@ func TestDeliverDue_SkipsPausedReminders(t *testing.T) {
 	})
 
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -306,7 +756,7 @@ func TestDeliverDue_SendFailureStillReschedules(t *testing.T) {
 	})
 
 	bot := &stubSender{err: errors.New("chat not found")}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -316,6 +766,472 @@ func TestDeliverDue_SendFailureStillReschedules(t *testing.T) {
 	assert.True(t, stored.NextTime.After(now))
 }
 
+func TestDeliverDue_RetriesTransientFailures(t *testing.T) {
+	start := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "разовое",
+		NextTime: start.Add(-30 * time.Second), Repeat: domain.RepeatNone,
+	})
+	bot := &stubSender{failures: []error{
+		errors.New("telegram: Bad Gateway (502)"),
+		errors.New("telebot: connection reset by peer"),
+	}}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	at := func(d time.Duration) {
+		s.nowFunc = func() time.Time { return start.Add(d) }
+		s.deliverDue(context.Background())
+	}
+
+	at(0)
+	assert.Nil(t, uc.get(1), "the reminder is finished even though the send failed")
+	d := uc.delivery(1)
+	require.NotNil(t, d)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, start.Add(domain.DeliveryRetryBase), d.NextAttemptAt)
+
+	at(10 * time.Second)
+	assert.Equal(t, 1, uc.delivery(1).Attempts, "the retry waits for its backoff")
+
+	at(30 * time.Second)
+	at(90 * time.Second)
+	sent := bot.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "⏰ Напоминание: разовое", sent[0].text)
+	assert.True(t, sent[0].snooze)
+	d = uc.delivery(1)
+	assert.Equal(t, start.Add(90*time.Second), d.SentAt)
+	assert.True(t, d.NextAttemptAt.IsZero())
+
+	at(10 * time.Minute)
+	assert.Len(t, bot.messages(), 1, "a sent delivery is not repeated")
+
+	history := uc.attempts()
+	require.Len(t, history, 3)
+	for i, want := range []struct {
+		outcome domain.DeliveryOutcome
+		class   string
+		at      time.Duration
+	}{
+		{domain.OutcomeRetry, telegramapi.ClassServer, 0},
+		{domain.OutcomeRetry, telegramapi.ClassNetwork, 30 * time.Second},
+		{domain.OutcomeSent, "", 90 * time.Second},
+	} {
+		assert.Equal(t, want.outcome, history[i].Outcome)
+		assert.Equal(t, want.class, history[i].ErrorClass)
+		assert.Equal(t, start.Add(want.at), history[i].AttemptedAt)
+		assert.Equal(t, start.Add(-30*time.Second), history[i].ScheduledAt)
+	}
+	assert.Equal(t, 1, history[2].MessageID)
+}
+
+func TestDeliverDue_PostponesThrottledSends(t *testing.T) {
+	start := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+	newUC := func() *stubReminderUC {
+		return newStubReminderUC(&domain.Reminder{
+			ID: 1, ChatID: 100, Text: "разовое",
+			NextTime: start.Add(-30 * time.Second), Repeat: domain.RepeatNone,
+		})
+	}
+	throttled := func(wait time.Duration) *stubSender {
+		return &stubSender{failures: []error{&telegramapi.ThrottledError{ChatID: "100", RetryAfter: wait}}}
+	}
+
+	t.Run("до срока попытка переносится", func(t *testing.T) {
+		uc, bot := newUC(), throttled(2*time.Minute)
+		s := NewScheduler(bot, uc, &stubChatUC{}, config.DeliveryConfig{RetryDeadline: 10 * time.Minute})
+		at := func(d time.Duration) {
+			s.nowFunc = func() time.Time { return start.Add(d) }
+			s.deliverDue(context.Background())
+		}
+
+		at(0)
+		d := uc.delivery(1)
+		require.NotNil(t, d)
+		assert.Zero(t, d.Attempts)
+		assert.Empty(t, d.LastError)
+		assert.Equal(t, start.Add(2*time.Minute), d.NextAttemptAt)
+		assert.Empty(t, uc.attempts(), "a throttled send is not a failed attempt")
+
+		at(2 * time.Minute)
+		require.Len(t, bot.messages(), 1)
+		assert.Equal(t, start.Add(2*time.Minute), uc.delivery(1).SentAt)
+		history := uc.attempts()
+		require.Len(t, history, 1)
+		assert.Equal(t, domain.OutcomeSent, history[0].Outcome)
+	})
+
+	t.Run("перенос за срок бросает доставку", func(t *testing.T) {
+		uc, bot := newUC(), throttled(20*time.Minute)
+		s := NewScheduler(bot, uc, &stubChatUC{}, config.DeliveryConfig{RetryDeadline: 10 * time.Minute})
+		s.nowFunc = func() time.Time { return start }
+
+		s.deliverDue(context.Background())
+
+		d := uc.delivery(1)
+		require.NotNil(t, d)
+		assert.Zero(t, d.Attempts, "the limiter hold is still not counted as an attempt")
+		assert.True(t, d.NextAttemptAt.IsZero())
+		assert.NotEmpty(t, d.LastError)
+		history := uc.attempts()
+		require.Len(t, history, 1)
+		assert.Equal(t, domain.OutcomeFailed, history[0].Outcome)
+		assert.Equal(t, telegramapi.ClassThrottled, history[0].ErrorClass)
+	})
+}
+
+func TestDeliverDue_DropsDeliveriesPastDeadline(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC)
+	uc := newStubReminderUC()
+	// Доставка встала в очередь в 09:00 и пролежала там, пока бот не работал.
+	uc.enqueue(&domain.Delivery{
+		ReminderID: 1, ChatID: 100, Kind: domain.DeliveryReminder, Text: "⏰ Напоминание: утреннее",
+		Occurrence:    now.Add(-3 * time.Hour),
+		NextAttemptAt: now.Add(-3 * time.Hour),
+		Deadline:      now.Add(-2 * time.Hour),
+	})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	assert.Empty(t, bot.messages(), "a stale delivery is not sent")
+	d := uc.delivery(1)
+	assert.True(t, d.NextAttemptAt.IsZero())
+	assert.True(t, d.SentAt.IsZero())
+	history := uc.attempts()
+	require.Len(t, history, 1)
+	assert.Equal(t, domain.OutcomeFailed, history[0].Outcome)
+}
+
+func TestDeliverDue_GivesUpOnPermanentFailures(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
+	tests := []struct {
+		name     string
+		err      error
+		deadline time.Duration
+	}{
+		{name: "rejected by Telegram", err: tele.ErrChatNotFound, deadline: time.Hour},
+		{name: "past the deadline", err: errors.New("telegram: Bad Gateway (502)"), deadline: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newStubReminderUC(&domain.Reminder{
+				ID: 1, ChatID: 100, Text: "полить цветы",
+				NextTime: now.Add(-30 * time.Second), Repeat: domain.RepeatEveryDay,
+				NagEveryMinutes: 15, NagMax: 2,
+			})
+			bot := &stubSender{failures: []error{tt.err}}
+			s := NewScheduler(bot, uc, &stubChatUC{}, config.DeliveryConfig{RetryDeadline: tt.deadline})
+			s.nowFunc = func() time.Time { return now }
+
+			s.deliverDue(context.Background())
+
+			d := uc.delivery(1)
+			require.NotNil(t, d)
+			assert.True(t, d.NextAttemptAt.IsZero())
+			assert.True(t, d.SentAt.IsZero())
+			assert.Equal(t, tt.err.Error(), d.LastError)
+			assert.True(t, uc.ack(d.AckID).NextNagAt.IsZero(), "an undelivered reminder is not nagged")
+			history := uc.attempts()
+			require.Len(t, history, 1)
+			assert.Equal(t, domain.OutcomeFailed, history[0].Outcome)
+			assert.Equal(t, tt.err.Error(), history[0].Error)
+		})
+	}
+}
+
+func TestDeliverDue_ShiftsToWorkday(t *testing.T) {
+	loc := berlin(t)
+	now := time.Date(2025, time.June, 2, 9, 0, 30, 0, loc)
+
+	// По понедельникам, 9 июня — праздник: срабатывание уходит на вторник, а серия
+	// продолжает считаться от понедельника.
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "планёрка",
+		NextTime:      time.Date(2025, time.June, 2, 9, 0, 0, 0, loc).UTC(),
+		Repeat:        domain.RepeatEveryWeek,
+		RepeatDays:    []int{int(time.Monday)},
+		WorkdayPolicy: domain.WorkdayNext,
+	})
+	chatUC := &stubChatUC{loc: loc, calendar: &domain.BusinessCalendar{
+		ChatID: 100,
+		Days:   map[string]bool{"2025-06-09": false},
+	}}
+	s := NewScheduler(&stubSender{}, uc, chatUC, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	stored := uc.get(1)
+	require.NotNil(t, stored)
+	assert.Equal(t, time.Date(2025, time.June, 10, 9, 0, 0, 0, loc).UTC(), stored.NextTime)
+	assert.Equal(t, time.Date(2025, time.June, 9, 9, 0, 0, 0, loc).UTC(), stored.ShiftedFrom)
+}
+
+func TestDeliverDue_CatchUpAfterDowntime(t *testing.T) {
+	loc := berlin(t)
+	// Бот лежал с утра 8 июня до полудня 10-го: ежедневные 9:00 пропущены трижды.
+	now := time.Date(2025, time.June, 10, 12, 0, 0, 0, loc)
+	daily := func() *domain.Reminder {
+		return &domain.Reminder{
+			ID: 1, ChatID: 100, Text: "планёрка",
+			NextTime: time.Date(2025, time.June, 8, 9, 0, 0, 0, loc).UTC(),
+			Repeat:   domain.RepeatEveryDay,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		reminder *domain.Reminder
+		catchUp  domain.CatchUp
+		want     []string // пусто — ничего не отправлено
+	}{
+		{
+			name:     "по умолчанию — с пометкой об опоздании",
+			reminder: daily(),
+			want:     []string{"⏰ Напоминание с опозданием (должно было прийти 08.06.2025 в 09:00): планёрка"},
+		},
+		{
+			name:     "пропуск",
+			reminder: daily(),
+			catchUp:  domain.CatchUp{Policy: domain.CatchUpSkip},
+		},
+		{
+			name:     "сводка",
+			reminder: daily(),
+			catchUp:  domain.CatchUp{Policy: domain.CatchUpSummary},
+			want: []string{"⏰ Напоминание: планёрка\n\n⚠️ Пока бот был недоступен, пропущено срабатываний: 3 — " +
+				"с 08.06.2025 в 09:00 по 10.06.2025 в 09:00."},
+		},
+		{
+			name:     "опоздание меньше порога",
+			reminder: daily(),
+			catchUp:  domain.CatchUp{Policy: domain.CatchUpSkip, After: 72 * time.Hour},
+			want:     []string{"⏰ Напоминание: планёрка"},
+		},
+		{
+			name: "разовое в сводке — как опоздавшее",
+			reminder: &domain.Reminder{
+				ID: 1, ChatID: 100, Text: "позвонить",
+				NextTime: time.Date(2025, time.June, 10, 10, 30, 0, 0, loc).UTC(),
+			},
+			catchUp: domain.CatchUp{Policy: domain.CatchUpSummary},
+			want:    []string{"⏰ Напоминание с опозданием (должно было прийти 10.06.2025 в 10:30): позвонить"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newStubReminderUC(tt.reminder)
+			bot := &stubSender{}
+			s := NewScheduler(bot, uc, &stubChatUC{loc: loc, catchUp: tt.catchUp}, testDelivery)
+			s.nowFunc = func() time.Time { return now }
+
+			s.deliverDue(context.Background())
+
+			var got []string
+			for _, m := range bot.messages() {
+				got = append(got, m.text)
+			}
+			assert.Equal(t, tt.want, got)
+
+			// Как бы ни была оформлена доставка, серия уходит в будущее, а разовое удаляется.
+			if stored := uc.get(1); stored != nil {
+				assert.Equal(t, time.Date(2025, time.June, 11, 9, 0, 0, 0, loc).UTC(), stored.NextTime)
+			} else {
+				assert.Equal(t, domain.RepeatNone, tt.reminder.Repeat)
+			}
+		})
+	}
+}
+
+func TestDeliverDue_QuietHours(t *testing.T) {
+	loc := berlin(t)
+	night := domain.QuietHours{Start: 23 * 60, End: 8 * 60}
+	// Каждые полчаса: за ночь набралось бы шестнадцать уведомлений.
+	halfHourly := func() *domain.Reminder {
+		return &domain.Reminder{
+			ID: 1, ChatID: 100, Text: "проверить сервер",
+			NextTime:        time.Date(2025, time.June, 10, 23, 30, 0, 0, loc).UTC(),
+			Repeat:          domain.RepeatInterval,
+			IntervalMinutes: 30,
+		}
+	}
+
+	t.Run("откладывается до конца тихих часов", func(t *testing.T) {
+		uc := newStubReminderUC(halfHourly())
+		bot := &stubSender{}
+		s := NewScheduler(bot, uc, &stubChatUC{loc: loc, quiet: night}, testDelivery)
+		now := time.Date(2025, time.June, 10, 23, 30, 20, 0, loc)
+		s.nowFunc = func() time.Time { return now }
+
+		s.deliverDue(context.Background())
+		assert.Empty(t, bot.messages())
+
+		stored := uc.get(1)
+		require.NotNil(t, stored)
+		assert.Equal(t, time.Date(2025, time.June, 11, 8, 0, 0, 0, loc).UTC(), stored.NextTime)
+		assert.Equal(t, time.Date(2025, time.June, 10, 23, 30, 0, 0, loc).UTC(), stored.ShiftedFrom)
+
+		// Утром приходит одно сообщение, а серия продолжается по своей сетке.
+		now = time.Date(2025, time.June, 11, 8, 0, 10, 0, loc)
+		s.deliverDue(context.Background())
+
+		sent := bot.messages()
+		require.Len(t, sent, 1)
+		assert.False(t, sent[0].silent)
+		stored = uc.get(1)
+		assert.Equal(t, time.Date(2025, time.June, 11, 8, 30, 0, 0, loc).UTC(), stored.NextTime)
+		assert.True(t, stored.ShiftedFrom.IsZero())
+	})
+
+	t.Run("без звука", func(t *testing.T) {
+		uc := newStubReminderUC(halfHourly())
+		bot := &stubSender{}
+		quiet := night
+		quiet.Mode = domain.QuietSilent
+		s := NewScheduler(bot, uc, &stubChatUC{loc: loc, quiet: quiet}, testDelivery)
+		s.nowFunc = func() time.Time { return time.Date(2025, time.June, 10, 23, 30, 20, 0, loc) }
+
+		s.deliverDue(context.Background())
+
+		sent := bot.messages()
+		require.Len(t, sent, 1)
+		assert.True(t, sent[0].silent)
+		assert.Equal(t, time.Date(2025, time.June, 11, 0, 0, 0, 0, loc).UTC(), uc.get(1).NextTime)
+	})
+
+	t.Run("серия кончается раньше тихих часов", func(t *testing.T) {
+		rem := halfHourly()
+		rem.EndsAt = time.Date(2025, time.June, 10, 23, 59, 0, 0, loc).UTC()
+		uc := newStubReminderUC(rem)
+		bot := &stubSender{}
+		s := NewScheduler(bot, uc, &stubChatUC{loc: loc, quiet: night}, testDelivery)
+		s.nowFunc = func() time.Time { return time.Date(2025, time.June, 10, 23, 30, 20, 0, loc) }
+
+		s.deliverDue(context.Background())
+
+		sent := bot.messages()
+		require.Len(t, sent, 1)
+		assert.True(t, sent[0].silent, "a message that cannot wait is sent silently")
+	})
+}
+
+func TestDeliverDue_NagsUntilAcknowledged(t *testing.T) {
+	loc := berlin(t)
+	start := time.Date(2025, time.June, 10, 9, 0, 30, 0, loc)
+	uc := newStubReminderUC(&domain.Reminder{
+		ID: 1, ChatID: 100, Text: "принять таблетку",
+		NextTime: time.Date(2025, time.June, 10, 9, 0, 0, 0, loc).UTC(), Repeat: domain.RepeatEveryDay,
+		NagEveryMinutes: 15, NagMax: 2,
+	})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{loc: loc}, testDelivery)
+	at := func(minutes int) {
+		s.nowFunc = func() time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+		s.deliverDue(context.Background())
+	}
+
+	at(0)
+	sent := bot.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "1", sent[0].ackID, "acknowledged delivery carries the Done button")
+	assert.False(t, sent[0].snooze)
+	ack := uc.ack(1)
+	require.NotNil(t, ack)
+	assert.Equal(t, 1, ack.MessageID)
+
+	at(10)
+	assert.Len(t, bot.messages(), 1, "nag waits for its interval")
+
+	at(15)
+	at(30)
+	at(45)
+	sent = bot.messages()
+	require.Len(t, sent, 3, "nags stop at the limit")
+	assert.Equal(t, "🔁 Напоминание (повтор 1 из 2): принять таблетку", sent[1].text)
+	assert.Equal(t, "1", sent[2].ackID)
+	assert.True(t, uc.ack(1).NextNagAt.IsZero())
+
+	history := uc.attempts()
+	require.Len(t, history, 3)
+	nag := history[1]
+	assert.Equal(t, domain.DeliveryNag, nag.Kind)
+	assert.Equal(t, sent[1].text, nag.Text)
+	assert.Equal(t, 2, nag.MessageID)
+	assert.Equal(t, start.Add(15*time.Minute).UTC(), nag.ScheduledAt)
+}
+
+func TestDeliverDue_NagRespectsQuietHours(t *testing.T) {
+	loc := berlin(t)
+	now := time.Date(2025, time.June, 10, 23, 10, 0, 0, loc)
+	uc := newStubReminderUC()
+	uc.acks[1] = &domain.Acknowledgement{
+		ID: 1, ChatID: 100, Text: "закрыть смену", MessageID: 5, NagMax: 3, NagEveryMinutes: 30,
+		NextNagAt: now.Add(-time.Minute).UTC(),
+	}
+	bot := &stubSender{}
+	chatUC := &stubChatUC{loc: loc, quiet: domain.QuietHours{Start: 23 * 60, End: 8 * 60}}
+	s := NewScheduler(bot, uc, chatUC, testDelivery)
+	s.nowFunc = func() time.Time { return now }
+
+	s.deliverDue(context.Background())
+
+	assert.Empty(t, bot.messages())
+	assert.Equal(t, time.Date(2025, time.June, 11, 8, 0, 0, 0, loc).UTC(), uc.ack(1).NextNagAt)
+	assert.Zero(t, uc.ack(1).Nags)
+}
+
+func TestDeliverDue_SendsAdvanceNotices(t *testing.T) {
+	loc := berlin(t)
+	meeting := time.Date(2025, time.June, 13, 10, 0, 0, 0, loc)
+	rem := &domain.Reminder{
+		ID: 1, ChatID: 100, Text: "сдать отчёт",
+		NextTime: meeting.UTC(), Repeat: domain.RepeatEveryNDays, RepeatEvery: 7,
+		LeadMinutes: []int{3 * domain.MinutesPerDay, 60},
+	}
+	rem.NoticeAt = scheduling.NextNotice(rem, meeting.Add(-4*24*time.Hour))
+	uc := newStubReminderUC(rem)
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{loc: loc}, testDelivery)
+	var now time.Time
+	s.nowFunc = func() time.Time { return now }
+	uc.now = s.nowFunc
+	at := func(t time.Time) {
+		now = t
+		s.deliverDue(context.Background())
+	}
+
+	// До первого предупреждения ничего не приходит.
+	at(time.Date(2025, time.June, 10, 9, 0, 20, 0, loc))
+	assert.Empty(t, bot.messages())
+	at(meeting.Add(-72 * time.Hour).Add(20 * time.Second))
+	sent := bot.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "🔔 Напоминание через 3 дня: сдать отчёт", sent[0].text)
+	assert.False(t, sent[0].snooze)
+	assert.Equal(t, meeting.Add(-time.Hour).UTC(), uc.get(1).NoticeAt)
+
+	at(meeting.Add(-time.Hour).Add(20 * time.Second))
+	at(meeting.Add(20 * time.Second))
+	sent = bot.messages()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "🔔 Напоминание через 1 час: сдать отчёт", sent[1].text)
+	assert.Equal(t, "⏰ Напоминание: сдать отчёт", sent[2].text)
+
+	// Следующее срабатывание получает свои предупреждения.
+	next := meeting.AddDate(0, 0, 7)
+	assert.Equal(t, next.UTC(), uc.get(1).NextTime)
+	assert.Equal(t, next.Add(-72*time.Hour).UTC(), uc.get(1).NoticeAt)
+
+	// После простоя опоздавшее предупреждение не отправляется, но следующее назначается.
+	at(next.Add(-69 * time.Hour))
+	assert.Len(t, bot.messages(), 3)
+	assert.Equal(t, next.Add(-time.Hour).UTC(), uc.get(1).NoticeAt)
+}
+
 func TestDeliverDue_KickedBotFreezesChat(t *testing.T) {
 	now := time.Date(2025, time.June, 10, 9, 0, 30, 0, time.UTC)
 	uc := newStubReminderUC(&domain.Reminder{
@@ -324,7 +1240,7 @@ func TestDeliverDue_KickedBotFreezesChat(t *testing.T) {
 	})
 	bot := &stubSender{err: tele.ErrKickedFromSuperGroup}
 	chatUC := &stubChatUC{}
-	s := NewScheduler(bot, uc, chatUC)
+	s := NewScheduler(bot, uc, chatUC, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -347,7 +1263,7 @@ func TestDeliverDue_HandlesBatch(t *testing.T) {
 
 	uc := newStubReminderUC(reminders...)
 	bot := &stubSender{}
-	s := NewScheduler(bot, uc, &stubChatUC{})
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
 	s.nowFunc = func() time.Time { return now }
 
 	s.deliverDue(context.Background())
@@ -358,7 +1274,7 @@ func TestDeliverDue_HandlesBatch(t *testing.T) {
 // Run обязан завершаться по отмене контекста, иначе процесс не остановится
 // по SIGTERM.
 func TestRun_StopsOnContextCancel(t *testing.T) {
-	s := NewScheduler(&stubSender{}, newStubReminderUC(), &stubChatUC{})
+	s := NewScheduler(&stubSender{}, newStubReminderUC(), &stubChatUC{}, testDelivery)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
@@ -376,3 +1292,87 @@ func TestRun_StopsOnContextCancel(t *testing.T) {
 		t.Fatal("scheduler did not stop on context cancellation")
 	}
 }
+
+func TestResync_PrunesHistory(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 0, 0, time.UTC)
+	uc := newStubReminderUC()
+	cfg := config.DeliveryConfig{RetryDeadline: time.Hour, HistoryRetention: 30 * 24 * time.Hour}
+	s := NewScheduler(&stubSender{}, uc, &stubChatUC{}, cfg)
+	s.nowFunc = func() time.Time { return now }
+
+	s.resync(context.Background(), now)
+	assert.Equal(t, now.Add(-cfg.HistoryRetention), uc.prunedBefore)
+
+	// Между чистками проходит не меньше pruneInterval.
+	s.resync(context.Background(), now.Add(resyncInterval))
+	assert.Equal(t, now.Add(-cfg.HistoryRetention), uc.prunedBefore)
+
+	s.resync(context.Background(), now.Add(pruneInterval))
+	assert.Equal(t, now.Add(pruneInterval-cfg.HistoryRetention), uc.prunedBefore)
+}
+
+func TestFairOrder(t *testing.T) {
+	var deliveries []*domain.Delivery
+	for i, chatID := range []int64{1, 1, 1, 2, 3, 3} {
+		deliveries = append(deliveries, &domain.Delivery{ID: int64(i + 1), ChatID: chatID})
+	}
+
+	var ids []int64
+	for _, d := range fairOrder(deliveries) {
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []int64{1, 4, 5, 2, 6, 3}, ids)
+}
+
+func TestRun_DeliversOnTime(t *testing.T) {
+	due := time.Now().Add(200 * time.Millisecond)
+	uc := newStubReminderUC(&domain.Reminder{ID: 1, ChatID: 100, Text: "созвон", NextTime: due})
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.Run(ctx)
+
+	require.Eventually(t, func() bool { return len(bot.messages()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.WithinDuration(t, due, time.Now(), time.Second, "the reminder fires on the second, not on a poll")
+	assert.Nil(t, uc.get(1))
+}
+
+func TestRun_WakesOnScheduleChange(t *testing.T) {
+	uc := newStubReminderUC()
+	bot := &stubSender{}
+	s := NewScheduler(bot, uc, &stubChatUC{}, testDelivery)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.Run(ctx)
+
+	// Напоминание появляется после сверки с базой: о нём планировщик узнаёт только из WakeAt.
+	time.Sleep(50 * time.Millisecond)
+	due := time.Now().Add(100 * time.Millisecond)
+	uc.mu.Lock()
+	uc.reminders[1] = &domain.Reminder{ID: 1, ChatID: 100, Text: "созвон", NextTime: due}
+	uc.mu.Unlock()
+	s.WakeAt(1, due)
+
+	require.Eventually(t, func() bool { return len(bot.messages()) == 1 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestResync_ReadsAheadOfTruncatedBatch(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 0, 0, time.UTC)
+	var reminders []*domain.Reminder
+	for i := range resyncLimit + 1 {
+		reminders = append(reminders, &domain.Reminder{
+			ID: int64(i + 1), ChatID: 100, NextTime: now.Add(time.Duration(i+1) * 100 * time.Millisecond),
+		})
+	}
+	s := NewScheduler(&stubSender{}, newStubReminderUC(reminders...), &stubChatUC{}, testDelivery)
+
+	next := s.resync(context.Background(), now)
+	assert.Equal(t, now.Add(resyncLimit*100*time.Millisecond), next,
+		"the next resync comes before the queue runs dry")
+	at, ok := s.wakeups.next()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(100*time.Millisecond), at)
+}
@@ -1,8 +1,11 @@
 package session
 
 import (
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 )
 
 // AddReminderStep описывает шаг мастера добавления напоминания.
@@ -10,14 +13,18 @@ type AddReminderStep int
 
 // Возможные шаги мастера добавления напоминания.
 const (
-	StepNone     AddReminderStep = iota // начальное состояние
-	StepType                            // выбор типа
-	StepTime                            // ввод времени
-	StepText                            // ввод текста
-	StepInterval                        // ввод интервала
-	StepDate                            // ввод даты
-	StepConfirm                         // подтверждение
-	StepTimezone                        // ввод таймзоны
+	StepNone           AddReminderStep = iota // начальное состояние
+	StepType                                  // выбор типа
+	StepTime                                  // ввод времени
+	StepText                                  // ввод текста
+	StepInterval                              // ввод интервала
+	StepDate                                  // ввод даты
+	StepConfirm                               // подтверждение
+	StepTimezone                              // ввод таймзоны
+	StepWindow                                // ввод окна интервального повтора
+	StepWeekdays                              // выбор дней недели интервального повтора
+	StepSnooze                                // ввод своего времени для отложенного напоминания
+	StepTimezoneRebase                        // выбор пересчёта напоминаний при смене таймзоны
 )
 
 // sessionTTL — срок жизни брошенного мастера.
@@ -34,8 +41,19 @@ type AddReminderSession struct {
 	Type     string // today, tomorrow, everyday, etc
 	Time     string // 15:00
 	Date     string // 13.06.2025
-	Interval int    // N дней
-	Text     string // текст напоминания
+	Interval int    // N дней; у интервального повтора — шаг в минутах
+	Ordinal  int    // номер дня недели в месяце: 1..4 или -1 для последнего
+	Weekdays []int  // дни недели для ежемесячного повтора по номеру и интервального
+	Every    int    // шаг «раз в N недель/месяцев/лет»; 0 — каждую
+	// WindowStart и WindowEnd — окно в минутах от полуночи: часы интервального повтора
+	// или, у остальных типов, окно случайного времени вместо времени по часам.
+	WindowStart int
+	WindowEnd   int
+	Text        string // текст напоминания
+	// Draft — напоминание, разобранное из фразы после /add; ждёт подтверждения на шаге StepConfirm.
+	Draft *domain.Reminder
+	// Timezone — новый часовой пояс чата; ждёт выбора пересчёта на шаге StepTimezoneRebase.
+	Timezone string
 }
 
 type sessionKey struct {
@@ -82,6 +100,8 @@ func (sm *Manager) Get(chatID, userID int64) *AddReminderSession {
 	}
 
 	copied := entry.session
+	copied.Weekdays = slices.Clone(entry.session.Weekdays)
+	copied.Draft = cloneDraft(entry.session.Draft)
 
 	return &copied
 }
@@ -92,8 +112,11 @@ func (sm *Manager) Set(s *AddReminderSession) {
 	defer sm.mu.Unlock()
 
 	sm.evictExpiredLocked()
+	stored := *s
+	stored.Weekdays = slices.Clone(s.Weekdays)
+	stored.Draft = cloneDraft(s.Draft)
 	sm.sessions[sessionKey{chatID: s.ChatID, userID: s.UserID}] = sessionEntry{
-		session:   *s,
+		session:   stored,
 		expiresAt: sm.now().Add(sessionTTL),
 	}
 }
@@ -122,3 +145,16 @@ func (sm *Manager) evictExpiredLocked() {
 		}
 	}
 }
+
+// cloneDraft копирует черновик вместе со срезами, чтобы правка копии не задевала хранимый.
+func cloneDraft(r *domain.Reminder) *domain.Reminder {
+	if r == nil {
+		return nil
+	}
+	copied := *r
+	copied.RepeatDays = slices.Clone(r.RepeatDays)
+	copied.Times = slices.Clone(r.Times)
+	copied.LeadMinutes = slices.Clone(r.LeadMinutes)
+
+	return &copied
+}
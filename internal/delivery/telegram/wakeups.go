@@ -0,0 +1,128 @@
+package telegram
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// wakeKey — запись, ради которой планировщик просыпается.
+type wakeKey struct {
+	kind domain.WakeupKind
+	id   int64
+}
+
+type wakeItem struct {
+	key   wakeKey
+	at    time.Time
+	index int
+}
+
+// wakeHeap — min-куча по времени пробуждения для container/heap.
+type wakeHeap []*wakeItem
+
+func (h wakeHeap) Len() int           { return len(h) }
+func (h wakeHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+
+func (h wakeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *wakeHeap) Push(x any) {
+	item := x.(*wakeItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *wakeHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+// wakeQueue — ближайшие моменты, когда у планировщика есть работа: у каждой записи
+// не больше одного. Очередь только будит планировщик, а что именно наступило, он
+// по-прежнему читает из базы, поэтому лишнее пробуждение безвредно.
+type wakeQueue struct {
+	mu    sync.Mutex
+	items wakeHeap
+	byKey map[wakeKey]*wakeItem
+}
+
+func newWakeQueue() *wakeQueue {
+	return &wakeQueue{byKey: make(map[wakeKey]*wakeItem)}
+}
+
+// set назначает записи новое время пробуждения; нулевое at снимает её с очереди.
+func (q *wakeQueue) set(key wakeKey, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.put(key, at)
+}
+
+// merge добавляет время, прочитанное из базы. Из двух времён остаётся раннее: чтение
+// могло начаться до правки, о которой очередь уже знает, и опоздать нельзя, а проснуться
+// зря — можно.
+func (q *wakeQueue) merge(key wakeKey, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if item, ok := q.byKey[key]; !ok || at.Before(item.at) {
+		q.put(key, at)
+	}
+}
+
+func (q *wakeQueue) put(key wakeKey, at time.Time) {
+	item, ok := q.byKey[key]
+	if at.IsZero() {
+		if ok {
+			heap.Remove(&q.items, item.index)
+			delete(q.byKey, key)
+		}
+		return
+	}
+
+	if ok {
+		item.at = at
+		heap.Fix(&q.items, item.index)
+		return
+	}
+	item = &wakeItem{key: key, at: at}
+	heap.Push(&q.items, item)
+	q.byKey[key] = item
+}
+
+// popDue снимает с очереди наступившие к now записи и возвращает, сколько их было.
+func (q *wakeQueue) popDue(now time.Time) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var n int
+	for len(q.items) > 0 && !q.items[0].at.After(now) {
+		item := heap.Pop(&q.items).(*wakeItem)
+		delete(q.byKey, item.key)
+		n++
+	}
+
+	return n
+}
+
+// next возвращает ближайшее время в очереди.
+func (q *wakeQueue) next() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return time.Time{}, false
+	}
+
+	return q.items[0].at, true
+}
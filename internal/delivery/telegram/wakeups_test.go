@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWakeQueue(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 9, 0, 0, 0, time.UTC)
+	reminder := wakeKey{kind: domain.WakeReminder, id: 1}
+	ack := wakeKey{kind: domain.WakeAck, id: 1}
+	q := newWakeQueue()
+
+	q.set(reminder, now.Add(time.Hour))
+	q.set(ack, now.Add(2*time.Hour))
+	q.set(reminder, now.Add(3*time.Hour))
+	at, ok := q.next()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(2*time.Hour), at, "set replaces the previous time")
+
+	q.merge(reminder, now.Add(4*time.Hour))
+	q.merge(ack, now.Add(time.Minute))
+	at, _ = q.next()
+	assert.Equal(t, now.Add(time.Minute), at, "merge keeps the earlier time")
+
+	q.set(ack, time.Time{})
+	at, _ = q.next()
+	assert.Equal(t, now.Add(3*time.Hour), at, "a zero time removes the entry")
+
+	assert.Zero(t, q.popDue(now.Add(time.Hour)))
+	assert.Equal(t, 1, q.popDue(now.Add(3*time.Hour)))
+	_, ok = q.next()
+	assert.False(t, ok)
+}
@@ -79,6 +79,7 @@ function makeHarness(reminderResponses) {
   const frames = [];
   const buttonCalls = [];
   const fetchCalls = [];
+  const alerts = [];
   const telegramEvents = new Map();
   let readyCount = 0;
 
@@ -111,7 +112,9 @@ function makeHarness(reminderResponses) {
     HapticFeedback: {
       notificationOccurred() {},
     },
-    showAlert() {},
+    showAlert(message) {
+      alerts.push(message);
+    },
     showConfirm(_message, callback) {
       callback(false);
     },
@@ -129,6 +132,10 @@ function makeHarness(reminderResponses) {
     '/api/v1/timezones': {
       timezones: ['Europe/Moscow', 'UTC'],
     },
+    '/api/v1/chats/-1002/calendar': {
+      workweek: [1, 2, 3, 4, 5],
+      days: [{ date: '2026-01-01', working: false }],
+    },
     ...reminderResponses,
   };
 
@@ -190,6 +197,7 @@ function makeHarness(reminderResponses) {
   vm.runInContext(appSource, context, { filename: 'app.js' });
 
   return {
+    alerts,
     buttonCalls,
     context,
     elements,
@@ -302,3 +310,253 @@ test('date formatting falls back when the chat timezone is unknown', () => {
     assert.equal(vm.runInContext(expression, harness.context), true);
   }
 });
+
+test('settings load the chat calendar and reminders describe workday policy', async () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+
+  await eventually(
+    () => harness.buttonCalls.some((call) => call.operation === 'hide'),
+    'bootstrap did not start forced synchronization',
+  );
+  harness.flushFrame();
+  await eventually(
+    () => harness.fetchCalls.includes('/api/v1/chats/-1002/calendar'),
+    'settings did not request the chat calendar',
+  );
+  await eventually(
+    () => harness.elements.get('calendar-summary').textContent !== '',
+    'calendar summary was not rendered',
+  );
+  assert.equal(
+    harness.elements.get('calendar-summary').textContent,
+    'Праздников: 1, рабочих переносов: 0',
+  );
+
+  assert.equal(
+    vm.runInContext(`describeRepeat({ repeat: 'daily', workdays: 'next' })`, harness.context),
+    'каждый день, с нерабочих дней — на следующий рабочий',
+  );
+});
+
+test('settings show the chat quiet hours', async () => {
+  const harness = makeHarness({
+    '/api/v1/me': {
+      user: { id: 42 },
+      chats: [{
+        id: -1002,
+        title: 'Команда',
+        is_group: true,
+        quiet_hours: { start: '23:00', end: '08:00', mode: 'silent' },
+      }],
+      launch_chat_id: -1002,
+    },
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+
+  await eventually(
+    () => harness.buttonCalls.some((call) => call.operation === 'hide'),
+    'bootstrap did not start forced synchronization',
+  );
+  harness.flushFrame();
+  await eventually(
+    () => harness.elements.get('field-quiet-start').value !== '',
+    'quiet hours were not filled in',
+  );
+  assert.equal(harness.elements.get('field-quiet-start').value, '23:00');
+  assert.equal(harness.elements.get('field-quiet-end').value, '08:00');
+  assert.equal(harness.elements.get('field-quiet-mode').value, 'silent');
+});
+
+test('saving settings reports how many reminders the time zone change rebased', async () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: 'Europe/Moscow', reminders: [] },
+    '/api/v1/chats/-1002/timezone': { timezone: 'Europe/Moscow', rebased: 3 },
+    '/api/v1/chats/-1002/catch-up': { catch_up: 'late', catch_up_after_minutes: 10 },
+    '/api/v1/chats/-1002/settings': { quiet_hours: null },
+  });
+
+  await eventually(
+    () => harness.buttonCalls.some((call) => call.operation === 'hide'),
+    'bootstrap did not start forced synchronization',
+  );
+  harness.flushFrame();
+
+  await vm.runInContext('saveSettings()', harness.context);
+  assert.equal(harness.elements.get('settings-error').hidden, true);
+  assert.deepEqual(harness.alerts, ['Пересчитано напоминаний: 3']);
+
+  // Форма напоминания счётчик не показывает и сохраняется без ошибок.
+  harness.alerts.length = 0;
+  harness.elements.get('field-text').value = 'выпить воды';
+  harness.elements.get('field-repeat').value = 'daily';
+  harness.elements.get('field-time').value = '09:00';
+  await vm.runInContext('submitForm()', harness.context);
+  assert.equal(harness.elements.get('form-error').hidden, true);
+  assert.deepEqual(harness.alerts, []);
+});
+
+test('reminder form collects the acknowledgement mode', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+
+  assert.equal(
+    run(`describeRepeat({ repeat: 'daily', nag_every_minutes: 15, nag_max: 4 })`),
+    'каждый день, до подтверждения: каждые 15 мин, максимум 4',
+  );
+
+  harness.elements.get('field-nag-every').value = '';
+  assert.equal(run('JSON.stringify(collectNag())'), '{"nag_every_minutes":0,"nag_max":0}');
+
+  harness.elements.get('field-nag-every').value = '30';
+  harness.elements.get('field-nag-max').value = '';
+  assert.equal(run('JSON.stringify(collectNag())'), '{"nag_every_minutes":30,"nag_max":3}');
+
+  harness.elements.get('field-nag-max').value = '50';
+  assert.throws(() => run('collectNag()'), /от 1 до 20/);
+});
+
+test('reminder form collects advance notices', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+
+  assert.equal(
+    run(`describeRepeat({ repeat: 'daily', lead_minutes: [4320, 90, 60] })`),
+    'каждый день, заранее за 3д, 90м, 1ч',
+  );
+
+  harness.elements.get('field-lead').value = '';
+  assert.equal(run('JSON.stringify(collectLeads())'), '{"lead_minutes":[]}');
+
+  harness.elements.get('field-lead').value = '3Д; 1 ч, 30';
+  assert.equal(run('JSON.stringify(collectLeads())'), '{"lead_minutes":[4320,60,30]}');
+
+  harness.elements.get('field-lead').value = '31д';
+  assert.throws(() => run('collectLeads()'), /не раньше чем за 30 дней/);
+  harness.elements.get('field-lead').value = 'завтра';
+  assert.throws(() => run('collectLeads()'), /через запятую/);
+});
+
+test('reminder form sends a random window instead of the time', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+  const field = (id, value) => {
+    harness.elements.get(id).value = value;
+  };
+
+  assert.equal(
+    run(`describeRepeat({ repeat: 'daily', window_start: '10:00', window_end: '12:00' })`),
+    'каждый день, в случайное время 10:00–12:00',
+  );
+
+  field('field-text', 'записать настроение');
+  field('field-repeat', 'daily');
+  field('field-time', '');
+  field('field-window-start', '10:00');
+  field('field-window-end', '12:00');
+  const payload = JSON.parse(run('JSON.stringify(collectFormPayload())'));
+  assert.equal(payload.window_start, '10:00');
+  assert.equal(payload.window_end, '12:00');
+  assert.equal(payload.time, undefined);
+
+  field('field-window-start', '');
+  field('field-window-end', '');
+  assert.throws(() => run('collectFormPayload()'), /Укажите время/);
+});
+
+test('reminder form collects the solar event and offset', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+
+  assert.equal(
+    run(`describeRepeat({ repeat: 'solar', solar_event: 'sunset', solar_offset: -30 })`),
+    'каждый день за 30 мин до заката',
+  );
+  assert.equal(
+    run(`describeRepeat({ repeat: 'solar', solar_event: 'sunrise', solar_offset: 60 })`),
+    'каждый день через 1 ч после восхода',
+  );
+  assert.equal(run(`describeRepeat({ repeat: 'solar', solar_event: 'dusk' })`), 'каждый день в вечерние сумерки');
+
+  harness.elements.get('field-solar-event').value = 'sunset';
+  harness.elements.get('field-solar-offset').value = '-45';
+  assert.equal(run('JSON.stringify(collectSolar())'), '{"solar_event":"sunset","solar_offset":-45}');
+
+  harness.elements.get('field-solar-offset').value = '400';
+  assert.throws(() => run('collectSolar()'), /от -360 до 360/);
+});
+
+test('reminders with their own timezone show the chat time too', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: 'Europe/Moscow', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+  run(`state.timezone = 'Europe/Moscow'`);
+
+  const iso = '2026-06-12T13:00:00Z';
+  assert.equal(run(`describeNextTime({ next_time: '${iso}' })`), run(`formatDateTime('${iso}', 'Europe/Moscow')`));
+  assert.equal(
+    run(`describeNextTime({ next_time: '${iso}', timezone: 'America/New_York' })`),
+    run(`formatDateTime('${iso}', 'America/New_York')`) + ' (America/New_York), в чате '
+      + run(`formatDateTime('${iso}', 'Europe/Moscow')`),
+  );
+});
+
+test('reminder form sends a follow-up instead of its own schedule', () => {
+  const harness = makeHarness({
+    '/api/v1/chats/-1002/reminders': { timezone: '', reminders: [] },
+  });
+  const run = (expression) => vm.runInContext(expression, harness.context);
+  const field = (id, value) => {
+    harness.elements.get(id).value = value;
+  };
+  run(`state.reminders = [{ id: 7, text: 'полить рассаду' }]`);
+
+  assert.equal(
+    run(`describeRepeat({ parent_id: 7, follow_offset_minutes: 4320, follow_on: 'acknowledged' })`),
+    'через 3д после «Готово» «полить рассаду»',
+  );
+  assert.equal(run(`describeRepeat({ parent_id: 9 })`), 'сразу после удалённого напоминания');
+  assert.equal(run(`describeNextTime({ waiting: true, next_time: '2026-06-12T13:00:00Z' })`), 'ждёт срабатывания');
+
+  field('field-text', 'подкормить');
+  field('field-parent', '7');
+  field('field-follow-offset', '3д');
+  field('field-follow-on', 'delivered');
+  field('field-lead', '');
+  field('field-nag-every', '');
+  run('syncFormFields()');
+  assert.equal(harness.elements.get('field-follow-wrap').hidden, false);
+  assert.equal(harness.elements.get('field-time-wrap').hidden, true);
+
+  const payload = JSON.parse(run('JSON.stringify(collectFormPayload())'));
+  assert.equal(payload.repeat, 'none');
+  assert.equal(payload.parent_id, 7);
+  assert.equal(payload.follow_offset_minutes, 4320);
+  assert.equal(payload.follow_on, 'delivered');
+  assert.equal(payload.time, undefined);
+
+  field('field-follow-offset', '91д');
+  assert.throws(() => run('collectFormPayload()'), /до 90 дней/);
+
+  // Отвязанное продолжение снова получает своё расписание.
+  run(`state.editing = { id: 8, parent_id: 7 }`);
+  field('field-parent', '');
+  field('field-repeat', 'daily');
+  field('field-time', '09:00');
+  field('field-window-start', '');
+  field('field-window-end', '');
+  field('field-times', '');
+  field('field-ends', '');
+  field('field-count', '');
+  assert.equal(JSON.parse(run('JSON.stringify(collectFormPayload())')).parent_id, 0);
+});
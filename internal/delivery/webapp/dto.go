@@ -1,7 +1,11 @@
 package webapp
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
@@ -18,8 +22,118 @@ const (
 	repeatMonthly   = "monthly"
 	repeatEveryDays = "every_n_days"
 	repeatYearly    = "yearly"
+	repeatRRule     = "rrule"
+	repeatInterval  = "interval"
+	repeatCron      = "cron"
+	repeatSolar     = "solar"
 )
 
+// Строковые обозначения SolarEvent.
+const (
+	solarSunrise = "sunrise"
+	solarSunset  = "sunset"
+	solarDawn    = "dawn"
+	solarDusk    = "dusk"
+)
+
+var solarToAPI = map[domain.SolarEvent]string{
+	domain.SolarSunrise: solarSunrise,
+	domain.SolarSunset:  solarSunset,
+	domain.SolarDawn:    solarDawn,
+	domain.SolarDusk:    solarDusk,
+}
+
+var apiToSolar = map[string]domain.SolarEvent{
+	solarSunrise: domain.SolarSunrise,
+	solarSunset:  domain.SolarSunset,
+	solarDawn:    domain.SolarDawn,
+	solarDusk:    domain.SolarDusk,
+}
+
+// Строковые обозначения WorkdayPolicy; пустая строка — нерабочие дни не учитываются.
+const (
+	workdaysSkip     = "skip"
+	workdaysPrevious = "previous"
+	workdaysNext     = "next"
+)
+
+var workdaysToAPI = map[domain.WorkdayPolicy]string{
+	domain.WorkdaySkip:     workdaysSkip,
+	domain.WorkdayPrevious: workdaysPrevious,
+	domain.WorkdayNext:     workdaysNext,
+}
+
+var apiToWorkdays = map[string]domain.WorkdayPolicy{
+	"":               domain.WorkdayAny,
+	workdaysSkip:     domain.WorkdaySkip,
+	workdaysPrevious: domain.WorkdayPrevious,
+	workdaysNext:     domain.WorkdayNext,
+}
+
+// Строковые обозначения TimezoneRebase; пустая строка — время по часам.
+const (
+	rebaseWallClock = "wall_clock"
+	rebaseInstant   = "instant"
+)
+
+var apiToRebase = map[string]domain.TimezoneRebase{
+	"":              domain.RebaseWallClock,
+	rebaseWallClock: domain.RebaseWallClock,
+	rebaseInstant:   domain.RebaseInstant,
+}
+
+// Строковые обозначения FollowTrigger.
+const (
+	followDelivered    = "delivered"
+	followAcknowledged = "acknowledged"
+)
+
+var followToAPI = map[domain.FollowTrigger]string{
+	domain.FollowOnDelivery: followDelivered,
+	domain.FollowOnAck:      followAcknowledged,
+}
+
+var apiToFollow = map[string]domain.FollowTrigger{
+	followDelivered:    domain.FollowOnDelivery,
+	followAcknowledged: domain.FollowOnAck,
+}
+
+// Строковые обозначения CatchUpPolicy.
+const (
+	catchUpLate    = "late"
+	catchUpSkip    = "skip"
+	catchUpSummary = "summary"
+)
+
+var catchUpToAPI = map[domain.CatchUpPolicy]string{
+	domain.CatchUpLate:    catchUpLate,
+	domain.CatchUpSkip:    catchUpSkip,
+	domain.CatchUpSummary: catchUpSummary,
+}
+
+var apiToCatchUp = map[string]domain.CatchUpPolicy{
+	catchUpLate:    domain.CatchUpLate,
+	catchUpSkip:    domain.CatchUpSkip,
+	catchUpSummary: domain.CatchUpSummary,
+}
+
+// Строковые обозначения QuietMode.
+const (
+	quietDefer  = "defer"
+	quietSilent = "silent"
+)
+
+var quietToAPI = map[domain.QuietMode]string{
+	domain.QuietDefer:  quietDefer,
+	domain.QuietSilent: quietSilent,
+}
+
+var apiToQuiet = map[string]domain.QuietMode{
+	"":          domain.QuietDefer,
+	quietDefer:  domain.QuietDefer,
+	quietSilent: domain.QuietSilent,
+}
+
 // Типы чатов Telegram, используемые в API.
 const (
 	chatTypePrivate = "private"
@@ -33,6 +147,10 @@ var repeatToAPI = map[domain.RepeatType]string{
 	domain.RepeatEveryMonth: repeatMonthly,
 	domain.RepeatEveryNDays: repeatEveryDays,
 	domain.RepeatEveryYear:  repeatYearly,
+	domain.RepeatRRule:      repeatRRule,
+	domain.RepeatInterval:   repeatInterval,
+	domain.RepeatCron:       repeatCron,
+	domain.RepeatSolar:      repeatSolar,
 }
 
 var apiToRepeat = map[string]domain.RepeatType{
@@ -42,6 +160,10 @@ var apiToRepeat = map[string]domain.RepeatType{
 	repeatMonthly:   domain.RepeatEveryMonth,
 	repeatEveryDays: domain.RepeatEveryNDays,
 	repeatYearly:    domain.RepeatEveryYear,
+	repeatRRule:     domain.RepeatRRule,
+	repeatInterval:  domain.RepeatInterval,
+	repeatCron:      domain.RepeatCron,
+	repeatSolar:     domain.RepeatSolar,
 }
 
 // userDTO описывает пользователя Mini App.
@@ -60,6 +182,19 @@ type chatDTO struct {
 	Username string `json:"username,omitempty"`
 	Timezone string `json:"timezone,omitempty"`
 	IsPublic bool   `json:"is_group"`
+	// Что делать с напоминаниями, опоздавшими дольше порога (в минутах; 0 — по умолчанию).
+	CatchUp             string `json:"catch_up,omitempty"`
+	CatchUpAfterMinutes int    `json:"catch_up_after_minutes,omitempty"`
+	// Тихие часы; отсутствуют, если не заданы.
+	QuietHours *quietHoursDTO `json:"quiet_hours,omitempty"`
+}
+
+// quietHoursDTO описывает тихие часы чата. Окно может переходить через полночь;
+// пустые start и end в запросе отключают тихие часы.
+type quietHoursDTO struct {
+	Start string `json:"start"` // ЧЧ:ММ в поясе чата
+	End   string `json:"end"`   // ЧЧ:ММ в поясе чата
+	Mode  string `json:"mode"`  // defer или silent
 }
 
 // meResponse — ответ GET /api/v1/me.
@@ -74,16 +209,61 @@ type meResponse struct {
 // NextTime всегда в UTC (RFC 3339); локальное представление собирает клиент,
 // используя Timezone чата.
 type reminderDTO struct {
-	ID          int64     `json:"id"`
-	ChatID      int64     `json:"chat_id"`
-	Text        string    `json:"text"`
-	NextTime    time.Time `json:"next_time"`
-	Repeat      string    `json:"repeat"`
-	RepeatDays  []int     `json:"repeat_days"`
-	RepeatEvery int       `json:"repeat_every"`
-	Paused      bool      `json:"paused"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID       int64     `json:"id"`
+	ChatID   int64     `json:"chat_id"`
+	Text     string    `json:"text"`
+	NextTime time.Time `json:"next_time"`
+	// Ближайшее срабатывание со смещением зоны напоминания и зоны чата. Различаются,
+	// только если у напоминания своя зона timezone.
+	LocalTime    time.Time  `json:"local_time"`
+	ChatTime     time.Time  `json:"chat_time"`
+	Timezone     string     `json:"timezone,omitempty"`
+	Repeat       string     `json:"repeat"`
+	RepeatDays   []int      `json:"repeat_days"`
+	RepeatEvery  int        `json:"repeat_every"`
+	MonthOrdinal int        `json:"month_ordinal,omitempty"` // «N-й день недели»: 1..4, -1 — последний
+	RRule        string     `json:"rrule,omitempty"`
+	StartTime    *time.Time `json:"start_time,omitempty"` // DTSTART правила RRULE
+	Cron         string     `json:"cron,omitempty"`       // выражение cron в поясе чата
+	Times        []string   `json:"times,omitempty"`      // ЧЧ:ММ в поясе чата, если срабатываний в день несколько
+	// Интервальный повтор: шаг в минутах и окно ЧЧ:ММ–ЧЧ:ММ; без окна — весь день.
+	// У остальных повторов окно — случайное время срабатывания вместо времени по часам.
+	IntervalMinutes int    `json:"interval_minutes,omitempty"`
+	WindowStart     string `json:"window_start,omitempty"`
+	WindowEnd       string `json:"window_end,omitempty"`
+	// Солнечный повтор: событие и сдвиг от него в минутах, отрицательный — раньше события.
+	SolarEvent  string `json:"solar_event,omitempty"`
+	SolarOffset int    `json:"solar_offset,omitempty"`
+	// Условия окончания серии: последняя минута последнего дня и число оставшихся срабатываний.
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	RemainingCount int        `json:"remaining_count,omitempty"`
+	// Поведение в нерабочие дни и время по расписанию, если ближайшее срабатывание перенесено.
+	Workdays    string     `json:"workdays,omitempty"`
+	ShiftedFrom *time.Time `json:"shifted_from,omitempty"`
+	// Режим подтверждения: интервал повторов в минутах и их предел; 0 — режим выключен.
+	NagEveryMinutes int `json:"nag_every_minutes,omitempty"`
+	NagMax          int `json:"nag_max,omitempty"`
+	// Предупреждения: за сколько минут до срабатывания и время ближайшего из них.
+	LeadMinutes []int      `json:"lead_minutes,omitempty"`
+	NoticeAt    *time.Time `json:"notice_at,omitempty"`
+	// Цепочка: родитель, через сколько минут после его события срабатывает продолжение
+	// и какое событие ждёт — delivered или acknowledged. waiting — событие ещё не случилось,
+	// next_time тогда не значим.
+	ParentID            int64  `json:"parent_id,omitempty"`
+	FollowOffsetMinutes int    `json:"follow_offset_minutes,omitempty"`
+	FollowOn            string `json:"follow_on,omitempty"`
+	Waiting             bool   `json:"waiting,omitempty"`
+	// Пропуски и переносы отдельных срабатываний; заполняются в ответах по одному напоминанию.
+	Exceptions []exceptionDTO `json:"exceptions,omitempty"`
+	Paused     bool           `json:"paused"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// exceptionDTO описывает пропуск или перенос одного срабатывания серии.
+type exceptionDTO struct {
+	Occurrence time.Time  `json:"occurrence"`         // время по расписанию, UTC
+	MovedTo    *time.Time `json:"moved_to,omitempty"` // новое время; нет — срабатывание пропущено
 }
 
 // reminderListResponse — ответ со списком напоминаний.
@@ -92,48 +272,316 @@ type reminderListResponse struct {
 	Reminders []reminderDTO `json:"reminders"`
 }
 
+// deliveryAttemptDTO описывает попытку доставки из истории чата. Время — UTC.
+type deliveryAttemptDTO struct {
+	ID          int64     `json:"id"`
+	ReminderID  int64     `json:"reminder_id"`
+	Kind        string    `json:"kind"` // reminder, notice или nag
+	Text        string    `json:"text"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	Outcome     string    `json:"outcome"` // sent, retry или failed
+	MessageID   int       `json:"message_id,omitempty"`
+	ErrorClass  string    `json:"error_class,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// deliveryListResponse — ответ с историей доставок чата, от новых попыток к старым.
+type deliveryListResponse struct {
+	Timezone   string               `json:"timezone"`
+	Deliveries []deliveryAttemptDTO `json:"deliveries"`
+}
+
 // reminderRequest — тело запроса на создание или изменение напоминания.
 //
 // Указатели позволяют отличить «поле не передано» от «передано нулевое значение»,
 // что нужно для PATCH: пропущенные поля сохраняют текущее значение.
 type reminderRequest struct {
-	Text        *string `json:"text"`
-	Time        *string `json:"time"`         // ЧЧ:ММ в часовом поясе чата
-	Date        *string `json:"date"`         // ДД.ММ.ГГГГ, для разовых и «каждые N дней»
-	Repeat      *string `json:"repeat"`       // строковое обозначение повтора
-	RepeatDays  *[]int  `json:"repeat_days"`  // дни недели (0..6) или число месяца (1..31)
-	RepeatEvery *int    `json:"repeat_every"` // интервал для every_n_days
-	Paused      *bool   `json:"paused"`
+	Text         *string    `json:"text"`
+	Time         *clockList `json:"time"`          // ЧЧ:ММ в часовом поясе чата или массив таких строк
+	Date         *string    `json:"date"`          // ДД.ММ.ГГГГ, для разовых и «каждые N дней»
+	Repeat       *string    `json:"repeat"`        // строковое обозначение повтора
+	RepeatDays   *[]int     `json:"repeat_days"`   // дни недели (0..6) или число месяца (1..31, -1 — последнее)
+	RepeatEvery  *int       `json:"repeat_every"`  // N дней; у weekly, monthly и yearly — шаг «раз в N»
+	MonthOrdinal *int       `json:"month_ordinal"` // monthly: N-й из дней недели repeat_days, 0 — число месяца
+	RRule        *string    `json:"rrule"`         // правило RFC 5545 для repeat=rrule
+	Cron         *string    `json:"cron"`          // выражение cron для repeat=cron
+	Timezone     *string    `json:"timezone"`      // своя IANA-зона; пустая строка — зона чата
+	// Поля repeat=interval; пустые window_start и window_end снимают окно. У разовых
+	// и календарных повторов окно задаёт случайное время вместо time, а time его снимает.
+	IntervalMinutes *int    `json:"interval_minutes"`
+	WindowStart     *string `json:"window_start"`
+	WindowEnd       *string `json:"window_end"`
+	// Поля repeat=solar: sunrise, sunset, dawn или dusk и сдвиг в минутах (меньше нуля — раньше).
+	SolarEvent  *string `json:"solar_event"`
+	SolarOffset *int    `json:"solar_offset"`
+	// Условия окончания: последний день серии ДД.ММ.ГГГГ (пустая строка снимает)
+	// и число оставшихся срабатываний (0 снимает).
+	EndsAt         *string `json:"ends_at"`
+	RemainingCount *int    `json:"remaining_count"`
+	Workdays       *string `json:"workdays"` // skip, previous, next; пустая строка снимает
+	// Режим подтверждения: повтор каждые nag_every_minutes до nag_max раз; 0 выключает.
+	NagEveryMinutes *int `json:"nag_every_minutes"`
+	NagMax          *int `json:"nag_max"`
+	// Предупреждения за N минут до срабатывания; пустой массив их снимает.
+	LeadMinutes *[]int `json:"lead_minutes"`
+	// Цепочка: parent_id 0 отвязывает напоминание от родителя; follow_on — delivered
+	// или acknowledged. У продолжения нет своего расписания: time и repeat не нужны.
+	ParentID            *int64  `json:"parent_id"`
+	FollowOffsetMinutes *int    `json:"follow_offset_minutes"`
+	FollowOn            *string `json:"follow_on"`
+	Paused              *bool   `json:"paused"`
+}
+
+// clockList — значение поля time запроса: одна строка ЧЧ:ММ или массив строк,
+// если напоминание срабатывает несколько раз в день. Старые клиенты шлют строку.
+type clockList []string
+
+// UnmarshalJSON принимает и строку, и массив строк.
+func (c *clockList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*c = clockList{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("time must be a string or an array of strings")
+	}
+	*c = many
+
+	return nil
+}
+
+// parseReminderRequest — тело запроса на разбор напоминания, записанного фразой.
+type parseReminderRequest struct {
+	Text string `json:"text"` // «завтра в 9 купить молоко» или "tomorrow at 9am call mom"
+}
+
+// parseReminderResponse — напоминание, как его понял разбор фразы. Оно не сохранено,
+// поэтому без id; создаётся обычным POST /reminders.
+type parseReminderResponse struct {
+	Language string      `json:"language"` // ru или en — на каком языке фраза разобралась
+	Timezone string      `json:"timezone"`
+	Reminder reminderDTO `json:"reminder"`
+}
+
+// exceptionRequest — тело запроса на пропуск или перенос одного срабатывания серии.
+// Без date и time срабатывание пропускается, с любым из них — переносится.
+type exceptionRequest struct {
+	Occurrence *time.Time `json:"occurrence"` // время по расписанию (RFC 3339); без него — ближайшее
+	Date       *string    `json:"date"`       // ДД.ММ.ГГГГ в поясе чата; без него — день исходного срабатывания
+	Time       *string    `json:"time"`       // ЧЧ:ММ в поясе чата; без него — время исходного срабатывания
+}
+
+// catchUpRequest — тело запроса на смену политики для опоздавших напоминаний.
+type catchUpRequest struct {
+	Policy       string `json:"policy"`        // late, skip или summary
+	AfterMinutes int    `json:"after_minutes"` // порог опоздания; 0 — по умолчанию
+}
+
+// settingsRequest — тело запроса на смену настроек чата. Отсутствующие поля
+// оставляют соответствующие настройки без изменений.
+type settingsRequest struct {
+	QuietHours *quietHoursDTO `json:"quiet_hours"`
 }
 
 // timezoneRequest — тело запроса на смену часового пояса.
 type timezoneRequest struct {
 	Timezone string `json:"timezone"`
+	// Что сохранить у напоминаний чата: wall_clock — время по часам, instant — момент
+	// срабатывания.
+	Rebase string `json:"rebase"`
 }
 
-func toReminderDTO(r *domain.Reminder) reminderDTO {
+// timezoneResponse — чат после смены пояса и число пересчитанных напоминаний.
+type timezoneResponse struct {
+	chatDTO
+	Rebased int `json:"rebased"`
+}
+
+// calendarDTO описывает производственный календарь чата.
+type calendarDTO struct {
+	Workweek []int            `json:"workweek"` // рабочие дни недели, 0 — воскресенье
+	Days     []calendarDayDTO `json:"days"`     // особые дни по возрастанию даты
+}
+
+// calendarDayDTO — праздник (working=false) или перенесённый рабочий день.
+type calendarDayDTO struct {
+	Date    string `json:"date"` // ГГГГ-ММ-ДД
+	Working bool   `json:"working"`
+}
+
+func toCalendarDTO(cal *domain.BusinessCalendar) calendarDTO {
+	workweek := cal.Workweek
+	if len(workweek) == 0 {
+		workweek = domain.DefaultWorkweek
+	}
+
+	days := make([]calendarDayDTO, 0, len(cal.Days))
+	for _, date := range slices.Sorted(maps.Keys(cal.Days)) {
+		days = append(days, calendarDayDTO{Date: date, Working: cal.Days[date]})
+	}
+
+	return calendarDTO{Workweek: workweek, Days: days}
+}
+
+func fromCalendarDTO(chatID int64, dto calendarDTO) *domain.BusinessCalendar {
+	cal := &domain.BusinessCalendar{
+		ChatID:   chatID,
+		Workweek: dto.Workweek,
+		Days:     make(map[string]bool, len(dto.Days)),
+	}
+	for _, day := range dto.Days {
+		cal.Days[day.Date] = day.Working
+	}
+
+	return cal
+}
+
+// toReminderDTO переводит напоминание в ответ API; chat — зона чата.
+func toReminderDTO(r *domain.Reminder, chat *time.Location) reminderDTO {
 	days := r.RepeatDays
 	if days == nil {
 		// Клиенту удобнее пустой массив, чем null.
 		days = []int{}
 	}
 
+	var start *time.Time
+	if !r.StartTime.IsZero() {
+		utc := r.StartTime.UTC()
+		start = &utc
+	}
+
+	var endsAt *time.Time
+	if !r.EndsAt.IsZero() {
+		utc := r.EndsAt.UTC()
+		endsAt = &utc
+	}
+
+	var shiftedFrom *time.Time
+	if !r.ShiftedFrom.IsZero() {
+		utc := r.ShiftedFrom.UTC()
+		shiftedFrom = &utc
+	}
+
+	var noticeAt *time.Time
+	if !r.NoticeAt.IsZero() {
+		utc := r.NoticeAt.UTC()
+		noticeAt = &utc
+	}
+
+	var windowStart, windowEnd string
+	if r.HasWindow() {
+		windowStart, windowEnd = formatClock(r.WindowStart), formatClock(r.WindowEnd)
+	}
+
+	// Нулевое событие — восход, поэтому у остальных повторов поле не заполняется.
+	var solarEvent string
+	if r.Repeat == domain.RepeatSolar {
+		solarEvent = solarToAPI[r.SolarEvent]
+	}
+
+	// Нулевое событие — доставка, поэтому у обычных напоминаний поле не заполняется.
+	var followOn string
+	if r.IsFollowUp() {
+		followOn = followToAPI[r.FollowOn]
+	}
+
 	return reminderDTO{
-		ID:          r.ID,
-		ChatID:      r.ChatID,
-		Text:        r.Text,
-		NextTime:    r.NextTime.UTC(),
-		Repeat:      repeatToAPI[r.Repeat],
-		RepeatDays:  days,
-		RepeatEvery: r.RepeatEvery,
-		Paused:      r.Paused,
-		CreatedAt:   r.CreatedAt.UTC(),
-		UpdatedAt:   r.UpdatedAt.UTC(),
+		ID:                  r.ID,
+		ChatID:              r.ChatID,
+		Text:                r.Text,
+		NextTime:            r.NextTime.UTC(),
+		LocalTime:           r.NextTime.In(r.Location(chat)),
+		ChatTime:            r.NextTime.In(chat),
+		Timezone:            r.Timezone,
+		Repeat:              repeatToAPI[r.Repeat],
+		RepeatDays:          days,
+		RepeatEvery:         r.RepeatEvery,
+		MonthOrdinal:        r.MonthOrdinal,
+		RRule:               r.RRule,
+		StartTime:           start,
+		Cron:                r.Cron,
+		Times:               formatClocks(r.Times),
+		IntervalMinutes:     r.IntervalMinutes,
+		WindowStart:         windowStart,
+		WindowEnd:           windowEnd,
+		SolarEvent:          solarEvent,
+		SolarOffset:         r.SolarOffset,
+		EndsAt:              endsAt,
+		RemainingCount:      r.RemainingCount,
+		Workdays:            workdaysToAPI[r.WorkdayPolicy],
+		ShiftedFrom:         shiftedFrom,
+		NagEveryMinutes:     r.NagEveryMinutes,
+		NagMax:              r.NagMax,
+		LeadMinutes:         r.LeadMinutes,
+		NoticeAt:            noticeAt,
+		ParentID:            r.ParentID,
+		FollowOffsetMinutes: r.FollowOffset,
+		FollowOn:            followOn,
+		Waiting:             r.Waiting,
+		Exceptions:          toExceptionDTOs(r.Exceptions),
+		Paused:              r.Paused,
+		CreatedAt:           r.CreatedAt.UTC(),
+		UpdatedAt:           r.UpdatedAt.UTC(),
+	}
+}
+
+func toExceptionDTOs(exceptions []domain.OccurrenceException) []exceptionDTO {
+	if len(exceptions) == 0 {
+		return nil
+	}
+
+	out := make([]exceptionDTO, len(exceptions))
+	for i, e := range exceptions {
+		out[i] = exceptionDTO{Occurrence: e.Occurrence.UTC()}
+		if !e.IsSkip() {
+			moved := e.MovedTo.UTC()
+			out[i].MovedTo = &moved
+		}
+	}
+
+	return out
+}
+
+func toDeliveryAttemptDTO(a *domain.DeliveryAttempt) deliveryAttemptDTO {
+	return deliveryAttemptDTO{
+		ID:          a.ID,
+		ReminderID:  a.ReminderID,
+		Kind:        string(a.Kind),
+		Text:        a.Text,
+		ScheduledAt: a.ScheduledAt.UTC(),
+		AttemptedAt: a.AttemptedAt.UTC(),
+		Outcome:     string(a.Outcome),
+		MessageID:   a.MessageID,
+		ErrorClass:  a.ErrorClass,
+		Error:       a.Error,
 	}
 }
 
+// formatClocks переводит минуты от полуночи в строки ЧЧ:ММ.
+func formatClocks(times []int) []string {
+	if len(times) == 0 {
+		return nil
+	}
+
+	clocks := make([]string, len(times))
+	for i, m := range times {
+		clocks[i] = formatClock(m)
+	}
+
+	return clocks
+}
+
+// formatClock переводит минуты от полуночи в ЧЧ:ММ.
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
 func toChatDTO(c *domain.Chat) chatDTO {
-	return chatDTO{
+	dto := chatDTO{
 		ID:       c.ID,
 		Type:     c.Type,
 		Title:    c.Name,
@@ -141,6 +589,26 @@ func toChatDTO(c *domain.Chat) chatDTO {
 		Timezone: c.Timezone,
 		IsPublic: c.Type != chatTypePrivate,
 	}
+	setSettings(&dto, c)
+
+	return dto
+}
+
+// setSettings заполняет настройки чата; настройки по умолчанию не передаются,
+// как и незаданный часовой пояс.
+func setSettings(dto *chatDTO, c *domain.Chat) {
+	dto.Timezone = c.Timezone
+	if c.CatchUp != (domain.CatchUp{}) {
+		dto.CatchUp = catchUpToAPI[c.CatchUp.Policy]
+		dto.CatchUpAfterMinutes = int(c.CatchUp.After / time.Minute)
+	}
+	if c.Quiet.Enabled() {
+		dto.QuietHours = &quietHoursDTO{
+			Start: formatClock(c.Quiet.Start),
+			End:   formatClock(c.Quiet.End),
+			Mode:  quietToAPI[c.Quiet.Mode],
+		}
+	}
 }
 
 // parseRepeat переводит строковое обозначение повтора в доменное значение.
@@ -152,3 +620,77 @@ func parseRepeat(s string) (domain.RepeatType, error) {
 
 	return r, nil
 }
+
+// parseWorkdays переводит строковое обозначение поведения в нерабочие дни в доменное значение.
+func parseWorkdays(s string) (domain.WorkdayPolicy, error) {
+	policy, ok := apiToWorkdays[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown workdays policy %q", s)
+	}
+
+	return policy, nil
+}
+
+// parseSolarEvent переводит строковое обозначение солнечного события в доменное значение.
+func parseSolarEvent(s string) (domain.SolarEvent, error) {
+	event, ok := apiToSolar[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown solar event %q", s)
+	}
+
+	return event, nil
+}
+
+// parseFollowTrigger переводит строковое обозначение события родителя в доменное значение.
+func parseFollowTrigger(s string) (domain.FollowTrigger, error) {
+	trigger, ok := apiToFollow[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown follow-up trigger %q", s)
+	}
+
+	return trigger, nil
+}
+
+func parseRebase(s string) (domain.TimezoneRebase, error) {
+	mode, ok := apiToRebase[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown rebase mode %q", s)
+	}
+
+	return mode, nil
+}
+
+// parseCatchUp переводит тело запроса в доменные настройки.
+func parseCatchUp(req catchUpRequest) (domain.CatchUp, error) {
+	policy, ok := apiToCatchUp[req.Policy]
+	if !ok {
+		return domain.CatchUp{}, fmt.Errorf("%w: unknown policy %q", domain.ErrInvalidCatchUp, req.Policy)
+	}
+
+	return domain.CatchUp{Policy: policy, After: time.Duration(req.AfterMinutes) * time.Minute}, nil
+}
+
+// parseQuietHours переводит тихие часы из запроса в доменные; пустое окно их отключает.
+func parseQuietHours(dto quietHoursDTO) (domain.QuietHours, error) {
+	mode, ok := apiToQuiet[dto.Mode]
+	if !ok {
+		return domain.QuietHours{}, fmt.Errorf("%w: unknown mode %q", domain.ErrInvalidQuietHours, dto.Mode)
+	}
+	if dto.Start == "" && dto.End == "" {
+		return domain.QuietHours{}, nil
+	}
+
+	start, err := parseClockMinutes(dto.Start)
+	if err != nil {
+		return domain.QuietHours{}, fmt.Errorf("%w: start: %v", domain.ErrInvalidQuietHours, err)
+	}
+	end, err := parseClockMinutes(dto.End)
+	if err != nil {
+		return domain.QuietHours{}, fmt.Errorf("%w: end: %v", domain.ErrInvalidQuietHours, err)
+	}
+	if start == end {
+		return domain.QuietHours{}, fmt.Errorf("%w: empty window", domain.ErrInvalidQuietHours)
+	}
+
+	return domain.QuietHours{Start: start, End: end, Mode: mode}, nil
+}
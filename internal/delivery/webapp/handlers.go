@@ -2,6 +2,7 @@ package webapp
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,11 +11,18 @@ import (
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/webapp/auth"
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/webapp/authz"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/quickadd"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/8thgencore/dory-reminder-bot/pkg/timezone"
 )
 
-const recentWebAppLaunchTTL = 10 * time.Minute
+const (
+	recentWebAppLaunchTTL = 10 * time.Minute
+	// maxCalendarFileBytes ограничивает импортируемый файл: годовой XML xmlcalendar.ru
+	// занимает несколько килобайт.
+	maxCalendarFileBytes = 256 << 10
+)
 
 type launchCandidate struct {
 	id     int64
@@ -54,8 +62,8 @@ func (s *server) handleMe(w http.ResponseWriter, r *http.Request) {
 
 	for _, chat := range known {
 		if chat.ID == user.User.ID {
-			// Личный чат уже добавлен, но с данными из initData — берём таймзону из базы.
-			chats[0].Timezone = chat.Timezone
+			// Личный чат уже добавлен, но с данными из initData — берём настройки из базы.
+			setSettings(&chats[0], chat)
 			continue
 		}
 		if included[chat.ID] {
@@ -91,7 +99,7 @@ func (s *server) handleMe(w http.ResponseWriter, r *http.Request) {
 
 	if chats[0].Timezone == "" {
 		if chat, err := s.chatUC.Get(r.Context(), user.User.ID); err == nil {
-			chats[0].Timezone = chat.Timezone
+			setSettings(&chats[0], chat)
 		}
 	}
 	if launchChatID != 0 {
@@ -303,7 +311,7 @@ func chatTypeFor(chatID int64, r *http.Request) string {
 	return chatTypeGroup
 }
 
-// handleSetTimezone задаёт часовой пояс чата.
+// handleSetTimezone задаёт часовой пояс чата и пересчитывает под него напоминания.
 func (s *server) handleSetTimezone(w http.ResponseWriter, r *http.Request) {
 	chatID, ok := s.authorizeChat(w, r)
 	if !ok {
@@ -314,6 +322,11 @@ func (s *server) handleSetTimezone(w http.ResponseWriter, r *http.Request) {
 	if !decodeJSON(w, r, &req) {
 		return
 	}
+	mode, err := parseRebase(req.Rebase)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Некорректное значение rebase")
+		return
+	}
 
 	// В базе может не быть строки чата: Mini App открывают и не написав боту ни разу.
 	if _, err := s.chatUC.GetOrCreateChat(r.Context(), chatID, chatTypeFor(chatID, r), "", ""); err != nil {
@@ -323,20 +336,106 @@ func (s *server) handleSetTimezone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.chatUC.SetTimezone(r.Context(), chatID, req.Timezone); err != nil {
+	rebased, err := s.chatUC.SetTimezone(r.Context(), chatID, req.Timezone, mode)
+	if err != nil {
 		s.writeDomainError(w, err)
 		return
 	}
 
 	chatType := chatTypeFor(chatID, r)
-	writeJSON(w, http.StatusOK, chatDTO{
-		ID:       chatID,
-		Type:     chatType,
-		Timezone: req.Timezone,
-		IsPublic: chatType != chatTypePrivate,
+	writeJSON(w, http.StatusOK, timezoneResponse{
+		chatDTO: chatDTO{
+			ID:       chatID,
+			Type:     chatType,
+			Timezone: req.Timezone,
+			IsPublic: chatType != chatTypePrivate,
+		},
+		Rebased: rebased,
 	})
 }
 
+// handleSetCatchUp задаёт, что делать с напоминаниями, опоздавшими после простоя бота.
+func (s *server) handleSetCatchUp(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	var req catchUpRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	catchUp, err := parseCatchUp(req)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	chat, err := s.chatUC.Get(r.Context(), chatID)
+	if errors.Is(err, repository.ErrChatNotFound) {
+		// Как и с часовым поясом: Mini App открывают и не написав боту ни разу.
+		chat, err = s.chatUC.GetOrCreateChat(r.Context(), chatID, chatTypeFor(chatID, r), "", "")
+	}
+	if err != nil {
+		s.logHandlerError(r, err)
+		s.writeDomainError(w, err)
+
+		return
+	}
+
+	if err := s.chatUC.SetCatchUp(r.Context(), chatID, catchUp); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	chat.CatchUp = catchUp
+
+	writeJSON(w, http.StatusOK, toChatDTO(chat))
+}
+
+// handleSetSettings меняет настройки чата, переданные в запросе; остальные остаются прежними.
+func (s *server) handleSetSettings(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	var req settingsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	// Все поля проверяются до записи: ошибка в одном не должна оставить чат
+	// с частично применёнными настройками.
+	var quiet domain.QuietHours
+	if req.QuietHours != nil {
+		var err error
+		if quiet, err = parseQuietHours(*req.QuietHours); err != nil {
+			s.writeDomainError(w, err)
+			return
+		}
+	}
+
+	chat, err := s.chatUC.Get(r.Context(), chatID)
+	if errors.Is(err, repository.ErrChatNotFound) {
+		chat, err = s.chatUC.GetOrCreateChat(r.Context(), chatID, chatTypeFor(chatID, r), "", "")
+	}
+	if err != nil {
+		s.logHandlerError(r, err)
+		s.writeDomainError(w, err)
+
+		return
+	}
+
+	if req.QuietHours != nil {
+		if err := s.chatUC.SetQuietHours(r.Context(), chatID, quiet); err != nil {
+			s.writeDomainError(w, err)
+			return
+		}
+		chat.Quiet = quiet
+	}
+
+	writeJSON(w, http.StatusOK, toChatDTO(chat))
+}
+
 // handleListReminders отдаёт напоминания чата.
 func (s *server) handleListReminders(w http.ResponseWriter, r *http.Request) {
 	chatID, ok := s.authorizeChat(w, r)
@@ -352,9 +451,10 @@ func (s *server) handleListReminders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc := s.chatUC.Location(r.Context(), chatID)
 	items := make([]reminderDTO, 0, len(reminders))
 	for _, rem := range reminders {
-		items = append(items, toReminderDTO(rem))
+		items = append(items, toReminderDTO(rem, loc))
 	}
 
 	writeJSON(w, http.StatusOK, reminderListResponse{
@@ -363,6 +463,43 @@ func (s *server) handleListReminders(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleListDeliveries отдаёт историю доставок чата. Необязательный параметр limit
+// ограничивает число попыток, но не больше domain.MaxHistoryEntries.
+func (s *server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	limit := domain.MaxHistoryEntries
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Некорректное значение limit")
+			return
+		}
+		limit = n
+	}
+
+	attempts, err := s.reminderUC.ListHistory(r.Context(), chatID, limit)
+	if err != nil {
+		s.logHandlerError(r, err)
+		s.writeDomainError(w, err)
+
+		return
+	}
+
+	items := make([]deliveryAttemptDTO, 0, len(attempts))
+	for _, a := range attempts {
+		items = append(items, toDeliveryAttemptDTO(a))
+	}
+
+	writeJSON(w, http.StatusOK, deliveryListResponse{
+		Timezone:   s.timezoneOf(r, chatID),
+		Deliveries: items,
+	})
+}
+
 // handleCreateReminder создаёт напоминание в чате.
 func (s *server) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
 	chatID, ok := s.authorizeChat(w, r)
@@ -380,7 +517,12 @@ func (s *server) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rem := &domain.Reminder{ChatID: chatID}
-	if err := s.applyRequest(rem, req, s.chatUC.Location(r.Context(), chatID)); err != nil {
+	if err := s.loadChatSchedule(r, rem); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	loc := s.chatUC.Location(r.Context(), chatID)
+	if err := s.applyRequest(rem, req, loc); err != nil {
 		s.writeDomainError(w, err)
 		return
 	}
@@ -390,7 +532,44 @@ func (s *server) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, toReminderDTO(rem))
+	writeJSON(w, http.StatusCreated, toReminderDTO(rem, loc))
+}
+
+// handleParseReminder разбирает напоминание, записанное фразой по-русски или по-английски,
+// и отдаёт, как его понял бот. Ничего не сохраняет.
+func (s *server) handleParseReminder(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	var req parseReminderRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Нужно указать фразу")
+		return
+	}
+
+	loc := s.chatUC.Location(r.Context(), chatID)
+	rem, lang, err := quickadd.Parse(req.Text, time.Now().In(loc))
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	rem.ChatID = chatID
+	rem.Normalize()
+	if err := rem.Validate(); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, parseReminderResponse{
+		Language: string(lang),
+		Timezone: s.timezoneOf(r, chatID),
+		Reminder: toReminderDTO(rem, loc),
+	})
 }
 
 // handleGetReminder отдаёт одно напоминание.
@@ -400,7 +579,14 @@ func (s *server) handleGetReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toReminderDTO(rem))
+	exceptions, err := s.reminderUC.ListExceptions(r.Context(), rem.ID)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	rem.Exceptions = exceptions
+
+	writeJSON(w, http.StatusOK, toReminderDTO(rem, s.chatUC.Location(r.Context(), rem.ChatID)))
 }
 
 // handleUpdateReminder меняет напоминание.
@@ -415,7 +601,12 @@ func (s *server) handleUpdateReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.applyRequest(rem, req, s.chatUC.Location(r.Context(), rem.ChatID)); err != nil {
+	if err := s.loadChatSchedule(r, rem); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	loc := s.chatUC.Location(r.Context(), rem.ChatID)
+	if err := s.applyRequest(rem, req, loc); err != nil {
 		s.writeDomainError(w, err)
 		return
 	}
@@ -425,7 +616,7 @@ func (s *server) handleUpdateReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toReminderDTO(rem))
+	writeJSON(w, http.StatusOK, toReminderDTO(rem, loc))
 }
 
 // handleDeleteReminder удаляет напоминание.
@@ -443,6 +634,136 @@ func (s *server) handleDeleteReminder(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleAddException пропускает или переносит одно срабатывание повторяющегося
+// напоминания, не меняя расписание серии.
+func (s *server) handleAddException(w http.ResponseWriter, r *http.Request) {
+	rem, ok := s.loadOwnedReminder(w, r)
+	if !ok {
+		return
+	}
+
+	var req exceptionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	var occurrence time.Time
+	if req.Occurrence != nil {
+		occurrence = *req.Occurrence
+	} else {
+		exceptions, err := s.reminderUC.ListExceptions(r.Context(), rem.ID)
+		if err != nil {
+			s.writeDomainError(w, err)
+			return
+		}
+		rem.Exceptions = exceptions
+		occurrence = scheduling.SeriesTime(rem)
+	}
+
+	if rem.WorkdayPolicy != domain.WorkdayAny || rem.Repeat == domain.RepeatSolar {
+		if err := s.loadChatSchedule(r, rem); err != nil {
+			s.writeDomainError(w, err)
+			return
+		}
+	}
+
+	chatLoc := s.chatUC.Location(r.Context(), rem.ChatID)
+	loc := rem.Location(chatLoc)
+	e, err := exceptionFromRequest(occurrence, req, loc)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	if err := s.reminderUC.AddException(r.Context(), rem, e, time.Now(), loc); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toReminderDTO(rem, chatLoc))
+}
+
+// handleGetCalendar отдаёт производственный календарь чата.
+func (s *server) handleGetCalendar(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	cal, err := s.chatUC.Calendar(r.Context(), chatID)
+	if err != nil {
+		s.logHandlerError(r, err)
+		s.writeDomainError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCalendarDTO(cal))
+}
+
+// handleSetCalendar целиком заменяет производственный календарь чата.
+func (s *server) handleSetCalendar(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	var req calendarDTO
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	cal := fromCalendarDTO(chatID, req)
+	if err := s.chatUC.SetCalendar(r.Context(), cal); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCalendarDTO(cal))
+}
+
+// handleImportCalendar дополняет календарь чата особыми днями из файла: XML
+// xmlcalendar.ru или текстового списка дат. Тело запроса — содержимое файла.
+func (s *server) handleImportCalendar(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCalendarFileBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Файл календаря слишком большой")
+		return
+	}
+
+	cal, err := s.chatUC.ImportCalendar(r.Context(), chatID, data)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCalendarDTO(cal))
+}
+
+// loadChatSchedule подгружает в напоминание календарь и координаты чата: без них
+// расписание «только по рабочим дням» не знало бы о праздниках, а солнечное —
+// о восходе и закате.
+func (s *server) loadChatSchedule(r *http.Request, rem *domain.Reminder) error {
+	cal, err := s.chatUC.Calendar(r.Context(), rem.ChatID)
+	if err != nil {
+		return err
+	}
+	rem.Calendar = cal
+
+	geo, err := s.chatUC.Geo(r.Context(), rem.ChatID)
+	if err != nil {
+		return err
+	}
+	rem.Geo = geo
+
+	return nil
+}
+
 // loadOwnedReminder загружает напоминание по идентификатору из пути и проверяет,
 // что запросивший пользователь имеет доступ к его чату.
 //
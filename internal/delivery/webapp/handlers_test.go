@@ -19,6 +19,7 @@ import (
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/webapp/authz"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/8thgencore/dory-reminder-bot/internal/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,7 +96,7 @@ func newTestEnvWithMembership(t *testing.T, checker interface {
 	require.NoError(t, repository.Migrate(db))
 
 	remUC := usecase.NewReminderUsecase(repository.NewReminderRepository(db))
-	chatUC := usecase.NewChatUsecase(repository.NewChatRepository(db))
+	chatUC := usecase.NewChatUsecase(repository.NewChatRepository(db), remUC)
 	memberUC := usecase.NewMemberUsecase(repository.NewMemberRepository(db))
 
 	s := &server{
@@ -131,7 +132,8 @@ func (e *testEnv) seedChat(chatID int64, chatType, tz string) {
 	_, err := e.chatUC.GetOrCreateChat(ctx, chatID, chatType, "Test", "")
 	require.NoError(e.t, err)
 	if tz != "" {
-		require.NoError(e.t, e.chatUC.SetTimezone(ctx, chatID, tz))
+		_, err := e.chatUC.SetTimezone(ctx, chatID, tz, domain.RebaseWallClock)
+		require.NoError(e.t, err)
 	}
 }
 
@@ -546,6 +548,203 @@ func TestCreateReminder_EveryNDaysWithPastStartDate(t *testing.T) {
 	assert.Equal(t, 8, created.NextTime.In(loc).Hour())
 }
 
+func TestCreateReminder_MonthlyModes(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	t.Run("последний рабочий день", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":          "закрыть табель",
+			"repeat":        "monthly",
+			"time":          "17:00",
+			"month_ordinal": -1,
+			"repeat_days":   []int{1, 2, 3, 4, 5},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		created := decode[reminderDTO](t, resp)
+		assert.Equal(t, -1, created.MonthOrdinal)
+
+		local := created.NextTime.In(loc)
+		assert.NotContains(t, []time.Weekday{time.Saturday, time.Sunday}, local.Weekday())
+		// После последнего рабочего дня до конца месяца остаются только выходные.
+		for day := local.AddDate(0, 0, 1); day.Month() == local.Month(); day = day.AddDate(0, 0, 1) {
+			assert.Contains(t, []time.Weekday{time.Saturday, time.Sunday}, day.Weekday())
+		}
+	})
+
+	t.Run("последний день месяца", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":        "оплатить аренду",
+			"repeat":      "monthly",
+			"time":        "10:00",
+			"repeat_days": []int{-1},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		local := decode[reminderDTO](t, resp).NextTime.In(loc)
+		assert.Equal(t, 1, local.AddDate(0, 0, 1).Day())
+	})
+
+	t.Run("пятый вторник отвергается", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":          "ретро",
+			"repeat":        "monthly",
+			"time":          "10:00",
+			"month_ordinal": 5,
+			"repeat_days":   []int{2},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCreateReminder_EveryNWeeks(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":         "ревью спринта",
+		"repeat":       "weekly",
+		"time":         "11:00",
+		"repeat_days":  []int{1, 4},
+		"repeat_every": 2,
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, 2, created.RepeatEvery)
+	assert.Equal(t, []int{1, 4}, created.RepeatDays)
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"repeat_every": 1})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Zero(t, decode[reminderDTO](t, resp).RepeatEvery, "step of one means every week")
+
+	resp = env.do(http.MethodPost, path, map[string]any{
+		"text":         "отчёт",
+		"repeat":       "monthly",
+		"time":         "10:00",
+		"repeat_days":  []int{15},
+		"repeat_every": -3,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCreateReminder_EndConditions(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	lastDay := time.Now().In(loc).AddDate(0, 0, 7)
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":             "антибиотик",
+		"repeat":           "interval",
+		"interval_minutes": 8 * 60,
+		"ends_at":          lastDay.Format("02.01.2006"),
+		"remaining_count":  21,
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	require.NotNil(t, created.EndsAt)
+	local := created.EndsAt.In(loc)
+	assert.Equal(t, lastDay.Day(), local.Day())
+	assert.Equal(t, 23, local.Hour())
+	assert.Equal(t, 21, created.RemainingCount)
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{
+		"ends_at":         "",
+		"remaining_count": 0,
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	updated := decode[reminderDTO](t, resp)
+	assert.Nil(t, updated.EndsAt)
+	assert.Zero(t, updated.RemainingCount)
+	assert.True(t, created.NextTime.Equal(updated.NextTime), "end conditions do not move the schedule")
+
+	resp = env.do(http.MethodPost, path, map[string]any{
+		"text":    "уже закончилось",
+		"repeat":  "daily",
+		"time":    "09:00",
+		"ends_at": time.Now().In(loc).AddDate(0, 0, -1).Format("02.01.2006"),
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAddException_SkipAndMove(t *testing.T) {
+	env := newTestEnv(t)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, "/api/v1/chats/"+itoa(testUserID)+"/reminders", map[string]any{
+		"text":        "планёрка",
+		"repeat":      "weekly",
+		"time":        "10:00",
+		"repeat_days": []int{2},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	created := decode[reminderDTO](t, resp)
+	path := "/api/v1/reminders/" + itoa(created.ID) + "/exceptions"
+
+	resp = env.do(http.MethodPost, path, map[string]any{})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	skipped := decode[reminderDTO](t, resp)
+	assert.True(t, created.NextTime.In(loc).AddDate(0, 0, 7).Equal(skipped.NextTime), "skip moves to the next week")
+	require.Len(t, skipped.Exceptions, 1)
+	assert.Nil(t, skipped.Exceptions[0].MovedTo)
+
+	resp = env.do(http.MethodPost, path, map[string]any{"time": "15:30"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	moved := decode[reminderDTO](t, resp)
+	local := moved.NextTime.In(loc)
+	assert.Equal(t, skipped.NextTime.In(loc).Day(), local.Day())
+	assert.Equal(t, 15, local.Hour())
+	assert.Equal(t, 30, local.Minute())
+
+	resp = env.do(http.MethodGet, "/api/v1/reminders/"+itoa(created.ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, decode[reminderDTO](t, resp).Exceptions, 2)
+
+	resp = env.do(http.MethodPost, path, map[string]any{"occurrence": created.NextTime.Add(time.Hour)})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "not an occurrence of the series")
+
+	resp = env.do(http.MethodPost, path, map[string]any{"date": "31.02.2026"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	oneTime := &domain.Reminder{
+		ChatID: testUserID, Text: "разовое", NextTime: time.Now().Add(time.Hour), Repeat: domain.RepeatNone,
+	}
+	require.NoError(t, env.remUC.AddReminder(context.Background(), oneTime))
+	resp = env.do(http.MethodPost, "/api/v1/reminders/"+itoa(oneTime.ID)+"/exceptions", map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	foreign := env.createReminder(foreignGroupID, "чужое")
+	resp = env.do(http.MethodPost, "/api/v1/reminders/"+itoa(foreign.ID)+"/exceptions", map[string]any{})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCreateReminder_RRule(t *testing.T) {
+	env := newTestEnv(t)
+
+	resp := env.do(http.MethodPost, "/api/v1/chats/"+itoa(testUserID)+"/reminders", map[string]any{
+		"text":   "ретро",
+		"repeat": "rrule",
+		"rrule":  "RRULE:FREQ=MONTHLY;BYDAY=2TU",
+		"time":   "11:00",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "rrule", created.Repeat)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=2TU", created.RRule)
+	assert.True(t, created.NextTime.After(time.Now()))
+
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	local := created.NextTime.In(loc)
+	assert.Equal(t, time.Tuesday, local.Weekday())
+	assert.Equal(t, 2, (local.Day()-1)/7+1, "must be the second Tuesday")
+	assert.Equal(t, 11, local.Hour())
+}
+
 func TestCreateReminder_RejectsInvalidInput(t *testing.T) {
 	env := newTestEnv(t)
 	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
@@ -565,6 +764,13 @@ func TestCreateReminder_RejectsInvalidInput(t *testing.T) {
 			"каждые N дней с нулевым интервалом",
 			map[string]any{"text": "привет", "repeat": "every_n_days", "time": "09:00", "repeat_every": 0},
 		},
+		{"правило без FREQ", map[string]any{"text": "привет", "repeat": "rrule", "rrule": "BYDAY=MO", "time": "09:00"}},
+		{"неподдерживаемая часть правила", map[string]any{
+			"text": "привет", "repeat": "rrule", "rrule": "FREQ=DAILY;BYHOUR=9", "time": "09:00",
+		}},
+		{"правило уже исчерпано", map[string]any{
+			"text": "привет", "repeat": "rrule", "rrule": "FREQ=DAILY;UNTIL=20200101", "time": "09:00",
+		}},
 		{"слишком длинный текст", map[string]any{
 			"text": strings.Repeat("я", domain.MaxTextLen+1), "repeat": "daily", "time": "09:00",
 		}},
@@ -592,6 +798,96 @@ func TestUpdateReminder_PauseKeepsSchedule(t *testing.T) {
 	assert.True(t, before.Equal(updated.NextTime), "want %s, got %s", before, updated.NextTime)
 }
 
+func TestUpdateReminder_NagUntilAcknowledged(t *testing.T) {
+	env := newTestEnv(t)
+	rem := env.createReminder(testUserID, "закрыть смену")
+	path := "/api/v1/reminders/" + itoa(rem.ID)
+
+	resp := env.do(http.MethodPatch, path, map[string]any{"nag_every_minutes": 15, "nag_max": 4})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	updated := decode[reminderDTO](t, resp)
+	assert.Equal(t, 15, updated.NagEveryMinutes)
+	assert.Equal(t, 4, updated.NagMax)
+	assert.True(t, rem.NextTime.Equal(updated.NextTime), "nag settings do not move the schedule")
+
+	resp = env.do(http.MethodPatch, path, map[string]any{"nag_max": 0})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = env.do(http.MethodPatch, path, map[string]any{"nag_every_minutes": 0})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	updated = decode[reminderDTO](t, resp)
+	assert.Zero(t, updated.NagEveryMinutes)
+	assert.Zero(t, updated.NagMax)
+}
+
+func TestUpdateReminder_AdvanceNotices(t *testing.T) {
+	env := newTestEnv(t)
+	rem := env.createReminder(testUserID, "созвон")
+	path := "/api/v1/reminders/" + itoa(rem.ID)
+
+	resp := env.do(http.MethodPatch, path, map[string]any{"lead_minutes": []int{30, 120}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	updated := decode[reminderDTO](t, resp)
+	assert.Equal(t, []int{120, 30}, updated.LeadMinutes)
+	// До срабатывания час: предупреждение за два часа уже опоздало.
+	require.NotNil(t, updated.NoticeAt)
+	assert.True(t, updated.NextTime.Add(-30*time.Minute).Equal(*updated.NoticeAt))
+
+	resp = env.do(http.MethodPatch, path, map[string]any{"lead_minutes": []int{31 * domain.MinutesPerDay}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = env.do(http.MethodPatch, path, map[string]any{"lead_minutes": []int{}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	updated = decode[reminderDTO](t, resp)
+	assert.Empty(t, updated.LeadMinutes)
+	assert.Nil(t, updated.NoticeAt)
+}
+
+func TestCreateReminder_FollowUp(t *testing.T) {
+	env := newTestEnv(t)
+	parent := env.createReminder(testUserID, "полить рассаду")
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":                  "подкормить",
+		"repeat":                "none",
+		"parent_id":             parent.ID,
+		"follow_offset_minutes": 3 * domain.MinutesPerDay,
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, parent.ID, created.ParentID)
+	assert.Equal(t, 3*domain.MinutesPerDay, created.FollowOffsetMinutes)
+	assert.Equal(t, "delivered", created.FollowOn)
+	assert.True(t, created.Waiting)
+	assert.Equal(t, "none", created.Repeat)
+
+	// Подтверждения ждать нельзя: родитель его не просит.
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"follow_on": "acknowledged"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(parent.ID), map[string]any{"parent_id": created.ID})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "цепочка не замыкается в кольцо")
+
+	foreign := env.createReminder(foreignGroupID, "чужое")
+	resp = env.do(http.MethodPost, path, map[string]any{
+		"text": "следом", "repeat": "none", "parent_id": foreign.ID,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{
+		"parent_id": 0,
+		"time":      "07:15",
+		"date":      time.Now().AddDate(0, 0, 2).Format("02.01.2006"),
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	detached := decode[reminderDTO](t, resp)
+	assert.Zero(t, detached.ParentID)
+	assert.False(t, detached.Waiting)
+	assert.Empty(t, detached.FollowOn)
+	assert.True(t, detached.NextTime.After(time.Now()))
+}
+
 func TestUpdateReminder_ChangesTextAndTime(t *testing.T) {
 	env := newTestEnv(t)
 	rem := env.createReminder(testUserID, "старый текст")
@@ -634,6 +930,63 @@ func TestListReminders_ReturnsChatTimezone(t *testing.T) {
 	assert.Len(t, body.Reminders, 2)
 }
 
+func TestParseReminder_DoesNotSave(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+
+	resp := env.do(http.MethodPost, path+":parse", map[string]any{"text": "every other Friday at 5pm retro"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body := decode[parseReminderResponse](t, resp)
+	assert.Equal(t, "en", body.Language)
+	assert.Equal(t, "Europe/Berlin", body.Timezone)
+	assert.Equal(t, "retro", body.Reminder.Text)
+	assert.Equal(t, "weekly", body.Reminder.Repeat)
+	assert.Equal(t, []int{5}, body.Reminder.RepeatDays)
+	assert.Equal(t, 2, body.Reminder.RepeatEvery)
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, 17, body.Reminder.NextTime.In(loc).Hour(), "time must be interpreted in the chat timezone")
+
+	resp = env.do(http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, decode[reminderListResponse](t, resp).Reminders)
+
+	resp = env.do(http.MethodPost, path+":parse", map[string]any{"text": "купить молоко"})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "no_schedule", decode[errorResponse](t, resp).Code)
+}
+
+func TestListDeliveries(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	scheduled := time.Date(2026, time.June, 2, 7, 0, 0, 0, time.UTC)
+	for i, chatID := range []int64{testUserID, testUserID, foreignGroupID} {
+		require.NoError(t, env.remUC.RecordAttempt(ctx, &domain.DeliveryAttempt{
+			ReminderID: 1, ChatID: chatID, Kind: domain.DeliveryReminder, Text: "⏰ Напоминание: чай",
+			ScheduledAt: scheduled, AttemptedAt: scheduled.Add(time.Duration(i) * time.Minute),
+			Outcome: domain.OutcomeSent, MessageID: 10 + i,
+		}))
+	}
+
+	resp := env.do(http.MethodGet, "/api/v1/chats/"+itoa(testUserID)+"/deliveries?limit=1", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := decode[deliveryListResponse](t, resp)
+	assert.Equal(t, "Europe/Berlin", body.Timezone)
+	require.Len(t, body.Deliveries, 1)
+	got := body.Deliveries[0]
+	assert.Equal(t, 11, got.MessageID, "newest first")
+	assert.Equal(t, "sent", got.Outcome)
+	assert.Equal(t, "reminder", got.Kind)
+	assert.Equal(t, scheduled, got.ScheduledAt)
+
+	resp = env.do(http.MethodGet, "/api/v1/chats/"+itoa(testUserID)+"/deliveries?limit=abc", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = env.do(http.MethodGet, "/api/v1/chats/"+itoa(foreignGroupID)+"/deliveries", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
 // --- Часовой пояс ---------------------------------------------------------
 
 func TestSetTimezone(t *testing.T) {
@@ -648,6 +1001,57 @@ func TestSetTimezone(t *testing.T) {
 	assert.Equal(t, "Asia/Tokyo", chat.Timezone)
 }
 
+func TestSetTimezone_RebasesReminders(t *testing.T) {
+	env := newTestEnv(t)
+	chatPath := "/api/v1/chats/" + itoa(testUserID)
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	resp := env.do(http.MethodPut, chatPath+"/timezone", map[string]any{"timezone": "Europe/Moscow"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = env.do(http.MethodPost, chatPath+"/reminders", map[string]any{
+		"text": "зарядка", "time": "09:00", "repeat": "daily",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	created := decode[reminderDTO](t, resp)
+	require.Equal(t, 9, created.NextTime.In(moscow).Hour())
+	resp = env.do(http.MethodPost, chatPath+"/reminders", map[string]any{
+		"text": "созвон", "time": "09:00", "repeat": "daily", "timezone": "America/New_York",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	own := decode[reminderDTO](t, resp)
+
+	resp = env.do(http.MethodPut, chatPath+"/timezone", map[string]any{"timezone": "Asia/Tokyo"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, decode[timezoneResponse](t, resp).Rebased, "own-zone reminders are not rebased")
+
+	rebased, err := env.remUC.GetReminder(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 9, rebased.NextTime.In(tokyo).Hour(), "wall-clock time is kept")
+	untouched, err := env.remUC.GetReminder(context.Background(), own.ID)
+	require.NoError(t, err)
+	assert.True(t, untouched.NextTime.Equal(own.NextTime))
+
+	t.Run("момент срабатывания", func(t *testing.T) {
+		resp := env.do(http.MethodPut, chatPath+"/timezone", map[string]any{
+			"timezone": "Europe/Moscow", "rebase": "instant",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Zero(t, decode[timezoneResponse](t, resp).Rebased)
+
+		kept, err := env.remUC.GetReminder(context.Background(), created.ID)
+		require.NoError(t, err)
+		assert.True(t, kept.NextTime.Equal(rebased.NextTime))
+	})
+
+	t.Run("неизвестный режим", func(t *testing.T) {
+		resp := env.do(http.MethodPut, chatPath+"/timezone", map[string]any{
+			"timezone": "Europe/Moscow", "rebase": "sometimes",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
 func TestSetTimezone_RejectsUnknownZone(t *testing.T) {
 	env := newTestEnv(t)
 
@@ -718,3 +1122,389 @@ func TestSecurityHeaders(t *testing.T) {
 func itoa(v int64) string {
 	return strconv.FormatInt(v, 10)
 }
+
+func TestCreateReminder_SeveralTimesPerDay(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":   "принять лекарство",
+		"repeat": "daily",
+		"time":   []string{"21:00", "09:00", "13:00"},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, []string{"09:00", "13:00", "21:00"}, created.Times)
+	assert.Contains(t, created.Times, created.NextTime.In(loc).Format(timeLayout))
+	assert.True(t, created.NextTime.After(time.Now()))
+
+	t.Run("одна строка сбрасывает список", func(t *testing.T) {
+		resp := env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"time": "08:30"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		updated := decode[reminderDTO](t, resp)
+		assert.Empty(t, updated.Times)
+		assert.Equal(t, "08:30", updated.NextTime.In(loc).Format(timeLayout))
+	})
+
+	t.Run("разовое напоминание отвергается", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":   "созвон",
+			"repeat": "none",
+			"date":   time.Now().In(loc).AddDate(0, 0, 1).Format("02.01.2006"),
+			"time":   []string{"09:00", "13:00"},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("неверное время в массиве", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":   "созвон",
+			"repeat": "daily",
+			"time":   []string{"09:00", "25:00"},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCreateReminder_Interval(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":             "размяться",
+		"repeat":           "interval",
+		"interval_minutes": 120,
+		"window_start":     "10:00",
+		"window_end":       "18:00",
+		"repeat_days":      []int{1, 2, 3, 4, 5},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "interval", created.Repeat)
+	assert.Equal(t, 120, created.IntervalMinutes)
+	assert.Equal(t, "10:00", created.WindowStart)
+	assert.Equal(t, "18:00", created.WindowEnd)
+
+	local := created.NextTime.In(loc)
+	assert.NotContains(t, []time.Weekday{time.Saturday, time.Sunday}, local.Weekday())
+	assert.Contains(t, []int{10, 12, 14, 16, 18}, local.Hour())
+	assert.Zero(t, local.Minute())
+
+	t.Run("окно задаётся обоими концами", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":             "размяться",
+			"repeat":           "interval",
+			"interval_minutes": 60,
+			"window_start":     "10:00",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("слишком частый интервал", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":             "спам",
+			"repeat":           "interval",
+			"interval_minutes": 1,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCreateReminder_Cron(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":   "планёрка",
+		"repeat": "cron",
+		"cron":   "30 9 * * MON-FRI",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "cron", created.Repeat)
+	assert.Equal(t, "30 9 * * mon-fri", created.Cron)
+
+	local := created.NextTime.In(loc)
+	assert.NotContains(t, []time.Weekday{time.Saturday, time.Sunday}, local.Weekday())
+	assert.Equal(t, 9, local.Hour())
+	assert.Equal(t, 30, local.Minute())
+
+	for name, expr := range map[string]string{
+		"без выражения":      "",
+		"лишнее поле":        "0 9 * * * *",
+		"каждую минуту":      "* * * * *",
+		"неизвестный макрос": "@reboot",
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := env.do(http.MethodPost, path, map[string]any{"text": "спам", "repeat": "cron", "cron": expr})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestCreateReminder_RandomWindow(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":         "записать настроение",
+		"repeat":       "daily",
+		"window_start": "10:00",
+		"window_end":   "12:00",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "10:00", created.WindowStart)
+	assert.Equal(t, "12:00", created.WindowEnd)
+	local := created.NextTime.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	assert.GreaterOrEqual(t, minutes, 10*60)
+	assert.LessOrEqual(t, minutes, 12*60)
+	assert.True(t, created.NextTime.After(time.Now()))
+
+	t.Run("время по часам снимает окно", func(t *testing.T) {
+		resp := env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"time": "09:00"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		updated := decode[reminderDTO](t, resp)
+		assert.Empty(t, updated.WindowStart)
+		assert.Equal(t, 9, updated.NextTime.In(loc).Hour())
+		assert.Equal(t, 0, updated.NextTime.In(loc).Minute())
+	})
+}
+
+func TestCreateReminder_Solar(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	body := map[string]any{
+		"text":         "полить цветы",
+		"repeat":       "solar",
+		"solar_event":  "sunset",
+		"solar_offset": -30,
+	}
+
+	resp := env.do(http.MethodPost, path, body)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "без геопозиции закат не посчитать")
+
+	moscow := domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	require.NoError(t, env.chatUC.SetGeo(context.Background(), testUserID, moscow))
+
+	resp = env.do(http.MethodPost, path, body)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "solar", created.Repeat)
+	assert.Equal(t, "sunset", created.SolarEvent)
+	assert.Equal(t, -30, created.SolarOffset)
+	assert.Empty(t, created.Times)
+
+	want, err := scheduling.NextSolar(domain.SolarSunset, -30, moscow, time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, want, created.NextTime, time.Minute)
+
+	t.Run("неизвестное событие", func(t *testing.T) {
+		resp := env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"solar_event": "noon"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCreateReminder_OwnTimezone(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/reminders"
+	newYork, _ := time.LoadLocation("America/New_York")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	resp := env.do(http.MethodPost, path, map[string]any{
+		"text":     "созвон с Нью-Йорком",
+		"time":     "09:00",
+		"repeat":   "daily",
+		"timezone": "America/New_York",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "America/New_York", created.Timezone)
+	assert.Equal(t, 9, created.NextTime.In(newYork).Hour())
+	assert.True(t, created.LocalTime.Equal(created.NextTime))
+	_, offset := created.ChatTime.Zone()
+	_, berlinOffset := created.NextTime.In(berlin).Zone()
+	assert.Equal(t, berlinOffset, offset)
+
+	t.Run("пустая зона возвращает время к зоне чата", func(t *testing.T) {
+		resp := env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{
+			"timezone": "",
+			"time":     "09:00",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		updated := decode[reminderDTO](t, resp)
+		assert.Empty(t, updated.Timezone)
+		assert.Equal(t, 9, updated.NextTime.In(berlin).Hour())
+	})
+
+	t.Run("неизвестная зона", func(t *testing.T) {
+		resp := env.do(http.MethodPost, path, map[string]any{
+			"text":     "созвон",
+			"time":     "09:00",
+			"repeat":   "daily",
+			"timezone": "Mars/Olympus",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// --- Производственный календарь --------------------------------------------
+
+func (e *testEnv) doRaw(method, path, body string) *http.Response {
+	e.t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, e.server.URL+path, strings.NewReader(body))
+	require.NoError(e.t, err)
+	req.Header.Set("Authorization", "tma "+initData())
+
+	resp, err := e.server.Client().Do(req)
+	require.NoError(e.t, err)
+	e.t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestCalendar_SetAndImport(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/calendar"
+
+	resp := env.do(http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, calendarDTO{Workweek: []int{1, 2, 3, 4, 5}, Days: []calendarDayDTO{}}, decode[calendarDTO](t, resp))
+
+	resp = env.do(http.MethodPut, path, map[string]any{
+		"workweek": []int{1, 2, 3, 4, 5, 6},
+		"days":     []map[string]any{{"date": "2026-01-01", "working": false}},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = env.doRaw(http.MethodPost, path+"/import", "2026-01-02 выходной\n2026-01-01 рабочий\n")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, calendarDTO{
+		Workweek: []int{1, 2, 3, 4, 5, 6},
+		Days: []calendarDayDTO{
+			{Date: "2026-01-01", Working: true},
+			{Date: "2026-01-02", Working: false},
+		},
+	}, decode[calendarDTO](t, resp))
+
+	resp = env.doRaw(http.MethodPost, path+"/import", "завтра")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "invalid_request", decode[errorResponse](t, resp).Code)
+}
+
+func TestCreateReminder_WorkdaysOnly(t *testing.T) {
+	env := newTestEnv(t)
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Рабочий день в неделе один — через три дня: ежедневное напоминание «только
+	// по рабочим» обязано дождаться его.
+	workday := time.Now().In(loc).AddDate(0, 0, 3).Weekday()
+	resp := env.do(http.MethodPut, "/api/v1/chats/"+itoa(testUserID)+"/calendar",
+		map[string]any{"workweek": []int{int(workday)}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = env.do(http.MethodPost, "/api/v1/chats/"+itoa(testUserID)+"/reminders", map[string]any{
+		"text":     "планёрка",
+		"repeat":   "daily",
+		"time":     "09:00",
+		"workdays": "skip",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := decode[reminderDTO](t, resp)
+	assert.Equal(t, "skip", created.Workdays)
+	assert.Equal(t, workday, created.NextTime.In(loc).Weekday())
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"workdays": "next"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	updated := decode[reminderDTO](t, resp)
+	assert.Equal(t, "next", updated.Workdays)
+	assert.Equal(t, workday, updated.NextTime.In(loc).Weekday())
+	require.NotNil(t, updated.ShiftedFrom)
+
+	resp = env.do(http.MethodPatch, "/api/v1/reminders/"+itoa(created.ID), map[string]any{"workdays": "sometimes"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSetCatchUp(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/catch-up"
+
+	resp := env.do(http.MethodPut, path, map[string]any{"policy": "summary", "after_minutes": 30})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body := decode[chatDTO](t, resp)
+	assert.Equal(t, "summary", body.CatchUp)
+	assert.Equal(t, 30, body.CatchUpAfterMinutes)
+	assert.Equal(t, "Europe/Berlin", body.Timezone, "other chat settings must survive")
+
+	chat, err := env.chatUC.Get(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CatchUp{Policy: domain.CatchUpSummary, After: 30 * time.Minute}, chat.CatchUp)
+
+	for _, req := range []map[string]any{
+		{"policy": "sometimes"},
+		{"policy": "skip", "after_minutes": 100_000},
+	} {
+		resp = env.do(http.MethodPut, path, req)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestSetSettings_QuietHours(t *testing.T) {
+	env := newTestEnv(t)
+	path := "/api/v1/chats/" + itoa(testUserID) + "/settings"
+
+	resp := env.do(http.MethodPut, path, map[string]any{
+		"quiet_hours": map[string]any{"start": "23:00", "end": "08:00", "mode": "silent"},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body := decode[chatDTO](t, resp)
+	require.NotNil(t, body.QuietHours)
+	assert.Equal(t, quietHoursDTO{Start: "23:00", End: "08:00", Mode: "silent"}, *body.QuietHours)
+	assert.Equal(t, "Europe/Berlin", body.Timezone, "other chat settings must survive")
+
+	chat, err := env.chatUC.Get(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.QuietHours{Start: 23 * 60, End: 8 * 60, Mode: domain.QuietSilent}, chat.Quiet)
+
+	// Запрос без тихих часов их не трогает.
+	resp = env.do(http.MethodPut, path, map[string]any{})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, decode[chatDTO](t, resp).QuietHours)
+
+	for _, quiet := range []map[string]any{
+		{"start": "23:00", "end": "23:00"},
+		{"start": "25:00", "end": "08:00"},
+		{"start": "23:00", "end": "08:00", "mode": "loud"},
+	} {
+		resp = env.do(http.MethodPut, path, map[string]any{"quiet_hours": quiet})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+
+	resp = env.do(http.MethodPut, path, map[string]any{"quiet_hours": map[string]any{"start": "", "end": ""}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, decode[chatDTO](t, resp).QuietHours)
+
+	chat, err = env.chatUC.Get(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.False(t, chat.Quiet.Enabled())
+}
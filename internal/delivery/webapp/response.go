@@ -8,6 +8,7 @@ import (
 
 	"github.com/8thgencore/dory-reminder-bot/internal/delivery/webapp/authz"
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/quickadd"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
 	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/8thgencore/dory-reminder-bot/internal/usecase"
@@ -54,6 +55,34 @@ func (s *server) writeDomainError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrTooManyReminders):
 		writeError(w, http.StatusConflict, "too_many_reminders", err.Error())
 
+	case errors.Is(err, domain.ErrTooManyExceptions):
+		writeError(w, http.StatusConflict, "too_many_exceptions", err.Error())
+
+	case errors.Is(err, scheduling.ErrSeriesEnded):
+		writeError(w, http.StatusConflict, "series_ended",
+			"У серии не остаётся срабатываний — удалите напоминание")
+
+	case errors.Is(err, scheduling.ErrNoWorkdays):
+		writeError(w, http.StatusConflict, "no_workdays",
+			"По календарю чата у расписания не остаётся рабочих дней")
+
+	case errors.Is(err, domain.ErrNoLocation):
+		writeError(w, http.StatusConflict, "no_location",
+			"Сначала отправьте боту геопозицию — по ней считаются восход и закат")
+
+	case errors.Is(err, domain.ErrInstantRebase):
+		writeError(w, http.StatusConflict, "rebase_conflict",
+			"У части напоминаний время задано по часам — выберите «Сохранить время по часам»")
+
+	case errors.Is(err, quickadd.ErrNoSchedule):
+		writeError(w, http.StatusBadRequest, "no_schedule", "Во фразе не нашлось ни даты, ни времени, ни повтора")
+
+	case errors.Is(err, quickadd.ErrTodayPassed):
+		writeError(w, http.StatusBadRequest, "today_passed", "Это время сегодня уже прошло")
+
+	case errors.Is(err, quickadd.ErrInvalidTime):
+		writeError(w, http.StatusBadRequest, "invalid_time", "Такого времени суток нет")
+
 	case errors.Is(err, usecase.ErrInvalidTimezone):
 		writeError(w, http.StatusBadRequest, "invalid_timezone", "Неизвестный часовой пояс")
 
@@ -61,6 +90,13 @@ func (s *server) writeDomainError(w http.ResponseWriter, err error) {
 		errors.Is(err, domain.ErrTextTooLong),
 		errors.Is(err, domain.ErrInvalidChatID),
 		errors.Is(err, domain.ErrInvalidRepeat),
+		errors.Is(err, domain.ErrInvalidException),
+		errors.Is(err, domain.ErrInvalidCalendar),
+		errors.Is(err, domain.ErrInvalidCatchUp),
+		errors.Is(err, domain.ErrInvalidQuietHours),
+		errors.Is(err, domain.ErrInvalidLocation),
+		errors.Is(err, domain.ErrInvalidFollowUp),
+		errors.Is(err, domain.ErrFollowUpCycle),
 		errors.Is(err, repository.ErrInvalidReminder),
 		errors.Is(err, scheduling.ErrInvalidDate),
 		errors.Is(err, scheduling.ErrInvalidInterval):
@@ -16,12 +16,20 @@ func (s *server) routes() http.Handler {
 
 	api.HandleFunc("GET /api/v1/chats/{chatID}", s.handleGetChat)
 	api.HandleFunc("PUT /api/v1/chats/{chatID}/timezone", s.handleSetTimezone)
+	api.HandleFunc("PUT /api/v1/chats/{chatID}/catch-up", s.handleSetCatchUp)
+	api.HandleFunc("PUT /api/v1/chats/{chatID}/settings", s.handleSetSettings)
+	api.HandleFunc("GET /api/v1/chats/{chatID}/calendar", s.handleGetCalendar)
+	api.HandleFunc("PUT /api/v1/chats/{chatID}/calendar", s.handleSetCalendar)
+	api.HandleFunc("POST /api/v1/chats/{chatID}/calendar/import", s.handleImportCalendar)
 	api.HandleFunc("GET /api/v1/chats/{chatID}/reminders", s.handleListReminders)
 	api.HandleFunc("POST /api/v1/chats/{chatID}/reminders", s.handleCreateReminder)
+	api.HandleFunc("POST /api/v1/chats/{chatID}/reminders:parse", s.handleParseReminder)
+	api.HandleFunc("GET /api/v1/chats/{chatID}/deliveries", s.handleListDeliveries)
 
 	api.HandleFunc("GET /api/v1/reminders/{id}", s.handleGetReminder)
 	api.HandleFunc("PATCH /api/v1/reminders/{id}", s.handleUpdateReminder)
 	api.HandleFunc("DELETE /api/v1/reminders/{id}", s.handleDeleteReminder)
+	api.HandleFunc("POST /api/v1/reminders/{id}/exceptions", s.handleAddException)
 
 	root := http.NewServeMux()
 
@@ -1,7 +1,9 @@
 package webapp
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
@@ -16,19 +18,61 @@ const timeLayout = "15:04"
 //
 // Все поля запроса — указатели, поэтому один и тот же код обслуживает и POST (напоминание
 // пустое, обязательные поля проверяются), и PATCH (пропущенные поля сохраняют значение).
+// loc — зона чата; даты и времена запроса читаются в собственной зоне напоминания, если она есть.
 func (s *server) applyRequest(rem *domain.Reminder, req reminderRequest, loc *time.Location) error {
 	if req.Text != nil {
 		rem.Text = *req.Text
 	}
+	if req.Timezone != nil {
+		rem.Timezone = *req.Timezone
+	}
+	loc = rem.Location(loc)
 	if req.Paused != nil {
 		rem.Paused = *req.Paused
 	}
+	if req.NagEveryMinutes != nil {
+		rem.NagEveryMinutes = *req.NagEveryMinutes
+	}
+	if req.NagMax != nil {
+		rem.NagMax = *req.NagMax
+	}
+	if req.LeadMinutes != nil {
+		rem.LeadMinutes = *req.LeadMinutes
+	}
 	if req.RepeatDays != nil {
 		rem.RepeatDays = *req.RepeatDays
 	}
 	if req.RepeatEvery != nil {
 		rem.RepeatEvery = *req.RepeatEvery
 	}
+	if req.MonthOrdinal != nil {
+		rem.MonthOrdinal = *req.MonthOrdinal
+	}
+	if req.RRule != nil {
+		rem.RRule = *req.RRule
+	}
+	if req.Cron != nil {
+		rem.Cron = *req.Cron
+	}
+	if req.IntervalMinutes != nil {
+		rem.IntervalMinutes = *req.IntervalMinutes
+	}
+	if req.SolarEvent != nil {
+		event, err := parseSolarEvent(*req.SolarEvent)
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidRepeat, err)
+		}
+		rem.SolarEvent = event
+	}
+	if req.SolarOffset != nil {
+		rem.SolarOffset = *req.SolarOffset
+	}
+	if err := applyWindow(rem, req); err != nil {
+		return err
+	}
+	if err := applyEnd(rem, req, loc); err != nil {
+		return err
+	}
 	if req.Repeat != nil {
 		repeat, err := parseRepeat(*req.Repeat)
 		if err != nil {
@@ -36,6 +80,25 @@ func (s *server) applyRequest(rem *domain.Reminder, req reminderRequest, loc *ti
 		}
 		rem.Repeat = repeat
 	}
+	if req.Workdays != nil {
+		policy, err := parseWorkdays(*req.Workdays)
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidRepeat, err)
+		}
+		rem.WorkdayPolicy = policy
+	}
+	if err := applyFollowUp(rem, req); err != nil {
+		return err
+	}
+	if rem.IsFollowUp() {
+		// Время продолжения назначает событие родителя, а до него напоминание ждёт.
+		// Валидации нужно непустое время: ставим текущее, его никто не увидит.
+		if rem.NextTime.IsZero() {
+			rem.NextTime = time.Now().UTC()
+		}
+
+		return nil
+	}
 
 	// Время срабатывания пересчитывается, только если клиент прислал что-то влияющее
 	// на расписание. Иначе PATCH с одним лишь paused сдвинул бы ближайший запуск.
@@ -43,6 +106,53 @@ func (s *server) applyRequest(rem *domain.Reminder, req reminderRequest, loc *ti
 		return nil
 	}
 
+	if rem.Repeat == domain.RepeatInterval {
+		// Времени суток у интервального повтора нет: срабатывания задают шаг и окно.
+		rem.Times = nil
+		next, err := scheduling.NextInterval(time.Now().In(loc), rem)
+		if err != nil {
+			return err
+		}
+		rem.NextTime = next.UTC()
+
+		return alignToWorkday(rem, loc)
+	}
+
+	if rem.Repeat == domain.RepeatCron {
+		// Время суток задаёт само выражение.
+		rem.Times = nil
+		next, err := scheduling.NextCron(rem.Cron, time.Now(), loc)
+		if err != nil {
+			return err
+		}
+		rem.NextTime = next
+
+		return alignToWorkday(rem, loc)
+	}
+
+	if rem.Repeat == domain.RepeatSolar {
+		// Время суток задаёт солнце над координатами чата.
+		rem.Times = nil
+		if rem.Geo == nil {
+			return domain.ErrNoLocation
+		}
+		next, err := scheduling.NextSolar(rem.SolarEvent, rem.SolarOffset, *rem.Geo, time.Now())
+		if err != nil {
+			return err
+		}
+		rem.NextTime = next
+
+		return alignToWorkday(rem, loc)
+	}
+
+	if req.Time != nil && req.WindowStart == nil {
+		// Время по часам заменяет окно случайного времени.
+		rem.WindowStart, rem.WindowEnd = 0, 0
+	}
+	if rem.HasRandomWindow() {
+		return s.applyRandomWindow(rem, req, loc)
+	}
+
 	clock, err := resolveClock(req, rem, loc)
 	if err != nil {
 		return err
@@ -52,33 +162,215 @@ func (s *server) applyRequest(rem *domain.Reminder, req reminderRequest, loc *ti
 	if err != nil {
 		return err
 	}
+	if len(rem.Times) > 0 {
+		next = scheduling.EarliestTimeInDay(next.In(loc), time.Now(), rem.Times)
+	}
 	rem.NextTime = next.UTC()
 
+	return alignToWorkday(rem, loc)
+}
+
+// applyRandomWindow назначает первое срабатывание напоминанию со случайным окном:
+// ближайший день расписания, окно которого ещё не закончилось, и случайную минуту в нём.
+func (s *server) applyRandomWindow(rem *domain.Reminder, req reminderRequest, loc *time.Location) error {
+	rem.Times = nil
+	now := time.Now()
+	day, err := s.firstOccurrence(rem, req, clockAt(rem.WindowEnd, loc), loc)
+	if err != nil {
+		return err
+	}
+	rem.NextTime = scheduling.FirstWindowTime(rem, day, now, loc)
+
+	return alignToWorkday(rem, loc)
+}
+
+// alignToWorkday применяет WorkdayPolicy к первому срабатыванию пересчитанного расписания:
+// без этого серия, начатая в праздник, сработала бы в него. Календарь чата загружает
+// вызывающий код.
+func alignToWorkday(rem *domain.Reminder, loc *time.Location) error {
+	rem.ShiftedFrom = time.Time{}
+	if rem.WorkdayPolicy == domain.WorkdayAny || rem.Repeat == domain.RepeatNone {
+		return nil
+	}
+
+	next, err := scheduling.NextOccurrence(rem, time.Now(), loc)
+	if err != nil {
+		return err
+	}
+	next.Apply(rem)
+
 	return nil
 }
 
-// affectsSchedule сообщает, влияет ли запрос на расписание.
+// affectsSchedule сообщает, влияет ли запрос на расписание. Отвязанное от родителя
+// продолжение получает своё расписание заново.
 func affectsSchedule(req reminderRequest) bool {
 	return req.Time != nil || req.Date != nil || req.Repeat != nil ||
-		req.RepeatDays != nil || req.RepeatEvery != nil
+		req.RepeatDays != nil || req.RepeatEvery != nil || req.MonthOrdinal != nil || req.RRule != nil ||
+		req.Cron != nil || req.IntervalMinutes != nil || req.WindowStart != nil || req.WindowEnd != nil ||
+		req.SolarEvent != nil || req.SolarOffset != nil || req.Workdays != nil || req.Timezone != nil ||
+		req.ParentID != nil
+}
+
+// applyFollowUp переносит поля цепочки. Родителя и циклы проверяет ReminderUsecase.
+func applyFollowUp(rem *domain.Reminder, req reminderRequest) error {
+	if req.ParentID != nil {
+		rem.ParentID = *req.ParentID
+	}
+	if req.FollowOffsetMinutes != nil {
+		rem.FollowOffset = *req.FollowOffsetMinutes
+	}
+	if req.FollowOn != nil {
+		trigger, err := parseFollowTrigger(*req.FollowOn)
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidFollowUp, err)
+		}
+		rem.FollowOn = trigger
+	}
+
+	return nil
+}
+
+// applyEnd переносит условия окончания серии. Дата окончания включительна: серия
+// может сработать в любое время последнего дня по часовому поясу чата.
+func applyEnd(rem *domain.Reminder, req reminderRequest, loc *time.Location) error {
+	if req.RemainingCount != nil {
+		rem.RemainingCount = *req.RemainingCount
+	}
+	if req.EndsAt == nil {
+		return nil
+	}
+	if *req.EndsAt == "" {
+		rem.EndsAt = time.Time{}
+		return nil
+	}
+
+	day, err := validator.ParseDateDDMMYYYY(*req.EndsAt, loc)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a valid DD.MM.YYYY date", scheduling.ErrInvalidDate, *req.EndsAt)
+	}
+	rem.EndsAt = time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, loc).UTC()
+
+	return nil
+}
+
+// exceptionFromRequest собирает исключение для срабатывания occurrence. Новые дата и время
+// задаются в поясе чата; недостающая часть берётся у исходного срабатывания.
+func exceptionFromRequest(
+	occurrence time.Time,
+	req exceptionRequest,
+	loc *time.Location,
+) (domain.OccurrenceException, error) {
+	e := domain.OccurrenceException{Occurrence: occurrence}
+	if req.Date == nil && req.Time == nil {
+		return e, nil
+	}
+
+	local := occurrence.In(loc)
+	year, month, day := local.Date()
+	minutes := local.Hour()*60 + local.Minute()
+
+	if req.Date != nil {
+		date, err := validator.ParseDateDDMMYYYY(*req.Date, loc)
+		if err != nil {
+			return e, fmt.Errorf("%w: %q is not a valid DD.MM.YYYY date", scheduling.ErrInvalidDate, *req.Date)
+		}
+		year, month, day = date.Date()
+	}
+	if req.Time != nil {
+		clock, err := parseClockMinutes(*req.Time)
+		if err != nil {
+			return e, err
+		}
+		minutes = clock
+	}
+	e.MovedTo = time.Date(year, month, day, minutes/60, minutes%60, 0, 0, loc).UTC()
+
+	return e, nil
+}
+
+// applyWindow переносит окно интервального повтора из запроса. Окно задаётся обоими
+// концами сразу либо снимается двумя пустыми строками.
+func applyWindow(rem *domain.Reminder, req reminderRequest) error {
+	if req.WindowStart == nil && req.WindowEnd == nil {
+		return nil
+	}
+	if req.WindowStart == nil || req.WindowEnd == nil {
+		return fmt.Errorf("%w: window_start and window_end must be set together", domain.ErrInvalidRepeat)
+	}
+	if *req.WindowStart == "" && *req.WindowEnd == "" {
+		rem.WindowStart, rem.WindowEnd = 0, 0
+		return nil
+	}
+
+	start, err := parseClockMinutes(*req.WindowStart)
+	if err != nil {
+		return err
+	}
+	end, err := parseClockMinutes(*req.WindowEnd)
+	if err != nil {
+		return err
+	}
+	rem.WindowStart, rem.WindowEnd = start, end
+
+	return nil
+}
+
+// parseClockMinutes разбирает ЧЧ:ММ в минуты от полуночи.
+func parseClockMinutes(value string) (int, error) {
+	clock, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a valid HH:MM time", scheduling.ErrInvalidDate, value)
+	}
+
+	return clock.Hour()*60 + clock.Minute(), nil
 }
 
 // resolveClock определяет время суток: из запроса или из уже сохранённого напоминания.
+//
+// Если времён в день несколько, они записываются в rem.Times, а возвращается последнее:
+// по нему ищется ближайший подходящий день, в котором ещё осталось срабатывание.
 func resolveClock(req reminderRequest, rem *domain.Reminder, loc *time.Location) (time.Time, error) {
 	if req.Time == nil {
+		if len(rem.Times) > 0 {
+			return clockAt(rem.Times[len(rem.Times)-1], loc), nil
+		}
 		if rem.NextTime.IsZero() {
 			return time.Time{}, fmt.Errorf("%w: time is required", scheduling.ErrInvalidDate)
 		}
 
 		return rem.NextTime.In(loc), nil
 	}
+	if len(*req.Time) == 0 {
+		return time.Time{}, fmt.Errorf("%w: time is required", scheduling.ErrInvalidDate)
+	}
+	if len(*req.Time) > domain.MaxTimesPerDay {
+		return time.Time{}, fmt.Errorf("%w: cannot have more than %d times per day",
+			domain.ErrInvalidRepeat, domain.MaxTimesPerDay)
+	}
 
-	clock, err := time.ParseInLocation(timeLayout, *req.Time, loc)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("%w: %q is not a valid HH:MM time", scheduling.ErrInvalidDate, *req.Time)
+	times := make([]int, 0, len(*req.Time))
+	for _, value := range *req.Time {
+		minutes, err := parseClockMinutes(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		times = append(times, minutes)
 	}
+	slices.Sort(times)
+	times = slices.Compact(times)
 
-	return clock, nil
+	rem.Times = nil
+	if len(times) > 1 {
+		rem.Times = times
+	}
+
+	return clockAt(times[len(times)-1], loc), nil
+}
+
+// clockAt собирает время суток из минут от полуночи.
+func clockAt(minutes int, loc *time.Location) time.Time {
+	return time.Date(0, time.January, 1, minutes/60, minutes%60, 0, 0, loc)
 }
 
 // firstOccurrence вычисляет ближайшее срабатывание для выбранного типа повтора.
@@ -105,6 +397,9 @@ func (s *server) firstOccurrence(
 		return s.earliestWeekday(now, clock, rem.RepeatDays)
 
 	case domain.RepeatEveryMonth:
+		if rem.MonthOrdinal != 0 {
+			return scheduling.NextMonthWeekday(now, clock, rem.MonthOrdinal, rem.RepeatDays)
+		}
 		if len(rem.RepeatDays) == 0 {
 			return time.Time{}, fmt.Errorf("%w: day of month is required", scheduling.ErrInvalidDate)
 		}
@@ -124,6 +419,9 @@ func (s *server) firstOccurrence(
 		}
 
 		return scheduling.NextYearDay(now, clock, dayMonth)
+
+	case domain.RepeatRRule:
+		return s.firstRRuleOccurrence(rem, req, now, clock, loc)
 	}
 
 	return time.Time{}, fmt.Errorf("%w: unsupported repeat type", domain.ErrInvalidRepeat)
@@ -187,6 +485,43 @@ func (s *server) firstNDaysOccurrence(
 	return next, nil
 }
 
+// firstRRuleOccurrence определяет DTSTART правила и первое срабатывание после now.
+//
+// DTSTART собирается из даты запроса, а без неё — из даты прежнего DTSTART, чтобы PATCH
+// одного лишь времени не сбивал отсчёт INTERVAL и COUNT. Новое правило без даты
+// начинается сегодня.
+func (s *server) firstRRuleOccurrence(
+	rem *domain.Reminder,
+	req reminderRequest,
+	now, clock time.Time,
+	loc *time.Location,
+) (time.Time, error) {
+	day := now
+	switch {
+	case req.Date != nil:
+		parsed, err := validator.ParseDateDDMMYYYY(*req.Date, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q is not a valid DD.MM.YYYY date", scheduling.ErrInvalidDate, *req.Date)
+		}
+		day = parsed
+	case !rem.StartTime.IsZero():
+		day = rem.StartTime.In(loc)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+
+	next, err := scheduling.NextRRule(rem.RRule, start, now, loc)
+	if errors.Is(err, scheduling.ErrSeriesEnded) {
+		return time.Time{}, fmt.Errorf("%w: rrule has no occurrences in the future", domain.ErrInvalidRepeat)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	rem.StartTime = start.UTC()
+
+	return next, nil
+}
+
 // toDayMonth отбрасывает год у даты ДД.ММ.ГГГГ: ежегодный повтор задаётся днём и месяцем.
 func toDayMonth(date string) (string, error) {
 	if validator.IsDateDDMM(date) {
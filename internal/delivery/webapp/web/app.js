@@ -18,6 +18,17 @@ const WEEKDAYS = [
   { value: 0, short: 'Вс' },
 ];
 
+/** Будни — набор дней для варианта «последний рабочий день месяца». */
+const WORKDAYS = [1, 2, 3, 4, 5];
+
+const ORDINAL_LABELS = {
+  1: 'первый',
+  2: 'второй',
+  3: 'третий',
+  4: 'четвёртый',
+  '-1': 'последний',
+};
+
 const REPEAT_LABELS = {
   none: 'один раз',
   daily: 'каждый день',
@@ -25,6 +36,55 @@ const REPEAT_LABELS = {
   monthly: 'раз в месяц',
   every_n_days: 'каждые N дней',
   yearly: 'раз в год',
+  rrule: 'по правилу',
+  interval: 'каждые N минут',
+  cron: 'по cron',
+  solar: 'по солнцу',
+};
+
+/** Солнечные события: «до заката» и «на закате». */
+const SOLAR_EVENTS = {
+  sunrise: { of: 'восхода', at: 'на восходе' },
+  sunset: { of: 'заката', at: 'на закате' },
+  dawn: { of: 'утренних сумерек', at: 'в утренние сумерки' },
+  dusk: { of: 'вечерних сумерек', at: 'в вечерние сумерки' },
+};
+
+/** Наибольший сдвиг от солнечного события в минутах, в обе стороны. */
+const MAX_SOLAR_OFFSET = 360;
+
+/** Наибольший сдвиг продолжения от события родителя в минутах — 90 дней. */
+const MAX_FOLLOW_OFFSET = 90 * 1440;
+
+/** События родителя, после которых срабатывает продолжение: «после доставки». */
+const FOLLOW_EVENTS = {
+  delivered: 'доставки',
+  acknowledged: '«Готово»',
+};
+
+/** Поля собственного расписания: у продолжения их нет. */
+const SCHEDULE_FIELDS = [
+  'field-repeat-wrap', 'field-time-wrap', 'field-interval-wrap', 'field-window-wrap', 'field-times-wrap',
+  'field-weekdays-wrap', 'field-monthmode-wrap', 'field-monthday-wrap', 'field-ordinal-wrap',
+  'field-every-wrap', 'field-rrule-wrap', 'field-cron-wrap', 'field-solar-wrap', 'field-date-wrap',
+  'field-end-wrap', 'field-workdays-wrap',
+];
+
+/** Пояснения к поведению в нерабочие дни для списка напоминаний. */
+const WORKDAYS_LABELS = {
+  skip: 'только по рабочим дням',
+  previous: 'с нерабочих дней — на предыдущий рабочий',
+  next: 'с нерабочих дней — на следующий рабочий',
+};
+
+/** Повторы, которые вместо времени по часам могут срабатывать в случайное время в окне. */
+const RANDOM_WINDOW_REPEATS = new Set(['none', 'daily', 'weekly', 'monthly', 'every_n_days', 'yearly']);
+
+/** Сокращённые единицы шага для «раз в N недель/месяцев/лет». */
+const STEP_UNITS = {
+  weekly: { short: 'нед.', label: 'Раз в сколько недель' },
+  monthly: { short: 'мес.', label: 'Раз в сколько месяцев' },
+  yearly: { short: 'г.', label: 'Раз в сколько лет' },
 };
 
 /** Текущее состояние приложения. */
@@ -36,6 +96,10 @@ const state = {
   reminders: [],
   editing: null,
   selectedWeekdays: new Set(),
+  // Производственный календарь открытого в настройках чата.
+  // calendarDays === null — календарь не загрузился, и сохранять его нельзя.
+  workweek: new Set(WORKDAYS),
+  calendarDays: null,
 };
 
 let mainButtonSyncVersion = 0;
@@ -110,26 +174,194 @@ function formatDateTime(iso, timezone) {
   return dateTimeFormatter('ru-RU', options, timezone).format(date);
 }
 
-/** Собирает человекочитаемое описание повтора. */
+/** Описывает ближайшее срабатывание; у напоминания со своей зоной — ещё и по времени чата. */
+function describeNextTime(reminder) {
+  if (reminder.waiting) {
+    // Время продолжения назначит событие родителя.
+    return 'ждёт срабатывания';
+  }
+  const chatTime = formatDateTime(reminder.next_time, state.timezone);
+  if (!reminder.timezone || reminder.timezone === state.timezone) {
+    return chatTime;
+  }
+
+  return `${formatDateTime(reminder.next_time, reminder.timezone)} (${reminder.timezone}), в чате ${chatTime}`;
+}
+
+/** Собирает человекочитаемое описание повтора вместе со временами срабатывания. */
 function describeRepeat(reminder) {
+  if (reminder.parent_id) {
+    return describeFollowUp(reminder) + describeLeads(reminder) + describeNag(reminder);
+  }
+  const kind = describeRepeatKind(reminder);
+  const times = reminder.times || [];
+
+  let text = times.length ? `${kind}, в ${times.join(', ')}` : kind;
+  if (reminder.repeat !== 'interval' && reminder.window_start) {
+    text += `, в случайное время ${reminder.window_start}–${reminder.window_end}`;
+  }
+  if (WORKDAYS_LABELS[reminder.workdays]) {
+    text += `, ${WORKDAYS_LABELS[reminder.workdays]}`;
+  }
+
+  return text + describeEnd(reminder) + describeLeads(reminder) + describeNag(reminder);
+}
+
+/** Описывает продолжение цепочки: «через 3д после доставки «полить рассаду»». */
+function describeFollowUp(reminder) {
+  const when = reminder.follow_offset_minutes ? `через ${formatLead(reminder.follow_offset_minutes)}` : 'сразу';
+  const parent = state.reminders.find((other) => other.id === reminder.parent_id);
+  if (!parent) {
+    return `${when} после удалённого напоминания`;
+  }
+
+  return `${when} после ${FOLLOW_EVENTS[reminder.follow_on] || FOLLOW_EVENTS.delivered} «${parent.text}»`;
+}
+
+/** Описывает предупреждения перед срабатыванием: «, заранее за 3д, 1ч». */
+function describeLeads(reminder) {
+  const leads = reminder.lead_minutes || [];
+  if (!leads.length) {
+    return '';
+  }
+
+  return `, заранее за ${leads.map(formatLead).join(', ')}`;
+}
+
+/** Записывает отступ предупреждения в самых крупных целых единицах: «3д», «2ч», «90м». */
+function formatLead(minutes) {
+  if (minutes % 1440 === 0) {
+    return `${minutes / 1440}д`;
+  }
+  if (minutes % 60 === 0) {
+    return `${minutes / 60}ч`;
+  }
+
+  return `${minutes}м`;
+}
+
+/** Описывает режим подтверждения: «, до подтверждения: каждые 15 мин, максимум 3». */
+function describeNag(reminder) {
+  if (!reminder.nag_every_minutes) {
+    return '';
+  }
+
+  return `, до подтверждения: каждые ${reminder.nag_every_minutes} мин, максимум ${reminder.nag_max}`;
+}
+
+/** Описывает условия окончания серии: «, до 07.08.2026, ещё 3 раза». */
+function describeEnd(reminder) {
+  let text = '';
+  if (reminder.ends_at) {
+    const options = { day: '2-digit', month: '2-digit', year: 'numeric' };
+    text += `, до ${dateTimeFormatter('ru-RU', options, state.timezone).format(new Date(reminder.ends_at))}`;
+  }
+  if (reminder.remaining_count) {
+    text += `, ещё ${reminder.remaining_count} раз`;
+  }
+
+  return text;
+}
+
+/** Описывает тип повтора без учёта времён срабатывания. */
+function describeRepeatKind(reminder) {
   switch (reminder.repeat) {
     case 'weekly': {
       const names = (reminder.repeat_days || [])
         .map((d) => (WEEKDAYS.find((w) => w.value === d) || {}).short)
         .filter(Boolean);
-      return names.length ? `еженедельно: ${names.join(', ')}` : 'еженедельно';
+      const weekly = stepLabel(reminder, 'еженедельно');
+      return names.length ? `${weekly}: ${names.join(', ')}` : weekly;
     }
     case 'monthly': {
-      const day = (reminder.repeat_days || [])[0];
-      return day ? `ежемесячно, ${day}-го числа` : 'ежемесячно';
+      const days = reminder.repeat_days || [];
+      const monthly = stepLabel(reminder, 'ежемесячно');
+      switch (monthModeOf(reminder)) {
+        case 'last':
+          return `${monthly}, в последний день`;
+        case 'lastwork':
+          return `${monthly}, в последний рабочий день`;
+        case 'nth': {
+          const names = days
+            .map((d) => (WEEKDAYS.find((w) => w.value === d) || {}).short)
+            .filter(Boolean);
+          return `${monthly}, ${ORDINAL_LABELS[reminder.month_ordinal]}: ${names.join(', ')}`;
+        }
+        default:
+          return days[0] ? `${monthly}, ${days[0]}-го числа` : monthly;
+      }
     }
+    case 'yearly':
+      return stepLabel(reminder, REPEAT_LABELS.yearly);
     case 'every_n_days':
       return `каждые ${reminder.repeat_every} дн.`;
+    case 'rrule':
+      return `по правилу ${reminder.rrule}`;
+    case 'interval':
+      return describeInterval(reminder);
+    case 'cron':
+      return `по cron ${reminder.cron}`;
+    case 'solar':
+      return describeSolar(reminder);
     default:
       return REPEAT_LABELS[reminder.repeat] || reminder.repeat;
   }
 }
 
+/** Описывает повтор с шагом: «раз в 2 нед.»; шаг 1 — обычная подпись single. */
+function stepLabel(reminder, single) {
+  const step = reminder.repeat_every || 0;
+  const unit = STEP_UNITS[reminder.repeat];
+
+  return step > 1 && unit ? `раз в ${step} ${unit.short}` : single;
+}
+
+/** Описывает солнечный повтор: «каждый день за 30 мин до заката». */
+function describeSolar(reminder) {
+  const event = SOLAR_EVENTS[reminder.solar_event || 'sunrise'];
+  if (!event) {
+    return REPEAT_LABELS.solar;
+  }
+  const offset = reminder.solar_offset || 0;
+  const minutes = Math.abs(offset);
+  const amount = minutes % 60 === 0 ? `${minutes / 60} ч` : `${minutes} мин`;
+  if (offset < 0) {
+    return `каждый день за ${amount} до ${event.of}`;
+  }
+  if (offset > 0) {
+    return `каждый день через ${amount} после ${event.of}`;
+  }
+
+  return `каждый день ${event.at}`;
+}
+
+/** Описывает интервальный повтор: «каждые 2 ч, 10:00–18:00, Пн, Вт». */
+function describeInterval(reminder) {
+  const minutes = reminder.interval_minutes || 0;
+  const hours = Math.floor(minutes / 60);
+  const rest = minutes % 60;
+  const parts = [];
+  if (hours) {
+    parts.push(`${hours} ч`);
+  }
+  if (rest || !hours) {
+    parts.push(`${rest} мин`);
+  }
+
+  let text = `каждые ${parts.join(' ')}`;
+  if (reminder.window_start && reminder.window_end) {
+    text += `, ${reminder.window_start}–${reminder.window_end}`;
+  }
+  const days = (reminder.repeat_days || [])
+    .map((d) => (WEEKDAYS.find((w) => w.value === d) || {}).short)
+    .filter(Boolean);
+  if (days.length) {
+    text += `, ${days.join(', ')}`;
+  }
+
+  return text;
+}
+
 /** Возвращает ЧЧ:ММ в часовом поясе чата — для предзаполнения формы. */
 function timeInZone(iso, timezone) {
   const options = { hour: '2-digit', minute: '2-digit', hour12: false };
@@ -259,7 +491,10 @@ function renderReminder(reminder) {
 
   const meta = document.createElement('p');
   meta.className = 'reminder__meta';
-  meta.textContent = `${formatDateTime(reminder.next_time, state.timezone)} · ${describeRepeat(reminder)}`;
+  meta.textContent = `${describeNextTime(reminder)} · ${describeRepeat(reminder)}`;
+  if (reminder.notice_at && !reminder.paused) {
+    meta.textContent += ` · предупреждение ${formatDateTime(reminder.notice_at, state.timezone)}`;
+  }
   item.appendChild(meta);
 
   const actions = document.createElement('div');
@@ -268,6 +503,9 @@ function renderReminder(reminder) {
   actions.appendChild(
     makeButton(reminder.paused ? 'Возобновить' : 'Пауза', () => togglePause(reminder)),
   );
+  if (reminder.repeat !== 'none') {
+    actions.appendChild(makeButton('Пропустить', () => skipNext(reminder)));
+  }
   actions.appendChild(makeButton('Удалить', () => confirmDelete(reminder), 'danger'));
   item.appendChild(actions);
 
@@ -300,6 +538,18 @@ async function togglePause(reminder) {
   }
 }
 
+// skipNext пропускает ближайшее срабатывание серии; расписание остаётся прежним.
+async function skipNext(reminder) {
+  try {
+    await api(`/reminders/${reminder.id}/exceptions`, { method: 'POST', body: '{}' });
+    haptic('success');
+    await loadReminders();
+  } catch (error) {
+    haptic('error');
+    showAlert(error.message);
+  }
+}
+
 function confirmDelete(reminder) {
   const remove = async () => {
     try {
@@ -336,8 +586,12 @@ function showAlert(message) {
 
 // --- Форма ----------------------------------------------------------------
 
-function buildWeekdayButtons() {
-  const container = $('field-weekdays');
+/**
+ * Строит переключатели дней недели. selected возвращает текущий набор: форма
+ * и настройки заменяют его целиком при открытии.
+ */
+function buildWeekdayButtons(containerId = 'field-weekdays', selected = () => state.selectedWeekdays) {
+  const container = $(containerId);
   container.textContent = '';
 
   for (const day of WEEKDAYS) {
@@ -347,11 +601,12 @@ function buildWeekdayButtons() {
     button.textContent = day.short;
     button.setAttribute('aria-pressed', 'false');
     button.addEventListener('click', () => {
-      if (state.selectedWeekdays.has(day.value)) {
-        state.selectedWeekdays.delete(day.value);
+      const days = selected();
+      if (days.has(day.value)) {
+        days.delete(day.value);
         button.setAttribute('aria-pressed', 'false');
       } else {
-        state.selectedWeekdays.add(day.value);
+        days.add(day.value);
         button.setAttribute('aria-pressed', 'true');
       }
     });
@@ -359,28 +614,58 @@ function buildWeekdayButtons() {
   }
 }
 
-function syncWeekdayButtons() {
-  const buttons = $('field-weekdays').querySelectorAll('.weekday');
+function syncWeekdayButtons(containerId = 'field-weekdays', selected = state.selectedWeekdays) {
+  const buttons = $(containerId).querySelectorAll('.weekday');
   buttons.forEach((button, index) => {
-    const selected = state.selectedWeekdays.has(WEEKDAYS[index].value);
-    button.setAttribute('aria-pressed', selected ? 'true' : 'false');
+    const pressed = selected.has(WEEKDAYS[index].value);
+    button.setAttribute('aria-pressed', pressed ? 'true' : 'false');
   });
 }
 
 /** Показывает поля, относящиеся к выбранному типу повтора. */
 function syncFormFields() {
   const repeat = $('field-repeat').value;
-
-  $('field-weekdays-wrap').hidden = repeat !== 'weekly';
-  $('field-monthday-wrap').hidden = repeat !== 'monthly';
-  $('field-every-wrap').hidden = repeat !== 'every_n_days';
-
-  const needsDate = repeat === 'none' || repeat === 'yearly' || repeat === 'every_n_days';
+  const followUp = $('field-parent').value !== '';
+  const monthMode = repeat === 'monthly' ? $('field-monthmode').value : '';
+
+  $('field-weekdays-wrap').hidden = repeat !== 'weekly' && repeat !== 'interval' && monthMode !== 'nth';
+  $('field-time-wrap').hidden = repeat === 'interval' || repeat === 'cron' || repeat === 'solar';
+  $('field-interval-wrap').hidden = repeat !== 'interval';
+  $('field-window-wrap').hidden = repeat !== 'interval' && !RANDOM_WINDOW_REPEATS.has(repeat);
+  $('field-window-label').textContent = repeat === 'interval'
+    ? 'Окно (необязательно): с — до'
+    : 'Или в случайное время в окне: с — до';
+  $('field-monthmode-wrap').hidden = repeat !== 'monthly';
+  $('field-monthday-wrap').hidden = monthMode !== 'day';
+  $('field-ordinal-wrap').hidden = monthMode !== 'nth';
+  $('field-every-wrap').hidden = repeat !== 'every_n_days' && !STEP_UNITS[repeat];
+  $('field-every-label').textContent = STEP_UNITS[repeat]
+    ? STEP_UNITS[repeat].label
+    : 'Повторять каждые (дней)';
+  $('field-rrule-wrap').hidden = repeat !== 'rrule';
+  $('field-cron-wrap').hidden = repeat !== 'cron';
+  $('field-solar-wrap').hidden = repeat !== 'solar';
+  // Разовое напоминание удаляется после первой отправки: второе время ему ни к чему.
+  $('field-times-wrap').hidden = repeat === 'none' || repeat === 'interval' || repeat === 'cron'
+    || repeat === 'solar';
+  $('field-end-wrap').hidden = repeat === 'none';
+  $('field-workdays-wrap').hidden = repeat === 'none';
+
+  const needsDate = repeat === 'none' || repeat === 'yearly' || repeat === 'every_n_days' || repeat === 'rrule';
   $('field-date-wrap').hidden = !needsDate;
 
+  // У продолжения нет своего расписания: время назначает событие родителя.
+  $('field-repeat-wrap').hidden = false;
+  $('field-follow-wrap').hidden = !followUp;
+  if (followUp) {
+    SCHEDULE_FIELDS.forEach((id) => {
+      $(id).hidden = true;
+    });
+  }
+
   if (repeat === 'yearly') {
     $('field-date-label').textContent = 'Дата (год не важен)';
-  } else if (repeat === 'every_n_days') {
+  } else if (repeat === 'every_n_days' || repeat === 'rrule') {
     $('field-date-label').textContent = 'Дата начала (необязательно)';
   } else {
     $('field-date-label').textContent = 'Дата';
@@ -396,28 +681,74 @@ function openForm(reminder) {
   const time = $('field-time');
 
   state.selectedWeekdays = new Set();
+  fillParentOptions(reminder);
 
   if (reminder) {
+    // Время и даты в форме — по зоне напоминания, если она своя.
+    const zone = reminder.timezone || state.timezone;
     text.value = reminder.text;
     repeat.value = reminder.repeat;
-    time.value = timeInZone(reminder.next_time, state.timezone);
+    $('field-reminder-tz').value = reminder.timezone || '';
+    const times = reminder.times || [];
+    time.value = times.length ? times[0] : timeInZone(reminder.next_time, zone);
+    $('field-times').value = times.slice(1).join(', ');
 
-    if (reminder.repeat === 'weekly') {
+    if (reminder.repeat === 'weekly' || reminder.repeat === 'interval') {
       state.selectedWeekdays = new Set(reminder.repeat_days || []);
     }
+    $('field-interval').value = reminder.interval_minutes || '';
+    $('field-window-start').value = reminder.window_start || '';
+    $('field-window-end').value = reminder.window_end || '';
     if (reminder.repeat === 'monthly') {
-      $('field-monthday').value = (reminder.repeat_days || [])[0] || '';
+      const mode = monthModeOf(reminder);
+      $('field-monthmode').value = mode;
+      $('field-monthday').value = mode === 'day' ? (reminder.repeat_days || [])[0] || '' : '';
+      if (mode === 'nth') {
+        $('field-ordinal').value = String(reminder.month_ordinal);
+        state.selectedWeekdays = new Set(reminder.repeat_days || []);
+      }
     }
-    if (reminder.repeat === 'every_n_days') {
+    if (reminder.repeat === 'every_n_days' || STEP_UNITS[reminder.repeat]) {
       $('field-every').value = reminder.repeat_every || '';
     }
-    $('field-date').value = isoToDateInput(reminder.next_time, state.timezone);
+    $('field-rrule').value = reminder.rrule || '';
+    $('field-cron').value = reminder.cron || '';
+    $('field-solar-event').value = reminder.solar_event || 'sunrise';
+    $('field-solar-offset').value = reminder.solar_offset || '';
+    $('field-ends').value = reminder.ends_at ? isoToDateInput(reminder.ends_at, zone) : '';
+    $('field-count').value = reminder.remaining_count || '';
+    $('field-workdays').value = reminder.workdays || '';
+    $('field-nag-every').value = reminder.nag_every_minutes || '';
+    $('field-nag-max').value = reminder.nag_max || '';
+    $('field-lead').value = (reminder.lead_minutes || []).map(formatLead).join(', ');
+    $('field-follow-offset').value = reminder.follow_offset_minutes ? formatLead(reminder.follow_offset_minutes) : '';
+    $('field-follow-on').value = reminder.follow_on || 'delivered';
+    // Для правила дата в форме — начало серии: от неё отсчитываются INTERVAL и COUNT.
+    $('field-date').value = isoToDateInput(reminder.start_time || reminder.next_time, zone);
   } else {
     text.value = '';
     repeat.value = 'none';
+    $('field-reminder-tz').value = '';
     time.value = '09:00';
+    $('field-times').value = '';
+    $('field-interval').value = '';
+    $('field-window-start').value = '';
+    $('field-window-end').value = '';
+    $('field-monthmode').value = 'day';
     $('field-monthday').value = '';
     $('field-every').value = '';
+    $('field-rrule').value = '';
+    $('field-cron').value = '';
+    $('field-solar-event').value = 'sunset';
+    $('field-solar-offset').value = '';
+    $('field-ends').value = '';
+    $('field-count').value = '';
+    $('field-workdays').value = '';
+    $('field-nag-every').value = '';
+    $('field-nag-max').value = '';
+    $('field-lead').value = '';
+    $('field-follow-offset').value = '';
+    $('field-follow-on').value = 'delivered';
     $('field-date').value = isoToDateInput(new Date().toISOString(), state.timezone);
   }
 
@@ -427,6 +758,176 @@ function openForm(reminder) {
   showView('form');
 }
 
+/** Заполняет выбор родителя напоминаниями чата, кроме самого редактируемого. */
+function fillParentOptions(reminder) {
+  const select = $('field-parent');
+  select.textContent = '';
+
+  const none = document.createElement('option');
+  none.value = '';
+  none.textContent = 'Нет, своё расписание';
+  select.appendChild(none);
+  for (const other of state.reminders) {
+    if (reminder && other.id === reminder.id) {
+      continue;
+    }
+    const option = document.createElement('option');
+    option.value = String(other.id);
+    option.textContent = other.text;
+    select.appendChild(option);
+  }
+  select.value = reminder && reminder.parent_id ? String(reminder.parent_id) : '';
+}
+
+/** Определяет вариант ежемесячного повтора для поля выбора в форме. */
+function monthModeOf(reminder) {
+  const days = reminder.repeat_days || [];
+  if (!reminder.month_ordinal) {
+    return days[0] === -1 ? 'last' : 'day';
+  }
+  if (reminder.month_ordinal === -1 && days.join(',') === WORKDAYS.join(',')) {
+    return 'lastwork';
+  }
+  return 'nth';
+}
+
+/** Собирает поля интервального повтора: шаг, окно и дни недели. */
+function collectInterval() {
+  const every = Number($('field-interval').value);
+  if (!Number.isInteger(every) || every < 5 || every > 1440) {
+    throw new Error('Укажите интервал от 5 до 1440 минут');
+  }
+
+  return {
+    interval_minutes: every,
+    ...collectWindow(),
+    repeat_days: [...state.selectedWeekdays].sort((a, b) => a - b),
+  };
+}
+
+/** Собирает окно внутри дня; оба конца пустые — окна нет. */
+function collectWindow() {
+  const start = $('field-window-start').value;
+  const end = $('field-window-end').value;
+  if (Boolean(start) !== Boolean(end)) {
+    throw new Error('Укажите оба конца окна или оставьте оба пустыми');
+  }
+  if (start && start >= end) {
+    throw new Error('Окно должно начинаться раньше, чем заканчивается');
+  }
+
+  return { window_start: start, window_end: end };
+}
+
+/** Собирает поля ежемесячного повтора по выбранному варианту. */
+function collectMonthly() {
+  switch ($('field-monthmode').value) {
+    case 'last':
+      return { repeat_days: [-1], month_ordinal: 0 };
+    case 'lastwork':
+      return { repeat_days: WORKDAYS, month_ordinal: -1 };
+    case 'nth': {
+      if (state.selectedWeekdays.size === 0) {
+        throw new Error('Выберите хотя бы один день недели');
+      }
+      return {
+        repeat_days: [...state.selectedWeekdays].sort((a, b) => a - b),
+        month_ordinal: Number($('field-ordinal').value),
+      };
+    }
+    default: {
+      const day = Number($('field-monthday').value);
+      if (!Number.isInteger(day) || day < 1 || day > 31) {
+        throw new Error('Укажите число месяца от 1 до 31');
+      }
+      return { repeat_days: [day], month_ordinal: 0 };
+    }
+  }
+}
+
+/** Собирает условия окончания серии; пустые поля снимают прежние условия. */
+function collectEnd() {
+  const count = Number($('field-count').value || 0);
+  if (!Number.isInteger(count) || count < 0) {
+    throw new Error('Число повторов должно быть целым');
+  }
+
+  return { ends_at: dateInputToAPI($('field-ends').value) || '', remaining_count: count };
+}
+
+/**
+ * Собирает режим подтверждения: пустой интервал выключает повторы, пустой предел —
+ * три повтора.
+ */
+/** Собирает солнечное событие и сдвиг от него; отрицательный сдвиг — раньше события. */
+function collectSolar() {
+  const offset = Number($('field-solar-offset').value || 0);
+  if (!Number.isInteger(offset) || Math.abs(offset) > MAX_SOLAR_OFFSET) {
+    throw new Error(`Сдвиг от события — целое число минут от -${MAX_SOLAR_OFFSET} до ${MAX_SOLAR_OFFSET}`);
+  }
+
+  return { solar_event: $('field-solar-event').value, solar_offset: offset };
+}
+
+function collectNag() {
+  const every = Number($('field-nag-every').value || 0);
+  if (!every) {
+    return { nag_every_minutes: 0, nag_max: 0 };
+  }
+  if (!Number.isInteger(every) || every < 1 || every > 1440) {
+    throw new Error('Повторять до подтверждения можно каждые 1–1440 минут');
+  }
+  const max = Number($('field-nag-max').value || 3);
+  if (!Number.isInteger(max) || max < 1 || max > 20) {
+    throw new Error('Повторов до подтверждения может быть от 1 до 20');
+  }
+
+  return { nag_every_minutes: every, nag_max: max };
+}
+
+/**
+ * Собирает предупреждения перед срабатыванием из строки вида «3д, 1ч, 30м»; число без
+ * единицы — минуты. Пустое поле снимает предупреждения.
+ */
+function collectLeads() {
+  const parts = $('field-lead').value.split(/[,;]/).map((part) => part.trim()).filter(Boolean);
+  const leads = parts.map((part) => {
+    const minutes = parseMinutes(part);
+    if (minutes === null) {
+      throw new Error('Предупреждения укажите через запятую, например: 3д, 1ч, 30м');
+    }
+
+    return minutes;
+  });
+  if (leads.length > 5 || leads.some((lead) => lead < 1 || lead > 30 * 1440)) {
+    throw new Error('Предупреждений может быть до 5, каждое — не раньше чем за 30 дней');
+  }
+
+  return { lead_minutes: leads };
+}
+
+/** Собирает сдвиг продолжения от события родителя: «3д», «1ч», «30м»; пустое поле — сразу. */
+function collectFollowUp() {
+  const value = $('field-follow-offset').value.trim();
+  const offset = value ? parseMinutes(value) : 0;
+  if (offset === null || offset > MAX_FOLLOW_OFFSET) {
+    throw new Error('Укажите, через сколько после события: до 90 дней, например 3д, 1ч, 30м');
+  }
+
+  return { follow_offset_minutes: offset, follow_on: $('field-follow-on').value };
+}
+
+/** Переводит «3д», «1 ч» или «30м» в минуты; число без единицы — минуты. Не разобрать — null. */
+function parseMinutes(value) {
+  const units = { д: 1440, ч: 60, м: 1 };
+  const match = /^(\d+)\s*([дчм])?$/.exec(value.toLowerCase());
+  if (!match) {
+    return null;
+  }
+
+  return Number(match[1]) * units[match[2] || 'м'];
+}
+
 /** Переводит момент времени в значение для <input type="date"> (ГГГГ-ММ-ДД). */
 function isoToDateInput(iso, timezone) {
   const options = { year: 'numeric', month: '2-digit', day: '2-digit' };
@@ -451,20 +952,95 @@ function updateTextCounter() {
   $('text-counter').textContent = String($('field-text').value.length);
 }
 
-/** Собирает тело запроса из полей формы, проверяя обязательные значения. */
+/**
+ * Собирает тело запроса из полей формы, проверяя обязательные значения. У продолжения
+ * вместо расписания — родитель, сдвиг и событие.
+ */
 function collectFormPayload() {
+  const parentId = Number($('field-parent').value || 0);
+  if (!parentId) {
+    const payload = collectSchedulePayload();
+    if (state.editing && state.editing.parent_id) {
+      // Отвязанное продолжение получает расписание из формы.
+      payload.parent_id = 0;
+    }
+
+    return payload;
+  }
+
+  const text = $('field-text').value.trim();
+  if (!text) {
+    throw new Error('Введите текст напоминания');
+  }
+
+  return {
+    text,
+    repeat: 'none',
+    timezone: $('field-reminder-tz').value.trim(),
+    parent_id: parentId,
+    ...collectFollowUp(),
+    ...collectLeads(),
+    ...collectNag(),
+  };
+}
+
+/** Собирает поля собственного расписания напоминания. */
+function collectSchedulePayload() {
   const repeat = $('field-repeat').value;
   const text = $('field-text').value.trim();
   const time = $('field-time').value;
+  // Пустая зона — зона чата; неизвестную отклонит сервер.
+  const timezone = $('field-reminder-tz').value.trim();
 
   if (!text) {
     throw new Error('Введите текст напоминания');
   }
-  if (!time) {
+  if (repeat === 'interval') {
+    return {
+      text, repeat, timezone, ...collectInterval(), ...collectEnd(), ...collectLeads(), ...collectNag(),
+      workdays: $('field-workdays').value,
+    };
+  }
+  if (repeat === 'cron') {
+    // Время срабатывания задаёт само выражение.
+    const cron = $('field-cron').value.trim();
+    if (!cron) {
+      throw new Error('Укажите выражение cron');
+    }
+
+    return {
+      text, repeat, cron, timezone, ...collectEnd(), ...collectLeads(), ...collectNag(),
+      workdays: $('field-workdays').value,
+    };
+  }
+  if (repeat === 'solar') {
+    // Время срабатывания задаёт солнце над геопозицией чата.
+    return {
+      text, repeat, timezone, ...collectSolar(), ...collectEnd(), ...collectLeads(), ...collectNag(),
+      workdays: $('field-workdays').value,
+    };
+  }
+  // Окно вместо времени: каждое срабатывание — в случайную минуту внутри него.
+  const randomWindow = collectWindow();
+  if (!time && !randomWindow.window_start) {
     throw new Error('Укажите время');
   }
 
-  const payload = { text, time, repeat };
+  const schedule = randomWindow.window_start ? randomWindow : { time };
+  const payload = { text, repeat, timezone, ...schedule, ...collectLeads(), ...collectNag() };
+  if (repeat !== 'none') {
+    Object.assign(payload, collectEnd(), { workdays: $('field-workdays').value });
+  }
+
+  if (repeat !== 'none' && !randomWindow.window_start) {
+    const extra = $('field-times').value.split(/[\s,;]+/).filter(Boolean);
+    if (extra.some((value) => !/^([01]\d|2[0-3]):[0-5]\d$/.test(value))) {
+      throw new Error('Дополнительное время укажите в формате ЧЧ:ММ через запятую');
+    }
+    if (extra.length) {
+      payload.time = [time, ...extra];
+    }
+  }
 
   if (repeat === 'weekly') {
     if (state.selectedWeekdays.size === 0) {
@@ -474,11 +1050,17 @@ function collectFormPayload() {
   }
 
   if (repeat === 'monthly') {
-    const day = Number($('field-monthday').value);
-    if (!Number.isInteger(day) || day < 1 || day > 31) {
-      throw new Error('Укажите число месяца от 1 до 31');
+    Object.assign(payload, collectMonthly());
+  }
+
+  if (STEP_UNITS[repeat]) {
+    // Пустое поле — каждую неделю (месяц, год). Единицу отправляем явно, чтобы
+    // при редактировании можно было убрать ранее заданный шаг.
+    const every = Number($('field-every').value || 1);
+    if (!Number.isInteger(every) || every < 1 || every > 365) {
+      throw new Error('Шаг повтора должен быть от 1 до 365');
     }
-    payload.repeat_days = [day];
+    payload.repeat_every = every;
   }
 
   if (repeat === 'every_n_days') {
@@ -494,6 +1076,19 @@ function collectFormPayload() {
     }
   }
 
+  if (repeat === 'rrule') {
+    const rule = $('field-rrule').value.trim();
+    if (!rule) {
+      throw new Error('Укажите правило повтора');
+    }
+    payload.rrule = rule;
+
+    const date = dateInputToAPI($('field-date').value);
+    if (date) {
+      payload.date = date;
+    }
+  }
+
   if (repeat === 'none' || repeat === 'yearly') {
     const date = dateInputToAPI($('field-date').value);
     if (!date) {
@@ -575,6 +1170,19 @@ async function openSettings(options = {}) {
 
   const detected = detectTimezone();
   select.value = state.timezone || detected || 'UTC';
+  // Пересчитывать нечего, пока часовой пояс чата не задан.
+  $('tz-rebase-field').hidden = !state.timezone;
+  $('field-tz-rebase').value = 'wall_clock';
+
+  const chat = state.chats.find((item) => item.id === state.chatId) || {};
+  $('field-catchup').value = chat.catch_up || 'late';
+  $('field-catchup-after').value = chat.catch_up_after_minutes || '';
+  const quiet = chat.quiet_hours || {};
+  $('field-quiet-start').value = quiet.start || '';
+  $('field-quiet-end').value = quiet.end || '';
+  $('field-quiet-mode').value = quiet.mode || 'defer';
+
+  await loadCalendar();
 
   const hint = $('tz-detected');
   if (!state.timezone && detected) {
@@ -585,6 +1193,60 @@ async function openSettings(options = {}) {
   }
 }
 
+/** Загружает производственный календарь чата в настройки. */
+async function loadCalendar() {
+  state.calendarDays = null;
+  try {
+    renderCalendar(await api(`/chats/${state.chatId}/calendar`));
+  } catch (error) {
+    // Часовой пояс настраивается и без календаря — ошибку показываем рядом.
+    $('settings-error').textContent = error.message;
+    $('settings-error').hidden = false;
+  }
+}
+
+function renderCalendar(calendar) {
+  state.workweek = new Set(calendar.workweek || WORKDAYS);
+  state.calendarDays = calendar.days || [];
+  syncWeekdayButtons('settings-workweek', state.workweek);
+
+  const holidays = state.calendarDays.filter((day) => !day.working).length;
+  const transfers = state.calendarDays.length - holidays;
+  $('calendar-summary').textContent = state.calendarDays.length
+    ? `Праздников: ${holidays}, рабочих переносов: ${transfers}`
+    : 'Праздники не загружены';
+}
+
+/** Отправляет выбранный файл календаря на сервер как есть: формат разбирает сервер. */
+async function importCalendar(event) {
+  const file = event.target.files && event.target.files[0];
+  if (!file) {
+    return;
+  }
+
+  const errorBox = $('settings-error');
+  errorBox.hidden = true;
+  try {
+    const calendar = await api(`/chats/${state.chatId}/calendar/import`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'text/plain; charset=utf-8' },
+      body: await file.text(),
+    });
+    // Рабочая неделя могла быть изменена, но ещё не сохранена — не сбрасываем её.
+    const workweek = state.workweek;
+    renderCalendar(calendar);
+    state.workweek = workweek;
+    syncWeekdayButtons('settings-workweek', state.workweek);
+    haptic('success');
+  } catch (error) {
+    errorBox.textContent = error.message;
+    errorBox.hidden = false;
+    haptic('error');
+  } finally {
+    event.target.value = '';
+  }
+}
+
 /** Определяет часовой пояс устройства — избавляет от ручного ввода. */
 function detectTimezone() {
   try {
@@ -598,18 +1260,65 @@ async function saveSettings() {
   const errorBox = $('settings-error');
   errorBox.hidden = true;
 
+  const after = Number($('field-catchup-after').value || 0);
+  if (!Number.isInteger(after) || after < 0 || after > 1440) {
+    errorBox.textContent = 'Порог опоздания — от 1 до 1440 минут';
+    errorBox.hidden = false;
+    haptic('error');
+
+    return;
+  }
+
+  const quietStart = $('field-quiet-start').value;
+  const quietEnd = $('field-quiet-end').value;
+  if (Boolean(quietStart) !== Boolean(quietEnd) || (quietStart && quietStart === quietEnd)) {
+    errorBox.textContent = 'Укажите начало и конец тихих часов — или оставьте оба поля пустыми';
+    errorBox.hidden = false;
+    haptic('error');
+
+    return;
+  }
+
   if (tg) {
     tg.MainButton.showProgress();
   }
 
   try {
-    await api(`/chats/${state.chatId}/timezone`, {
+    const { rebased } = await api(`/chats/${state.chatId}/timezone`, {
+      method: 'PUT',
+      body: JSON.stringify({ timezone: $('field-timezone').value, rebase: $('field-tz-rebase').value }),
+    });
+    const updated = await api(`/chats/${state.chatId}/catch-up`, {
+      method: 'PUT',
+      body: JSON.stringify({ policy: $('field-catchup').value, after_minutes: after }),
+    });
+    const settings = await api(`/chats/${state.chatId}/settings`, {
       method: 'PUT',
-      body: JSON.stringify({ timezone: $('field-timezone').value }),
+      body: JSON.stringify({
+        quiet_hours: { start: quietStart, end: quietEnd, mode: $('field-quiet-mode').value },
+      }),
     });
+    const chat = state.chats.find((item) => item.id === state.chatId);
+    if (chat) {
+      chat.catch_up = updated.catch_up;
+      chat.catch_up_after_minutes = updated.catch_up_after_minutes;
+      chat.quiet_hours = settings.quiet_hours;
+    }
+    if (state.calendarDays) {
+      await api(`/chats/${state.chatId}/calendar`, {
+        method: 'PUT',
+        body: JSON.stringify({
+          workweek: [...state.workweek].sort((a, b) => a - b),
+          days: state.calendarDays,
+        }),
+      });
+    }
     haptic('success');
     await loadReminders();
     showView('list');
+    if (rebased > 0) {
+      showAlert(`Пересчитано напоминаний: ${rebased}`);
+    }
   } catch (error) {
     errorBox.textContent = error.message;
     errorBox.hidden = false;
@@ -737,8 +1446,11 @@ async function bootstrap() {
 
 function wireEvents() {
   $('field-repeat').addEventListener('change', syncFormFields);
+  $('field-monthmode').addEventListener('change', syncFormFields);
+  $('field-parent').addEventListener('change', syncFormFields);
   $('field-text').addEventListener('input', updateTextCounter);
   $('settings-button').addEventListener('click', openSettings);
+  $('calendar-file').addEventListener('change', importCalendar);
 
   $('chat-select').addEventListener('change', async (event) => {
     try {
@@ -780,5 +1492,6 @@ function wireEvents() {
 }
 
 buildWeekdayButtons();
+buildWeekdayButtons('settings-workweek', () => state.workweek);
 wireEvents();
 bootstrap();
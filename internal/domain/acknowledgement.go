@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAlreadyAcknowledged возвращается при повторном подтверждении доставки.
+var ErrAlreadyAcknowledged = errors.New("delivery is already acknowledged")
+
+// Acknowledgement — доставка напоминания в режиме подтверждения, которую ждут
+// или уже дождались: кто и когда нажал «Готово», сколько раз она повторена.
+//
+// Текст и параметры повторов копируются из напоминания при доставке: разового
+// напоминания к моменту повтора уже нет, а правка серии не должна менять то,
+// о чём чату уже напомнили.
+type Acknowledgement struct {
+	ID         int64
+	ReminderID int64
+	ChatID     int64
+	Text       string
+	// MessageID — первое сообщение доставки: после подтверждения в нём отмечается, кто его дал.
+	MessageID int
+	// Nags — сколько повторов уже отправлено; NextNagAt — время следующего, нулевое —
+	// повторов больше не будет.
+	Nags            int
+	NagMax          int
+	NagEveryMinutes int
+	NextNagAt       time.Time
+	// AckedBy — пользователь Telegram, нажавший «Готово»; AckedAt нулевое, пока его не нажали.
+	AckedBy     int64
+	AckedByName string
+	AckedAt     time.Time
+	CreatedAt   time.Time
+}
+
+// NewAcknowledgement начинает ожидание подтверждения доставки r, отправленной в момент
+// sentAt сообщением messageID.
+func NewAcknowledgement(r *Reminder, messageID int, sentAt time.Time) *Acknowledgement {
+	return &Acknowledgement{
+		ReminderID:      r.ID,
+		ChatID:          r.ChatID,
+		Text:            r.Text,
+		MessageID:       messageID,
+		NagMax:          r.NagMax,
+		NagEveryMinutes: r.NagEveryMinutes,
+		NextNagAt:       sentAt.Add(time.Duration(r.NagEveryMinutes) * time.Minute).UTC(),
+		CreatedAt:       sentAt.UTC(),
+	}
+}
+
+// Delivered отмечает, что первое сообщение доставки messageID ушло в чат в момент at.
+// Повторы отсчитываются от него: до отправки сообщение могло долго стоять в очереди.
+func (a *Acknowledgement) Delivered(messageID int, at time.Time) {
+	a.MessageID = messageID
+	a.NextNagAt = at.Add(time.Duration(a.NagEveryMinutes) * time.Minute).UTC()
+}
+
+// Acknowledged сообщает, подтверждена ли доставка.
+func (a *Acknowledgement) Acknowledged() bool {
+	return !a.AckedAt.IsZero()
+}
+
+// Nagged отмечает отправленный в момент at повтор и назначает следующий,
+// если предел ещё не исчерпан.
+func (a *Acknowledgement) Nagged(at time.Time) {
+	a.Nags++
+	if a.Nags >= a.NagMax {
+		a.NextNagAt = time.Time{}
+		return
+	}
+	a.NextNagAt = at.Add(time.Duration(a.NagEveryMinutes) * time.Minute).UTC()
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcknowledgementNagged(t *testing.T) {
+	sent := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+	a := NewAcknowledgement(&Reminder{ID: 1, ChatID: 42, Text: "полить цветы", NagEveryMinutes: 30, NagMax: 2}, 5, sent)
+	assert.Equal(t, sent.Add(30*time.Minute), a.NextNagAt)
+
+	a.Nagged(sent.Add(31 * time.Minute))
+	assert.Equal(t, 1, a.Nags)
+	assert.Equal(t, sent.Add(61*time.Minute), a.NextNagAt)
+
+	a.Nagged(sent.Add(61 * time.Minute))
+	assert.Equal(t, 2, a.Nags)
+	assert.True(t, a.NextNagAt.IsZero(), "no nags after the limit")
+	assert.False(t, a.Acknowledged())
+}
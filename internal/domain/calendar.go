@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkdayPolicy определяет, что делать со срабатыванием, выпавшим на нерабочий день
+// по календарю чата.
+type WorkdayPolicy int
+
+// Возможные режимы «только по рабочим дням».
+const (
+	WorkdayAny      WorkdayPolicy = iota // календарь не учитывается
+	WorkdaySkip                          // срабатывание пропускается
+	WorkdayPrevious                      // переносится на предыдущий рабочий день
+	WorkdayNext                          // переносится на следующий рабочий день
+)
+
+// Ограничения производственного календаря.
+const (
+	// MaxCalendarDays ограничивает число особых дней календаря: в российском
+	// производственном календаре их около тридцати в год, запаса хватает на десятилетия.
+	MaxCalendarDays = 1000
+	// CalendarDateLayout — формат даты особого дня: в нём дни хранятся и сравниваются.
+	CalendarDateLayout = "2006-01-02"
+)
+
+// ErrInvalidCalendar возвращается при неверной рабочей неделе или файле календаря.
+var ErrInvalidCalendar = errors.New("invalid business calendar")
+
+// DefaultWorkweek — рабочая неделя календаря, в котором она не задана: понедельник–пятница.
+var DefaultWorkweek = []int{1, 2, 3, 4, 5}
+
+// IsValid сообщает, входит ли значение в известный диапазон режимов.
+func (p WorkdayPolicy) IsValid() bool {
+	return p >= WorkdayAny && p <= WorkdayNext
+}
+
+// BusinessCalendar — производственный календарь чата: рабочая неделя и отступления
+// от неё — праздники и перенесённые рабочие дни.
+type BusinessCalendar struct {
+	ChatID int64
+	// Workweek — рабочие дни недели (0 — воскресенье). Пусто — DefaultWorkweek.
+	Workweek []int
+	// Days — особые дни: ключ — дата в формате CalendarDateLayout, значение — рабочий
+	// ли это день. Особый день важнее Workweek: так задаются и праздник в среду,
+	// и рабочая суббота.
+	Days map[string]bool
+}
+
+// IsWorkday сообщает, рабочий ли день t. Дата берётся в часовом поясе t, поэтому
+// вызывающий переводит время в пояс чата. Нулевой календарь — пятидневка без праздников.
+func (c *BusinessCalendar) IsWorkday(t time.Time) bool {
+	workweek := DefaultWorkweek
+	if c != nil {
+		if working, ok := c.Days[t.Format(CalendarDateLayout)]; ok {
+			return working
+		}
+		if len(c.Workweek) > 0 {
+			workweek = c.Workweek
+		}
+	}
+
+	return slices.Contains(workweek, int(t.Weekday()))
+}
+
+// Normalize сортирует рабочую неделю и убирает повторы. Неделя, совпадающая
+// с DefaultWorkweek, сворачивается в nil: одинаковые календари не различаются в базе.
+func (c *BusinessCalendar) Normalize() {
+	workweek := slices.Clone(c.Workweek)
+	slices.Sort(workweek)
+	workweek = slices.Compact(workweek)
+	if slices.Equal(workweek, DefaultWorkweek) || len(workweek) == 0 {
+		workweek = nil
+	}
+	c.Workweek = workweek
+}
+
+// Validate проверяет календарь перед сохранением.
+func (c *BusinessCalendar) Validate() error {
+	if c.ChatID == 0 {
+		return ErrInvalidChatID
+	}
+	for _, d := range c.Workweek {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("%w: weekday %d is out of range 0..6", ErrInvalidCalendar, d)
+		}
+	}
+	if len(c.Days) > MaxCalendarDays {
+		return fmt.Errorf("%w: cannot have more than %d special days", ErrInvalidCalendar, MaxCalendarDays)
+	}
+	for day := range c.Days {
+		if _, err := time.Parse(CalendarDateLayout, day); err != nil {
+			return fmt.Errorf("%w: %q is not a YYYY-MM-DD date", ErrInvalidCalendar, day)
+		}
+	}
+
+	return nil
+}
+
+// ParseCalendarDays разбирает файл с особыми днями календаря.
+//
+// Понимает два формата. XML сайта xmlcalendar.ru — в нём публикуется российский
+// производственный календарь: t="1" — выходной, t="3" — перенесённый рабочий день,
+// сокращённые дни (t="2") остаются рабочими и не нужны. И простой текст: по дате
+// на строке (ГГГГ-ММ-ДД или ДД.ММ.ГГГГ), за которой может идти пометка «рабочий»
+// (work) или «выходной» (holiday); без пометки день выходной. Строки с # — комментарии.
+func ParseCalendarDays(data []byte) (map[string]bool, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return parseCalendarXML(trimmed)
+	}
+
+	return parseCalendarText(trimmed)
+}
+
+// xmlCalendar — корневой элемент файла xmlcalendar.ru.
+type xmlCalendar struct {
+	Year int `xml:"year,attr"`
+	Days []struct {
+		Date string `xml:"d,attr"` // ММ.ДД
+		Type int    `xml:"t,attr"`
+	} `xml:"days>day"`
+}
+
+func parseCalendarXML(data []byte) (map[string]bool, error) {
+	var cal xmlCalendar
+	if err := xml.Unmarshal(data, &cal); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	if cal.Year == 0 {
+		return nil, fmt.Errorf("%w: calendar year is missing", ErrInvalidCalendar)
+	}
+
+	days := make(map[string]bool, len(cal.Days))
+	for _, d := range cal.Days {
+		if d.Type != 1 && d.Type != 3 {
+			continue
+		}
+		date, err := time.Parse("2006.01.02", strconv.Itoa(cal.Year)+"."+d.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a MM.DD date", ErrInvalidCalendar, d.Date)
+		}
+		days[date.Format(CalendarDateLayout)] = d.Type == 3
+	}
+
+	return days, checkCalendarSize(days)
+}
+
+func parseCalendarText(data []byte) (map[string]bool, error) {
+	days := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == ';'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+
+		date, err := parseCalendarDate(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %q is not a date", ErrInvalidCalendar, line, fields[0])
+		}
+		working := false
+		if len(fields) > 1 {
+			working, err = parseDayKind(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, line, err)
+			}
+		}
+		days[date.Format(CalendarDateLayout)] = working
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	return days, checkCalendarSize(days)
+}
+
+func parseCalendarDate(s string) (time.Time, error) {
+	if date, err := time.Parse(CalendarDateLayout, s); err == nil {
+		return date, nil
+	}
+
+	return time.Parse("02.01.2006", s)
+}
+
+// parseDayKind разбирает пометку дня: рабочий он или выходной.
+func parseDayKind(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "рабочий", "work", "workday", "+":
+		return true, nil
+	case "выходной", "праздник", "holiday", "off", "-":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unknown day kind %q", s)
+}
+
+func checkCalendarSize(days map[string]bool) error {
+	if len(days) > MaxCalendarDays {
+		return fmt.Errorf("%w: cannot have more than %d special days", ErrInvalidCalendar, MaxCalendarDays)
+	}
+
+	return nil
+}
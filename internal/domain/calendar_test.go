@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessCalendarIsWorkday(t *testing.T) {
+	cal := &BusinessCalendar{Days: map[string]bool{
+		"2026-01-05": false, // понедельник, праздник
+		"2026-11-07": true,  // суббота, перенесённый рабочий день
+	}}
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 9, 0, 0, 0, time.UTC) }
+
+	assert.True(t, cal.IsWorkday(day(time.January, 6)))
+	assert.False(t, cal.IsWorkday(day(time.January, 5)))
+	assert.False(t, cal.IsWorkday(day(time.January, 10)))
+	assert.True(t, cal.IsWorkday(day(time.November, 7)))
+
+	var none *BusinessCalendar
+	assert.True(t, none.IsWorkday(day(time.January, 5)))
+	assert.False(t, none.IsWorkday(day(time.January, 10)))
+
+	sixDays := &BusinessCalendar{Workweek: []int{1, 2, 3, 4, 5, 6}}
+	assert.True(t, sixDays.IsWorkday(day(time.January, 10)))
+}
+
+func TestBusinessCalendarNormalizeAndValidate(t *testing.T) {
+	cal := &BusinessCalendar{ChatID: 1, Workweek: []int{5, 1, 2, 3, 4, 1}}
+	cal.Normalize()
+	assert.Nil(t, cal.Workweek)
+	require.NoError(t, cal.Validate())
+
+	cal.Workweek = []int{7}
+	assert.ErrorIs(t, cal.Validate(), ErrInvalidCalendar)
+
+	cal.Workweek = nil
+	cal.Days = map[string]bool{"05.01.2026": false}
+	assert.ErrorIs(t, cal.Validate(), ErrInvalidCalendar)
+
+	assert.ErrorIs(t, (&BusinessCalendar{}).Validate(), ErrInvalidChatID)
+}
+
+func TestParseCalendarDays(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		days, err := ParseCalendarDays([]byte(`
+# Новогодние каникулы
+2026-01-01
+02.01.2026 выходной
+2026-11-07 рабочий   # перенос с 4 ноября
+2026-12-31, holiday
+`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{
+			"2026-01-01": false,
+			"2026-01-02": false,
+			"2026-11-07": true,
+			"2026-12-31": false,
+		}, days)
+	})
+
+	t.Run("xmlcalendar", func(t *testing.T) {
+		days, err := ParseCalendarDays([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<calendar year="2026" lang="ru" date="2025.09.01" country="ru">
+  <holidays><holiday id="1" title="Новогодние каникулы"/></holidays>
+  <days>
+    <day d="01.01" t="1" h="1"/>
+    <day d="02.22" t="2"/>
+    <day d="11.07" t="3" f="11.04"/>
+  </days>
+</calendar>`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"2026-01-01": false, "2026-11-07": true}, days)
+	})
+
+	for name, input := range map[string]string{
+		"bad date":      "2026-13-01",
+		"bad kind":      "2026-01-01 maybe",
+		"xml sans year": `<calendar><days><day d="01.01" t="1"/></days></calendar>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCalendarDays([]byte(input))
+			assert.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
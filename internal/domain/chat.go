@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
 
 // Chat описывает чат Telegram (private/group/supergroup/channel)
 type Chat struct {
@@ -10,6 +15,183 @@ type Chat struct {
 	Username  string
 	Timezone  string
 	Available bool
+	CatchUp   CatchUp
+	Quiet     QuietHours
+	// Geo — координаты чата для солнечных напоминаний; nil — не заданы.
+	Geo       *GeoPoint
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Ошибки координат чата.
+var (
+	// ErrInvalidLocation возвращается для координат за пределами земного шара.
+	ErrInvalidLocation = errors.New("invalid location")
+	// ErrNoLocation возвращается, когда солнечному напоминанию не по чему считать время:
+	// чат не поделился геопозицией.
+	ErrNoLocation = errors.New("chat location is not set")
+)
+
+// GeoPoint — точка на земле в градусах: северная широта и восточная долгота положительны.
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Validate проверяет границы широты и долготы.
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Latitude) || p.Latitude < -90 || p.Latitude > 90 {
+		return fmt.Errorf("%w: latitude %v is out of range -90..90", ErrInvalidLocation, p.Latitude)
+	}
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("%w: longitude %v is out of range -180..180", ErrInvalidLocation, p.Longitude)
+	}
+
+	return nil
+}
+
+// TimezoneRebase — что происходит с напоминаниями чата при смене его часового пояса.
+// Напоминаний со своим поясом смена не касается.
+type TimezoneRebase int
+
+const (
+	// RebaseWallClock сохраняет время по часам: «каждый день в 09:00» и в новом поясе
+	// срабатывает в 09:00.
+	RebaseWallClock TimezoneRebase = iota
+	// RebaseInstant сохраняет момент срабатывания: 09:00 по Москве становится 08:00
+	// по Берлину, и дальше серия идёт от этого времени.
+	RebaseInstant
+)
+
+// IsValid сообщает, известен ли режим.
+func (m TimezoneRebase) IsValid() bool {
+	return m == RebaseWallClock || m == RebaseInstant
+}
+
+// ErrInstantRebase возвращается, когда сохранить момент срабатывания нельзя: у напоминания
+// есть время по часам — список времён, окно или выражение cron, — и в новом поясе оно
+// сработало бы в другой момент.
+var ErrInstantRebase = errors.New("reminder keeps wall-clock times and cannot keep its instant")
+
+// CatchUpPolicy — что делать с напоминанием, которое опоздало дольше порога:
+// обычно это срабатывания, пришедшиеся на простой бота.
+type CatchUpPolicy int
+
+const (
+	// CatchUpLate отправляет напоминание один раз с пометкой об опоздании и исходным временем.
+	CatchUpLate CatchUpPolicy = iota
+	// CatchUpSkip молча пропускает опоздавшее срабатывание.
+	CatchUpSkip
+	// CatchUpSummary отправляет одно сообщение со сводкой всех пропущенных срабатываний.
+	CatchUpSummary
+)
+
+// IsValid сообщает, известна ли политика.
+func (p CatchUpPolicy) IsValid() bool {
+	return p >= CatchUpLate && p <= CatchUpSummary
+}
+
+const (
+	// DefaultCatchUpAfter — порог опоздания, если чат его не задал. Планировщик срабатывает
+	// с точностью до секунды, так что опаздывают напоминания обычно только после простоя.
+	DefaultCatchUpAfter = 10 * time.Minute
+	// MinCatchUpAfter и MaxCatchUpAfter ограничивают порог, заданный чатом.
+	MinCatchUpAfter = time.Minute
+	MaxCatchUpAfter = 24 * time.Hour
+)
+
+// ErrInvalidCatchUp возвращается для неизвестной политики или порога вне допустимых границ.
+var ErrInvalidCatchUp = errors.New("invalid catch-up settings")
+
+// CatchUp — настройки чата для напоминаний, опоздавших после простоя.
+type CatchUp struct {
+	Policy CatchUpPolicy
+	// After — с какого опоздания срабатывание считается пропущенным; ноль — DefaultCatchUpAfter.
+	After time.Duration
+}
+
+// Threshold возвращает действующий порог опоздания.
+func (c CatchUp) Threshold() time.Duration {
+	if c.After == 0 {
+		return DefaultCatchUpAfter
+	}
+
+	return c.After
+}
+
+// Validate проверяет политику и порог.
+func (c CatchUp) Validate() error {
+	if !c.Policy.IsValid() {
+		return fmt.Errorf("%w: unknown policy %d", ErrInvalidCatchUp, c.Policy)
+	}
+	if c.After != 0 && (c.After < MinCatchUpAfter || c.After > MaxCatchUpAfter) {
+		return fmt.Errorf("%w: threshold %s must be between %s and %s",
+			ErrInvalidCatchUp, c.After, MinCatchUpAfter, MaxCatchUpAfter)
+	}
+
+	return nil
+}
+
+// QuietMode — что делать с напоминанием, наступившим в тихие часы.
+type QuietMode int
+
+const (
+	// QuietDefer откладывает доставку до конца тихих часов.
+	QuietDefer QuietMode = iota
+	// QuietSilent отправляет сразу, но без звука уведомления.
+	QuietSilent
+)
+
+// ErrInvalidQuietHours возвращается для времени вне суток или неизвестного режима.
+var ErrInvalidQuietHours = errors.New("invalid quiet hours")
+
+// QuietHours — тихие часы чата по его часовому поясу. Окно может переходить через
+// полночь: 23:00–08:00 — Start=1380, End=480.
+type QuietHours struct {
+	Start int // минуты от полуночи
+	End   int
+	Mode  QuietMode
+}
+
+// Enabled сообщает, заданы ли тихие часы: пустое окно Start == End означает, что их нет.
+func (q QuietHours) Enabled() bool {
+	return q.Start != q.End
+}
+
+// Contains сообщает, попадает ли t в тихие часы. t должно быть в поясе чата.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.Enabled() {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if q.Start < q.End {
+		return minute >= q.Start && minute < q.End
+	}
+
+	return minute >= q.Start || minute < q.End
+}
+
+// EndAfter возвращает ближайший после t конец тихих часов в поясе t.
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	year, month, day := t.Date()
+	end := time.Date(year, month, day, q.End/60, q.End%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = time.Date(year, month, day+1, q.End/60, q.End%60, 0, 0, t.Location())
+	}
+
+	return end
+}
+
+// Validate проверяет границы окна и режим.
+func (q QuietHours) Validate() error {
+	const minutesPerDay = 24 * 60
+	if q.Start < 0 || q.Start >= minutesPerDay || q.End < 0 || q.End >= minutesPerDay {
+		return fmt.Errorf("%w: window %d-%d is outside a day", ErrInvalidQuietHours, q.Start, q.End)
+	}
+	if q.Mode != QuietDefer && q.Mode != QuietSilent {
+		return fmt.Errorf("%w: unknown mode %d", ErrInvalidQuietHours, q.Mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatchUpThresholdAndValidate(t *testing.T) {
+	assert.Equal(t, DefaultCatchUpAfter, CatchUp{}.Threshold())
+	assert.Equal(t, time.Hour, CatchUp{After: time.Hour}.Threshold())
+
+	assert.NoError(t, CatchUp{Policy: CatchUpSummary, After: 30 * time.Minute}.Validate())
+	assert.ErrorIs(t, CatchUp{Policy: CatchUpPolicy(7)}.Validate(), ErrInvalidCatchUp)
+	assert.ErrorIs(t, CatchUp{After: 30 * time.Second}.Validate(), ErrInvalidCatchUp)
+	assert.ErrorIs(t, CatchUp{After: 48 * time.Hour}.Validate(), ErrInvalidCatchUp)
+}
+
+func TestQuietHours(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, loc)
+	}
+
+	night := QuietHours{Start: 23 * 60, End: 8 * 60}
+	assert.True(t, night.Enabled())
+	assert.True(t, night.Contains(at(10, 23, 0)))
+	assert.True(t, night.Contains(at(11, 7, 59)))
+	assert.False(t, night.Contains(at(11, 8, 0)))
+	assert.False(t, night.Contains(at(10, 22, 59)))
+	assert.Equal(t, at(11, 8, 0), night.EndAfter(at(10, 23, 30)))
+	assert.Equal(t, at(11, 8, 0), night.EndAfter(at(11, 2, 0)))
+
+	lunch := QuietHours{Start: 13 * 60, End: 14 * 60}
+	assert.True(t, lunch.Contains(at(10, 13, 30)))
+	assert.False(t, lunch.Contains(at(10, 14, 0)))
+
+	assert.False(t, QuietHours{}.Enabled())
+	assert.False(t, QuietHours{}.Contains(at(10, 0, 0)))
+
+	require.NoError(t, night.Validate())
+	assert.ErrorIs(t, QuietHours{Start: 24 * 60}.Validate(), ErrInvalidQuietHours)
+	assert.ErrorIs(t, QuietHours{Mode: QuietMode(5)}.Validate(), ErrInvalidQuietHours)
+}
+
+func TestGeoPointValidate(t *testing.T) {
+	assert.NoError(t, GeoPoint{Latitude: 55.75, Longitude: 37.62}.Validate())
+	assert.NoError(t, GeoPoint{Latitude: -90, Longitude: 180}.Validate())
+	assert.ErrorIs(t, GeoPoint{Latitude: 91}.Validate(), ErrInvalidLocation)
+	assert.ErrorIs(t, GeoPoint{Longitude: -181}.Validate(), ErrInvalidLocation)
+}
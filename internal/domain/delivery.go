@@ -0,0 +1,166 @@
+package domain
+
+import "time"
+
+// DeliveryKind — что доставляется: само напоминание или предупреждение о нём.
+type DeliveryKind string
+
+const (
+	// DeliveryReminder — само срабатывание, с кнопками под сообщением.
+	DeliveryReminder DeliveryKind = "reminder"
+	// DeliveryNotice — предупреждение перед срабатыванием.
+	DeliveryNotice DeliveryKind = "notice"
+	// DeliveryNag — повтор неподтверждённого напоминания. В очередь не ставится
+	// и встречается только в истории доставок.
+	DeliveryNag DeliveryKind = "nag"
+)
+
+const (
+	// DeliveryRetryBase — пауза перед первым повтором; дальше она удваивается.
+	DeliveryRetryBase = 30 * time.Second
+	// DeliveryRetryMax ограничивает паузу между повторами.
+	DeliveryRetryMax = 15 * time.Minute
+)
+
+// Delivery — сообщение в очереди на отправку. Планировщик ставит его в очередь в одной
+// транзакции с переносом напоминания, поэтому срабатывание не теряется, если Telegram
+// временно недоступен: отправка повторяется, пока не выйдет Deadline.
+type Delivery struct {
+	ID         int64
+	ReminderID int64
+	ChatID     int64
+	Kind       DeliveryKind
+	// Occurrence — срабатывание или предупреждение по расписанию. Вместе с ReminderID
+	// и Kind оно не даёт поставить одну и ту же доставку в очередь дважды.
+	Occurrence time.Time
+	Text       string
+	Silent     bool
+	// AckID — запись режима подтверждения: под сообщением кнопка «Готово», а не «отложить».
+	AckID int64
+	// Attempts — сколько попыток уже не удалось; NextAttemptAt — время следующей,
+	// нулевое — попыток больше не будет. LastError объясняет последнюю неудачу.
+	Attempts      int
+	NextAttemptAt time.Time
+	Deadline      time.Time
+	LastError     string
+	SentAt        time.Time
+	CreatedAt     time.Time
+}
+
+// Sent отмечает успешную отправку в момент at.
+func (d *Delivery) Sent(at time.Time) {
+	d.SentAt = at.UTC()
+	d.NextAttemptAt = time.Time{}
+}
+
+// Retry назначает следующую попытку после временной ошибки reason: пауза растёт вдвое
+// с каждой неудачей, но не бывает меньше retryAfter, которого потребовал Telegram.
+// Возвращает false, если следующая попытка не успевает до Deadline и доставка брошена.
+func (d *Delivery) Retry(now time.Time, retryAfter time.Duration, reason string) bool {
+	wait := max(min(DeliveryRetryBase<<min(d.Attempts, 10), DeliveryRetryMax), retryAfter)
+	next := now.Add(wait)
+	if next.After(d.Deadline) {
+		d.Fail(reason)
+		return false
+	}
+
+	d.Attempts++
+	d.NextAttemptAt = next.UTC()
+	d.LastError = reason
+
+	return true
+}
+
+// Expire бросает доставку, которая к моменту now так и не ушла до Deadline: она
+// пролежала в очереди, пока бот простаивал или чат был недоступен, и устарела.
+// Возвращает true, если доставка брошена.
+func (d *Delivery) Expire(now time.Time) bool {
+	if !now.After(d.Deadline) {
+		return false
+	}
+	d.Fail("delivery deadline passed")
+
+	return true
+}
+
+// Postpone переносит попытку на момент at, не считая её неудачей: отправку отложил
+// свой лимит рассылки reason, до Telegram она не дошла. Срок доставки лимит не продлевает:
+// если at позже Deadline, доставка бросается, и Postpone возвращает false.
+func (d *Delivery) Postpone(at time.Time, reason string) bool {
+	if at.After(d.Deadline) {
+		d.NextAttemptAt = time.Time{}
+		d.LastError = reason
+
+		return false
+	}
+	d.NextAttemptAt = at.UTC()
+
+	return true
+}
+
+// Fail прекращает попытки доставки после ошибки reason.
+func (d *Delivery) Fail(reason string) {
+	d.Attempts++
+	d.NextAttemptAt = time.Time{}
+	d.LastError = reason
+}
+
+// MaxHistoryEntries ограничивает число попыток доставки, которое история отдаёт за раз.
+const MaxHistoryEntries = 100
+
+// DeliveryOutcome — чем закончилась попытка отправки.
+type DeliveryOutcome string
+
+const (
+	// OutcomeSent — сообщение доставлено.
+	OutcomeSent DeliveryOutcome = "sent"
+	// OutcomeRetry — временная ошибка, попытка будет повторена.
+	OutcomeRetry DeliveryOutcome = "retry"
+	// OutcomeFailed — доставка брошена.
+	OutcomeFailed DeliveryOutcome = "failed"
+)
+
+// DeliveryAttempt — запись истории доставок: одна попытка отправить сообщение в чат.
+// История отвечает на вопрос «напомнил ли бот вчера» и после удаления напоминания,
+// поэтому текст сообщения хранится вместе с попыткой.
+type DeliveryAttempt struct {
+	ID int64
+	// DeliveryID — доставка из очереди; у повторов неподтверждённых напоминаний нулевой.
+	DeliveryID int64
+	ReminderID int64
+	ChatID     int64
+	Kind       DeliveryKind
+	Text       string
+	// ScheduledAt — время срабатывания по расписанию, AttemptedAt — самой попытки.
+	ScheduledAt time.Time
+	AttemptedAt time.Time
+	Outcome     DeliveryOutcome
+	// MessageID — отправленное сообщение; ErrorClass и Error — причина неудачи.
+	MessageID  int
+	ErrorClass string
+	Error      string
+}
+
+// Attempt описывает для истории попытку, которой доставка только что завершилась
+// в момент at: итог определяется по Sent, Retry или Fail, вызванному перед этим.
+func (d *Delivery) Attempt(at time.Time) *DeliveryAttempt {
+	a := &DeliveryAttempt{
+		DeliveryID:  d.ID,
+		ReminderID:  d.ReminderID,
+		ChatID:      d.ChatID,
+		Kind:        d.Kind,
+		Text:        d.Text,
+		ScheduledAt: d.Occurrence,
+		AttemptedAt: at.UTC(),
+	}
+	switch {
+	case !d.SentAt.IsZero():
+		a.Outcome = OutcomeSent
+	case !d.NextAttemptAt.IsZero():
+		a.Outcome, a.Error = OutcomeRetry, d.LastError
+	default:
+		a.Outcome, a.Error = OutcomeFailed, d.LastError
+	}
+
+	return a
+}
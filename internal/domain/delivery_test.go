@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryRetry(t *testing.T) {
+	now := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+	d := &Delivery{NextAttemptAt: now, Deadline: now.Add(time.Hour)}
+
+	assert.True(t, d.Retry(now, 0, "bad gateway"))
+	assert.Equal(t, now.Add(DeliveryRetryBase), d.NextAttemptAt)
+	assert.True(t, d.Retry(now, 0, "bad gateway"))
+	assert.Equal(t, now.Add(2*DeliveryRetryBase), d.NextAttemptAt, "the pause doubles")
+
+	// Пауза, которую потребовал Telegram, важнее собственной.
+	assert.True(t, d.Retry(now, 5*time.Minute, "too many requests"))
+	assert.Equal(t, now.Add(5*time.Minute), d.NextAttemptAt)
+	assert.Equal(t, 3, d.Attempts)
+
+	d.Attempts = 20
+	assert.True(t, d.Retry(now, 0, "bad gateway"))
+	assert.Equal(t, now.Add(DeliveryRetryMax), d.NextAttemptAt, "the pause is capped")
+
+	assert.False(t, d.Retry(now.Add(50*time.Minute), 0, "bad gateway"), "no attempts after the deadline")
+	assert.True(t, d.NextAttemptAt.IsZero())
+	assert.Equal(t, 22, d.Attempts)
+	assert.Equal(t, "bad gateway", d.LastError)
+}
+
+func TestDeliveryPostpone(t *testing.T) {
+	now := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+	d := &Delivery{NextAttemptAt: now, Deadline: now.Add(time.Hour)}
+
+	assert.True(t, d.Postpone(now.Add(10*time.Minute), "throttled"))
+	assert.Equal(t, now.Add(10*time.Minute), d.NextAttemptAt)
+	assert.Empty(t, d.LastError)
+
+	assert.False(t, d.Postpone(now.Add(2*time.Hour), "throttled"), "the limiter does not extend the deadline")
+	assert.True(t, d.NextAttemptAt.IsZero())
+	assert.Zero(t, d.Attempts)
+	assert.Equal(t, "throttled", d.LastError)
+}
+
+func TestDeliveryExpire(t *testing.T) {
+	now := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+	d := &Delivery{NextAttemptAt: now, Deadline: now.Add(time.Hour)}
+
+	assert.False(t, d.Expire(now.Add(time.Hour)))
+	assert.Equal(t, now, d.NextAttemptAt)
+
+	assert.True(t, d.Expire(now.Add(time.Hour+time.Second)))
+	assert.True(t, d.NextAttemptAt.IsZero())
+	assert.NotEmpty(t, d.LastError)
+}
+
+func TestDeliveryAttempt(t *testing.T) {
+	occurrence := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+	now := occurrence.Add(time.Minute)
+	newDelivery := func() *Delivery {
+		return &Delivery{
+			ID: 7, ReminderID: 3, ChatID: 100, Kind: DeliveryReminder, Text: "⏰ Напоминание: чай",
+			Occurrence: occurrence, NextAttemptAt: occurrence, Deadline: occurrence.Add(time.Hour),
+		}
+	}
+
+	d := newDelivery()
+	d.Sent(now)
+	assert.Equal(t, &DeliveryAttempt{
+		DeliveryID: 7, ReminderID: 3, ChatID: 100, Kind: DeliveryReminder, Text: "⏰ Напоминание: чай",
+		ScheduledAt: occurrence, AttemptedAt: now, Outcome: OutcomeSent,
+	}, d.Attempt(now))
+
+	d = newDelivery()
+	d.Retry(now, 0, "bad gateway")
+	a := d.Attempt(now)
+	assert.Equal(t, OutcomeRetry, a.Outcome)
+	assert.Equal(t, "bad gateway", a.Error)
+
+	d = newDelivery()
+	d.Fail("chat not found")
+	a = d.Attempt(now)
+	assert.Equal(t, OutcomeFailed, a.Outcome)
+	assert.Equal(t, "chat not found", a.Error)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxExceptionsPerReminder ограничивает число исключений одной серии: каждое из них
+// перебирается при каждом пересчёте времени срабатывания.
+const MaxExceptionsPerReminder = 50
+
+// Ошибки исключений из серии.
+var (
+	// ErrInvalidException возвращается, если исключение не относится к срабатыванию серии
+	// или переносит его бессмысленно.
+	ErrInvalidException = errors.New("invalid occurrence exception")
+	// ErrTooManyExceptions возвращается при превышении MaxExceptionsPerReminder.
+	ErrTooManyExceptions = fmt.Errorf("reminder cannot have more than %d exceptions", MaxExceptionsPerReminder)
+)
+
+// OccurrenceException описывает отступление от расписания для одного срабатывания
+// повторяющегося напоминания: пропуск или перенос на другое время.
+//
+// Occurrence — время срабатывания по расписанию серии, а не то, что в итоге сработает:
+// по нему исключение находится при пересчёте, как бы ни менялся NextTime.
+type OccurrenceException struct {
+	ID         int64
+	ReminderID int64
+	Occurrence time.Time
+	MovedTo    time.Time // новое время срабатывания; нулевое — срабатывание пропускается
+	CreatedAt  time.Time
+}
+
+// IsSkip сообщает, пропускается ли срабатывание целиком.
+func (e *OccurrenceException) IsSkip() bool {
+	return e.MovedTo.IsZero()
+}
+
+// Normalize приводит времена к UTC — в этом виде они хранятся в базе.
+func (e *OccurrenceException) Normalize() {
+	e.Occurrence = e.Occurrence.UTC()
+	e.MovedTo = e.MovedTo.UTC()
+}
+
+// Validate проверяет исключение перед сохранением. Принадлежность Occurrence серии
+// проверяет пакет scheduling: для этого нужен часовой пояс чата.
+func (e *OccurrenceException) Validate() error {
+	if e.Occurrence.IsZero() {
+		return fmt.Errorf("%w: occurrence is not set", ErrInvalidException)
+	}
+	if e.MovedTo.Equal(e.Occurrence) {
+		return fmt.Errorf("%w: occurrence is moved to the same time", ErrInvalidException)
+	}
+
+	return nil
+}
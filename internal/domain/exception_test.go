@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccurrenceExceptionValidate(t *testing.T) {
+	occurrence := time.Date(2026, time.July, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		exception OccurrenceException
+		wantErr   bool
+	}{
+		{"skip", OccurrenceException{Occurrence: occurrence}, false},
+		{"move", OccurrenceException{Occurrence: occurrence, MovedTo: occurrence.Add(time.Hour)}, false},
+		{"move to an earlier time", OccurrenceException{Occurrence: occurrence, MovedTo: occurrence.Add(-time.Hour)}, false},
+		{"missing occurrence", OccurrenceException{MovedTo: occurrence}, true},
+		{"move to the same time", OccurrenceException{Occurrence: occurrence, MovedTo: occurrence}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.exception.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidException)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOccurrenceExceptionNormalize(t *testing.T) {
+	loc := time.FixedZone("test", 3*60*60)
+	e := OccurrenceException{Occurrence: time.Date(2026, time.July, 31, 12, 0, 0, 0, loc)}
+
+	e.Normalize()
+
+	assert.Equal(t, time.Date(2026, time.July, 31, 9, 0, 0, 0, time.UTC), e.Occurrence)
+	assert.True(t, e.IsSkip())
+}
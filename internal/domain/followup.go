@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FollowTrigger — событие родителя, которое назначает напоминание-продолжение.
+type FollowTrigger int
+
+const (
+	FollowOnDelivery FollowTrigger = iota // срабатывание родителя доставлено в чат
+	FollowOnAck                           // доставку родителя подтвердили кнопкой «Готово»
+)
+
+// IsValid сообщает, известно ли событие.
+func (t FollowTrigger) IsValid() bool {
+	return t == FollowOnDelivery || t == FollowOnAck
+}
+
+// Ограничения на цепочки напоминаний.
+const (
+	// MaxFollowOffsetMinutes — самое позднее продолжение: через 90 дней после родителя.
+	MaxFollowOffsetMinutes = 90 * MinutesPerDay
+	// MaxChainDepth ограничивает число звеньев над продолжением: проверка цикла
+	// и удаление цепочки проходят её целиком.
+	MaxChainDepth = 10
+)
+
+// Ошибки цепочек напоминаний.
+var (
+	// ErrInvalidFollowUp возвращается при несогласованных параметрах продолжения
+	// или родителе из другого чата.
+	ErrInvalidFollowUp = errors.New("invalid follow-up")
+	// ErrFollowUpCycle возвращается, если родитель сам продолжает напоминание,
+	// которое к нему привязывают.
+	ErrFollowUpCycle = errors.New("follow-up chain cannot loop back")
+)
+
+// IsFollowUp сообщает, продолжает ли напоминание другое.
+func (r *Reminder) IsFollowUp() bool {
+	return r.ParentID != 0
+}
+
+// Arm назначает продолжение через FollowOffset минут после события родителя,
+// случившегося в момент at.
+func (r *Reminder) Arm(at time.Time) {
+	r.NextTime = at.Add(time.Duration(r.FollowOffset) * time.Minute).UTC()
+	r.Waiting = false
+}
+
+// validateFollowUp проверяет параметры продолжения. Существование родителя и отсутствие
+// циклов проверяет ReminderUsecase: для этого нужна база.
+func (r *Reminder) validateFollowUp() error {
+	if !r.IsFollowUp() {
+		return nil
+	}
+	if r.ParentID < 0 {
+		return fmt.Errorf("%w: invalid parent ID %d", ErrInvalidFollowUp, r.ParentID)
+	}
+	if r.ParentID == r.ID {
+		return fmt.Errorf("%w: reminder %d cannot follow itself", ErrFollowUpCycle, r.ID)
+	}
+	// Каждое срабатывание родителя назначает продолжение заново, своего расписания у него нет.
+	if r.Repeat != RepeatNone {
+		return fmt.Errorf("%w: follow-up must be a one-time reminder", ErrInvalidFollowUp)
+	}
+	if !r.FollowOn.IsValid() {
+		return fmt.Errorf("%w: unknown trigger %d", ErrInvalidFollowUp, r.FollowOn)
+	}
+	if r.FollowOffset < 0 || r.FollowOffset > MaxFollowOffsetMinutes {
+		return fmt.Errorf("%w: offset %d is out of range 0..%d minutes",
+			ErrInvalidFollowUp, r.FollowOffset, MaxFollowOffsetMinutes)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
@@ -19,17 +20,71 @@ const (
 	RepeatEveryMonth                   // ежемесячно
 	RepeatEveryNDays                   // каждые N дней
 	RepeatEveryYear                    // ежегодно
+	RepeatRRule                        // по правилу RFC 5545 RRULE
+	RepeatInterval                     // каждые N минут, с окном в течение дня
+	RepeatCron                         // по выражению cron в часовом поясе чата
+	RepeatSolar                        // каждый день относительно восхода, заката или сумерек
 )
 
+// SolarEvent — положение солнца, к которому привязан RepeatSolar.
+type SolarEvent int
+
+const (
+	SolarSunrise SolarEvent = iota // восход
+	SolarSunset                    // закат
+	SolarDawn                      // утренние гражданские сумерки: солнце в 6° под горизонтом
+	SolarDusk                      // вечерние гражданские сумерки
+)
+
+// IsValid сообщает, известно ли событие.
+func (e SolarEvent) IsValid() bool {
+	return e >= SolarSunrise && e <= SolarDusk
+}
+
 // Ограничения на данные напоминания.
 const (
 	// MaxTextLen ограничивает длину текста: он уходит в сообщение Telegram (лимит 4096 символов)
 	// вместе с оформлением, и без верхней границы одно напоминание может забить всю выдачу.
 	MaxTextLen = 500
-	// MaxRepeatEvery — верхняя граница интервала «каждые N дней».
+	// MaxRepeatEvery — верхняя граница интервала «каждые N дней» и «раз в N недель,
+	// месяцев или лет».
 	MaxRepeatEvery = 365
 	// MaxRemindersPerChat ограничивает число напоминаний в одном чате.
 	MaxRemindersPerChat = 100
+	// MaxRRuleLen ограничивает длину правила RRULE.
+	MaxRRuleLen = 512
+	// MaxCronLen ограничивает длину выражения cron.
+	MaxCronLen = 128
+	// MaxSolarOffsetMinutes ограничивает сдвиг солнечного повтора от события: дальше
+	// шести часов «до заката» — это уже скорее время суток, чем закат.
+	MaxSolarOffsetMinutes = 6 * 60
+	// MaxTimesPerDay ограничивает число срабатываний одного напоминания в сутки.
+	MaxTimesPerDay = 24
+	// MinutesPerDay — число минут в сутках; Times хранит время как минуты от полуночи.
+	MinutesPerDay = 24 * 60
+	// MinIntervalMinutes — самый частый интервальный повтор: чаще раза в пять минут
+	// напоминание превращается в спам.
+	MinIntervalMinutes = 5
+	// MaxIntervalMinutes — самый редкий интервальный повтор; реже — это уже ежедневный.
+	MaxIntervalMinutes = MinutesPerDay
+	// MaxNags ограничивает число повторов неподтверждённой доставки: напоминание,
+	// которое никто не подтверждает, не должно досаждать чату бесконечно.
+	MaxNags = 20
+	// MaxLeads ограничивает число предупреждений перед срабатыванием.
+	MaxLeads = 5
+	// MaxLeadMinutes — самое раннее предупреждение: за 30 дней до срабатывания.
+	MaxLeadMinutes = 30 * MinutesPerDay
+)
+
+// Особые значения ежемесячного повтора.
+const (
+	// LastMonthDay в RepeatDays ежемесячного повтора означает последний день месяца.
+	LastMonthDay = -1
+	// MonthOrdinalLast в MonthOrdinal означает последний подходящий день недели месяца.
+	MonthOrdinalLast = -1
+	// MaxMonthOrdinal — наибольший порядковый номер дня недели: пятого вторника
+	// бывает не в каждом месяце.
+	MaxMonthOrdinal = 4
 )
 
 // Ошибки валидации напоминания.
@@ -44,25 +99,104 @@ var (
 	ErrInvalidRepeat = errors.New("invalid repeat configuration")
 	// ErrTooManyReminders возвращается при превышении MaxRemindersPerChat.
 	ErrTooManyReminders = fmt.Errorf("chat cannot have more than %d reminders", MaxRemindersPerChat)
+	// ErrInvalidTimezone возвращается при неизвестной IANA-зоне чата или напоминания.
+	ErrInvalidTimezone = errors.New("invalid timezone")
 )
 
 // IsValid сообщает, входит ли значение в известный диапазон типов повтора.
 func (r RepeatType) IsValid() bool {
-	return r >= RepeatNone && r <= RepeatEveryYear
+	return r >= RepeatNone && r <= RepeatSolar
+}
+
+// SupportsRandomWindow сообщает, может ли повтор этого типа срабатывать в случайное
+// время внутри окна. У интервального окно означает другое, а у RRULE, cron и солнечного
+// время суток задаёт само правило.
+func (r RepeatType) SupportsRandomWindow() bool {
+	switch r {
+	case RepeatNone, RepeatEveryDay, RepeatEveryWeek, RepeatEveryMonth, RepeatEveryNDays, RepeatEveryYear:
+		return true
+	case RepeatRRule, RepeatInterval, RepeatCron, RepeatSolar:
+	}
+
+	return false
 }
 
 // Reminder описывает напоминание пользователя.
 type Reminder struct {
-	ID          int64
-	ChatID      int64
-	Text        string
-	NextTime    time.Time
-	Repeat      RepeatType
-	RepeatDays  []int // для дней недели/месяца
-	RepeatEvery int   // для N дней
-	Paused      bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID         int64
+	ChatID     int64
+	Text       string
+	NextTime   time.Time
+	Repeat     RepeatType
+	RepeatDays []int // для дней недели/месяца
+	// RepeatEvery — шаг повтора: N дней для RepeatEveryNDays, а для недельного, месячного
+	// и годового — «раз в N недель/месяцев/лет». Ноль у этих трёх равносилен единице.
+	RepeatEvery int
+	// MonthOrdinal переключает ежемесячный повтор в режим «N-й день недели»: 1..4 или
+	// MonthOrdinalLast, а RepeatDays тогда хранит дни недели. Ноль — обычное число месяца.
+	MonthOrdinal int
+	RRule        string    // правило RFC 5545 без префикса «RRULE:», для RepeatRRule
+	StartTime    time.Time // DTSTART правила: от него отсчитываются INTERVAL и COUNT
+	Cron         string    // выражение cron из пяти полей или макрос вроде @daily, для RepeatCron
+	// SolarEvent и SolarOffset задают RepeatSolar: срабатывание через SolarOffset минут
+	// после события, отрицательный сдвиг — до него. Geo — координаты чата, по которым
+	// считается событие; как и Calendar, в таблице reminders не хранятся.
+	SolarEvent  SolarEvent
+	SolarOffset int
+	Geo         *GeoPoint
+	// Timezone — IANA-зона, в которой считаются и показываются срабатывания, если она
+	// отличается от зоны чата: созвон по нью-йоркскому времени в московском чате.
+	// Пусто — зона чата.
+	Timezone string
+	// Times — времена срабатывания в течение дня повтора, в минутах от полуночи по
+	// возрастанию. Пусто, если время одно: тогда оно берётся из NextTime.
+	Times []int
+	// IntervalMinutes — шаг RepeatInterval. Окно WindowStart..WindowEnd (минуты от полуночи,
+	// оба конца включительно) ограничивает срабатывания частью дня; два нуля — весь день.
+	// RepeatDays для этого типа — дни недели, в которые повтор активен; пусто — все.
+	// У разовых и календарных повторов окно заменяет время суток: каждое срабатывание
+	// выпадает на случайную минуту внутри окна (см. HasRandomWindow).
+	IntervalMinutes int
+	WindowStart     int
+	WindowEnd       int
+	// EndsAt — последний момент, в который серия ещё может сработать; нулевое — без
+	// даты окончания. RemainingCount — сколько срабатываний осталось, включая NextTime;
+	// ноль — без ограничения. Разовым напоминаниям оба условия не нужны.
+	EndsAt         time.Time
+	RemainingCount int
+	// Exceptions — пропущенные и перенесённые срабатывания серии. В таблице reminders
+	// не хранятся: их подгружает тот, кому нужен пересчёт времени.
+	Exceptions []OccurrenceException
+	// WorkdayPolicy включает режим «только по рабочим дням»: что делать со срабатыванием,
+	// выпавшим на нерабочий день календаря чата. ShiftedFrom — время по расписанию, если
+	// NextTime перенесён с нерабочего дня или отложен до конца тихих часов чата; от него,
+	// а не от NextTime, шагает серия.
+	WorkdayPolicy WorkdayPolicy
+	ShiftedFrom   time.Time
+	// Calendar — календарь чата для WorkdayPolicy. Как и Exceptions, не хранится
+	// в таблице reminders; nil — пятидневка без праздников.
+	Calendar *BusinessCalendar
+	// NagEveryMinutes включает режим подтверждения: под доставкой появляется кнопка
+	// «Готово», и пока её не нажали, напоминание повторяется каждые NagEveryMinutes
+	// минут, но не больше NagMax раз. Ноль — подтверждение не требуется.
+	NagEveryMinutes int
+	NagMax          int
+	// LeadMinutes — за сколько минут до каждого срабатывания приходят предупреждения,
+	// от самого раннего к позднему. NoticeAt — время ближайшего неотправленного из них,
+	// нулевое — до срабатывания предупреждений больше нет; его планирует ReminderUsecase.
+	LeadMinutes []int
+	NoticeAt    time.Time
+	// ParentID делает напоминание продолжением другого: его назначает не календарь,
+	// а событие FollowOn родителя — через FollowOffset минут после доставки или
+	// подтверждения. Waiting — продолжение ждёт родителя, и NextTime не действует.
+	ParentID     int64
+	FollowOffset int
+	FollowOn     FollowTrigger
+	Waiting      bool
+
+	Paused    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // Normalize приводит поля к каноническому виду: чистит текст и обнуляет параметры повтора,
@@ -72,14 +206,75 @@ type Reminder struct {
 // а Advance читает поля, которые к текущему типу повтора отношения не имеют.
 func (r *Reminder) Normalize() {
 	r.Text = sanitizeText(r.Text)
+	r.Timezone = strings.TrimSpace(r.Timezone)
 	r.NextTime = r.NextTime.UTC()
+	r.EndsAt = r.EndsAt.UTC()
+	r.ShiftedFrom = r.ShiftedFrom.UTC()
+	if r.Repeat == RepeatNone {
+		r.EndsAt = time.Time{}
+		r.RemainingCount = 0
+		r.WorkdayPolicy = WorkdayAny
+		r.ShiftedFrom = time.Time{}
+	}
+
+	if r.Repeat != RepeatRRule {
+		r.RRule = ""
+		r.StartTime = time.Time{}
+	}
+	if r.Repeat != RepeatCron {
+		r.Cron = ""
+	}
+	if r.Repeat != RepeatSolar {
+		r.SolarEvent, r.SolarOffset = SolarSunrise, 0
+	}
+	if r.Repeat != RepeatEveryMonth {
+		r.MonthOrdinal = 0
+	}
+	r.Times = normalizeTimes(r.Times)
+	if r.NagEveryMinutes == 0 {
+		r.NagMax = 0
+	}
+	r.LeadMinutes = normalizeLeads(r.LeadMinutes)
+	if r.IsFollowUp() {
+		// Время продолжения задаёт родитель.
+		r.Times = nil
+		r.WindowStart, r.WindowEnd = 0, 0
+	} else {
+		r.FollowOffset, r.FollowOn, r.Waiting = 0, FollowOnDelivery, false
+	}
+	if r.Repeat != RepeatInterval {
+		r.IntervalMinutes = 0
+	}
+	if r.Repeat != RepeatInterval && !r.Repeat.SupportsRandomWindow() {
+		r.WindowStart, r.WindowEnd = 0, 0
+	}
 
 	switch r.Repeat {
 	case RepeatEveryWeek, RepeatEveryMonth:
+		r.RepeatEvery = canonicalStep(r.RepeatEvery)
+	case RepeatInterval:
 		r.RepeatEvery = 0
 	case RepeatEveryNDays:
 		r.RepeatDays = nil
-	case RepeatNone, RepeatEveryDay, RepeatEveryYear:
+	case RepeatEveryYear:
+		r.RepeatDays = nil
+		r.RepeatEvery = canonicalStep(r.RepeatEvery)
+	case RepeatNone, RepeatEveryDay:
+		r.RepeatDays = nil
+		r.RepeatEvery = 0
+	case RepeatRRule:
+		r.RepeatDays = nil
+		r.RepeatEvery = 0
+		r.RRule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(r.RRule)), "RRULE:")
+		if r.StartTime.IsZero() {
+			r.StartTime = r.NextTime
+		}
+		r.StartTime = r.StartTime.UTC()
+	case RepeatCron:
+		r.RepeatDays = nil
+		r.RepeatEvery = 0
+		r.Cron = strings.Join(strings.Fields(strings.ToLower(r.Cron)), " ")
+	case RepeatSolar:
 		r.RepeatDays = nil
 		r.RepeatEvery = 0
 	}
@@ -102,6 +297,35 @@ func (r *Reminder) Validate() error {
 	if r.NextTime.IsZero() {
 		return fmt.Errorf("%w: next time is not set", ErrInvalidRepeat)
 	}
+	if err := r.validateTimezone(); err != nil {
+		return err
+	}
+
+	if err := r.validateTimes(); err != nil {
+		return err
+	}
+	if err := r.validateRandomWindow(); err != nil {
+		return err
+	}
+
+	if err := r.validateStep(); err != nil {
+		return err
+	}
+	if err := r.validateEnd(); err != nil {
+		return err
+	}
+	if err := r.validateWorkdays(); err != nil {
+		return err
+	}
+	if err := r.validateNag(); err != nil {
+		return err
+	}
+	if err := r.validateLeads(); err != nil {
+		return err
+	}
+	if err := r.validateFollowUp(); err != nil {
+		return err
+	}
 
 	switch r.Repeat {
 	case RepeatEveryWeek:
@@ -111,16 +335,33 @@ func (r *Reminder) Validate() error {
 			}
 		}
 	case RepeatEveryMonth:
-		for _, d := range r.RepeatDays {
-			if d < 1 || d > 31 {
-				return fmt.Errorf("%w: day of month %d is out of range 1..31", ErrInvalidRepeat, d)
-			}
-		}
+		return r.validateMonthly()
+	case RepeatInterval:
+		return r.validateInterval()
 	case RepeatEveryNDays:
 		if r.RepeatEvery < 1 || r.RepeatEvery > MaxRepeatEvery {
 			return fmt.Errorf("%w: interval %d is out of range 1..%d",
 				ErrInvalidRepeat, r.RepeatEvery, MaxRepeatEvery)
 		}
+	case RepeatRRule:
+		// Синтаксис правила разбирает пакет scheduling при расчёте времени; здесь
+		// отсекаем только то, что заведомо не может быть правилом.
+		if r.RRule == "" {
+			return fmt.Errorf("%w: rrule is required", ErrInvalidRepeat)
+		}
+		if len(r.RRule) > MaxRRuleLen {
+			return fmt.Errorf("%w: rrule cannot exceed %d characters", ErrInvalidRepeat, MaxRRuleLen)
+		}
+	case RepeatCron:
+		// Как и RRULE, выражение разбирает пакет scheduling.
+		if r.Cron == "" {
+			return fmt.Errorf("%w: cron expression is required", ErrInvalidRepeat)
+		}
+		if len(r.Cron) > MaxCronLen {
+			return fmt.Errorf("%w: cron expression cannot exceed %d characters", ErrInvalidRepeat, MaxCronLen)
+		}
+	case RepeatSolar:
+		return r.validateSolar()
 	case RepeatNone, RepeatEveryDay, RepeatEveryYear:
 		// Дополнительных параметров нет.
 	}
@@ -128,6 +369,280 @@ func (r *Reminder) Validate() error {
 	return nil
 }
 
+// validateTimezone проверяет собственную зону напоминания. «Local» — зона сервера,
+// а не IANA-зона: с ней расписание зависело бы от того, где запущен бот.
+func (r *Reminder) validateTimezone() error {
+	if r.Timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(r.Timezone); err != nil || r.Timezone == "Local" {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, r.Timezone)
+	}
+
+	return nil
+}
+
+// Location возвращает зону, в которой считаются срабатывания: собственную зону
+// напоминания или, если она не задана, зону чата chat. Зона проверяется при сохранении,
+// поэтому не загрузиться она может, только если в образе нет tzdata, — тогда
+// напоминание живёт по зоне чата.
+func (r *Reminder) Location(chat *time.Location) *time.Location {
+	if r.Timezone == "" {
+		return chat
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return chat
+	}
+
+	return loc
+}
+
+// RepeatStep возвращает шаг недельного, месячного или годового повтора: 1 — каждую
+// неделю (месяц, год), 2 — через одну и так далее.
+func (r *Reminder) RepeatStep() int {
+	return max(1, r.RepeatEvery)
+}
+
+// validateEnd проверяет условия окончания серии.
+func (r *Reminder) validateEnd() error {
+	if r.RemainingCount < 0 {
+		return fmt.Errorf("%w: remaining count %d cannot be negative", ErrInvalidRepeat, r.RemainingCount)
+	}
+	// Серия, закончившаяся раньше ближайшего срабатывания, не сработала бы ни разу.
+	if !r.EndsAt.IsZero() && r.EndsAt.Before(r.NextTime) {
+		return fmt.Errorf("%w: series ends at %s before its next time %s",
+			ErrInvalidRepeat, r.EndsAt.UTC(), r.NextTime.UTC())
+	}
+
+	return nil
+}
+
+// validateWorkdays проверяет режим «только по рабочим дням».
+func (r *Reminder) validateWorkdays() error {
+	if !r.WorkdayPolicy.IsValid() {
+		return fmt.Errorf("%w: unknown workday policy %d", ErrInvalidRepeat, r.WorkdayPolicy)
+	}
+	// Перенос целого дня интервальных срабатываний на соседний день наложил бы их
+	// на собственные срабатывания того дня; cron тоже может срабатывать несколько раз в день.
+	// Закат же в соседний день наступает в другое время.
+	onlySkip := r.Repeat == RepeatInterval || r.Repeat == RepeatCron || r.Repeat == RepeatSolar
+	if onlySkip && r.WorkdayPolicy != WorkdayAny && r.WorkdayPolicy != WorkdaySkip {
+		return fmt.Errorf("%w: interval, cron and solar repeats can only skip non-working days", ErrInvalidRepeat)
+	}
+
+	return nil
+}
+
+// validateSolar проверяет событие и сдвиг солнечного повтора. Координаты здесь не
+// проверяются: они принадлежат чату и нужны только при расчёте времени.
+func (r *Reminder) validateSolar() error {
+	if !r.SolarEvent.IsValid() {
+		return fmt.Errorf("%w: unknown solar event %d", ErrInvalidRepeat, r.SolarEvent)
+	}
+	if r.SolarOffset < -MaxSolarOffsetMinutes || r.SolarOffset > MaxSolarOffsetMinutes {
+		return fmt.Errorf("%w: solar offset %d is out of range ±%d minutes",
+			ErrInvalidRepeat, r.SolarOffset, MaxSolarOffsetMinutes)
+	}
+
+	return nil
+}
+
+// validateNag проверяет режим подтверждения.
+func (r *Reminder) validateNag() error {
+	if r.NagEveryMinutes == 0 && r.NagMax == 0 {
+		return nil
+	}
+	if r.NagEveryMinutes < 1 || r.NagEveryMinutes > MinutesPerDay {
+		return fmt.Errorf("%w: nag interval %d is out of range 1..%d minutes",
+			ErrInvalidRepeat, r.NagEveryMinutes, MinutesPerDay)
+	}
+	if r.NagMax < 1 || r.NagMax > MaxNags {
+		return fmt.Errorf("%w: nag count %d is out of range 1..%d", ErrInvalidRepeat, r.NagMax, MaxNags)
+	}
+
+	return nil
+}
+
+// validateLeads проверяет предупреждения перед срабатыванием.
+func (r *Reminder) validateLeads() error {
+	if len(r.LeadMinutes) > MaxLeads {
+		return fmt.Errorf("%w: at most %d advance notices are allowed", ErrInvalidRepeat, MaxLeads)
+	}
+	for _, lead := range r.LeadMinutes {
+		if lead < 1 || lead > MaxLeadMinutes {
+			return fmt.Errorf("%w: advance notice %d is out of range 1..%d minutes",
+				ErrInvalidRepeat, lead, MaxLeadMinutes)
+		}
+	}
+
+	return nil
+}
+
+// validateStep проверяет шаг «раз в N недель/месяцев/лет».
+func (r *Reminder) validateStep() error {
+	if r.Repeat != RepeatEveryWeek && r.Repeat != RepeatEveryMonth && r.Repeat != RepeatEveryYear {
+		return nil
+	}
+	if r.RepeatEvery < 0 || r.RepeatEvery > MaxRepeatEvery {
+		return fmt.Errorf("%w: step %d is out of range 1..%d", ErrInvalidRepeat, r.RepeatEvery, MaxRepeatEvery)
+	}
+
+	return nil
+}
+
+// canonicalStep сворачивает шаг 1 в 0: «каждую неделю» хранится нулём, как и до
+// появления шага, и одинаковые расписания не различаются в базе.
+func canonicalStep(step int) int {
+	if step == 1 {
+		return 0
+	}
+
+	return step
+}
+
+// validateMonthly проверяет оба режима ежемесячного повтора: число месяца и N-й день недели.
+func (r *Reminder) validateMonthly() error {
+	if r.MonthOrdinal == 0 {
+		for _, d := range r.RepeatDays {
+			if (d < 1 || d > 31) && d != LastMonthDay {
+				return fmt.Errorf("%w: day of month %d is out of range 1..31", ErrInvalidRepeat, d)
+			}
+		}
+
+		return nil
+	}
+
+	if (r.MonthOrdinal < 1 || r.MonthOrdinal > MaxMonthOrdinal) && r.MonthOrdinal != MonthOrdinalLast {
+		return fmt.Errorf("%w: month ordinal %d is out of range 1..%d", ErrInvalidRepeat, r.MonthOrdinal, MaxMonthOrdinal)
+	}
+	if len(r.RepeatDays) == 0 {
+		return fmt.Errorf("%w: at least one weekday is required", ErrInvalidRepeat)
+	}
+	for _, d := range r.RepeatDays {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("%w: weekday %d is out of range 0..6", ErrInvalidRepeat, d)
+		}
+	}
+
+	return nil
+}
+
+// validateInterval проверяет шаг, окно и дни недели интервального повтора.
+func (r *Reminder) validateInterval() error {
+	if r.IntervalMinutes < MinIntervalMinutes || r.IntervalMinutes > MaxIntervalMinutes {
+		return fmt.Errorf("%w: interval %d minutes is out of range %d..%d",
+			ErrInvalidRepeat, r.IntervalMinutes, MinIntervalMinutes, MaxIntervalMinutes)
+	}
+	// Окно через полночь (22:00–06:00) не поддерживается: его пришлось бы относить
+	// к одному из двух дней, и маска дней недели стала бы неоднозначной.
+	if err := r.validateWindow(); err != nil {
+		return err
+	}
+	for _, d := range r.RepeatDays {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("%w: weekday %d is out of range 0..6", ErrInvalidRepeat, d)
+		}
+	}
+
+	return nil
+}
+
+// validateWindow проверяет, что окно, если оно задано, лежит внутри одних суток.
+func (r *Reminder) validateWindow() error {
+	if r.HasWindow() && (r.WindowStart < 0 || r.WindowEnd >= MinutesPerDay || r.WindowStart >= r.WindowEnd) {
+		return fmt.Errorf("%w: window %d..%d must lie within one day", ErrInvalidRepeat, r.WindowStart, r.WindowEnd)
+	}
+
+	return nil
+}
+
+// validateRandomWindow проверяет окно случайного времени. Оно заменяет время суток,
+// поэтому со списком времён не сочетается.
+func (r *Reminder) validateRandomWindow() error {
+	if !r.HasRandomWindow() {
+		return nil
+	}
+	if len(r.Times) > 0 {
+		return fmt.Errorf("%w: random window cannot have a list of times", ErrInvalidRepeat)
+	}
+
+	return r.validateWindow()
+}
+
+// HasWindow сообщает, задано ли окно внутри дня: у интервального повтора — часы
+// срабатываний, у остальных — окно случайного времени.
+func (r *Reminder) HasWindow() bool {
+	return r.WindowStart != 0 || r.WindowEnd != 0
+}
+
+// HasRandomWindow сообщает, что время срабатывания не задано часами, а выбирается
+// случайно в окне WindowStart..WindowEnd: «когда-нибудь между 10:00 и 12:00».
+func (r *Reminder) HasRandomWindow() bool {
+	return r.HasWindow() && r.Repeat.SupportsRandomWindow()
+}
+
+// validateTimes проверяет список времён срабатывания в течение дня.
+func (r *Reminder) validateTimes() error {
+	if len(r.Times) == 0 {
+		return nil
+	}
+	// Разовое напоминание удаляется после первой отправки, поэтому несколько времён
+	// для него бессмысленны.
+	if r.Repeat == RepeatNone {
+		return fmt.Errorf("%w: several times per day require a repeating reminder", ErrInvalidRepeat)
+	}
+	if r.Repeat == RepeatInterval {
+		return fmt.Errorf("%w: interval repeat cannot have a list of times", ErrInvalidRepeat)
+	}
+	if r.Repeat == RepeatCron {
+		return fmt.Errorf("%w: cron repeat cannot have a list of times", ErrInvalidRepeat)
+	}
+	if r.Repeat == RepeatSolar {
+		return fmt.Errorf("%w: solar repeat cannot have a list of times", ErrInvalidRepeat)
+	}
+	if len(r.Times) > MaxTimesPerDay {
+		return fmt.Errorf("%w: cannot have more than %d times per day", ErrInvalidRepeat, MaxTimesPerDay)
+	}
+	for _, m := range r.Times {
+		if m < 0 || m >= MinutesPerDay {
+			return fmt.Errorf("%w: time %d is out of range 0..%d minutes", ErrInvalidRepeat, m, MinutesPerDay-1)
+		}
+	}
+
+	return nil
+}
+
+// normalizeTimes сортирует времена и убирает повторы. Единственное время хранится
+// в NextTime, поэтому список из одного элемента сворачивается в nil.
+func normalizeTimes(times []int) []int {
+	if len(times) < 2 {
+		return nil
+	}
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	if len(sorted) < 2 {
+		return nil
+	}
+
+	return sorted
+}
+
+// normalizeLeads сортирует предупреждения от самого раннего (большего отступа)
+// к позднему и убирает повторы.
+func normalizeLeads(leads []int) []int {
+	if len(leads) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(leads)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	slices.Reverse(sorted)
+
+	return sorted
+}
+
 // sanitizeText удаляет управляющие символы и лишние пробелы по краям.
 //
 // Переводы строк оставляем: многострочные напоминания — нормальный сценарий.
@@ -38,6 +38,135 @@ func TestReminderNormalize(t *testing.T) {
 	assert.Equal(t, 5, reminder.RepeatEvery)
 }
 
+func TestReminderNormalizeRRule(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatRRule
+	reminder.RRule = " rrule:freq=monthly;byday=2tu "
+	reminder.RepeatDays = []int{1}
+
+	reminder.Normalize()
+
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=2TU", reminder.RRule)
+	assert.Equal(t, reminder.NextTime, reminder.StartTime, "DTSTART defaults to the first occurrence")
+	assert.Nil(t, reminder.RepeatDays)
+
+	reminder.Repeat = RepeatEveryDay
+	reminder.Normalize()
+
+	assert.Empty(t, reminder.RRule)
+	assert.True(t, reminder.StartTime.IsZero())
+}
+
+func TestReminderNormalizeRandomWindow(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatEveryWeek
+	reminder.WindowStart, reminder.WindowEnd = 10*60, 12*60
+
+	reminder.Normalize()
+	assert.True(t, reminder.HasRandomWindow())
+
+	reminder.Repeat = RepeatCron
+	reminder.Cron = "0 9 * * *"
+	reminder.Normalize()
+	assert.False(t, reminder.HasWindow(), "у cron время суток задаёт выражение")
+}
+
+func TestReminderNormalizeTimes(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatEveryDay
+	reminder.Times = []int{21 * 60, 9 * 60, 13 * 60, 9 * 60}
+
+	reminder.Normalize()
+	assert.Equal(t, []int{9 * 60, 13 * 60, 21 * 60}, reminder.Times)
+
+	reminder.Times = []int{9 * 60, 9 * 60}
+	reminder.Normalize()
+	assert.Nil(t, reminder.Times, "a single time lives in NextTime")
+}
+
+func TestReminderNormalizeLeads(t *testing.T) {
+	reminder := validReminder()
+	reminder.LeadMinutes = []int{60, 3 * MinutesPerDay, 60}
+
+	reminder.Normalize()
+	assert.Equal(t, []int{3 * MinutesPerDay, 60}, reminder.LeadMinutes)
+}
+
+func TestReminderNormalizeFollowUp(t *testing.T) {
+	reminder := validReminder()
+	reminder.ParentID, reminder.FollowOffset = 7, 60
+	reminder.Times = []int{9 * 60, 13 * 60}
+	reminder.WindowStart, reminder.WindowEnd = 10*60, 12*60
+
+	reminder.Normalize()
+	assert.Nil(t, reminder.Times, "время продолжению назначает родитель")
+	assert.False(t, reminder.HasWindow())
+
+	reminder.ParentID, reminder.Waiting = 0, true
+	reminder.Normalize()
+	assert.Zero(t, reminder.FollowOffset)
+	assert.False(t, reminder.Waiting)
+}
+
+func TestReminderNormalizeStep(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatEveryWeek
+	reminder.RepeatDays = []int{1, 4}
+	reminder.RepeatEvery = 2
+
+	reminder.Normalize()
+	assert.Equal(t, 2, reminder.RepeatEvery, "weekly repeat keeps its step")
+	assert.Equal(t, 2, reminder.RepeatStep())
+
+	reminder.RepeatEvery = 1
+	reminder.Normalize()
+	assert.Zero(t, reminder.RepeatEvery, "a step of one is stored as zero")
+	assert.Equal(t, 1, reminder.RepeatStep())
+
+	reminder.Repeat = RepeatEveryDay
+	reminder.RepeatEvery = 3
+	reminder.Normalize()
+	assert.Zero(t, reminder.RepeatEvery)
+}
+
+func TestReminderNormalizeEnd(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatEveryDay
+	reminder.EndsAt = time.Date(2026, time.August, 7, 23, 59, 0, 0, time.FixedZone("test", 3*60*60))
+	reminder.RemainingCount = 5
+
+	reminder.Normalize()
+	assert.Equal(t, time.UTC, reminder.EndsAt.Location())
+	assert.Equal(t, 5, reminder.RemainingCount)
+
+	reminder.Repeat = RepeatNone
+	reminder.Normalize()
+	assert.True(t, reminder.EndsAt.IsZero(), "one-time reminder has no end condition")
+	assert.Zero(t, reminder.RemainingCount)
+}
+
+func TestReminderNormalizeWorkdays(t *testing.T) {
+	reminder := validReminder()
+	reminder.Repeat = RepeatEveryWeek
+	reminder.WorkdayPolicy = WorkdayNext
+	reminder.ShiftedFrom = reminder.NextTime.Add(-24 * time.Hour)
+
+	reminder.Normalize()
+	assert.Equal(t, WorkdayNext, reminder.WorkdayPolicy)
+	assert.False(t, reminder.ShiftedFrom.IsZero())
+
+	// Сдвиг остаётся и без политики: его оставляют и тихие часы чата.
+	reminder.WorkdayPolicy = WorkdayAny
+	reminder.Normalize()
+	assert.False(t, reminder.ShiftedFrom.IsZero())
+
+	reminder.WorkdayPolicy = WorkdaySkip
+	reminder.Repeat = RepeatNone
+	reminder.Normalize()
+	assert.Equal(t, WorkdayAny, reminder.WorkdayPolicy)
+	assert.True(t, reminder.ShiftedFrom.IsZero(), "one-time reminders have no series to shift")
+}
+
 func TestReminderValidate(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -68,6 +197,44 @@ func TestReminderValidate(t *testing.T) {
 			change: func(r *Reminder) { r.NextTime = time.Time{} },
 			want:   ErrInvalidRepeat,
 		},
+		{
+			name:   "own timezone",
+			change: func(r *Reminder) { r.Timezone = "America/New_York" },
+		},
+		{
+			name:   "unknown timezone",
+			change: func(r *Reminder) { r.Timezone = "Mars/Olympus" },
+			want:   ErrInvalidTimezone,
+		},
+		{
+			name:   "server timezone",
+			change: func(r *Reminder) { r.Timezone = "Local" },
+			want:   ErrInvalidTimezone,
+		},
+		{
+			name: "solar",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatSolar
+				r.SolarEvent = SolarSunset
+				r.SolarOffset = -30
+			},
+		},
+		{
+			name: "solar offset too far",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatSolar
+				r.SolarOffset = MaxSolarOffsetMinutes + 1
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "solar shifted to another day",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatSolar
+				r.WorkdayPolicy = WorkdayNext
+			},
+			want: ErrInvalidRepeat,
+		},
 		{
 			name: "invalid weekday",
 			change: func(r *Reminder) {
@@ -92,6 +259,236 @@ func TestReminderValidate(t *testing.T) {
 			},
 			want: ErrInvalidRepeat,
 		},
+		{
+			name: "nag until acknowledged",
+			change: func(r *Reminder) {
+				r.NagEveryMinutes = 15
+				r.NagMax = MaxNags
+			},
+		},
+		{
+			name: "nag without a limit",
+			change: func(r *Reminder) {
+				r.NagEveryMinutes = 15
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "advance notices",
+			change: func(r *Reminder) {
+				r.LeadMinutes = []int{MaxLeadMinutes, 30}
+			},
+		},
+		{
+			name: "advance notice too early",
+			change: func(r *Reminder) {
+				r.LeadMinutes = []int{MaxLeadMinutes + 1}
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "ends with the next time",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.EndsAt = r.NextTime
+				r.RemainingCount = 3
+			},
+		},
+		{
+			name: "ends before the next time",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.EndsAt = r.NextTime.Add(-time.Minute)
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "interval skips non-working days",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatInterval
+				r.IntervalMinutes = 30
+				r.WorkdayPolicy = WorkdaySkip
+			},
+		},
+		{
+			name: "interval cannot move to another working day",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatInterval
+				r.IntervalMinutes = 30
+				r.WorkdayPolicy = WorkdayNext
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "unknown workday policy",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.WorkdayPolicy = WorkdayNext + 1
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "negative remaining count",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.RemainingCount = -1
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "every three months",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryMonth
+				r.RepeatDays = []int{15}
+				r.RepeatEvery = 3
+			},
+		},
+		{
+			name: "negative weekly step",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryWeek
+				r.RepeatEvery = -2
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "yearly step too large",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryYear
+				r.RepeatEvery = MaxRepeatEvery + 1
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "last day of month",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryMonth
+				r.RepeatDays = []int{LastMonthDay}
+			},
+		},
+		{
+			name: "second tuesday",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryMonth
+				r.MonthOrdinal = 2
+				r.RepeatDays = []int{2}
+			},
+		},
+		{
+			name: "fifth weekday of month",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryMonth
+				r.MonthOrdinal = 5
+				r.RepeatDays = []int{2}
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "month ordinal without weekdays",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryMonth
+				r.MonthOrdinal = MonthOrdinalLast
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name:   "missing rrule",
+			change: func(r *Reminder) { r.Repeat = RepeatRRule },
+			want:   ErrInvalidRepeat,
+		},
+		{
+			name: "interval with window on weekdays",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatInterval
+				r.IntervalMinutes = 120
+				r.WindowStart, r.WindowEnd = 10*60, 18*60
+				r.RepeatDays = []int{1, 2, 3, 4, 5}
+			},
+		},
+		{
+			name: "interval too short",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatInterval
+				r.IntervalMinutes = 1
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "interval window across midnight",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatInterval
+				r.IntervalMinutes = 60
+				r.WindowStart, r.WindowEnd = 22*60, 6*60
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "several times per day",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.Times = []int{9 * 60, 13 * 60, 21 * 60}
+			},
+		},
+		{
+			name: "daily random window",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.WindowStart, r.WindowEnd = 10*60, 12*60
+			},
+		},
+		{
+			name: "random window with several times",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.WindowStart, r.WindowEnd = 10*60, 12*60
+				r.Times = []int{9 * 60, 13 * 60}
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name:   "empty random window",
+			change: func(r *Reminder) { r.WindowStart, r.WindowEnd = 12*60, 12*60 },
+			want:   ErrInvalidRepeat,
+		},
+		{
+			name:   "several times for one-time reminder",
+			change: func(r *Reminder) { r.Times = []int{9 * 60, 13 * 60} },
+			want:   ErrInvalidRepeat,
+		},
+		{
+			name: "time out of day",
+			change: func(r *Reminder) {
+				r.Repeat = RepeatEveryDay
+				r.Times = []int{9 * 60, MinutesPerDay}
+			},
+			want: ErrInvalidRepeat,
+		},
+		{
+			name: "follow-up",
+			change: func(r *Reminder) {
+				r.ParentID, r.FollowOffset, r.FollowOn = 7, 3*MinutesPerDay, FollowOnAck
+			},
+		},
+		{
+			name:   "repeating follow-up",
+			change: func(r *Reminder) { r.ParentID, r.Repeat = 7, RepeatEveryDay },
+			want:   ErrInvalidFollowUp,
+		},
+		{
+			name:   "follow-up offset too far",
+			change: func(r *Reminder) { r.ParentID, r.FollowOffset = 7, MaxFollowOffsetMinutes+1 },
+			want:   ErrInvalidFollowUp,
+		},
+		{
+			name:   "unknown follow-up trigger",
+			change: func(r *Reminder) { r.ParentID, r.FollowOn = 7, FollowTrigger(5) },
+			want:   ErrInvalidFollowUp,
+		},
+		{
+			name:   "follows itself",
+			change: func(r *Reminder) { r.ID, r.ParentID = 7, 7 },
+			want:   ErrFollowUpCycle,
+		},
 	}
 
 	for _, tt := range tests {
@@ -108,3 +505,29 @@ func TestReminderValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestReminderLocation(t *testing.T) {
+	chat, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	r := &Reminder{}
+	assert.Same(t, chat, r.Location(chat))
+
+	r.Timezone = "America/New_York"
+	assert.Equal(t, "America/New_York", r.Location(chat).String())
+}
+
+func TestReminderWakeAt(t *testing.T) {
+	next := time.Date(2026, time.June, 2, 9, 0, 0, 0, time.UTC)
+	r := &Reminder{NextTime: next}
+	assert.Equal(t, next, r.WakeAt())
+
+	r.NoticeAt = next.Add(-time.Hour)
+	assert.Equal(t, next.Add(-time.Hour), r.WakeAt(), "an advance notice comes first")
+
+	r.Paused = true
+	assert.True(t, r.WakeAt().IsZero())
+
+	r.Paused, r.Waiting = false, true
+	assert.True(t, r.WakeAt().IsZero(), "ждущее продолжение будит событие родителя")
+}
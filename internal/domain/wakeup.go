@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// WakeupKind — чья запись ждёт своего времени в планировщике.
+type WakeupKind int
+
+const (
+	// WakeReminder — срабатывание напоминания или предупреждение о нём.
+	WakeReminder WakeupKind = iota
+	// WakeAck — повтор неподтверждённой доставки.
+	WakeAck
+	// WakeDelivery — повторная попытка отправки из очереди.
+	WakeDelivery
+)
+
+// Wakeup — момент, к которому планировщику нужно проснуться ради записи ID.
+type Wakeup struct {
+	Kind WakeupKind
+	ID   int64
+	At   time.Time
+}
+
+// WakeAt возвращает, когда напоминание нужно обработать: в ближайшее из срабатывания
+// и предупреждения. Нулевое время — напоминание на паузе или продолжение ждёт родителя,
+// и будить планировщик незачем.
+func (r *Reminder) WakeAt() time.Time {
+	if r.Paused || r.Waiting {
+		return time.Time{}
+	}
+	if !r.NoticeAt.IsZero() && r.NoticeAt.Before(r.NextTime) {
+		return r.NoticeAt
+	}
+
+	return r.NextTime
+}
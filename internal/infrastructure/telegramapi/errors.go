@@ -1,9 +1,14 @@
-// Package telegramapi классифицирует ответы Telegram, которые требуют изменения
-// локального состояния чата, а не обычного повторения запроса.
+// Package telegramapi классифицирует ошибки Telegram: какие требуют изменения локального
+// состояния чата, а какие проходят сами и требуют лишь повторить запрос позже. Limiter
+// держит рассылку в лимитах Bot API, чтобы до ошибок 429 не доходило.
 package telegramapi
 
 import (
 	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	tele "gopkg.in/telebot.v4"
 )
@@ -26,3 +31,100 @@ func IsBotUnavailable(err error) bool {
 		errors.Is(err, tele.ErrKickedFromChannel) ||
 		errors.Is(err, tele.ErrNotChannelMember)
 }
+
+// apiErrorCode вытаскивает код ответа из ошибок, которые telebot не распознал: их текст
+// заканчивается кодом в скобках, «telegram: Bad Gateway (502)».
+var apiErrorCode = regexp.MustCompile(`^telegram: .* \((\d{3})\)$`)
+
+// RetryAfter возвращает паузу, которую Telegram потребовал перед повтором запроса (429).
+func RetryAfter(err error) (time.Duration, bool) {
+	var floodErr tele.FloodError
+	if !errors.As(err, &floodErr) {
+		return 0, false
+	}
+
+	return time.Duration(floodErr.RetryAfter) * time.Second, true
+}
+
+// Throttled возвращает паузу, которую назначил Limiter. Такая отправка до Telegram
+// не доходила: это не неудача, а очередь на свой лимит.
+func Throttled(err error) (time.Duration, bool) {
+	var throttledErr *ThrottledError
+	if !errors.As(err, &throttledErr) {
+		return 0, false
+	}
+
+	return throttledErr.RetryAfter, true
+}
+
+// IsTemporary сообщает, стоит ли повторить запрос позже: сетевой сбой, ошибка на стороне
+// Telegram (5xx), превышение лимита (429) или свой лимит Limiter. Остальные отказы API
+// повторять бессмысленно — ответ будет тем же.
+func IsTemporary(err error) bool {
+	if _, ok := Throttled(err); ok {
+		return true
+	}
+	code, ok := statusCode(err)
+
+	return !ok || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Классы ошибок отправки для истории доставок.
+const (
+	ClassFlood       = "flood"       // Telegram ответил 429: превышен лимит запросов
+	ClassThrottled   = "throttled"   // свой лимит Limiter: запрос до Telegram не дошёл
+	ClassServer      = "server"      // ошибка на стороне Telegram (5xx)
+	ClassNetwork     = "network"     // Telegram не ответил
+	ClassUnavailable = "unavailable" // бота удалили из чата
+	ClassMigrated    = "migrated"    // группа стала супергруппой
+	ClassRejected    = "rejected"    // прочие отказы API
+)
+
+// ErrorClass коротко называет причину неудачной отправки; для nil — пустая строка.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	if IsBotUnavailable(err) {
+		return ClassUnavailable
+	}
+	if _, ok := MigratedTo(err); ok {
+		return ClassMigrated
+	}
+	if _, ok := Throttled(err); ok {
+		return ClassThrottled
+	}
+
+	code, ok := statusCode(err)
+	switch {
+	case !ok:
+		return ClassNetwork
+	case code == http.StatusTooManyRequests:
+		return ClassFlood
+	case code >= http.StatusInternalServerError:
+		return ClassServer
+	default:
+		return ClassRejected
+	}
+}
+
+// statusCode возвращает код ответа Telegram; ok=false — ответа не было вовсе.
+func statusCode(err error) (int, bool) {
+	if _, ok := RetryAfter(err); ok {
+		return http.StatusTooManyRequests, true
+	}
+	var apiErr *tele.Error
+	var groupErr tele.GroupError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, true
+	}
+	if errors.As(err, &groupErr) {
+		return http.StatusBadRequest, true
+	}
+	if m := apiErrorCode.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code, true
+	}
+
+	return 0, false
+}
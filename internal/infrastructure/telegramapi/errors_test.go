@@ -0,0 +1,77 @@
+package telegramapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v4"
+)
+
+func TestIsTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "flood control", err: tele.FloodError{RetryAfter: 3}, want: true},
+		{name: "own rate limit", err: &ThrottledError{ChatID: "1", RetryAfter: time.Minute}, want: true},
+		{name: "server error", err: errors.New("telegram: Bad Gateway (502)"), want: true},
+		{name: "network error", err: fmt.Errorf("telebot: %w", errors.New("connection reset by peer")), want: true},
+		{name: "unknown bad request", err: errors.New("telegram: Bad Request: message is too long (400)")},
+		{name: "known API error", err: tele.ErrChatNotFound},
+		{name: "bot kicked", err: tele.ErrKickedFromGroup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTemporary(tt.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	wait, ok := RetryAfter(fmt.Errorf("send: %w", tele.FloodError{RetryAfter: 42}))
+	assert.True(t, ok)
+	assert.Equal(t, 42*time.Second, wait)
+
+	_, ok = RetryAfter(tele.ErrChatNotFound)
+	assert.False(t, ok)
+
+	_, ok = RetryAfter(&ThrottledError{ChatID: "1", RetryAfter: time.Minute})
+	assert.False(t, ok, "свой лимит — не ответ Telegram")
+}
+
+func TestThrottled(t *testing.T) {
+	wait, ok := Throttled(fmt.Errorf("send: %w", &ThrottledError{ChatID: "1", RetryAfter: 7 * time.Second}))
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	_, ok = Throttled(tele.FloodError{RetryAfter: 3})
+	assert.False(t, ok)
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "no error", err: nil, want: ""},
+		{name: "flood control", err: tele.FloodError{RetryAfter: 3}, want: ClassFlood},
+		{name: "own rate limit", err: &ThrottledError{ChatID: "1", RetryAfter: time.Minute}, want: ClassThrottled},
+		{name: "server error", err: errors.New("telegram: Bad Gateway (502)"), want: ClassServer},
+		{name: "network error", err: fmt.Errorf("telebot: %w", errors.New("connection reset")), want: ClassNetwork},
+		{name: "bot kicked", err: tele.ErrKickedFromGroup, want: ClassUnavailable},
+		{name: "group migrated", err: tele.GroupError{MigratedTo: -100123}, want: ClassMigrated},
+		{name: "known API error", err: tele.ErrChatNotFound, want: ClassRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorClass(tt.err))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package telegramapi
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+// Sender — часть API бота, через которую идёт рассылка.
+type Sender interface {
+	Send(to tele.Recipient, what any, opts ...any) (*tele.Message, error)
+}
+
+// rate — лимит частоты: не чаще раза в interval, но до burst сообщений подряд.
+type rate struct {
+	interval time.Duration
+	burst    int
+}
+
+// Лимиты Bot API: около 30 сообщений в секунду на бота, не больше одного в секунду
+// в личный чат и 20 в минуту в группу.
+var (
+	globalRate  = rate{interval: time.Second / 30, burst: 30}
+	privateRate = rate{interval: time.Second, burst: 1}
+	groupRate   = rate{interval: 3 * time.Second, burst: 3}
+)
+
+const (
+	// MaxSendWait — дольше этого Limiter отправку не задерживает: она возвращается
+	// с ThrottledError, и слот рассылки достаётся другим чатам.
+	MaxSendWait = 5 * time.Second
+	// sweepInterval — как часто забываются чаты, лимит которых уже восстановился.
+	sweepInterval = time.Minute
+)
+
+// ThrottledError — отправку пришлось бы задержать дольше MaxSendWait. Повторить её
+// стоит не раньше чем через RetryAfter.
+type ThrottledError struct {
+	ChatID     string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("telegram: rate limit for chat %s, retry after %s", e.ChatID, e.RetryAfter)
+}
+
+// bucket — ведро токенов в виде GCRA: tat — момент, когда ведро опустеет полностью.
+type bucket struct {
+	rate rate
+	tat  time.Time
+}
+
+// earliest возвращает ближайший момент не раньше now, когда в ведре есть токен.
+func (b *bucket) earliest(now time.Time) time.Time {
+	return later(now, b.tat.Add(-time.Duration(b.rate.burst-1)*b.rate.interval))
+}
+
+// take расходует токен на отправку в момент at.
+func (b *bucket) take(at time.Time) {
+	b.tat = later(b.tat, at).Add(b.rate.interval)
+}
+
+type chatBucket struct {
+	bucket
+	// pausedUntil — до этого момента Telegram просил в чат не писать (429).
+	pausedUntil time.Time
+}
+
+// Limiter ограничивает рассылку общим лимитом бота и лимитом каждого чата.
+//
+// Место под отправку резервируется в порядке вызовов, поэтому чат, в который уходит
+// много сообщений, занимает общий лимит не чаще, чем позволяет его собственный:
+// остальные чаты не ждут, пока он выговорится.
+type Limiter struct {
+	next  Sender
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	mu      sync.Mutex
+	global  bucket
+	chats   map[string]*chatBucket
+	sweptAt time.Time
+}
+
+// NewLimiter оборачивает next лимитами Bot API.
+func NewLimiter(next Sender) *Limiter {
+	return &Limiter{
+		next:   next,
+		now:    time.Now,
+		sleep:  time.Sleep,
+		global: bucket{rate: globalRate},
+		chats:  make(map[string]*chatBucket),
+	}
+}
+
+// Send дожидается места в лимитах и отправляет сообщение. Если ждать пришлось бы
+// дольше MaxSendWait, возвращает ThrottledError, ничего не отправив. После ответа 429
+// чат замолкает на время, которое назвал Telegram.
+func (l *Limiter) Send(to tele.Recipient, what any, opts ...any) (*tele.Message, error) {
+	chatID := to.Recipient()
+	wait, err := l.reserve(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		l.sleep(wait)
+	}
+
+	msg, err := l.next.Send(to, what, opts...)
+	if retryAfter, ok := RetryAfter(err); ok {
+		l.pause(chatID, retryAfter)
+	}
+
+	return msg, err
+}
+
+// reserve занимает место под отправку в чат и возвращает, сколько до неё ждать.
+func (l *Limiter) reserve(chatID string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	chat := l.chat(chatID)
+	at := later(later(l.global.earliest(now), chat.earliest(now)), chat.pausedUntil)
+	wait := at.Sub(now)
+	if wait > MaxSendWait {
+		return 0, &ThrottledError{ChatID: chatID, RetryAfter: wait}
+	}
+	l.global.take(at)
+	chat.take(at)
+
+	return wait, nil
+}
+
+func (l *Limiter) pause(chatID string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	chat := l.chat(chatID)
+	chat.pausedUntil = later(chat.pausedUntil, l.now().Add(d))
+}
+
+func (l *Limiter) chat(chatID string) *chatBucket {
+	chat, ok := l.chats[chatID]
+	if !ok {
+		// Отрицательные идентификаторы — у групп и каналов.
+		r := privateRate
+		if id, err := strconv.ParseInt(chatID, 10, 64); err == nil && id < 0 {
+			r = groupRate
+		}
+		chat = &chatBucket{bucket: bucket{rate: r}}
+		l.chats[chatID] = chat
+	}
+
+	return chat
+}
+
+// sweep забывает чаты, лимит которых полностью восстановился: иначе карта росла бы
+// с каждым чатом, куда бот хоть раз писал.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < sweepInterval {
+		return
+	}
+	l.sweptAt = now
+
+	for id, chat := range l.chats {
+		if !chat.tat.After(now) && !chat.pausedUntil.After(now) {
+			delete(l.chats, id)
+		}
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,119 @@
+package telegramapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v4"
+)
+
+type recordingSender struct {
+	sent []string
+	err  error
+}
+
+func (s *recordingSender) Send(to tele.Recipient, _ any, _ ...any) (*tele.Message, error) {
+	if s.err != nil {
+		err := s.err
+		s.err = nil
+		return nil, err
+	}
+	s.sent = append(s.sent, to.Recipient())
+
+	return &tele.Message{ID: len(s.sent)}, nil
+}
+
+// newTestLimiter возвращает лимитер на поддельных часах: sleep сдвигает их,
+// а waits запоминает каждую паузу.
+func newTestLimiter(next Sender) (*Limiter, *[]time.Duration) {
+	clock := time.Date(2026, time.June, 2, 9, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	l := NewLimiter(next)
+	l.now = func() time.Time { return clock }
+	l.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		clock = clock.Add(d)
+	}
+
+	return l, &waits
+}
+
+func TestLimiter_GlobalRate(t *testing.T) {
+	next := &recordingSender{}
+	l, waits := newTestLimiter(next)
+
+	for id := int64(1); id <= 31; id++ {
+		_, err := l.Send(&tele.Chat{ID: id}, "text")
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, next.sent, 31)
+	assert.Equal(t, []time.Duration{time.Second / 30}, *waits, "only the message over the burst waits")
+}
+
+func TestLimiter_PerChatRate(t *testing.T) {
+	next := &recordingSender{}
+	l, waits := newTestLimiter(next)
+	send := func(id int64) {
+		_, err := l.Send(&tele.Chat{ID: id}, "text")
+		require.NoError(t, err)
+	}
+
+	send(1)
+	send(1)
+	assert.Equal(t, []time.Duration{time.Second}, *waits, "a private chat gets one message per second")
+
+	*waits = nil
+	for range 4 {
+		send(-100)
+	}
+	assert.Equal(t, []time.Duration{3 * time.Second}, *waits, "a group gets a short burst, then 20 per minute")
+}
+
+func TestLimiter_ThrottlesBusyChat(t *testing.T) {
+	next := &recordingSender{}
+	l, _ := newTestLimiter(next)
+	// Часы стоят: отправки идут одновременно, и очередь к чату только растёт.
+	l.sleep = func(time.Duration) {}
+
+	var throttled int
+	for range 10 {
+		_, err := l.Send(&tele.Chat{ID: -100}, "text")
+		var throttledErr *ThrottledError
+		if errors.As(err, &throttledErr) {
+			throttled++
+			assert.Greater(t, throttledErr.RetryAfter, MaxSendWait)
+			assert.True(t, IsTemporary(err))
+			assert.Equal(t, ClassThrottled, ErrorClass(err))
+		}
+	}
+	// Три сообщения уходят сразу, четвёртое — через 3 секунды, пятому ждать дольше MaxSendWait.
+	assert.Len(t, next.sent, 4)
+	assert.Equal(t, 6, throttled, "a busy chat gets its surplus back instead of holding the sender")
+
+	_, err := l.Send(&tele.Chat{ID: 1}, "text")
+	require.NoError(t, err, "other chats are not affected")
+	assert.Equal(t, "1", next.sent[len(next.sent)-1])
+}
+
+func TestLimiter_HonoursFloodRetryAfter(t *testing.T) {
+	next := &recordingSender{err: tele.FloodError{RetryAfter: 30}}
+	l, _ := newTestLimiter(next)
+
+	_, err := l.Send(&tele.Chat{ID: 1}, "text")
+	require.Error(t, err)
+
+	// Второй запрос до Telegram не доходит: его задерживает уже сам Limiter.
+	_, err = l.Send(&tele.Chat{ID: 1}, "text")
+	wait, ok := Throttled(err)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+	assert.Empty(t, next.sent)
+
+	_, err = l.Send(&tele.Chat{ID: 2}, "text")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, next.sent)
+}
@@ -0,0 +1,447 @@
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// englishFillers — вводные слова перед напоминанием; "to" остаётся после расписания:
+// "tomorrow at 9 to call mom".
+var englishFillers = []string{"remind me to", "remind us to", "remind me", "remind us", "to"}
+
+// englishWeekdays — дни недели полностью, во множественном числе и сокращённо.
+var englishWeekdays = map[string]int{
+	"sunday": 0, "sundays": 0, "sun": 0,
+	"monday": 1, "mondays": 1, "mon": 1,
+	"tuesday": 2, "tuesdays": 2, "tue": 2, "tues": 2,
+	"wednesday": 3, "wednesdays": 3, "wed": 3,
+	"thursday": 4, "thursdays": 4, "thu": 4, "thur": 4, "thurs": 4,
+	"friday": 5, "fridays": 5, "fri": 5,
+	"saturday": 6, "saturdays": 6, "sat": 6,
+}
+
+// englishNumbers — числа, которые пишут словами: "in an hour", "every two weeks".
+var englishNumbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "twelve": 12, "fifteen": 15, "twenty": 20,
+	"thirty": 30, "forty": 40, "forty-five": 45,
+}
+
+// englishMonths — месяцы; принимаются и сокращения от трёх букв: "mar", "sept".
+var englishMonths = [...]string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+var (
+	englishClockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a\.m|p\.m)?$`)
+	englishOrdinal      = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	englishISODate      = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+)
+
+// english — грамматика английских фраз: "tomorrow at 9am", "every other Friday at 5pm",
+// "in 45 minutes", "on March 3rd".
+type english struct {
+	*phrase
+}
+
+// next разбирает очередную часть расписания. Возвращает false, когда дальше идёт текст.
+func (g english) next() bool {
+	return g.relativeTime() || g.repeatRule() || g.dayWord() || g.weekday() || g.date() ||
+		g.clockTime() || g.partOfDay()
+}
+
+// relativeTime — "in 45 minutes", "in an hour", "in half an hour", "in 2 weeks".
+func (g english) relativeTime() bool {
+	if g.peek(0) != "in" || g.relUnit != unitNone {
+		return false
+	}
+	if g.peek(1) == "half" && g.peek(2) == "an" && g.peek(3) == "hour" {
+		g.relative, g.relUnit = 30, unitMinute
+		g.pos += 4
+		return true
+	}
+
+	n, k := 1, 1
+	if v, ok := parseNumber(englishNumbers, g.peek(1)); ok {
+		n, k = v, 2
+	}
+	unit := englishUnit(g.peek(k))
+	if unit == unitNone {
+		return false
+	}
+	g.relative, g.relUnit = n, unit
+	g.pos += k + 1
+
+	return true
+}
+
+// repeatRule — "daily", "every day", "weekdays", "every Monday and Thursday", "on Fridays",
+// "every other week", "every 3 days", "every 30 minutes", "every 15th", "monthly".
+func (g english) repeatRule() bool {
+	if g.hasRepeat {
+		return false
+	}
+
+	word, next := g.peek(0), g.peek(1)
+	switch word {
+	case "daily", "everyday":
+		return g.setRepeat(domain.RepeatEveryDay, 0, 1)
+	case "weekly":
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+	case "monthly":
+		return g.setRepeat(domain.RepeatEveryMonth, 0, 1)
+	case "yearly", "annually":
+		return g.setRepeat(domain.RepeatEveryYear, 0, 1)
+	case "hourly":
+		return g.setRepeat(domain.RepeatInterval, 60, 1)
+	case "weekdays":
+		g.weekdays = []int{1, 2, 3, 4, 5}
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+	case "on":
+		if next == "weekdays" {
+			g.weekdays = []int{1, 2, 3, 4, 5}
+			return g.setRepeat(domain.RepeatEveryWeek, 0, 2)
+		}
+		if englishPlural(next) {
+			g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+			g.weekdayList(englishWeekdays, "and")
+			return true
+		}
+	case "every", "each":
+		return g.everyRule(next)
+	}
+
+	if englishPlural(word) {
+		g.setRepeat(domain.RepeatEveryWeek, 0, 0)
+		g.weekdayList(englishWeekdays, "and")
+		return true
+	}
+
+	return false
+}
+
+// everyRule разбирает продолжение после "every": next — следующее слово.
+func (g english) everyRule(next string) bool {
+	if _, ok := englishWeekdays[next]; ok {
+		g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+		g.weekdayList(englishWeekdays, "and")
+		return true
+	}
+
+	switch next {
+	case "day":
+		return g.setRepeat(domain.RepeatEveryDay, 0, 2)
+	case "weekday":
+		g.weekdays = []int{1, 2, 3, 4, 5}
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 2)
+	case "hour":
+		return g.setRepeat(domain.RepeatInterval, 60, 2)
+	case "week":
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 2)
+	case "month":
+		return g.setRepeat(domain.RepeatEveryMonth, 0, 2)
+	case "year":
+		return g.setRepeat(domain.RepeatEveryYear, 0, 2)
+	case "other":
+		return g.everyOther()
+	}
+
+	// "every 15th", "every 15th of the month".
+	if m := englishOrdinal.FindStringSubmatch(next); m != nil && m[2] != "" {
+		g.dayOfMonth, _ = strconv.Atoi(m[1])
+		words := 2
+		if g.peek(2) == "of" && g.peek(4) == "month" {
+			words += 3
+		}
+		return g.setRepeat(domain.RepeatEveryMonth, 0, words)
+	}
+
+	n, ok := parseNumber(englishNumbers, next)
+	if !ok {
+		return false
+	}
+
+	return g.setEvery(n, englishUnit(g.peek(2)), 3)
+}
+
+// everyOther — "every other day", "every other Friday": повтор через раз.
+func (g english) everyOther() bool {
+	after := g.peek(2)
+	if _, ok := englishWeekdays[after]; ok {
+		g.setRepeat(domain.RepeatEveryWeek, 2, 2)
+		g.weekdayList(englishWeekdays, "and")
+		return true
+	}
+	unit := englishUnit(after)
+	if unit == unitMinute || unit == unitHour {
+		return false
+	}
+
+	return g.setEvery(2, unit, 3)
+}
+
+// dayWord — "today", "tomorrow", "the day after tomorrow".
+func (g english) dayWord() bool {
+	if g.day != phraseNoDay || g.hasRepeat {
+		return false
+	}
+
+	switch g.peek(0) {
+	case "today":
+		g.day = phraseToday
+		g.pos++
+		return true
+	case "tomorrow", "tmrw":
+		g.day = phraseTomorrow
+		g.pos++
+		return true
+	}
+
+	k := 0
+	if g.peek(0) == "the" {
+		k = 1
+	}
+	if g.peek(k) != "day" || g.peek(k+1) != "after" || g.peek(k+2) != "tomorrow" {
+		return false
+	}
+	g.day = phraseAfterTomorrow
+	g.pos += k + 3
+
+	return true
+}
+
+// weekday — "Monday", "on Friday", "next Tuesday", "this Sat".
+func (g english) weekday() bool {
+	k := 0
+	if w := g.peek(0); w == "on" || w == "next" || w == "this" {
+		k = 1
+	}
+	if _, ok := englishWeekdays[g.peek(k)]; !ok || englishPlural(g.peek(k)) {
+		return false
+	}
+
+	return g.onWeekday(k, englishWeekdays, "and")
+}
+
+// date — "March 3rd", "on Mar 3, 2027", "3 March", "the 3rd of March", "on the 15th",
+// "2027-03-03". Число без месяца, порядкового окончания или "the" — уже текст.
+func (g english) date() bool {
+	if g.day != phraseNoDay || g.dayOfMonth != 0 {
+		return false
+	}
+
+	k := 0
+	if g.peek(0) == "on" {
+		k = 1
+	}
+	the := g.peek(k) == "the"
+	if the {
+		k++
+	}
+
+	if m := englishISODate.FindStringSubmatch(g.peek(k)); m != nil && !the {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 {
+			return false
+		}
+		return g.setDate(day, time.Month(month), year, k+1)
+	}
+
+	if month := englishMonth(g.peek(k)); month != 0 && !the {
+		m := englishOrdinal.FindStringSubmatch(g.peek(k + 1))
+		if m == nil {
+			return false
+		}
+		day, _ := strconv.Atoi(m[1])
+		year, words := g.year(k + 2)
+		return g.setDate(day, month, year, words)
+	}
+
+	m := englishOrdinal.FindStringSubmatch(g.peek(k))
+	if m == nil {
+		return false
+	}
+	day, _ := strconv.Atoi(m[1])
+	words := k + 1
+	if g.peek(words) == "of" {
+		words++
+	}
+	if month := englishMonth(g.peek(words)); month != 0 {
+		year, words := g.year(words + 1)
+		return g.setDate(day, month, year, words)
+	}
+
+	if !the && m[2] == "" {
+		return false
+	}
+	words = k + 1
+	if g.peek(words) == "of" && g.peek(words+2) == "month" {
+		words += 3
+	}
+
+	return g.setDate(day, 0, 0, words)
+}
+
+// year разбирает необязательный год на месте k; возвращает его и число слов даты.
+func (g english) year(k int) (int, int) {
+	if !phraseYearPattern.MatchString(g.peek(k)) {
+		return 0, k
+	}
+	year, _ := strconv.Atoi(g.peek(k))
+
+	return year, k + 1
+}
+
+// clockTime — "at 9", "at 9am", "at 5 pm", "9:30", "17:00", "at noon", "at 6 o'clock".
+// Число без "at", двоеточия или am/pm временем не считается: "2 tickets" — это уже текст.
+func (g english) clockTime() bool {
+	if g.hasClock && !g.rough {
+		return false
+	}
+	k := 0
+	if w := g.peek(0); w == "at" || w == "by" {
+		k = 1
+	}
+	word := g.peek(k)
+	switch word {
+	case "noon", "midday":
+		return g.setClock(12, 0, k+1)
+	case "midnight":
+		return g.setClock(0, 0, k+1)
+	}
+
+	m := englishClockPattern.FindStringSubmatch(word)
+	if m == nil {
+		return false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	words, suffix := k+1, m[3]
+	if g.peek(words) == "o'clock" {
+		words++
+	}
+	if suffix == "" {
+		switch w := g.peek(words); w {
+		case "am", "a.m", "pm", "p.m":
+			suffix = w
+			words++
+		}
+	}
+	if k == 0 && suffix == "" && !strings.Contains(word, ":") {
+		return false
+	}
+
+	switch {
+	case minute > 59, hour > 23, suffix != "" && (hour < 1 || hour > 12):
+		return g.invalidClock(word)
+	case suffix == "am" || suffix == "a.m":
+		hour %= 12
+	case suffix != "":
+		hour = hour%12 + 12
+	case g.rough && g.clock >= 12*60 && hour < 12:
+		// "tonight at 9" — это 21:00.
+		hour += 12
+	}
+
+	return g.setClock(hour, minute, words)
+}
+
+// partOfDay — "in the morning", "this afternoon", "in the evening", "at night", "tonight".
+// Уже названное время переводится во вторую половину дня: "at 7 in the evening".
+func (g english) partOfDay() bool {
+	hours := map[string]int{
+		"morning":   phraseMorningHour,
+		"afternoon": phraseDayHour,
+		"evening":   phraseEveningHour,
+		"night":     phraseNightHour,
+	}
+
+	k, today := 1, false
+	switch g.peek(0) {
+	case "tonight":
+		k, today = 0, true
+	case "in":
+		if g.peek(1) != "the" {
+			return false
+		}
+		k = 2
+	case "at":
+		if g.peek(1) != "night" {
+			return false
+		}
+	case "this":
+		today = true
+	default:
+		return false
+	}
+	hour, ok := hours[g.peek(k)]
+	if k == 0 {
+		hour, ok = phraseEveningHour, true
+	}
+	if !ok || g.rough {
+		return false
+	}
+
+	if today {
+		if g.day != phraseNoDay || g.hasRepeat {
+			return false
+		}
+		g.day = phraseToday
+	}
+	switch {
+	case !g.hasClock:
+		g.hasClock, g.clock, g.rough = true, hour*60, true
+	case hour >= 12 && g.clock < 12*60:
+		g.clock += 12 * 60
+	}
+	g.pos += k + 1
+
+	return true
+}
+
+// englishPlural — день недели во множественном числе: "Mondays" значит «по понедельникам».
+func englishPlural(s string) bool {
+	_, ok := englishWeekdays[s]
+
+	return ok && strings.HasSuffix(s, "days")
+}
+
+func englishUnit(s string) phraseUnit {
+	switch s {
+	case "min", "mins", "minute", "minutes":
+		return unitMinute
+	case "h", "hr", "hrs", "hour", "hours":
+		return unitHour
+	case "day", "days":
+		return unitDay
+	case "week", "weeks":
+		return unitWeek
+	case "month", "months":
+		return unitMonth
+	case "year", "years":
+		return unitYear
+	}
+
+	return unitNone
+}
+
+// englishMonth распознаёт месяц по названию или его началу: "march", "mar", "sept".
+func englishMonth(s string) time.Month {
+	if len(s) < 3 {
+		return 0
+	}
+	for i, name := range englishMonths {
+		if strings.HasPrefix(name, s) {
+			return time.Month(i + 1)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,143 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIn_English(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	// Среда, 10 июня 2026 года, 14:00.
+	now := at(loc, 2026, time.June, 10, 14, 0)
+
+	tests := []struct {
+		phrase string
+		text   string
+		next   time.Time
+		repeat domain.RepeatType
+		days   []int
+		every  int
+	}{
+		{
+			phrase: "tomorrow at 9am call mom",
+			text:   "call mom",
+			next:   at(loc, 2026, time.June, 11, 9, 0),
+		},
+		{
+			phrase: "every other Friday at 5pm team retro",
+			text:   "team retro",
+			next:   at(loc, 2026, time.June, 12, 17, 0),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{5},
+			every:  2,
+		},
+		{
+			phrase: "in 45 minutes take the pizza out",
+			text:   "take the pizza out",
+			next:   at(loc, 2026, time.June, 10, 14, 45),
+		},
+		{
+			phrase: "on March 3rd renew passport",
+			text:   "renew passport",
+			next:   at(loc, 2027, time.March, 3, 9, 0),
+		},
+		{
+			phrase: "every Monday and Thursday at 8:00 gym",
+			text:   "gym",
+			next:   at(loc, 2026, time.June, 11, 8, 0),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1, 4},
+		},
+		{
+			phrase: "weekdays at 9:15 standup",
+			text:   "standup",
+			next:   at(loc, 2026, time.June, 11, 9, 15),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1, 2, 3, 4, 5},
+		},
+		{
+			phrase: "every month on the 25th at noon pay rent",
+			text:   "pay rent",
+			next:   at(loc, 2026, time.June, 25, 12, 0),
+			repeat: domain.RepeatEveryMonth,
+			days:   []int{25},
+		},
+		{
+			phrase: "tonight at 9 take out the trash",
+			text:   "take out the trash",
+			next:   at(loc, 2026, time.June, 10, 21, 0),
+		},
+		{
+			phrase: "on Friday at 7 in the evening dinner",
+			text:   "dinner",
+			next:   at(loc, 2026, time.June, 12, 19, 0),
+		},
+		{
+			phrase: "the 3rd of August 2027 at 12:30 anniversary",
+			text:   "anniversary",
+			next:   at(loc, 2027, time.August, 3, 12, 30),
+		},
+		{
+			phrase: "daily at 10pm take a pill",
+			text:   "take a pill",
+			next:   at(loc, 2026, time.June, 10, 22, 0),
+			repeat: domain.RepeatEveryDay,
+		},
+		{
+			phrase: "every 3 days water the plants",
+			text:   "water the plants",
+			next:   at(loc, 2026, time.June, 11, 9, 0),
+			repeat: domain.RepeatEveryNDays,
+			every:  3,
+		},
+		{
+			phrase: "at 9am 2 tickets",
+			text:   "2 tickets",
+			next:   at(loc, 2026, time.June, 11, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			r, err := ParseIn(English, tt.phrase, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, r.Text)
+			assert.True(t, tt.next.Equal(r.NextTime), "next time %s, want %s", r.NextTime, tt.next)
+			assert.Equal(t, tt.repeat, r.Repeat)
+			assert.Equal(t, tt.days, r.RepeatDays)
+			assert.Equal(t, tt.every, r.RepeatEvery)
+		})
+	}
+}
+
+func TestParseIn_EnglishErrors(t *testing.T) {
+	now := at(time.UTC, 2026, time.June, 10, 14, 0)
+
+	tests := []struct {
+		phrase string
+		err    error
+	}{
+		{"buy milk", ErrNoSchedule},
+		{"2 tickets to Paris", ErrNoSchedule},
+		{"tomorrow at 9", domain.ErrEmptyText},
+		{"on March 3 2026 party", scheduling.ErrInvalidDate},
+		{"in 2 hours at 10 call", scheduling.ErrInvalidDate},
+		{"today at 9am call mom", ErrTodayPassed},
+		{"tomorrow at 25 tea", ErrInvalidTime},
+		{"every day at 25:00 x", ErrInvalidTime},
+		{"tomorrow at 13pm call", ErrInvalidTime},
+		{"call mom tomorrow at 9:75", ErrInvalidTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			_, err := ParseIn(English, tt.phrase, now)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
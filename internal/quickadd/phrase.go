@@ -0,0 +1,372 @@
+package quickadd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+)
+
+// Часы для частей суток; утро подставляется, когда время не названо.
+const (
+	phraseMorningHour = 9
+	phraseDayHour     = 13
+	phraseEveningHour = 19
+	phraseNightHour   = 21
+)
+
+// phraseDay — какой день назван во фразе разового напоминания.
+type phraseDay int
+
+const (
+	phraseNoDay phraseDay = iota
+	phraseToday
+	phraseTomorrow
+	phraseAfterTomorrow
+	phraseWeekday
+	phraseDate
+)
+
+// phraseUnit — единица «через N …» и «каждые N …».
+type phraseUnit int
+
+const (
+	unitNone phraseUnit = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+var (
+	phraseWordPattern  = regexp.MustCompile(`\S+`)
+	phraseClockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
+	phraseYearPattern  = regexp.MustCompile(`^\d{4}$`)
+)
+
+// phraseWord — слово фразы в нижнем регистре без знаков препинания; start и end — где
+// оно начинается и кончается в исходной строке.
+type phraseWord struct {
+	norm       string
+	start, end int
+}
+
+// phrase — расписание, собранное из фразы. Грамматики языков разбирают слова и заполняют
+// его, а apply одинаково превращает в напоминание.
+type phrase struct {
+	words []phraseWord
+	pos   int
+
+	day        phraseDay
+	weekdays   []int
+	dayOfMonth int
+	month      time.Month
+	year       int
+
+	hasClock bool
+	clock    int    // минуты от полуночи
+	rough    bool   // время взято из части суток и может уточниться: "tonight at 9"
+	badClock string // время вне суток вроде «в 25» или "at 9:75"; фраза с ним ошибочна
+
+	hasRepeat bool
+	repeat    domain.RepeatType
+	every     int // шаг «каждые N …»; у интервального повтора — в минутах
+
+	relative int
+	relUnit  phraseUnit
+}
+
+func splitPhrase(s string) []phraseWord {
+	var words []phraseWord
+	for _, loc := range phraseWordPattern.FindAllStringIndex(s, -1) {
+		norm := strings.ReplaceAll(strings.ToLower(s[loc[0]:loc[1]]), "ё", "е")
+		words = append(words, phraseWord{norm: strings.TrimRight(norm, ",.;:!?"), start: loc[0], end: loc[1]})
+	}
+
+	return words
+}
+
+func (p *phrase) peek(k int) string {
+	if p.pos+k >= len(p.words) {
+		return ""
+	}
+
+	return p.words[p.pos+k].norm
+}
+
+func (p *phrase) empty() bool {
+	return p.relUnit == unitNone && !p.hasRepeat && p.day == phraseNoDay && !p.hasClock
+}
+
+func (p *phrase) setRepeat(repeat domain.RepeatType, every, words int) bool {
+	p.hasRepeat, p.repeat, p.every = true, repeat, every
+	p.pos += words
+
+	return true
+}
+
+// setEvery — повтор «каждые n unit».
+func (p *phrase) setEvery(n int, unit phraseUnit, words int) bool {
+	switch unit {
+	case unitMinute:
+		return p.setRepeat(domain.RepeatInterval, n, words)
+	case unitHour:
+		return p.setRepeat(domain.RepeatInterval, n*60, words)
+	case unitDay:
+		return p.setRepeat(domain.RepeatEveryNDays, n, words)
+	case unitWeek:
+		return p.setRepeat(domain.RepeatEveryWeek, n, words)
+	case unitMonth:
+		return p.setRepeat(domain.RepeatEveryMonth, n, words)
+	case unitYear:
+		return p.setRepeat(domain.RepeatEveryYear, n, words)
+	case unitNone:
+	}
+
+	return false
+}
+
+// weekdayList разбирает дни недели через запятую или союз and: «понедельник и среду».
+func (p *phrase) weekdayList(days map[string]int, and string) {
+	for {
+		d, ok := days[p.peek(0)]
+		if !ok {
+			return
+		}
+		if !slices.Contains(p.weekdays, d) {
+			p.weekdays = append(p.weekdays, d)
+		}
+		p.pos++
+		if _, more := days[p.peek(1)]; p.peek(0) == and && more {
+			p.pos++
+		}
+	}
+}
+
+// onWeekday разбирает дни недели, начиная со слова k. После «еженедельно» день недели
+// уточняет повтор, иначе это разовое напоминание в ближайший такой день.
+func (p *phrase) onWeekday(k int, days map[string]int, and string) bool {
+	weeklyRepeat := p.hasRepeat && p.repeat == domain.RepeatEveryWeek && len(p.weekdays) == 0
+	if !weeklyRepeat {
+		if p.day != phraseNoDay || p.hasRepeat {
+			return false
+		}
+		p.day = phraseWeekday
+	}
+	p.pos += k
+	p.weekdayList(days, and)
+
+	return true
+}
+
+func (p *phrase) setDate(day int, month time.Month, year, words int) bool {
+	if day < 1 || day > 31 {
+		return false
+	}
+	if !p.hasRepeat {
+		p.day = phraseDate
+	}
+	p.dayOfMonth, p.month, p.year = day, month, year
+	p.pos += words
+
+	return true
+}
+
+func (p *phrase) setClock(hour, minute, words int) bool {
+	if hour > 23 || minute > 59 {
+		return false
+	}
+	p.hasClock, p.clock, p.rough = true, hour*60+minute, false
+	p.pos += words
+
+	return true
+}
+
+// invalidClock запоминает время word вне суток и останавливает разбор: такое время —
+// ошибка в записи, а не начало текста.
+func (p *phrase) invalidClock(word string) bool {
+	p.badClock = word
+
+	return false
+}
+
+// apply заполняет расписание напоминания r.
+func (p *phrase) apply(r *domain.Reminder, now time.Time) error {
+	clock := time.Date(0, time.January, 1, phraseMorningHour, 0, 0, 0, now.Location())
+	if p.hasClock {
+		clock = time.Date(0, time.January, 1, p.clock/60, p.clock%60, 0, 0, now.Location())
+	}
+
+	var err error
+	switch {
+	case p.relUnit != unitNone:
+		r.NextTime, err = p.relativeNext(now, clock)
+	case p.hasRepeat:
+		err = p.applyRepeat(r, now, clock)
+	default:
+		r.Repeat = domain.RepeatNone
+		r.NextTime, err = p.onceNext(now, clock)
+	}
+
+	return err
+}
+
+// relativeNext считает время «через N …». К дням, неделям и месяцам можно добавить
+// время суток: «через 2 дня в 10».
+func (p *phrase) relativeNext(now, clock time.Time) (time.Time, error) {
+	if p.hasRepeat || p.day != phraseNoDay {
+		return time.Time{}, fmt.Errorf("%w: relative time cannot be combined with a date", scheduling.ErrInvalidDate)
+	}
+
+	var at time.Time
+	switch p.relUnit {
+	case unitMinute:
+		at = now.Add(time.Duration(p.relative) * time.Minute)
+	case unitHour:
+		at = now.Add(time.Duration(p.relative) * time.Hour)
+	case unitDay:
+		at = now.AddDate(0, 0, p.relative)
+	case unitWeek:
+		at = now.AddDate(0, 0, 7*p.relative)
+	case unitMonth:
+		at = now.AddDate(0, p.relative, 0)
+	case unitYear:
+		at = now.AddDate(p.relative, 0, 0)
+	case unitNone:
+	}
+	if !p.hasClock {
+		return at, nil
+	}
+	if p.relUnit == unitMinute || p.relUnit == unitHour {
+		return time.Time{}, fmt.Errorf("%w: relative time already sets the clock", scheduling.ErrInvalidDate)
+	}
+
+	return time.Date(at.Year(), at.Month(), at.Day(), clock.Hour(), clock.Minute(), 0, 0, at.Location()), nil
+}
+
+// onceNext считает время разового напоминания.
+func (p *phrase) onceNext(now, clock time.Time) (time.Time, error) {
+	switch p.day {
+	case phraseTomorrow:
+		return scheduling.NextTomorrow(now, clock), nil
+	case phraseAfterTomorrow:
+		return scheduling.NextTomorrow(now, clock).AddDate(0, 0, 1), nil
+	case phraseWeekday:
+		return nextOfWeekdays(now, clock, p.weekdays)
+	case phraseDate:
+		return p.nextDate(now, clock)
+	case phraseToday:
+		at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			return time.Time{}, fmt.Errorf("%w: %s", ErrTodayPassed, at.Format("15:04"))
+		}
+
+		return at, nil
+	case phraseNoDay:
+	}
+
+	return scheduling.NextToday(now, clock), nil
+}
+
+// nextDate считает время для названной даты: без года — ближайшая такая, без месяца —
+// ближайшее такое число.
+func (p *phrase) nextDate(now, clock time.Time) (time.Time, error) {
+	switch {
+	case p.month == 0:
+		return scheduling.NextMonthDay(now, clock, p.dayOfMonth)
+	case p.year == 0:
+		return scheduling.NextYearDay(now, clock, fmt.Sprintf("%02d.%02d", p.dayOfMonth, p.month))
+	}
+
+	date := fmt.Sprintf("%02d.%02d.%04d", p.dayOfMonth, p.month, p.year)
+	at, err := scheduling.AtDate(clock, date, now.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("%w: %s is in the past", scheduling.ErrInvalidDate, date)
+	}
+
+	return at, nil
+}
+
+// applyRepeat заполняет повтор и первое срабатывание. Не названные день недели, число
+// или дата берутся из сегодняшнего дня.
+func (p *phrase) applyRepeat(r *domain.Reminder, now, clock time.Time) error {
+	if p.day == phraseToday || p.day == phraseTomorrow || p.day == phraseAfterTomorrow {
+		return fmt.Errorf("%w: a repeating reminder cannot start on a single day", scheduling.ErrInvalidDate)
+	}
+
+	r.Repeat = p.repeat
+	var err error
+	switch p.repeat {
+	case domain.RepeatEveryDay:
+		r.NextTime = scheduling.NextToday(now, clock)
+	case domain.RepeatEveryNDays:
+		r.RepeatEvery = p.every
+		r.NextTime = scheduling.NextToday(now, clock)
+	case domain.RepeatEveryWeek:
+		if len(p.weekdays) == 0 {
+			p.weekdays = []int{int(now.Weekday())}
+		}
+		r.RepeatDays = slices.Sorted(slices.Values(p.weekdays))
+		r.RepeatEvery = p.every
+		r.NextTime, err = nextOfWeekdays(now, clock, p.weekdays)
+	case domain.RepeatEveryMonth:
+		if p.dayOfMonth == 0 {
+			p.dayOfMonth = now.Day()
+		}
+		r.RepeatDays = []int{p.dayOfMonth}
+		r.RepeatEvery = p.every
+		r.NextTime, err = scheduling.NextMonthDay(now, clock, p.dayOfMonth)
+	case domain.RepeatEveryYear:
+		if p.dayOfMonth == 0 {
+			p.dayOfMonth = now.Day()
+		}
+		if p.month == 0 {
+			p.month = now.Month()
+		}
+		r.RepeatEvery = p.every
+		r.NextTime, err = scheduling.NextYearDay(now, clock, fmt.Sprintf("%02d.%02d", p.dayOfMonth, p.month))
+	case domain.RepeatInterval:
+		r.IntervalMinutes = p.every
+		r.NextTime, err = scheduling.NextInterval(now, r)
+	default:
+		err = fmt.Errorf("%w: unsupported repeat %d", scheduling.ErrInvalidDate, p.repeat)
+	}
+
+	return err
+}
+
+// nextOfWeekdays — ближайшее срабатывание в один из дней недели days.
+func nextOfWeekdays(now, clock time.Time, days []int) (time.Time, error) {
+	var next time.Time
+	for _, d := range days {
+		candidate, err := scheduling.NextWeekday(now, clock, d)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || candidate.Before(next) {
+			next = candidate
+		}
+	}
+
+	return next, nil
+}
+
+// parseNumber распознаёт число цифрами или словом из words.
+func parseNumber(words map[string]int, s string) (int, bool) {
+	if n, ok := words[s]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(s)
+
+	return n, err == nil && n > 0 && n <= 999
+}
@@ -0,0 +1,160 @@
+// Package quickadd разбирает напоминание, записанное одной фразой по-русски или
+// по-английски: «завтра в 9 купить молоко», "every other Friday at 5pm retro".
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// Language — язык фразы.
+type Language string
+
+const (
+	Russian Language = "ru"
+	English Language = "en"
+)
+
+var (
+	// ErrNoSchedule возвращается, если во фразе не нашлось ни даты, ни времени, ни повтора.
+	ErrNoSchedule = errors.New("phrase has no schedule")
+	// ErrTodayPassed возвращается, если названное «сегодня» время уже прошло: молча
+	// перенести его на завтра значило бы напомнить не в тот день, который назвали.
+	ErrTodayPassed = errors.New("time today has already passed")
+	// ErrInvalidTime возвращается, если названное время выходит за пределы суток: «в 25», "at 9:75".
+	ErrInvalidTime = errors.New("invalid time of day")
+)
+
+// grammar — правила одного языка.
+type grammar struct {
+	// fillers — вводные слова, которые отбрасываются с начала фразы и текста.
+	fillers []string
+	// next разбирает очередную часть расписания и возвращает false, когда дальше текст.
+	next func(p *phrase) bool
+}
+
+var grammars = map[Language]grammar{
+	Russian: {fillers: russianFillers, next: func(p *phrase) bool { return russian{p}.next() }},
+	English: {fillers: englishFillers, next: func(p *phrase) bool { return english{p}.next() }},
+}
+
+// Detect определяет язык фразы по тому, каких букв в ней больше: кириллицы или латиницы.
+// Фраза без букв считается русской.
+func Detect(s string) Language {
+	var cyrillic, latin int
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if latin > cyrillic {
+		return English
+	}
+
+	return Russian
+}
+
+// Parse разбирает фразу на языке, который определил Detect. Если расписания на нём
+// не нашлось, пробует второй язык: в «созвон with team at 5pm» кириллицы больше,
+// а расписание английское. Возвращает язык, на котором фраза разобралась.
+func Parse(s string, now time.Time) (*domain.Reminder, Language, error) {
+	lang := Detect(s)
+	r, err := ParseIn(lang, s, now)
+	if !errors.Is(err, ErrNoSchedule) {
+		return r, lang, err
+	}
+
+	other := English
+	if lang == English {
+		other = Russian
+	}
+	if r, otherErr := ParseIn(other, s, now); !errors.Is(otherErr, ErrNoSchedule) {
+		return r, other, otherErr
+	}
+
+	return nil, lang, err
+}
+
+// ParseIn разбирает фразу на языке lang. Расписание ищется в начале фразы, а если там
+// его нет — в конце: «купить молоко завтра в 9». Остаток становится текстом.
+//
+// now — в часовом поясе чата; NextTime возвращается в нём же. Если время не названо,
+// разовое и повторяющееся напоминание приходят в 9 утра.
+func ParseIn(lang Language, s string, now time.Time) (*domain.Reminder, error) {
+	g, ok := grammars[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", lang)
+	}
+
+	s = trimFillers(s, g.fillers)
+	words := splitPhrase(s)
+	p, text := g.scan(words, 0), ""
+	if p.badClock != "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTime, p.badClock)
+	}
+	if !p.empty() {
+		text = strings.TrimLeft(s[words[p.pos-1].end:], " ,.:;—–-")
+	} else {
+		for start := 1; start < len(words); start++ {
+			tail := g.scan(words, start)
+			if !tail.empty() && tail.badClock != "" {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidTime, tail.badClock)
+			}
+			if !tail.empty() && tail.pos == len(words) {
+				p, text = tail, strings.TrimRight(s[:words[start].start], " ,.:;—–-")
+				break
+			}
+		}
+	}
+	if p.empty() {
+		return nil, ErrNoSchedule
+	}
+
+	text = trimFillers(text, g.fillers)
+	if text == "" {
+		return nil, domain.ErrEmptyText
+	}
+
+	r := &domain.Reminder{Text: text}
+	if err := p.apply(r, now); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// scan разбирает расписание, начиная со слова start, пока не дойдёт до текста.
+func (g grammar) scan(words []phraseWord, start int) *phrase {
+	p := &phrase{words: words, pos: start}
+	for p.pos < len(words) {
+		if !g.next(p) {
+			break
+		}
+	}
+
+	return p
+}
+
+// trimFillers отбрасывает с начала s первое подходящее вводное слово.
+func trimFillers(s string, fillers []string) string {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, filler := range fillers {
+		rest, ok := strings.CutPrefix(lower, filler)
+		if !ok || rest != "" && !unicode.IsSpace([]rune(rest)[0]) && !unicode.IsPunct([]rune(rest)[0]) {
+			continue
+		}
+		// Строчные буквы кириллицы и латиницы занимают столько же байт, сколько заглавные.
+		return strings.TrimSpace(strings.TrimLeft(s[len(filler):], " ,:"))
+	}
+
+	return s
+}
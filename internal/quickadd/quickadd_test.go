@@ -0,0 +1,55 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(loc *time.Location, year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, Russian, Detect("завтра в 9 купить молоко"))
+	assert.Equal(t, English, Detect("tomorrow at 9am call mom"))
+	assert.Equal(t, Russian, Detect("позвонить в IKEA завтра"))
+	assert.Equal(t, Russian, Detect("17:00"))
+}
+
+func TestParse_RoutesByLanguage(t *testing.T) {
+	now := at(time.UTC, 2026, time.June, 10, 14, 0)
+
+	tests := []struct {
+		phrase string
+		lang   Language
+		text   string
+		next   time.Time
+	}{
+		{"завтра в 9 купить молоко", Russian, "купить молоко", at(time.UTC, 2026, time.June, 11, 9, 0)},
+		{"tomorrow at 9am call mom", English, "call mom", at(time.UTC, 2026, time.June, 11, 9, 0)},
+		// Кириллицы больше, но расписание записано по-английски.
+		{"созвон at 5pm", English, "созвон", at(time.UTC, 2026, time.June, 10, 17, 0)},
+		{"купить молоко завтра в 9", Russian, "купить молоко", at(time.UTC, 2026, time.June, 11, 9, 0)},
+		{"Remind me to call mom tomorrow at 9am", English, "call mom", at(time.UTC, 2026, time.June, 11, 9, 0)},
+		{"remind me tomorrow to call mom", English, "call mom", at(time.UTC, 2026, time.June, 11, 9, 0)},
+		{"Напомни мне в пятницу, сдать отчёт", Russian, "сдать отчёт", at(time.UTC, 2026, time.June, 12, 9, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			r, lang, err := Parse(tt.phrase, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.lang, lang)
+			assert.Equal(t, tt.text, r.Text)
+			assert.Equal(t, tt.next, r.NextTime)
+		})
+	}
+}
+
+func TestParse_NoSchedule(t *testing.T) {
+	_, lang, err := Parse("buy milk", time.Now())
+	require.ErrorIs(t, err, ErrNoSchedule)
+	assert.Equal(t, English, lang)
+}
@@ -0,0 +1,309 @@
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// russianFillers — вводные слова перед напоминанием.
+var russianFillers = []string{"напомни мне", "напомните мне", "напомни", "напомните", "напомнить"}
+
+// russianWeekdays — формы дней недели: «понедельник», «в среду», «по пятницам», «пт».
+var russianWeekdays = map[string]int{
+	"воскресенье": 0, "воскресеньям": 0, "вс": 0,
+	"понедельник": 1, "понедельникам": 1, "пн": 1,
+	"вторник": 2, "вторникам": 2, "вт": 2,
+	"среда": 3, "среду": 3, "средам": 3, "ср": 3,
+	"четверг": 4, "четвергам": 4, "чт": 4,
+	"пятница": 5, "пятницу": 5, "пятницам": 5, "пт": 5,
+	"суббота": 6, "субботу": 6, "субботам": 6, "сб": 6,
+}
+
+// russianNumbers — числа, которые пишут словами: «через два часа», «каждые пять минут».
+var russianNumbers = map[string]int{
+	"один": 1, "одну": 1, "два": 2, "две": 2, "три": 3, "четыре": 4, "пять": 5, "шесть": 6,
+	"семь": 7, "восемь": 8, "девять": 9, "десять": 10, "пятнадцать": 15, "двадцать": 20,
+	"тридцать": 30, "сорок": 40,
+}
+
+var (
+	russianDatePattern = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?$`)
+	russianOrdinal     = regexp.MustCompile(`^(\d{1,2})(?:-?го|-?е)?$`)
+)
+
+// russian — грамматика русских фраз: «завтра в 9», «каждый понедельник в 10:30»,
+// «через 2 часа», «15 июня в 18:00».
+type russian struct {
+	*phrase
+}
+
+// next разбирает очередную часть расписания. Возвращает false, когда дальше идёт текст.
+func (g russian) next() bool {
+	return g.relativeTime() || g.repeatRule() || g.dayWord() || g.weekday() || g.date() ||
+		g.clockTime() || g.partOfDay()
+}
+
+// relativeTime — «через 2 часа», «через полчаса», «через неделю».
+func (g russian) relativeTime() bool {
+	if g.peek(0) != "через" || g.relUnit != unitNone {
+		return false
+	}
+	if g.peek(1) == "полчаса" {
+		g.relative, g.relUnit = 30, unitMinute
+		g.pos += 2
+		return true
+	}
+
+	n, k := 1, 1
+	if v, ok := parseNumber(russianNumbers, g.peek(1)); ok {
+		n, k = v, 2
+	}
+	unit := russianUnit(g.peek(k))
+	if unit == unitNone {
+		return false
+	}
+	g.relative, g.relUnit = n, unit
+	g.pos += k + 1
+
+	return true
+}
+
+// repeatRule — «каждый день», «ежедневно», «по будням», «каждую среду», «по пятницам»,
+// «каждые 3 дня», «каждые 30 минут», «каждое 15 число», «каждый месяц», «ежегодно».
+func (g russian) repeatRule() bool {
+	if g.hasRepeat {
+		return false
+	}
+
+	word, next := g.peek(0), g.peek(1)
+	switch word {
+	case "ежедневно":
+		return g.setRepeat(domain.RepeatEveryDay, 0, 1)
+	case "еженедельно":
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+	case "ежемесячно":
+		return g.setRepeat(domain.RepeatEveryMonth, 0, 1)
+	case "ежегодно":
+		return g.setRepeat(domain.RepeatEveryYear, 0, 1)
+	case "по":
+		if next == "будням" {
+			g.weekdays = []int{1, 2, 3, 4, 5}
+			return g.setRepeat(domain.RepeatEveryWeek, 0, 2)
+		}
+		if _, ok := russianWeekdays[next]; ok {
+			g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+			g.weekdayList(russianWeekdays, "и")
+			return true
+		}
+	case "каждый", "каждую", "каждое", "каждые":
+		return g.everyRule(next)
+	}
+
+	return false
+}
+
+// everyRule разбирает продолжение после «каждый»: next — следующее слово.
+func (g russian) everyRule(next string) bool {
+	if _, ok := russianWeekdays[next]; ok {
+		g.setRepeat(domain.RepeatEveryWeek, 0, 1)
+		g.weekdayList(russianWeekdays, "и")
+		return true
+	}
+
+	switch next {
+	case "день":
+		return g.setRepeat(domain.RepeatEveryDay, 0, 2)
+	case "будний":
+		if g.peek(2) != "день" {
+			return false
+		}
+		g.weekdays = []int{1, 2, 3, 4, 5}
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 3)
+	case "час":
+		return g.setRepeat(domain.RepeatInterval, 60, 2)
+	case "неделю":
+		return g.setRepeat(domain.RepeatEveryWeek, 0, 2)
+	case "месяц":
+		return g.setRepeat(domain.RepeatEveryMonth, 0, 2)
+	case "год":
+		return g.setRepeat(domain.RepeatEveryYear, 0, 2)
+	}
+
+	n, ok := parseNumber(russianNumbers, next)
+	if !ok {
+		return false
+	}
+	if after := g.peek(2); after == "число" || after == "числа" {
+		g.dayOfMonth = n
+		return g.setRepeat(domain.RepeatEveryMonth, 0, 3)
+	}
+
+	return g.setEvery(n, russianUnit(g.peek(2)), 3)
+}
+
+// dayWord — «сегодня», «завтра», «послезавтра».
+func (g russian) dayWord() bool {
+	days := map[string]phraseDay{
+		"сегодня":     phraseToday,
+		"завтра":      phraseTomorrow,
+		"послезавтра": phraseAfterTomorrow,
+	}
+	day, ok := days[g.peek(0)]
+	if !ok || g.day != phraseNoDay || g.hasRepeat {
+		return false
+	}
+	g.day = day
+	g.pos++
+
+	return true
+}
+
+// weekday — «в понедельник», «во вторник», «пятницу».
+func (g russian) weekday() bool {
+	k := 0
+	if w := g.peek(0); w == "в" || w == "во" {
+		k = 1
+	}
+	if _, ok := russianWeekdays[g.peek(k)]; !ok {
+		return false
+	}
+
+	return g.onWeekday(k, russianWeekdays, "и")
+}
+
+// date — «15 июня», «15 июня 2027», «15.06», «15.06.2027», «15 числа», «15-го».
+func (g russian) date() bool {
+	if g.day != phraseNoDay || g.dayOfMonth != 0 {
+		return false
+	}
+
+	if m := russianDatePattern.FindStringSubmatch(g.peek(0)); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 {
+			return false
+		}
+		return g.setDate(day, time.Month(month), year, 1)
+	}
+
+	m := russianOrdinal.FindStringSubmatch(g.peek(0))
+	if m == nil {
+		return false
+	}
+	day, _ := strconv.Atoi(m[1])
+	if next := g.peek(1); next == "числа" || next == "число" {
+		return g.setDate(day, 0, 0, 2)
+	}
+	if m[0] != m[1] {
+		// «15-го» без месяца — число текущего месяца.
+		return g.setDate(day, 0, 0, 1)
+	}
+
+	month := russianMonth(g.peek(1))
+	if month == 0 {
+		return false
+	}
+	words, year := 2, 0
+	if phraseYearPattern.MatchString(g.peek(2)) {
+		year, _ = strconv.Atoi(g.peek(2))
+		words++
+		if w := g.peek(3); w == "года" || w == "г" {
+			words++
+		}
+	}
+
+	return g.setDate(day, month, year, words)
+}
+
+// clockTime — «в 9», «в 10:30», «к 18:00», «9:30», «в 7 вечера», «в 21 час».
+// Число без «в» временем не считается: «2 таблетки» — это уже текст.
+func (g russian) clockTime() bool {
+	if g.hasClock {
+		return false
+	}
+	k := 0
+	if w := g.peek(0); w == "в" || w == "во" || w == "к" {
+		k = 1
+	}
+	word := g.peek(k)
+	if k == 0 && !strings.Contains(word, ":") {
+		return false
+	}
+	m := phraseClockPattern.FindStringSubmatch(word)
+	if m == nil {
+		return false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if hour > 23 || minute > 59 {
+		return g.invalidClock(word)
+	}
+	words := k + 1
+
+	if w := g.peek(words); w == "час" || w == "часа" || w == "часов" {
+		words++
+	}
+	switch g.peek(words) {
+	case "утра":
+		words++
+	case "дня", "вечера":
+		if hour < 12 {
+			hour += 12
+		}
+		words++
+	case "ночи":
+		if hour == 12 {
+			hour = 0
+		}
+		words++
+	}
+
+	return g.setClock(hour, minute, words)
+}
+
+// partOfDay — «утром», «днём», «вечером».
+func (g russian) partOfDay() bool {
+	hours := map[string]int{"утром": phraseMorningHour, "днем": phraseDayHour, "вечером": phraseEveningHour}
+	hour, ok := hours[g.peek(0)]
+	if !ok || g.hasClock {
+		return false
+	}
+
+	return g.setClock(hour, 0, 1)
+}
+
+func russianUnit(s string) phraseUnit {
+	switch {
+	case strings.HasPrefix(s, "мин"):
+		return unitMinute
+	case strings.HasPrefix(s, "час"):
+		return unitHour
+	case s == "день" || s == "дня" || s == "дней" || strings.HasPrefix(s, "сут"):
+		return unitDay
+	case strings.HasPrefix(s, "недел"):
+		return unitWeek
+	case strings.HasPrefix(s, "месяц"):
+		return unitMonth
+	case s == "год" || s == "года" || s == "лет":
+		return unitYear
+	}
+
+	return unitNone
+}
+
+// russianMonth распознаёт месяц в родительном падеже: «июня», «мая», «дек».
+func russianMonth(s string) time.Month {
+	prefixes := [...]string{"янв", "фев", "мар", "апр", "ма", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"}
+	for i, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) && (prefix != "ма" || s == "мая" || s == "май") {
+			return time.Month(i + 1)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,170 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIn_Russian(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	// Среда, 10 июня 2026 года, 14:00.
+	now := at(loc, 2026, time.June, 10, 14, 0)
+
+	tests := []struct {
+		phrase string
+		text   string
+		next   time.Time
+		repeat domain.RepeatType
+		days   []int
+		every  int
+	}{
+		{
+			phrase: "завтра в 9 купить молоко",
+			text:   "купить молоко",
+			next:   at(loc, 2026, time.June, 11, 9, 0),
+		},
+		{
+			phrase: "каждый понедельник в 10:30 планёрка",
+			text:   "планёрка",
+			next:   at(loc, 2026, time.June, 15, 10, 30),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1},
+		},
+		{
+			phrase: "через 2 часа позвонить",
+			text:   "позвонить",
+			next:   at(loc, 2026, time.June, 10, 16, 0),
+		},
+		{
+			phrase: "15 июня в 18:00 день рождения",
+			text:   "день рождения",
+			next:   at(loc, 2026, time.June, 15, 18, 0),
+		},
+		{
+			phrase: "Сегодня в 7 вечера: забрать посылку",
+			text:   "забрать посылку",
+			next:   at(loc, 2026, time.June, 10, 19, 0),
+		},
+		{
+			phrase: "в пятницу вечером сдать отчёт",
+			text:   "сдать отчёт",
+			next:   at(loc, 2026, time.June, 12, 19, 0),
+		},
+		{
+			phrase: "по понедельникам и четвергам в 8:00 спортзал",
+			text:   "спортзал",
+			next:   at(loc, 2026, time.June, 11, 8, 0),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1, 4},
+		},
+		{
+			phrase: "по будням в 9:15 стендап",
+			text:   "стендап",
+			next:   at(loc, 2026, time.June, 11, 9, 15),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1, 2, 3, 4, 5},
+		},
+		{
+			phrase: "ежедневно в 22:00 выпить таблетку",
+			text:   "выпить таблетку",
+			next:   at(loc, 2026, time.June, 10, 22, 0),
+			repeat: domain.RepeatEveryDay,
+		},
+		{
+			phrase: "каждое 25 число в 12:00 оплатить аренду",
+			text:   "оплатить аренду",
+			next:   at(loc, 2026, time.June, 25, 12, 0),
+			repeat: domain.RepeatEveryMonth,
+			days:   []int{25},
+		},
+		{
+			phrase: "каждые 2 недели в пятницу в 17:00 ретро",
+			text:   "ретро",
+			next:   at(loc, 2026, time.June, 12, 17, 0),
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{5},
+			every:  2,
+		},
+		{
+			phrase: "ежегодно 1 сентября в 8:00 линейка",
+			text:   "линейка",
+			next:   at(loc, 2026, time.September, 1, 8, 0),
+			repeat: domain.RepeatEveryYear,
+		},
+		{
+			phrase: "через 3 дня в 10 продлить полис",
+			text:   "продлить полис",
+			next:   at(loc, 2026, time.June, 13, 10, 0),
+		},
+		{
+			phrase: "послезавтра забрать ключи",
+			text:   "забрать ключи",
+			next:   at(loc, 2026, time.June, 12, 9, 0),
+		},
+		{
+			phrase: "1 мая 2027 года в 12:00 шашлыки",
+			text:   "шашлыки",
+			next:   at(loc, 2027, time.May, 1, 12, 0),
+		},
+		{
+			phrase: "в 9 утра 2 таблетки",
+			text:   "2 таблетки",
+			next:   at(loc, 2026, time.June, 11, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			r, err := ParseIn(Russian, tt.phrase, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, r.Text)
+			assert.True(t, tt.next.Equal(r.NextTime), "next time %s, want %s", r.NextTime, tt.next)
+			assert.Equal(t, tt.repeat, r.Repeat)
+			assert.Equal(t, tt.days, r.RepeatDays)
+			assert.Equal(t, tt.every, r.RepeatEvery)
+		})
+	}
+}
+
+func TestParseIn_RussianInterval(t *testing.T) {
+	now := at(time.UTC, 2026, time.June, 10, 14, 7)
+
+	r, err := ParseIn(Russian, "каждые 30 минут размяться", now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RepeatInterval, r.Repeat)
+	assert.Equal(t, 30, r.IntervalMinutes)
+	assert.Equal(t, at(time.UTC, 2026, time.June, 10, 14, 37), r.NextTime)
+}
+
+func TestParseIn_RussianErrors(t *testing.T) {
+	now := at(time.UTC, 2026, time.June, 10, 14, 0)
+
+	tests := []struct {
+		phrase string
+		err    error
+	}{
+		{"купить молоко", ErrNoSchedule},
+		{"2 таблетки выпить", ErrNoSchedule},
+		{"завтра в 9", domain.ErrEmptyText},
+		{"1 мая 2026 в 12:00 шашлыки", scheduling.ErrInvalidDate},
+		{"через 2 часа в 10 позвонить", scheduling.ErrInvalidDate},
+		{"завтра каждый день зарядка", scheduling.ErrInvalidDate},
+		{"сегодня в 9 позвонить маме", ErrTodayPassed},
+		{"сегодня купить молоко", ErrTodayPassed},
+		{"завтра в 9:75 x", ErrInvalidTime},
+		{"завтра в 25 позвонить", ErrInvalidTime},
+		{"каждый день в 24:00 зарядка", ErrInvalidTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			_, err := ParseIn(Russian, tt.phrase, now)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+const (
+	getWorkweekQuery = `SELECT workweek FROM chat_calendars WHERE chat_id = ?`
+
+	listCalendarDaysQuery = `SELECT day, working FROM chat_calendar_days WHERE chat_id = ?`
+
+	upsertCalendarQuery = `INSERT INTO chat_calendars (chat_id, workweek, updated_at) VALUES (?, ?, ?)
+        ON CONFLICT(chat_id) DO UPDATE SET
+            workweek = excluded.workweek,
+            updated_at = excluded.updated_at`
+
+	deleteCalendarDaysQuery = `DELETE FROM chat_calendar_days WHERE chat_id = ?`
+
+	insertCalendarDayQuery = `INSERT INTO chat_calendar_days (chat_id, day, working) VALUES (?, ?, ?)`
+)
+
+func (r *chatRepository) GetCalendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error) {
+	if chatID == 0 {
+		return nil, fmt.Errorf("%w: invalid chat ID", ErrDatabaseError)
+	}
+
+	cal := &domain.BusinessCalendar{ChatID: chatID, Days: map[string]bool{}}
+
+	var workweek string
+	err := r.db.QueryRowContext(ctx, getWorkweekQuery, chatID).Scan(&workweek)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return cal, nil
+	case err != nil:
+		return nil, fmt.Errorf("%w: failed to read calendar: %v", ErrDatabaseError, err)
+	}
+	cal.Workweek = deserializeRepeatDays(workweek)
+
+	rows, err := r.db.QueryContext(ctx, listCalendarDaysQuery, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query calendar days: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var day string
+		var working bool
+		if err := rows.Scan(&day, &working); err != nil {
+			return nil, fmt.Errorf("%w: failed to scan calendar day: %v", ErrDatabaseError, err)
+		}
+		cal.Days[day] = working
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read calendar days: %v", ErrDatabaseError, err)
+	}
+
+	return cal, nil
+}
+
+func (r *chatRepository) SaveCalendar(ctx context.Context, cal *domain.BusinessCalendar) error {
+	if cal == nil || cal.ChatID == 0 {
+		return fmt.Errorf("%w: calendar needs a chat", ErrDatabaseError)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: begin calendar update: %v", ErrDatabaseError, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, upsertCalendarQuery,
+		cal.ChatID, serializeRepeatDays(cal.Workweek), time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("%w: failed to save calendar: %v", ErrDatabaseError, err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteCalendarDaysQuery, cal.ChatID); err != nil {
+		return fmt.Errorf("%w: failed to clear calendar days: %v", ErrDatabaseError, err)
+	}
+	for day, working := range cal.Days {
+		if _, err := tx.ExecContext(ctx, insertCalendarDayQuery, cal.ChatID, day, working); err != nil {
+			return fmt.Errorf("%w: failed to save calendar day: %v", ErrDatabaseError, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: commit calendar update: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// migrateCalendarTx переносит календарь группы на новый ID. Календарь, уже заданный
+// для нового ID, авторитетнее — как и остальные его настройки в mergeMigratedChat.
+func migrateCalendarTx(ctx context.Context, tx *sql.Tx, oldChatID, newChatID int64) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM chat_calendars WHERE chat_id=?)`, newChatID,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("%w: check migrated calendar: %v", ErrDatabaseError, err)
+	}
+
+	if !exists {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE chat_calendars SET chat_id=? WHERE chat_id=?`, newChatID, oldChatID,
+		); err != nil {
+			return fmt.Errorf("%w: move calendar: %v", ErrDatabaseError, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE chat_calendar_days SET chat_id=? WHERE chat_id=?`, newChatID, oldChatID,
+		); err != nil {
+			return fmt.Errorf("%w: move calendar days: %v", ErrDatabaseError, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chat_calendars WHERE chat_id=?`, oldChatID); err != nil {
+		return fmt.Errorf("%w: delete old calendar: %v", ErrDatabaseError, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chat_calendar_days WHERE chat_id=?`, oldChatID); err != nil {
+		return fmt.Errorf("%w: delete old calendar days: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatRepository_Calendar(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) ChatRepository {
+		t.Helper()
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+
+		return NewChatRepository(db)
+	}
+
+	t.Run("empty by default", func(t *testing.T) {
+		repo := setup(t)
+
+		cal, err := repo.GetCalendar(ctx, 42)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), cal.ChatID)
+		assert.Nil(t, cal.Workweek)
+		assert.Empty(t, cal.Days)
+	})
+
+	t.Run("save replaces calendar", func(t *testing.T) {
+		repo := setup(t)
+
+		require.NoError(t, repo.SaveCalendar(ctx, &domain.BusinessCalendar{
+			ChatID:   42,
+			Workweek: []int{1, 2, 3, 4, 5, 6},
+			Days:     map[string]bool{"2026-01-01": false, "2026-01-02": false},
+		}))
+		require.NoError(t, repo.SaveCalendar(ctx, &domain.BusinessCalendar{
+			ChatID: 42,
+			Days:   map[string]bool{"2026-11-07": true},
+		}))
+
+		cal, err := repo.GetCalendar(ctx, 42)
+		require.NoError(t, err)
+		assert.Nil(t, cal.Workweek)
+		assert.Equal(t, map[string]bool{"2026-11-07": true}, cal.Days)
+	})
+
+	t.Run("migration moves calendar", func(t *testing.T) {
+		repo := setup(t)
+		const oldChatID, newChatID = int64(-100), int64(-1000100)
+
+		require.NoError(t, repo.SaveCalendar(ctx, &domain.BusinessCalendar{
+			ChatID: oldChatID,
+			Days:   map[string]bool{"2026-01-01": false},
+		}))
+		require.NoError(t, repo.Migrate(ctx, oldChatID, newChatID))
+
+		cal, err := repo.GetCalendar(ctx, newChatID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"2026-01-01": false}, cal.Days)
+
+		old, err := repo.GetCalendar(ctx, oldChatID)
+		require.NoError(t, err)
+		assert.Empty(t, old.Days)
+	})
+
+	t.Run("invalid chat", func(t *testing.T) {
+		repo := setup(t)
+
+		_, err := repo.GetCalendar(ctx, 0)
+		assert.ErrorIs(t, err, ErrDatabaseError)
+		assert.ErrorIs(t, repo.SaveCalendar(ctx, &domain.BusinessCalendar{}), ErrDatabaseError)
+	})
+}
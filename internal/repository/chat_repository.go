@@ -20,12 +20,21 @@ type ChatRepository interface {
 	GetByID(ctx context.Context, chatID int64) (*domain.Chat, error)
 	Upsert(ctx context.Context, chat *domain.Chat) error
 	UpdateTimezone(ctx context.Context, chatID int64, timezone string) error
+	UpdateCatchUp(ctx context.Context, chatID int64, catchUp domain.CatchUp) error
+	UpdateQuietHours(ctx context.Context, chatID int64, quiet domain.QuietHours) error
+	// UpdateLocation сохраняет координаты чата; nil их стирает.
+	UpdateLocation(ctx context.Context, chatID int64, geo *domain.GeoPoint) error
 	// ResolveID заменяет устаревший ID группы на актуальный ID супергруппы.
 	ResolveID(ctx context.Context, chatID int64) (int64, error)
 	// Migrate атомарно переносит все данные группы на новый Telegram ID.
 	Migrate(ctx context.Context, oldChatID, newChatID int64) error
 	// SetAvailable включает или замораживает чат без удаления его данных.
 	SetAvailable(ctx context.Context, chatID int64, available bool) error
+
+	// Производственный календарь чата. GetCalendar возвращает пустой календарь,
+	// если он не задавался; SaveCalendar заменяет календарь целиком.
+	GetCalendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error)
+	SaveCalendar(ctx context.Context, cal *domain.BusinessCalendar) error
 }
 
 type chatRepository struct {
@@ -44,7 +53,9 @@ func NewChatRepository(db *sql.DB) ChatRepository {
 func (r *chatRepository) GetByID(ctx context.Context, chatID int64) (*domain.Chat, error) {
 	slog.Debug("[Chat.GetByID] called", "chatID", chatID)
 
-	q := `SELECT chat_id, type, name, username, timezone, available, created_at, updated_at
+	q := `SELECT chat_id, type, name, username, timezone, available,
+        catch_up_policy, catch_up_after_minutes, quiet_start, quiet_end, quiet_mode,
+        latitude, longitude, created_at, updated_at
         FROM chats WHERE chat_id=?`
 	ch, err := scanChat(r.db.QueryRowContext(ctx, q, chatID))
 	if err != nil {
@@ -176,15 +187,25 @@ func (r *chatRepository) Migrate(ctx context.Context, oldChatID, newChatID int64
 	}
 
 	merged := mergeMigratedChat(oldChat, newChat, newChatID)
+	latitude, longitude := nullableGeo(merged.Geo)
 	if _, err := tx.ExecContext(ctx, `INSERT INTO chats
-        (chat_id, type, name, username, timezone, available, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+        (chat_id, type, name, username, timezone, available,
+            catch_up_policy, catch_up_after_minutes, quiet_start, quiet_end, quiet_mode,
+            latitude, longitude, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
         ON CONFLICT(chat_id) DO UPDATE SET
             type=excluded.type,
             name=excluded.name,
             username=excluded.username,
             timezone=excluded.timezone,
             available=excluded.available,
+            catch_up_policy=excluded.catch_up_policy,
+            catch_up_after_minutes=excluded.catch_up_after_minutes,
+            quiet_start=excluded.quiet_start,
+            quiet_end=excluded.quiet_end,
+            quiet_mode=excluded.quiet_mode,
+            latitude=excluded.latitude,
+            longitude=excluded.longitude,
             created_at=excluded.created_at,
             updated_at=excluded.updated_at`,
 		merged.ID,
@@ -193,6 +214,13 @@ func (r *chatRepository) Migrate(ctx context.Context, oldChatID, newChatID int64
 		merged.Username,
 		merged.Timezone,
 		merged.Available,
+		merged.CatchUp.Policy,
+		int(merged.CatchUp.After/time.Minute),
+		merged.Quiet.Start,
+		merged.Quiet.End,
+		merged.Quiet.Mode,
+		latitude,
+		longitude,
 		merged.CreatedAt,
 		merged.UpdatedAt,
 	); err != nil {
@@ -219,6 +247,30 @@ func (r *chatRepository) Migrate(ctx context.Context, oldChatID, newChatID int64
 	); err != nil {
 		return fmt.Errorf("%w: move reminders: %v", ErrDatabaseError, err)
 	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE reminder_acks SET chat_id=? WHERE chat_id=?`,
+		newChatID,
+		oldChatID,
+	); err != nil {
+		return fmt.Errorf("%w: move acknowledgements: %v", ErrDatabaseError, err)
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE reminder_deliveries SET chat_id=? WHERE chat_id=?`,
+		newChatID,
+		oldChatID,
+	); err != nil {
+		return fmt.Errorf("%w: move deliveries: %v", ErrDatabaseError, err)
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE delivery_attempts SET chat_id=? WHERE chat_id=?`,
+		newChatID,
+		oldChatID,
+	); err != nil {
+		return fmt.Errorf("%w: move delivery history: %v", ErrDatabaseError, err)
+	}
 
 	if _, err := tx.ExecContext(ctx, `INSERT INTO chat_members (chat_id, user_id, last_seen)
         SELECT ?, user_id, last_seen FROM chat_members WHERE chat_id=?
@@ -242,6 +294,9 @@ func (r *chatRepository) Migrate(ctx context.Context, oldChatID, newChatID int64
 	); err != nil {
 		return fmt.Errorf("%w: move webapp launch context: %v", ErrDatabaseError, err)
 	}
+	if err := migrateCalendarTx(ctx, tx, oldChatID, newChatID); err != nil {
+		return err
+	}
 	if _, err := tx.ExecContext(ctx, `DELETE FROM chats WHERE chat_id=?`, oldChatID); err != nil {
 		return fmt.Errorf("%w: delete old chat: %v", ErrDatabaseError, err)
 	}
@@ -279,7 +334,9 @@ func (r *chatRepository) SetAvailable(ctx context.Context, chatID int64, availab
 
 func getChatTx(ctx context.Context, tx *sql.Tx, chatID int64) (*domain.Chat, error) {
 	ch, err := scanChat(tx.QueryRowContext(ctx, `SELECT chat_id, type, name, username, timezone,
-        available, created_at, updated_at FROM chats WHERE chat_id=?`, chatID))
+        available, catch_up_policy, catch_up_after_minutes, quiet_start, quiet_end, quiet_mode,
+        latitude, longitude, created_at, updated_at
+        FROM chats WHERE chat_id=?`, chatID))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrChatNotFound
 	}
@@ -304,6 +361,9 @@ func mergeMigratedChat(oldChat, newChat *domain.Chat, newChatID int64) *domain.C
 		merged.Name = oldChat.Name
 		merged.Username = oldChat.Username
 		merged.Timezone = oldChat.Timezone
+		merged.CatchUp = oldChat.CatchUp
+		merged.Quiet = oldChat.Quiet
+		merged.Geo = oldChat.Geo
 		merged.Available = oldChat.Available
 		merged.CreatedAt = oldChat.CreatedAt
 	}
@@ -320,6 +380,15 @@ func mergeMigratedChat(oldChat, newChat *domain.Chat, newChatID int64) *domain.C
 		if newChat.Timezone != "" {
 			merged.Timezone = newChat.Timezone
 		}
+		if newChat.CatchUp != (domain.CatchUp{}) {
+			merged.CatchUp = newChat.CatchUp
+		}
+		if newChat.Quiet.Enabled() {
+			merged.Quiet = newChat.Quiet
+		}
+		if newChat.Geo != nil {
+			merged.Geo = newChat.Geo
+		}
 		// Уже зафиксированное состояние нового ID авторитетнее состояния старой группы.
 		merged.Available = newChat.Available
 		if !newChat.CreatedAt.IsZero() && (merged.CreatedAt.IsZero() || newChat.CreatedAt.Before(merged.CreatedAt)) {
@@ -334,6 +403,55 @@ func mergeMigratedChat(oldChat, newChat *domain.Chat, newChatID int64) *domain.C
 	return merged
 }
 
+func (r *chatRepository) UpdateCatchUp(ctx context.Context, chatID int64, catchUp domain.CatchUp) error {
+	q := `UPDATE chats SET catch_up_policy=?, catch_up_after_minutes=?, updated_at=? WHERE chat_id=?`
+	res, err := r.db.ExecContext(ctx, q, catchUp.Policy, int(catchUp.After/time.Minute), time.Now(), chatID)
+	if err != nil {
+		return fmt.Errorf("%w: update catch-up policy: %v", ErrDatabaseError, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return ErrChatNotFound
+	}
+
+	return nil
+}
+
+func (r *chatRepository) UpdateQuietHours(ctx context.Context, chatID int64, quiet domain.QuietHours) error {
+	q := `UPDATE chats SET quiet_start=?, quiet_end=?, quiet_mode=?, updated_at=? WHERE chat_id=?`
+	res, err := r.db.ExecContext(ctx, q, quiet.Start, quiet.End, quiet.Mode, time.Now(), chatID)
+	if err != nil {
+		return fmt.Errorf("%w: update quiet hours: %v", ErrDatabaseError, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return ErrChatNotFound
+	}
+
+	return nil
+}
+
+func (r *chatRepository) UpdateLocation(ctx context.Context, chatID int64, geo *domain.GeoPoint) error {
+	latitude, longitude := nullableGeo(geo)
+	q := `UPDATE chats SET latitude=?, longitude=?, updated_at=? WHERE chat_id=?`
+	res, err := r.db.ExecContext(ctx, q, latitude, longitude, time.Now(), chatID)
+	if err != nil {
+		return fmt.Errorf("%w: update location: %v", ErrDatabaseError, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return ErrChatNotFound
+	}
+
+	return nil
+}
+
+// nullableGeo раскладывает координаты на две колонки; nil — NULL в обеих.
+func nullableGeo(geo *domain.GeoPoint) (latitude, longitude sql.NullFloat64) {
+	if geo == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+
+	return sql.NullFloat64{Float64: geo.Latitude, Valid: true}, sql.NullFloat64{Float64: geo.Longitude, Valid: true}
+}
+
 func (r *chatRepository) UpdateTimezone(ctx context.Context, chatID int64, timezone string) error {
 	slog.Debug("[Chat.UpdateTimezone] called", "chatID", chatID, "timezone", timezone)
 
@@ -49,6 +49,13 @@ func TestChatRepository_MigratePreservesAndFreezesData(t *testing.T) {
 		CreatedAt: now,
 	}))
 
+	catchUp := domain.CatchUp{Policy: domain.CatchUpSummary, After: 30 * time.Minute}
+	require.NoError(t, chatRepo.UpdateCatchUp(ctx, oldChatID, catchUp))
+	quiet := domain.QuietHours{Start: 23 * 60, End: 8 * 60, Mode: domain.QuietSilent}
+	require.NoError(t, chatRepo.UpdateQuietHours(ctx, oldChatID, quiet))
+	geo := &domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	require.NoError(t, chatRepo.UpdateLocation(ctx, oldChatID, geo))
+
 	reminder := &domain.Reminder{
 		ChatID:   oldChatID,
 		Text:     "не потерять",
@@ -72,6 +79,9 @@ func TestChatRepository_MigratePreservesAndFreezesData(t *testing.T) {
 	assert.Equal(t, "Новая супергруппа", chat.Name)
 	assert.Equal(t, "Europe/Moscow", chat.Timezone, "empty target timezone must inherit the old value")
 	assert.True(t, chat.Available)
+	assert.Equal(t, catchUp, chat.CatchUp, "catch-up policy must follow the chat")
+	assert.Equal(t, quiet, chat.Quiet, "quiet hours must follow the chat")
+	assert.Equal(t, geo, chat.Geo, "location must follow the chat")
 
 	resolvedID, err := chatRepo.ResolveID(ctx, oldChatID)
 	require.NoError(t, err)
@@ -113,3 +123,43 @@ func TestChatRepository_MigratePreservesAndFreezesData(t *testing.T) {
 	require.Len(t, due, 1)
 	assert.Equal(t, reminder.ID, due[0].ID)
 }
+
+func TestChatRepository_UpdateChatSettings(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_loc=UTC")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	require.NoError(t, Migrate(db))
+
+	ctx := context.Background()
+	repo := NewChatRepository(db)
+
+	assert.ErrorIs(t, repo.UpdateCatchUp(ctx, 7, domain.CatchUp{Policy: domain.CatchUpSkip}), ErrChatNotFound)
+
+	require.NoError(t, repo.Upsert(ctx, &domain.Chat{ID: 7, Type: "private", Available: true}))
+	chat, err := repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CatchUp{}, chat.CatchUp)
+
+	want := domain.CatchUp{Policy: domain.CatchUpSkip, After: 2 * time.Hour}
+	require.NoError(t, repo.UpdateCatchUp(ctx, 7, want))
+	quiet := domain.QuietHours{Start: 22 * 60, End: 7*60 + 30}
+	require.NoError(t, repo.UpdateQuietHours(ctx, 7, quiet))
+	geo := &domain.GeoPoint{Latitude: -33.8688, Longitude: 151.2093}
+	require.NoError(t, repo.UpdateLocation(ctx, 7, geo))
+
+	// Обновление профиля чата не должно сбрасывать настройки.
+	chat.Name = "Дарья"
+	require.NoError(t, repo.Upsert(ctx, chat))
+
+	chat, err = repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, want, chat.CatchUp)
+	assert.Equal(t, quiet, chat.Quiet)
+	assert.Equal(t, geo, chat.Geo)
+
+	require.NoError(t, repo.UpdateLocation(ctx, 7, nil))
+	chat, err = repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Nil(t, chat.Geo)
+}
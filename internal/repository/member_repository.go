@@ -25,14 +25,16 @@ const (
 	// Чаты пользователя вместе с данными самого чата: личный чат Mini App подставляет сам,
 	// поэтому здесь интересны прежде всего группы.
 	listChatsByUserQuery = `SELECT c.chat_id, c.type, c.name, c.username, c.timezone,
-            c.available, c.created_at, c.updated_at
+            c.available, c.catch_up_policy, c.catch_up_after_minutes,
+            c.quiet_start, c.quiet_end, c.quiet_mode, c.latitude, c.longitude, c.created_at, c.updated_at
         FROM chat_members m
         JOIN chats c ON c.chat_id = m.chat_id
         WHERE m.user_id = ? AND c.available = 1
         ORDER BY c.name, c.chat_id`
 
 	recentWebAppLaunchQuery = `SELECT c.chat_id, c.type, c.name, c.username, c.timezone,
-            c.available, c.created_at, c.updated_at
+            c.available, c.catch_up_policy, c.catch_up_after_minutes,
+            c.quiet_start, c.quiet_end, c.quiet_mode, c.latitude, c.longitude, c.created_at, c.updated_at
         FROM webapp_launch_contexts l
         JOIN chats c ON c.chat_id = l.chat_id
         WHERE l.user_id = ? AND l.launched_at >= ? AND c.available = 1
@@ -113,6 +113,242 @@ var migrations = []migration{
             )`,
 		},
 	},
+	{
+		Version: 7,
+		Name:    "rrule repeat",
+		Stmts: []string{
+			`ALTER TABLE reminders ADD COLUMN rrule TEXT NOT NULL DEFAULT ''`,
+			// DTSTART правила: COUNT и INTERVAL отсчитываются от него, а не от next_time.
+			`ALTER TABLE reminders ADD COLUMN start_time DATETIME`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "monthly weekday mode",
+		Stmts: []string{
+			// Ненулевое значение превращает repeat_days ежемесячного повтора из числа
+			// месяца в набор дней недели: «второй вторник», «последний рабочий день».
+			`ALTER TABLE reminders ADD COLUMN month_ordinal INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 9,
+		Name:    "several times per day",
+		Stmts: []string{
+			// Минуты от полуночи через запятую, как repeat_days; пусто — единственное время из next_time.
+			`ALTER TABLE reminders ADD COLUMN times TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		Version: 10,
+		Name:    "interval repeat",
+		Stmts: []string{
+			`ALTER TABLE reminders ADD COLUMN interval_minutes INTEGER NOT NULL DEFAULT 0`,
+			// Окно активности в минутах от полуночи; два нуля — весь день.
+			`ALTER TABLE reminders ADD COLUMN window_start INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN window_end INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "end conditions",
+		Stmts: []string{
+			// NULL — серия без даты окончания.
+			`ALTER TABLE reminders ADD COLUMN ends_at DATETIME`,
+			// Сколько срабатываний осталось, включая next_time; 0 — без ограничения.
+			`ALTER TABLE reminders ADD COLUMN remaining_count INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 12,
+		Name:    "occurrence exceptions",
+		Stmts: []string{
+			// Пропуски и переносы отдельных срабатываний серии. occurrence — время по
+			// расписанию, moved_to — новое время или NULL, если срабатывание пропускается.
+			// Уникальный ключ заодно служит индексом выборки исключений напоминания.
+			`CREATE TABLE IF NOT EXISTS reminder_exceptions (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                reminder_id INTEGER NOT NULL,
+                occurrence DATETIME NOT NULL,
+                moved_to DATETIME,
+                created_at DATETIME NOT NULL,
+                UNIQUE (reminder_id, occurrence)
+            )`,
+		},
+	},
+	{
+		Version: 13,
+		Name:    "business calendar",
+		Stmts: []string{
+			`ALTER TABLE reminders ADD COLUMN workday_policy INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN shifted_from DATETIME`,
+			// Производственный календарь чата: рабочая неделя отдельной строкой, особые
+			// дни — по строке на дату. Чат без строки работает по пятидневке.
+			`CREATE TABLE IF NOT EXISTS chat_calendars (
+                chat_id INTEGER PRIMARY KEY,
+                workweek TEXT NOT NULL DEFAULT '',
+                updated_at DATETIME NOT NULL
+            )`,
+			`CREATE TABLE IF NOT EXISTS chat_calendar_days (
+                chat_id INTEGER NOT NULL,
+                day TEXT NOT NULL,
+                working INTEGER NOT NULL,
+                PRIMARY KEY (chat_id, day)
+            )`,
+		},
+	},
+	{
+		Version: 14,
+		Name:    "chat catch-up policy",
+		Stmts: []string{
+			`ALTER TABLE chats ADD COLUMN catch_up_policy INTEGER NOT NULL DEFAULT 0`,
+			// Ноль — порог по умолчанию, domain.DefaultCatchUpAfter.
+			`ALTER TABLE chats ADD COLUMN catch_up_after_minutes INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 15,
+		Name:    "chat quiet hours",
+		Stmts: []string{
+			// Границы — минуты от полуночи в поясе чата; равные границы — тихих часов нет.
+			`ALTER TABLE chats ADD COLUMN quiet_start INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE chats ADD COLUMN quiet_end INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE chats ADD COLUMN quiet_mode INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 16,
+		Name:    "acknowledgements",
+		Stmts: []string{
+			// Ноль — подтверждение не требуется.
+			`ALTER TABLE reminders ADD COLUMN nag_every_minutes INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN nag_max INTEGER NOT NULL DEFAULT 0`,
+			// Доставки, ждущие подтверждения. Связи с reminders нет намеренно: разовое
+			// напоминание удаляется до отправки, а ждать подтверждения его доставка продолжает.
+			// next_nag_at NULL — повторов больше не будет.
+			`CREATE TABLE IF NOT EXISTS reminder_acks (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                reminder_id INTEGER NOT NULL,
+                chat_id INTEGER NOT NULL,
+                text TEXT NOT NULL,
+                message_id INTEGER NOT NULL,
+                nags INTEGER NOT NULL DEFAULT 0,
+                nag_max INTEGER NOT NULL,
+                nag_every_minutes INTEGER NOT NULL,
+                next_nag_at DATETIME,
+                acked_by INTEGER NOT NULL DEFAULT 0,
+                acked_by_name TEXT NOT NULL DEFAULT '',
+                acked_at DATETIME,
+                created_at DATETIME NOT NULL
+            )`,
+			`CREATE INDEX IF NOT EXISTS idx_reminder_acks_due ON reminder_acks(next_nag_at)
+                WHERE next_nag_at IS NOT NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_reminder_acks_reminder ON reminder_acks(reminder_id)`,
+		},
+	},
+	{
+		Version: 17,
+		Name:    "advance notices",
+		Stmts: []string{
+			// Отступы предупреждений в минутах через запятую, как times.
+			`ALTER TABLE reminders ADD COLUMN lead_minutes TEXT NOT NULL DEFAULT ''`,
+			// NULL — до срабатывания предупреждений больше нет.
+			`ALTER TABLE reminders ADD COLUMN notice_at DATETIME`,
+			`CREATE INDEX IF NOT EXISTS idx_reminders_notice_at ON reminders(notice_at)
+                WHERE notice_at IS NOT NULL`,
+		},
+	},
+	{
+		Version: 18,
+		Name:    "delivery outbox",
+		Stmts: []string{
+			// Очередь отправки. Как и у reminder_acks, связи с reminders нет: разовое
+			// напоминание удаляется в той же транзакции, в которой ставится в очередь.
+			// next_attempt_at NULL — попыток больше не будет: доставка отправлена или брошена.
+			`CREATE TABLE IF NOT EXISTS reminder_deliveries (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                reminder_id INTEGER NOT NULL,
+                chat_id INTEGER NOT NULL,
+                kind TEXT NOT NULL,
+                occurrence DATETIME NOT NULL,
+                text TEXT NOT NULL,
+                silent BOOLEAN NOT NULL DEFAULT 0,
+                ack_id INTEGER NOT NULL DEFAULT 0,
+                attempts INTEGER NOT NULL DEFAULT 0,
+                next_attempt_at DATETIME,
+                deadline DATETIME NOT NULL,
+                last_error TEXT NOT NULL DEFAULT '',
+                sent_at DATETIME,
+                created_at DATETIME NOT NULL
+            )`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_reminder_deliveries_occurrence
+                ON reminder_deliveries(reminder_id, kind, occurrence)`,
+			`CREATE INDEX IF NOT EXISTS idx_reminder_deliveries_pending ON reminder_deliveries(next_attempt_at)
+                WHERE next_attempt_at IS NOT NULL`,
+		},
+	},
+	{
+		Version: 19,
+		Name:    "delivery history",
+		Stmts: []string{
+			// История попыток доставки. Записи переживают напоминание и очередь
+			// и удаляются только по сроку хранения.
+			`CREATE TABLE IF NOT EXISTS delivery_attempts (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                delivery_id INTEGER NOT NULL DEFAULT 0,
+                reminder_id INTEGER NOT NULL,
+                chat_id INTEGER NOT NULL,
+                kind TEXT NOT NULL,
+                text TEXT NOT NULL,
+                scheduled_at DATETIME NOT NULL,
+                attempted_at DATETIME NOT NULL,
+                outcome TEXT NOT NULL,
+                message_id INTEGER NOT NULL DEFAULT 0,
+                error_class TEXT NOT NULL DEFAULT '',
+                error TEXT NOT NULL DEFAULT ''
+            )`,
+			`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_chat ON delivery_attempts(chat_id, attempted_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_attempted_at ON delivery_attempts(attempted_at)`,
+		},
+	},
+	{
+		Version: 20,
+		Name:    "cron repeat",
+		Stmts: []string{
+			`ALTER TABLE reminders ADD COLUMN cron TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		Version: 21,
+		Name:    "reminder timezone",
+		Stmts: []string{
+			`ALTER TABLE reminders ADD COLUMN timezone TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		Version: 22,
+		Name:    "solar repeat",
+		Stmts: []string{
+			// Координаты чата из присланной геопозиции; NULL — не заданы.
+			`ALTER TABLE chats ADD COLUMN latitude REAL`,
+			`ALTER TABLE chats ADD COLUMN longitude REAL`,
+			`ALTER TABLE reminders ADD COLUMN solar_event INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN solar_offset INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 23,
+		Name:    "reminder chains",
+		Stmts: []string{
+			// Родитель продолжения; 0 — напоминание ни от кого не зависит.
+			`ALTER TABLE reminders ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN follow_offset INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN follow_on INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE reminders ADD COLUMN waiting BOOLEAN NOT NULL DEFAULT 0`,
+			// Каждая доставка ищет продолжения своего напоминания.
+			`CREATE INDEX IF NOT EXISTS idx_reminders_parent ON reminders(parent_id) WHERE parent_id != 0`,
+		},
+	},
 }
 
 // Migrate приводит схему БД к последней версии, применяя недостающие миграции по порядку.
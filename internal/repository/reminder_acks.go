@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// ErrAckNotFound возвращается, если доставки, ждущей подтверждения, нет в этом чате.
+var ErrAckNotFound = errors.New("acknowledgement not found")
+
+// ackRetention — сколько хранятся завершённые доставки: кнопка «Готово» под старым
+// сообщением ещё может ответить, кто его подтвердил, но вечно их держать незачем.
+const ackRetention = 7 * 24 * time.Hour
+
+const (
+	ackColumns = `id, reminder_id, chat_id, text, message_id, nags, nag_max, nag_every_minutes,
+        next_nag_at, acked_by, acked_by_name, acked_at, created_at`
+
+	createAckQuery = `INSERT INTO reminder_acks (reminder_id, chat_id, text, message_id, nags, nag_max,
+        nag_every_minutes, next_nag_at, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	// Новая доставка серии отменяет повторы прежней: о том же деле чату уже напомнили заново.
+	supersedeAcksQuery = `UPDATE reminder_acks SET next_nag_at = NULL
+        WHERE reminder_id = ? AND acked_at IS NULL`
+
+	deleteStaleAcksQuery = `DELETE FROM reminder_acks WHERE next_nag_at IS NULL AND created_at < ?`
+
+	updateAckQuery = `UPDATE reminder_acks SET message_id = ?, nags = ?, next_nag_at = ?
+        WHERE id = ? AND acked_at IS NULL`
+
+	// Пока исходное сообщение стоит в очереди доставок, message_id нулевой и повторять нечего.
+	listDueAcksQuery = `SELECT ` + ackColumns + `
+        FROM reminder_acks a
+        WHERE next_nag_at <= ? AND acked_at IS NULL AND message_id != 0
+            AND NOT EXISTS (
+                SELECT 1 FROM chats c
+                WHERE c.chat_id = a.chat_id AND c.available = 0
+            )
+        ORDER BY next_nag_at, id`
+
+	acknowledgeQuery = `UPDATE reminder_acks SET acked_by = ?, acked_by_name = ?, acked_at = ?, next_nag_at = NULL
+        WHERE id = ? AND chat_id = ? AND acked_at IS NULL`
+
+	getAckQuery = `SELECT ` + ackColumns + ` FROM reminder_acks WHERE id = ? AND chat_id = ?`
+)
+
+func (r *reminderRepository) CreateAck(ctx context.Context, a *domain.Acknowledgement) error {
+	if a == nil || a.ChatID == 0 || a.Text == "" {
+		return fmt.Errorf("%w: acknowledgement needs a chat and a text", ErrInvalidReminder)
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+
+	if _, err := r.db.ExecContext(ctx, supersedeAcksQuery, a.ReminderID); err != nil {
+		return fmt.Errorf("%w: failed to supersede acknowledgements: %v", ErrDatabaseError, err)
+	}
+	// Заодно убираются завершённые доставки старше ackRetention.
+	if _, err := r.db.ExecContext(ctx, deleteStaleAcksQuery, a.CreatedAt.Add(-ackRetention).UTC()); err != nil {
+		return fmt.Errorf("%w: failed to delete stale acknowledgements: %v", ErrDatabaseError, err)
+	}
+
+	result, err := r.db.ExecContext(ctx, createAckQuery,
+		a.ReminderID,
+		a.ChatID,
+		a.Text,
+		a.MessageID,
+		a.Nags,
+		a.NagMax,
+		a.NagEveryMinutes,
+		nullableTime(a.NextNagAt),
+		a.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create acknowledgement: %v", ErrDatabaseError, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert ID: %v", ErrDatabaseError, err)
+	}
+	a.ID = id
+
+	return nil
+}
+
+func (r *reminderRepository) UpdateAck(ctx context.Context, a *domain.Acknowledgement) error {
+	if a == nil || a.ID <= 0 {
+		return fmt.Errorf("%w: invalid acknowledgement ID", ErrInvalidReminder)
+	}
+
+	// Подтверждение могло прийти, пока отправлялся повтор, — тогда записывать нечего.
+	_, err := r.db.ExecContext(ctx, updateAckQuery, a.MessageID, a.Nags, nullableTime(a.NextNagAt), a.ID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update acknowledgement: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) ListDueAcks(ctx context.Context, now time.Time) ([]*domain.Acknowledgement, error) {
+	rows, err := r.db.QueryContext(ctx, listDueAcksQuery, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query due acknowledgements: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	var acks []*domain.Acknowledgement
+	for rows.Next() {
+		a, err := scanAck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to scan acknowledgement: %v", ErrDatabaseError, err)
+		}
+		acks = append(acks, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read acknowledgements: %v", ErrDatabaseError, err)
+	}
+
+	return acks, nil
+}
+
+func (r *reminderRepository) Acknowledge(
+	ctx context.Context,
+	id, chatID, userID int64,
+	userName string,
+	at time.Time,
+) (*domain.Acknowledgement, error) {
+	// Условие acked_at IS NULL делает подтверждение атомарным: из двух одновременных
+	// нажатий в группе засчитывается одно.
+	result, err := r.db.ExecContext(ctx, acknowledgeQuery, userID, userName, at.UTC(), id, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to acknowledge: %v", ErrDatabaseError, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %v", ErrDatabaseError, err)
+	}
+
+	a, err := r.GetAck(ctx, id, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return a, domain.ErrAlreadyAcknowledged
+	}
+
+	return a, nil
+}
+
+func (r *reminderRepository) GetAck(ctx context.Context, id, chatID int64) (*domain.Acknowledgement, error) {
+	a, err := scanAck(r.db.QueryRowContext(ctx, getAckQuery, id, chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: acknowledgement %d in chat %d", ErrAckNotFound, id, chatID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get acknowledgement: %v", ErrDatabaseError, err)
+	}
+
+	return a, nil
+}
+
+func scanAck(scanner rowScanner) (*domain.Acknowledgement, error) {
+	var a domain.Acknowledgement
+	var nextNagAt, ackedAt sql.NullTime
+	if err := scanner.Scan(
+		&a.ID,
+		&a.ReminderID,
+		&a.ChatID,
+		&a.Text,
+		&a.MessageID,
+		&a.Nags,
+		&a.NagMax,
+		&a.NagEveryMinutes,
+		&nextNagAt,
+		&a.AckedBy,
+		&a.AckedByName,
+		&ackedAt,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	a.NextNagAt = nextNagAt.Time.UTC()
+	a.AckedAt = ackedAt.Time.UTC()
+	a.CreatedAt = a.CreatedAt.UTC()
+
+	return &a, nil
+}
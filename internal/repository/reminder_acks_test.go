@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_Acks(t *testing.T) {
+	ctx := context.Background()
+	sent := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (ReminderRepository, *domain.Reminder) {
+		t.Helper()
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+
+		repo := NewReminderRepository(db)
+		rem := createTestReminder()
+		rem.NagEveryMinutes, rem.NagMax = 15, 2
+		require.NoError(t, repo.Create(ctx, rem))
+
+		return repo, rem
+	}
+
+	t.Run("nag fields round-trip", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		got, err := repo.GetByID(ctx, rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 15, got.NagEveryMinutes)
+		assert.Equal(t, 2, got.NagMax)
+	})
+
+	t.Run("due nags until acknowledged", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		ack := domain.NewAcknowledgement(rem, 0, sent)
+		require.NoError(t, repo.CreateAck(ctx, ack))
+		require.NotZero(t, ack.ID)
+		ack.MessageID = 77
+		require.NoError(t, repo.UpdateAck(ctx, ack))
+
+		due, err := repo.ListDueAcks(ctx, sent.Add(10*time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		due, err = repo.ListDueAcks(ctx, sent.Add(15*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, 77, due[0].MessageID)
+		assert.Equal(t, rem.Text, due[0].Text)
+
+		at := sent.Add(20 * time.Minute)
+		got, err := repo.Acknowledge(ctx, ack.ID, rem.ChatID, 7, "Анна", at)
+		require.NoError(t, err)
+		assert.True(t, got.Acknowledged())
+		assert.Equal(t, "Анна", got.AckedByName)
+		assert.Equal(t, at, got.AckedAt)
+
+		got, err = repo.Acknowledge(ctx, ack.ID, rem.ChatID, 8, "Борис", at.Add(time.Minute))
+		require.ErrorIs(t, err, domain.ErrAlreadyAcknowledged)
+		assert.Equal(t, int64(7), got.AckedBy, "the first confirmation wins")
+
+		due, err = repo.ListDueAcks(ctx, sent.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+
+	t.Run("foreign chat cannot acknowledge", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		ack := domain.NewAcknowledgement(rem, 1, sent)
+		require.NoError(t, repo.CreateAck(ctx, ack))
+
+		_, err := repo.Acknowledge(ctx, ack.ID, rem.ChatID+1, 7, "Анна", sent)
+		require.ErrorIs(t, err, ErrAckNotFound)
+	})
+
+	t.Run("new delivery supersedes previous nags", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		first := domain.NewAcknowledgement(rem, 1, sent)
+		require.NoError(t, repo.CreateAck(ctx, first))
+		second := domain.NewAcknowledgement(rem, 2, sent.Add(24*time.Hour))
+		require.NoError(t, repo.CreateAck(ctx, second))
+
+		due, err := repo.ListDueAcks(ctx, sent.Add(48*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, second.ID, due[0].ID)
+	})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_FollowUps(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+	repo := NewReminderRepository(db)
+	now := time.Now()
+
+	parent := createTestReminder()
+	parent.Repeat = domain.RepeatEveryDay
+	require.NoError(t, repo.Create(ctx, parent))
+
+	waiting := createTestReminder()
+	waiting.NextTime = now.Add(-time.Hour)
+	waiting.ParentID = parent.ID
+	waiting.FollowOffset = 3 * domain.MinutesPerDay
+	waiting.FollowOn = domain.FollowOnAck
+	waiting.Waiting = true
+	require.NoError(t, repo.Create(ctx, waiting))
+
+	armed := createTestReminder()
+	armed.NextTime = now.Add(-time.Minute)
+	armed.ParentID = parent.ID
+	require.NoError(t, repo.Create(ctx, armed))
+
+	stored, err := repo.GetByID(ctx, waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, parent.ID, stored.ParentID)
+	assert.Equal(t, 3*domain.MinutesPerDay, stored.FollowOffset)
+	assert.Equal(t, domain.FollowOnAck, stored.FollowOn)
+	assert.True(t, stored.Waiting)
+
+	followUps, err := repo.ListFollowUps(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Len(t, followUps, 2)
+	assert.Equal(t, waiting.ID, followUps[0].ID)
+	assert.Equal(t, armed.ID, followUps[1].ID)
+
+	due, err := repo.ListDue(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, due, 1, "ждущее продолжение не срабатывает, хоть его время и прошло")
+	assert.Equal(t, armed.ID, due[0].ID)
+
+	wakeups, err := repo.ListWakeups(ctx, 10)
+	require.NoError(t, err)
+	for _, w := range wakeups {
+		assert.NotEqual(t, waiting.ID, w.ID)
+	}
+
+	none, err := repo.ListFollowUps(ctx, armed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// deliveryRetention — сколько хранятся отправленные и брошенные доставки. Пока запись
+// жива, то же срабатывание не встанет в очередь повторно.
+const deliveryRetention = 7 * 24 * time.Hour
+
+const (
+	deliveryColumns = `id, reminder_id, chat_id, kind, occurrence, text, silent, ack_id, attempts,
+        next_attempt_at, deadline, last_error, sent_at, created_at`
+
+	// Срабатывание, уже стоящее в очереди, второй раз не ставится — это и есть дедупликация.
+	enqueueDeliveryQuery = `INSERT INTO reminder_deliveries (reminder_id, chat_id, kind, occurrence, text,
+        silent, ack_id, next_attempt_at, deadline, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT(reminder_id, kind, occurrence) DO NOTHING`
+
+	deleteStaleDeliveriesQuery = `DELETE FROM reminder_deliveries
+        WHERE next_attempt_at IS NULL AND created_at < ?`
+
+	updateDeliveryQuery = `UPDATE reminder_deliveries SET ack_id = ?, attempts = ?, next_attempt_at = ?,
+        last_error = ?, sent_at = ?
+        WHERE id = ?`
+
+	listPendingDeliveriesQuery = `SELECT ` + deliveryColumns + `
+        FROM reminder_deliveries d
+        WHERE next_attempt_at <= ?
+            AND NOT EXISTS (
+                SELECT 1 FROM chats c
+                WHERE c.chat_id = d.chat_id AND c.available = 0
+            )
+        ORDER BY next_attempt_at, id`
+)
+
+func (r *reminderRepository) Advance(ctx context.Context, rem *domain.Reminder, d *domain.Delivery) error {
+	return r.inTx(ctx, func(tx DBExecutor) error {
+		if err := updateReminder(ctx, tx, rem); err != nil {
+			return err
+		}
+
+		return enqueueDelivery(ctx, tx, d)
+	})
+}
+
+func (r *reminderRepository) Finish(ctx context.Context, id int64, d *domain.Delivery) error {
+	err := r.inTx(ctx, func(tx DBExecutor) error {
+		if err := deleteReminder(ctx, tx, id); err != nil {
+			return err
+		}
+
+		return enqueueDelivery(ctx, tx, d)
+	})
+	if err != nil {
+		return err
+	}
+	r.deleteExceptions(ctx, id)
+
+	return nil
+}
+
+func (r *reminderRepository) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	if d == nil || d.ID <= 0 {
+		return fmt.Errorf("%w: invalid delivery ID", ErrInvalidReminder)
+	}
+
+	_, err := r.db.ExecContext(ctx, updateDeliveryQuery,
+		d.AckID,
+		d.Attempts,
+		nullableTime(d.NextAttemptAt),
+		d.LastError,
+		nullableTime(d.SentAt),
+		d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update delivery: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) ListPendingDeliveries(ctx context.Context, now time.Time) ([]*domain.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, listPendingDeliveriesQuery, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query pending deliveries: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	var deliveries []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to scan delivery: %v", ErrDatabaseError, err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read deliveries: %v", ErrDatabaseError, err)
+	}
+
+	return deliveries, nil
+}
+
+// inTx выполняет fn в транзакции. Соединение, которое транзакций не умеет, — ошибка
+// конфигурации: писать по отдельности то, что должно записаться вместе, нельзя.
+func (r *reminderRepository) inTx(ctx context.Context, fn func(tx DBExecutor) error) error {
+	db, ok := r.db.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return fmt.Errorf("%w: connection does not support transactions", ErrDatabaseError)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: begin transaction: %v", ErrDatabaseError, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: commit transaction: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// enqueueDelivery ставит d в очередь. Если это срабатывание уже в очереди, d.ID остаётся
+// нулевым.
+func enqueueDelivery(ctx context.Context, db DBExecutor, d *domain.Delivery) error {
+	if d == nil {
+		return nil
+	}
+	if d.ChatID == 0 || d.Text == "" || d.Occurrence.IsZero() {
+		return fmt.Errorf("%w: delivery needs a chat, a text and an occurrence", ErrInvalidReminder)
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+
+	// Заодно убираются завершённые доставки старше deliveryRetention.
+	cutoff := d.CreatedAt.Add(-deliveryRetention).UTC()
+	if _, err := db.ExecContext(ctx, deleteStaleDeliveriesQuery, cutoff); err != nil {
+		return fmt.Errorf("%w: failed to delete stale deliveries: %v", ErrDatabaseError, err)
+	}
+
+	result, err := db.ExecContext(ctx, enqueueDeliveryQuery,
+		d.ReminderID,
+		d.ChatID,
+		d.Kind,
+		d.Occurrence.UTC(),
+		d.Text,
+		d.Silent,
+		d.AckID,
+		nullableTime(d.NextAttemptAt),
+		d.Deadline.UTC(),
+		d.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: failed to enqueue delivery: %v", ErrDatabaseError, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %v", ErrDatabaseError, err)
+	}
+	if affected == 0 {
+		return nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert ID: %v", ErrDatabaseError, err)
+	}
+	d.ID = id
+
+	return nil
+}
+
+func scanDelivery(scanner rowScanner) (*domain.Delivery, error) {
+	var d domain.Delivery
+	var nextAttemptAt, sentAt sql.NullTime
+	if err := scanner.Scan(
+		&d.ID,
+		&d.ReminderID,
+		&d.ChatID,
+		&d.Kind,
+		&d.Occurrence,
+		&d.Text,
+		&d.Silent,
+		&d.AckID,
+		&d.Attempts,
+		&nextAttemptAt,
+		&d.Deadline,
+		&d.LastError,
+		&sentAt,
+		&d.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Occurrence = d.Occurrence.UTC()
+	d.NextAttemptAt = nextAttemptAt.Time.UTC()
+	d.Deadline = d.Deadline.UTC()
+	d.SentAt = sentAt.Time.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+
+	return &d, nil
+}
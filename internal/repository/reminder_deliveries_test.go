@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.June, 2, 8, 0, 30, 0, time.UTC)
+
+	setup := func(t *testing.T) (ReminderRepository, *domain.Reminder) {
+		t.Helper()
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+
+		repo := NewReminderRepository(db)
+		rem := createTestReminder()
+		rem.NextTime = now.Add(-30 * time.Second)
+		require.NoError(t, repo.Create(ctx, rem))
+
+		return repo, rem
+	}
+	delivery := func(rem *domain.Reminder) *domain.Delivery {
+		return &domain.Delivery{
+			ReminderID: rem.ID, ChatID: rem.ChatID, Kind: domain.DeliveryReminder,
+			Occurrence: rem.NextTime, Text: "⏰ Напоминание: " + rem.Text, Silent: true,
+			NextAttemptAt: now, Deadline: now.Add(time.Hour), CreatedAt: now,
+		}
+	}
+
+	t.Run("advance enqueues once per occurrence", func(t *testing.T) {
+		repo, rem := setup(t)
+		d := delivery(rem)
+
+		rem.Repeat = domain.RepeatEveryDay
+		rem.NextTime = rem.NextTime.Add(24 * time.Hour)
+		require.NoError(t, repo.Advance(ctx, rem, d))
+		require.NotZero(t, d.ID)
+
+		duplicate := delivery(rem)
+		duplicate.Occurrence = d.Occurrence
+		require.NoError(t, repo.Advance(ctx, rem, duplicate))
+		assert.Zero(t, duplicate.ID, "the same occurrence is queued once")
+
+		pending, err := repo.ListPendingDeliveries(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, d.ID, pending[0].ID)
+		assert.Equal(t, d.Text, pending[0].Text)
+		assert.True(t, pending[0].Silent)
+		assert.True(t, d.Occurrence.Equal(pending[0].Occurrence))
+		assert.True(t, d.Deadline.Equal(pending[0].Deadline))
+
+		stored, err := repo.GetByID(ctx, rem.ID)
+		require.NoError(t, err)
+		assert.True(t, rem.NextTime.Equal(stored.NextTime))
+	})
+
+	t.Run("retry and send", func(t *testing.T) {
+		repo, rem := setup(t)
+		d := delivery(rem)
+		require.NoError(t, repo.Finish(ctx, rem.ID, d))
+
+		require.True(t, d.Retry(now, 0, "bad gateway"))
+		d.AckID = 5
+		require.NoError(t, repo.UpdateDelivery(ctx, d))
+
+		pending, err := repo.ListPendingDeliveries(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, pending, "the retry waits for its backoff")
+
+		pending, err = repo.ListPendingDeliveries(ctx, d.NextAttemptAt)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, 1, pending[0].Attempts)
+		assert.Equal(t, int64(5), pending[0].AckID)
+		assert.Equal(t, "bad gateway", pending[0].LastError)
+
+		d.Sent(d.NextAttemptAt)
+		require.NoError(t, repo.UpdateDelivery(ctx, d))
+		pending, err = repo.ListPendingDeliveries(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("failed finish enqueues nothing", func(t *testing.T) {
+		repo, rem := setup(t)
+		require.NoError(t, repo.Delete(ctx, rem.ID))
+
+		err := repo.Finish(ctx, rem.ID, delivery(rem))
+		require.ErrorIs(t, err, ErrReminderNotFound)
+
+		pending, err := repo.ListPendingDeliveries(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("unavailable chat waits", func(t *testing.T) {
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		repo := NewReminderRepository(db)
+		rem := createTestReminder()
+		require.NoError(t, repo.Create(ctx, rem))
+		require.NoError(t, repo.Finish(ctx, rem.ID, delivery(rem)))
+
+		chatRepo := NewChatRepository(db)
+		require.NoError(t, chatRepo.Upsert(ctx, &domain.Chat{ID: rem.ChatID, Type: "group", Available: true}))
+		require.NoError(t, chatRepo.SetAvailable(ctx, rem.ChatID, false))
+
+		pending, err := repo.ListPendingDeliveries(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
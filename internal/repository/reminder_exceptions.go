@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+const (
+	// Повторное исключение для того же срабатывания заменяет прежнее: «перенести»
+	// после «пропустить» означает передумать, а не завести второе правило.
+	upsertExceptionQuery = `INSERT INTO reminder_exceptions (reminder_id, occurrence, moved_to, created_at)
+        VALUES (?, ?, ?, ?)
+        ON CONFLICT(reminder_id, occurrence) DO UPDATE SET
+            moved_to = excluded.moved_to,
+            created_at = excluded.created_at`
+
+	listExceptionsQuery = `SELECT id, reminder_id, occurrence, moved_to, created_at
+        FROM reminder_exceptions WHERE reminder_id = ? ORDER BY occurrence, id`
+
+	// Исключение отжило своё, когда прошли и исходное время, и время переноса.
+	deleteExceptionsBeforeQuery = `DELETE FROM reminder_exceptions
+        WHERE reminder_id = ? AND occurrence < ? AND (moved_to IS NULL OR moved_to < ?)`
+
+	deleteExceptionsByReminderQuery = `DELETE FROM reminder_exceptions WHERE reminder_id = ?`
+)
+
+// ExceptionFunc заполняет и проверяет исключение e для напоминания rem, прочитанного
+// вместе с исключениями, и пересчитывает rem. Возвращает момент, раньше которого
+// отжившие исключения удаляются.
+type ExceptionFunc func(rem *domain.Reminder, e *domain.OccurrenceException) (time.Time, error)
+
+func (r *reminderRepository) AddException(ctx context.Context, e *domain.OccurrenceException) error {
+	return addException(ctx, r.db, e)
+}
+
+func (r *reminderRepository) ApplyException(
+	ctx context.Context,
+	e *domain.OccurrenceException,
+	apply ExceptionFunc,
+) error {
+	if e == nil || e.ReminderID <= 0 {
+		return fmt.Errorf("%w: exception needs a reminder", ErrInvalidReminder)
+	}
+
+	return r.inTx(ctx, func(tx DBExecutor) error {
+		rem, err := getReminder(ctx, tx, e.ReminderID)
+		if err != nil {
+			return err
+		}
+		if rem.Exceptions, err = listExceptions(ctx, tx, rem.ID); err != nil {
+			return err
+		}
+		before, err := apply(rem, e)
+		if err != nil {
+			return err
+		}
+		if err := deleteExceptionsBefore(ctx, tx, rem.ID, before); err != nil {
+			return err
+		}
+		if err := addException(ctx, tx, e); err != nil {
+			return err
+		}
+
+		return updateReminder(ctx, tx, rem)
+	})
+}
+
+func addException(ctx context.Context, db DBExecutor, e *domain.OccurrenceException) error {
+	if e == nil || e.ReminderID <= 0 || e.Occurrence.IsZero() {
+		return fmt.Errorf("%w: exception needs a reminder and an occurrence", ErrInvalidReminder)
+	}
+
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	_, err := db.ExecContext(ctx, upsertExceptionQuery,
+		e.ReminderID,
+		e.Occurrence.UTC(),
+		nullableTime(e.MovedTo),
+		e.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: failed to save exception: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) ListExceptions(
+	ctx context.Context,
+	reminderID int64,
+) ([]domain.OccurrenceException, error) {
+	if reminderID <= 0 {
+		return nil, fmt.Errorf("%w: invalid reminder ID", ErrInvalidReminder)
+	}
+
+	return listExceptions(ctx, r.db, reminderID)
+}
+
+func listExceptions(ctx context.Context, db DBExecutor, reminderID int64) ([]domain.OccurrenceException, error) {
+	rows, err := db.QueryContext(ctx, listExceptionsQuery, reminderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query exceptions: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	var exceptions []domain.OccurrenceException
+	for rows.Next() {
+		var e domain.OccurrenceException
+		var movedTo sql.NullTime
+		if err := rows.Scan(&e.ID, &e.ReminderID, &e.Occurrence, &movedTo, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w: failed to scan exception: %v", ErrDatabaseError, err)
+		}
+		if movedTo.Valid {
+			e.MovedTo = movedTo.Time.UTC()
+		}
+		e.Occurrence = e.Occurrence.UTC()
+		exceptions = append(exceptions, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read exceptions: %v", ErrDatabaseError, err)
+	}
+
+	return exceptions, nil
+}
+
+func (r *reminderRepository) DeleteExceptionsBefore(ctx context.Context, reminderID int64, before time.Time) error {
+	if reminderID <= 0 {
+		return fmt.Errorf("%w: invalid reminder ID", ErrInvalidReminder)
+	}
+
+	return deleteExceptionsBefore(ctx, r.db, reminderID, before)
+}
+
+func deleteExceptionsBefore(ctx context.Context, db DBExecutor, reminderID int64, before time.Time) error {
+	if _, err := db.ExecContext(ctx, deleteExceptionsBeforeQuery, reminderID, before.UTC(), before.UTC()); err != nil {
+		return fmt.Errorf("%w: failed to delete old exceptions: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_Exceptions(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (ReminderRepository, *domain.Reminder) {
+		t.Helper()
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+
+		repo := NewReminderRepository(db)
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatEveryWeek
+		require.NoError(t, repo.Create(ctx, rem))
+
+		return repo, rem
+	}
+
+	t.Run("add and list", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		moved := base.Add(7*24*time.Hour + 2*time.Hour)
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{
+			ReminderID: rem.ID,
+			Occurrence: base.Add(7 * 24 * time.Hour),
+			MovedTo:    moved,
+		}))
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: rem.ID, Occurrence: base}))
+
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, base, got[0].Occurrence)
+		assert.True(t, got[0].IsSkip())
+		assert.Equal(t, moved, got[1].MovedTo)
+		assert.NotZero(t, got[1].ID)
+	})
+
+	t.Run("same occurrence replaces exception", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: rem.ID, Occurrence: base}))
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{
+			ReminderID: rem.ID,
+			Occurrence: base,
+			MovedTo:    base.Add(time.Hour),
+		}))
+
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, base.Add(time.Hour), got[0].MovedTo)
+	})
+
+	t.Run("delete before keeps pending moves", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		week := 7 * 24 * time.Hour
+		for _, e := range []domain.OccurrenceException{
+			{ReminderID: rem.ID, Occurrence: base},
+			{ReminderID: rem.ID, Occurrence: base.Add(week), MovedTo: base.Add(3 * week)},
+			{ReminderID: rem.ID, Occurrence: base.Add(2 * week)},
+		} {
+			require.NoError(t, repo.AddException(ctx, &e))
+		}
+
+		require.NoError(t, repo.DeleteExceptionsBefore(ctx, rem.ID, base.Add(2*week)))
+
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, base.Add(week), got[0].Occurrence)
+		assert.Equal(t, base.Add(2*week), got[1].Occurrence)
+	})
+
+	t.Run("deleting reminder removes exceptions", func(t *testing.T) {
+		repo, rem := setup(t)
+
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: rem.ID, Occurrence: base}))
+		require.NoError(t, repo.Delete(ctx, rem.ID))
+
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("apply saves exception and reminder together", func(t *testing.T) {
+		repo, rem := setup(t)
+		week := 7 * 24 * time.Hour
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: rem.ID, Occurrence: base}))
+
+		e := &domain.OccurrenceException{ReminderID: rem.ID}
+		apply := func(r *domain.Reminder, e *domain.OccurrenceException) (time.Time, error) {
+			require.Len(t, r.Exceptions, 1, "исключения читаются в той же транзакции")
+			e.Occurrence = base.Add(week)
+			r.NextTime = base.Add(2 * week)
+
+			return base.Add(time.Hour), nil
+		}
+		require.NoError(t, repo.ApplyException(ctx, e, apply))
+
+		stored, err := repo.GetByID(ctx, rem.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.NextTime.Equal(base.Add(2*week)))
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 1, "отжившее исключение удалено")
+		assert.Equal(t, base.Add(week), got[0].Occurrence)
+	})
+
+	t.Run("failed reminder update rolls back the exception", func(t *testing.T) {
+		repo, rem := setup(t)
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: rem.ID, Occurrence: base}))
+
+		e := &domain.OccurrenceException{ReminderID: rem.ID}
+		err := repo.ApplyException(ctx, e, func(r *domain.Reminder, e *domain.OccurrenceException) (time.Time, error) {
+			e.Occurrence = base.Add(24 * time.Hour)
+			r.Text = "" // напоминание без текста не сохранится
+
+			return base.Add(time.Hour), nil
+		})
+		require.Error(t, err)
+
+		got, err := repo.ListExceptions(ctx, rem.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, base, got[0].Occurrence, "ни удаления, ни нового исключения")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		repo, _ := setup(t)
+
+		assert.ErrorIs(t, repo.AddException(ctx, &domain.OccurrenceException{Occurrence: base}), ErrInvalidReminder)
+		assert.ErrorIs(t, repo.AddException(ctx, &domain.OccurrenceException{ReminderID: 1}), ErrInvalidReminder)
+		_, err := repo.ListExceptions(ctx, 0)
+		assert.ErrorIs(t, err, ErrInvalidReminder)
+		assert.ErrorIs(t, repo.DeleteExceptionsBefore(ctx, 0, base), ErrInvalidReminder)
+	})
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+const (
+	attemptColumns = `id, delivery_id, reminder_id, chat_id, kind, text, scheduled_at, attempted_at,
+        outcome, message_id, error_class, error`
+
+	recordAttemptQuery = `INSERT INTO delivery_attempts (delivery_id, reminder_id, chat_id, kind, text,
+        scheduled_at, attempted_at, outcome, message_id, error_class, error)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	listAttemptsQuery = `SELECT ` + attemptColumns + `
+        FROM delivery_attempts
+        WHERE chat_id = ?
+        ORDER BY attempted_at DESC, id DESC
+        LIMIT ?`
+
+	deleteAttemptsBeforeQuery = `DELETE FROM delivery_attempts WHERE attempted_at < ?`
+)
+
+func (r *reminderRepository) RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt) error {
+	if a == nil || a.ChatID == 0 || a.Outcome == "" {
+		return fmt.Errorf("%w: delivery attempt needs a chat and an outcome", ErrInvalidReminder)
+	}
+
+	result, err := r.db.ExecContext(ctx, recordAttemptQuery,
+		a.DeliveryID,
+		a.ReminderID,
+		a.ChatID,
+		a.Kind,
+		a.Text,
+		a.ScheduledAt.UTC(),
+		a.AttemptedAt.UTC(),
+		a.Outcome,
+		a.MessageID,
+		a.ErrorClass,
+		a.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: failed to record delivery attempt: %v", ErrDatabaseError, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert ID: %v", ErrDatabaseError, err)
+	}
+	a.ID = id
+
+	return nil
+}
+
+func (r *reminderRepository) ListAttempts(
+	ctx context.Context,
+	chatID int64,
+	limit int,
+) ([]*domain.DeliveryAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, listAttemptsQuery, chatID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query delivery history: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	var attempts []*domain.DeliveryAttempt
+	for rows.Next() {
+		var a domain.DeliveryAttempt
+		if err := rows.Scan(
+			&a.ID,
+			&a.DeliveryID,
+			&a.ReminderID,
+			&a.ChatID,
+			&a.Kind,
+			&a.Text,
+			&a.ScheduledAt,
+			&a.AttemptedAt,
+			&a.Outcome,
+			&a.MessageID,
+			&a.ErrorClass,
+			&a.Error,
+		); err != nil {
+			return nil, fmt.Errorf("%w: failed to scan delivery attempt: %v", ErrDatabaseError, err)
+		}
+		a.ScheduledAt = a.ScheduledAt.UTC()
+		a.AttemptedAt = a.AttemptedAt.UTC()
+		attempts = append(attempts, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read delivery history: %v", ErrDatabaseError, err)
+	}
+
+	return attempts, nil
+}
+
+func (r *reminderRepository) DeleteAttemptsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, deleteAttemptsBeforeQuery, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to delete old delivery history: %v", ErrDatabaseError, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get rows affected: %v", ErrDatabaseError, err)
+	}
+
+	return deleted, nil
+}
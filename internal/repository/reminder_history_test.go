@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_History(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+
+	db := setupTestDB(t)
+	defer db.Close()
+	repo := NewReminderRepository(db)
+
+	attempt := func(chatID int64, at time.Time, outcome domain.DeliveryOutcome) *domain.DeliveryAttempt {
+		return &domain.DeliveryAttempt{
+			DeliveryID: 1, ReminderID: 2, ChatID: chatID, Kind: domain.DeliveryReminder,
+			Text: "⏰ Напоминание: чай", ScheduledAt: now, AttemptedAt: at, Outcome: outcome,
+		}
+	}
+	old := attempt(100, now.Add(-40*24*time.Hour), domain.OutcomeSent)
+	failed := attempt(100, now, domain.OutcomeRetry)
+	failed.ErrorClass, failed.Error = "server", "telegram: Bad Gateway (502)"
+	sent := attempt(100, now.Add(30*time.Second), domain.OutcomeSent)
+	sent.MessageID = 77
+	for _, a := range []*domain.DeliveryAttempt{old, failed, sent, attempt(200, now, domain.OutcomeSent)} {
+		require.NoError(t, repo.RecordAttempt(ctx, a))
+		require.NotZero(t, a.ID)
+	}
+	require.ErrorIs(t, repo.RecordAttempt(ctx, &domain.DeliveryAttempt{ChatID: 100}), ErrInvalidReminder)
+
+	history, err := repo.ListAttempts(ctx, 100, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.DeliveryAttempt{sent, failed}, history, "newest first, limited")
+
+	deleted, err := repo.DeleteAttemptsBefore(ctx, now.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	history, err = repo.ListAttempts(ctx, 100, 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+const updateChatTimezoneQuery = `UPDATE chats SET timezone=?, updated_at=? WHERE chat_id=?`
+
+// RebaseFunc пересчитывает напоминание под новый часовой пояс чата и сообщает,
+// изменилось ли оно.
+type RebaseFunc func(rem *domain.Reminder) (bool, error)
+
+func (r *reminderRepository) RebaseChat(
+	ctx context.Context,
+	chatID int64,
+	timezone string,
+	rebase RebaseFunc,
+) error {
+	return r.inTx(ctx, func(tx DBExecutor) error {
+		if _, err := tx.ExecContext(ctx, updateChatTimezoneQuery, timezone, time.Now().UTC(), chatID); err != nil {
+			return fmt.Errorf("%w: failed to update chat timezone: %v", ErrDatabaseError, err)
+		}
+
+		reminders, err := listByChat(ctx, tx, chatID)
+		if err != nil {
+			return err
+		}
+		for _, rem := range reminders {
+			if rem.Exceptions, err = listExceptions(ctx, tx, rem.ID); err != nil {
+				return err
+			}
+			changed, err := rebase(rem)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			if err := updateReminder(ctx, tx, rem); err != nil {
+				return err
+			}
+			if err := replaceExceptions(ctx, tx, rem); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// replaceExceptions перезаписывает исключения напоминания. Сдвинутые по одному, они
+// могли бы на время совпасть с ещё не сдвинутым соседом и нарушить уникальность
+// (reminder_id, occurrence), поэтому старые сначала удаляются.
+func replaceExceptions(ctx context.Context, db DBExecutor, rem *domain.Reminder) error {
+	if _, err := db.ExecContext(ctx, deleteExceptionsByReminderQuery, rem.ID); err != nil {
+		return fmt.Errorf("%w: failed to delete exceptions: %v", ErrDatabaseError, err)
+	}
+	for _, e := range rem.Exceptions {
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = time.Now()
+		}
+		if _, err := db.ExecContext(ctx, upsertExceptionQuery,
+			rem.ID,
+			e.Occurrence.UTC(),
+			nullableTime(e.MovedTo),
+			e.CreatedAt.UTC(),
+		); err != nil {
+			return fmt.Errorf("%w: failed to save exception: %v", ErrDatabaseError, err)
+		}
+	}
+
+	return nil
+}
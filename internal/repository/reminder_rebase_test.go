@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_RebaseChat(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	chats := NewChatRepository(db)
+	require.NoError(t, chats.Upsert(ctx, &domain.Chat{ID: 12345, Type: "private", Timezone: "Europe/Moscow"}))
+
+	repo := NewReminderRepository(db)
+	rem := createTestReminder()
+	rem.Repeat = domain.RepeatEveryDay
+	require.NoError(t, repo.Create(ctx, rem))
+	base := rem.NextTime.UTC().Truncate(time.Minute)
+	for i := range 2 {
+		require.NoError(t, repo.AddException(ctx, &domain.OccurrenceException{
+			ReminderID: rem.ID,
+			Occurrence: base.Add(time.Duration(i+1) * time.Hour),
+		}))
+	}
+
+	// Сдвиг ровно на промежуток между исключениями: первое встаёт на место второго.
+	require.NoError(t, repo.RebaseChat(ctx, 12345, "Europe/Berlin", func(r *domain.Reminder) (bool, error) {
+		require.Equal(t, rem.ID, r.ID)
+		require.Len(t, r.Exceptions, 2, "исключения читаются в той же транзакции")
+		for i := range r.Exceptions {
+			r.Exceptions[i].Occurrence = r.Exceptions[i].Occurrence.Add(time.Hour)
+		}
+		r.NextTime = base.Add(time.Hour)
+
+		return true, nil
+	}))
+
+	chat, err := chats.GetByID(ctx, 12345)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", chat.Timezone)
+
+	stored, err := repo.GetByID(ctx, rem.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.NextTime.Equal(base.Add(time.Hour)))
+
+	got, err := repo.ListExceptions(ctx, rem.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, base.Add(2*time.Hour), got[0].Occurrence)
+	assert.Equal(t, base.Add(3*time.Hour), got[1].Occurrence)
+
+	t.Run("ошибка rebase откатывает всё", func(t *testing.T) {
+		errRebase := errors.New("rebase failed")
+		err := repo.RebaseChat(ctx, 12345, "Asia/Tokyo", func(r *domain.Reminder) (bool, error) {
+			return false, errRebase
+		})
+		require.ErrorIs(t, err, errRebase)
+
+		chat, err := chats.GetByID(ctx, 12345)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", chat.Timezone)
+	})
+
+	t.Run("неизменённые напоминания не перезаписываются", func(t *testing.T) {
+		require.NoError(t, repo.RebaseChat(ctx, 12345, "Asia/Tokyo", func(r *domain.Reminder) (bool, error) {
+			r.NextTime = base.Add(5 * time.Hour)
+			return false, nil
+		}))
+
+		stored, err := repo.GetByID(ctx, rem.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.NextTime.Equal(base.Add(time.Hour)))
+	})
+}
@@ -13,32 +13,44 @@ import (
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 )
 
+// reminderColumns — порядок колонок, который ожидает scanReminder.
+const reminderColumns = `id, chat_id, text, next_time, repeat, repeat_days, repeat_every, month_ordinal,
+        rrule, start_time, cron, solar_event, solar_offset, timezone, times, interval_minutes, window_start,
+        window_end, ends_at, remaining_count, workday_policy, shifted_from, nag_every_minutes, nag_max, lead_minutes,
+        notice_at, parent_id, follow_offset, follow_on, waiting, paused, created_at, updated_at`
+
 // SQL запросы вынесены в константы для лучшей читаемости и переиспользования
 const (
 	createReminderQuery = `INSERT INTO reminders (chat_id, text, next_time, repeat, repeat_days, 
-        repeat_every, paused, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+        repeat_every, month_ordinal, rrule, start_time, cron, solar_event, solar_offset, timezone, times,
+        interval_minutes, window_start, window_end, ends_at, remaining_count, workday_policy, shifted_from,
+        nag_every_minutes, nag_max, lead_minutes, notice_at, parent_id, follow_offset, follow_on, waiting, paused,
+        created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	updateReminderQuery = `UPDATE reminders SET chat_id=?, text=?, next_time=?, repeat=?, repeat_days=?, 
-        repeat_every=?, paused=?, created_at=?, updated_at=? WHERE id=?`
+        repeat_every=?, month_ordinal=?, rrule=?, start_time=?, cron=?, solar_event=?, solar_offset=?, timezone=?,
+        times=?, interval_minutes=?, window_start=?, window_end=?, ends_at=?, remaining_count=?, workday_policy=?,
+        shifted_from=?, nag_every_minutes=?, nag_max=?, lead_minutes=?, notice_at=?, parent_id=?, follow_offset=?,
+        follow_on=?, waiting=?, paused=?, created_at=?, updated_at=? WHERE id=?`
 
 	deleteReminderQuery = `DELETE FROM reminders WHERE id = ?`
 
-	getReminderByIDQuery = `SELECT id, chat_id, text, next_time, repeat, repeat_days, repeat_every, paused, 
-        created_at, updated_at
+	getReminderByIDQuery = `SELECT ` + reminderColumns + `
         FROM reminders WHERE id = ?`
 
 	// ORDER BY обязателен: команды /edit, /delete, /pause адресуют напоминания по порядковому
 	// номеру из /list, а Mini App — по ID. Без явной сортировки порядок строк в SQLite
 	// не определён, и номер в списке может не совпасть с тем, что удаляется.
-	listRemindersByChatQuery = `SELECT id, chat_id, text, next_time, repeat, repeat_days, repeat_every, paused,
-        created_at, updated_at
+	listRemindersByChatQuery = `SELECT ` + reminderColumns + `
         FROM reminders WHERE chat_id = ? ORDER BY next_time, id`
 
-	listDueRemindersQuery = `SELECT id, chat_id, text, next_time, repeat, repeat_days, repeat_every, paused,
-        created_at, updated_at
+	listFollowUpsQuery = `SELECT ` + reminderColumns + `
+        FROM reminders WHERE parent_id = ? ORDER BY id`
+
+	listDueRemindersQuery = `SELECT ` + reminderColumns + `
         FROM reminders r
-        WHERE next_time <= ? AND paused = 0
+        WHERE (next_time <= ? OR notice_at <= ?) AND paused = 0 AND waiting = 0
             AND NOT EXISTS (
                 SELECT 1 FROM chats c
                 WHERE c.chat_id = r.chat_id AND c.available = 0
@@ -74,6 +86,50 @@ type ReminderRepository interface {
 	GetByID(ctx context.Context, id int64) (*domain.Reminder, error)
 	ListByChat(ctx context.Context, chatID int64) ([]*domain.Reminder, error)
 	ListDue(ctx context.Context, now time.Time) ([]*domain.Reminder, error)
+	// ListFollowUps возвращает продолжения напоминания parentID, в том числе из уже
+	// удалённого родителя.
+	ListFollowUps(ctx context.Context, parentID int64) ([]*domain.Reminder, error)
+	// ListWakeups возвращает limit ближайших моментов, когда планировщику есть что
+	// делать: срабатывания, предупреждения, повторы подтверждений и попытки доставки.
+	// Наступившие, но не обработанные моменты идут первыми.
+	ListWakeups(ctx context.Context, limit int) ([]domain.Wakeup, error)
+
+	// Исключения из серии: пропуски и переносы отдельных срабатываний.
+	AddException(ctx context.Context, e *domain.OccurrenceException) error
+	ListExceptions(ctx context.Context, reminderID int64) ([]domain.OccurrenceException, error)
+	DeleteExceptionsBefore(ctx context.Context, reminderID int64, before time.Time) error
+	// ApplyException одной транзакцией читает напоминание e.ReminderID с исключениями
+	// и отдаёт его в apply, а затем удаляет отжившие исключения, сохраняет e
+	// и записывает пересчитанное напоминание. Ошибка apply откатывает всё.
+	ApplyException(ctx context.Context, e *domain.OccurrenceException, apply ExceptionFunc) error
+
+	// Доставки в режиме подтверждения.
+	CreateAck(ctx context.Context, a *domain.Acknowledgement) error
+	UpdateAck(ctx context.Context, a *domain.Acknowledgement) error
+	ListDueAcks(ctx context.Context, now time.Time) ([]*domain.Acknowledgement, error)
+	GetAck(ctx context.Context, id, chatID int64) (*domain.Acknowledgement, error)
+	// Acknowledge отмечает подтверждение доставки id в чате chatID. Уже подтверждённая
+	// доставка возвращается вместе с domain.ErrAlreadyAcknowledged.
+	Acknowledge(ctx context.Context, id, chatID, userID int64, userName string,
+		at time.Time) (*domain.Acknowledgement, error)
+
+	// Очередь доставок. Advance и Finish сохраняют или удаляют напоминание и ставят d
+	// в очередь одной транзакцией; d == nil — ставить нечего. Срабатывание, которое
+	// уже в очереди, второй раз не ставится: d.ID тогда остаётся нулевым.
+	Advance(ctx context.Context, rem *domain.Reminder, d *domain.Delivery) error
+	Finish(ctx context.Context, id int64, d *domain.Delivery) error
+	UpdateDelivery(ctx context.Context, d *domain.Delivery) error
+	ListPendingDeliveries(ctx context.Context, now time.Time) ([]*domain.Delivery, error)
+
+	// RebaseChat записывает новый часовой пояс чата и в той же транзакции читает его
+	// напоминания с исключениями и отдаёт каждое в rebase. Напоминание, для которого
+	// rebase вернул true, сохраняется вместе с исключениями; ошибка откатывает всё.
+	RebaseChat(ctx context.Context, chatID int64, timezone string, rebase RebaseFunc) error
+
+	// История доставок: попытки чата — от новых к старым.
+	RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt) error
+	ListAttempts(ctx context.Context, chatID int64, limit int) ([]*domain.DeliveryAttempt, error)
+	DeleteAttemptsBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type reminderRepository struct {
@@ -129,6 +185,29 @@ func (r *reminderRepository) Create(ctx context.Context, rem *domain.Reminder) e
 		rem.Repeat,
 		days,
 		rem.RepeatEvery,
+		rem.MonthOrdinal,
+		rem.RRule,
+		nullableTime(rem.StartTime),
+		rem.Cron,
+		rem.SolarEvent,
+		rem.SolarOffset,
+		rem.Timezone,
+		serializeRepeatDays(rem.Times),
+		rem.IntervalMinutes,
+		rem.WindowStart,
+		rem.WindowEnd,
+		nullableTime(rem.EndsAt),
+		rem.RemainingCount,
+		rem.WorkdayPolicy,
+		nullableTime(rem.ShiftedFrom),
+		rem.NagEveryMinutes,
+		rem.NagMax,
+		serializeRepeatDays(rem.LeadMinutes),
+		nullableTime(rem.NoticeAt),
+		rem.ParentID,
+		rem.FollowOffset,
+		rem.FollowOn,
+		rem.Waiting,
 		rem.Paused,
 		rem.CreatedAt.UTC(),
 		rem.UpdatedAt.UTC(),
@@ -152,6 +231,11 @@ func (r *reminderRepository) Create(ctx context.Context, rem *domain.Reminder) e
 }
 
 func (r *reminderRepository) Update(ctx context.Context, rem *domain.Reminder) error {
+	return updateReminder(ctx, r.db, rem)
+}
+
+// updateReminder сохраняет напоминание через db — соединение или транзакцию.
+func updateReminder(ctx context.Context, db DBExecutor, rem *domain.Reminder) error {
 	if err := validateReminder(rem); err != nil {
 		return err
 	}
@@ -163,13 +247,36 @@ func (r *reminderRepository) Update(ctx context.Context, rem *domain.Reminder) e
 	rem.UpdatedAt = time.Now()
 	days := serializeRepeatDays(rem.RepeatDays)
 
-	result, err := r.db.ExecContext(ctx, updateReminderQuery,
+	result, err := db.ExecContext(ctx, updateReminderQuery,
 		rem.ChatID,
 		rem.Text,
 		rem.NextTime.UTC(),
 		rem.Repeat,
 		days,
 		rem.RepeatEvery,
+		rem.MonthOrdinal,
+		rem.RRule,
+		nullableTime(rem.StartTime),
+		rem.Cron,
+		rem.SolarEvent,
+		rem.SolarOffset,
+		rem.Timezone,
+		serializeRepeatDays(rem.Times),
+		rem.IntervalMinutes,
+		rem.WindowStart,
+		rem.WindowEnd,
+		nullableTime(rem.EndsAt),
+		rem.RemainingCount,
+		rem.WorkdayPolicy,
+		nullableTime(rem.ShiftedFrom),
+		rem.NagEveryMinutes,
+		rem.NagMax,
+		serializeRepeatDays(rem.LeadMinutes),
+		nullableTime(rem.NoticeAt),
+		rem.ParentID,
+		rem.FollowOffset,
+		rem.FollowOn,
+		rem.Waiting,
 		rem.Paused,
 		rem.CreatedAt.UTC(),
 		rem.UpdatedAt.UTC(),
@@ -192,11 +299,21 @@ func (r *reminderRepository) Update(ctx context.Context, rem *domain.Reminder) e
 }
 
 func (r *reminderRepository) Delete(ctx context.Context, id int64) error {
+	if err := deleteReminder(ctx, r.db, id); err != nil {
+		return err
+	}
+	r.deleteExceptions(ctx, id)
+
+	return nil
+}
+
+// deleteReminder удаляет напоминание через db — соединение или транзакцию.
+func deleteReminder(ctx context.Context, db DBExecutor, id int64) error {
 	if id <= 0 {
 		return fmt.Errorf("%w: invalid reminder ID", ErrInvalidReminder)
 	}
 
-	result, err := r.db.ExecContext(ctx, deleteReminderQuery, id)
+	result, err := db.ExecContext(ctx, deleteReminderQuery, id)
 	if err != nil {
 		return fmt.Errorf("%w: failed to delete reminder: %v", ErrDatabaseError, err)
 	}
@@ -213,14 +330,25 @@ func (r *reminderRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// deleteExceptions убирает исключения удалённого напоминания. Напоминание уже удалено,
+// поэтому ошибку только логируем: осиротевшие исключения ни на что не влияют, а сообщать
+// об ошибке удаления было бы неправдой.
+func (r *reminderRepository) deleteExceptions(ctx context.Context, id int64) {
+	if _, err := r.db.ExecContext(ctx, deleteExceptionsByReminderQuery, id); err != nil {
+		slog.Error("[Delete] failed to delete reminder exceptions", "reminderID", id, "error", err)
+	}
+}
+
 func (r *reminderRepository) GetByID(ctx context.Context, id int64) (*domain.Reminder, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("%w: invalid reminder ID", ErrInvalidReminder)
 	}
 
-	row := r.db.QueryRowContext(ctx, getReminderByIDQuery, id)
+	return getReminder(ctx, r.db, id)
+}
 
-	rem, err := scanReminder(row)
+func getReminder(ctx context.Context, db DBExecutor, id int64) (*domain.Reminder, error) {
+	rem, err := scanReminder(db.QueryRowContext(ctx, getReminderByIDQuery, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%w: reminder with ID %d not found", ErrReminderNotFound, id)
@@ -236,7 +364,11 @@ func (r *reminderRepository) ListByChat(ctx context.Context, chatID int64) ([]*d
 		return nil, fmt.Errorf("%w: invalid chat ID", ErrInvalidReminder)
 	}
 
-	rows, err := r.db.QueryContext(ctx, listRemindersByChatQuery, chatID)
+	return listByChat(ctx, r.db, chatID)
+}
+
+func listByChat(ctx context.Context, db DBExecutor, chatID int64) ([]*domain.Reminder, error) {
+	rows, err := db.QueryContext(ctx, listRemindersByChatQuery, chatID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to query reminders by chat: %v", ErrDatabaseError, err)
 	}
@@ -252,7 +384,7 @@ func (r *reminderRepository) ListDue(ctx context.Context, now time.Time) ([]*dom
 
 	// Драйвер сериализует time.Time в строку со смещением, а сравнение next_time <= ?
 	// лексикографическое. Все хранимые значения в UTC, поэтому и границу приводим к UTC.
-	rows, err := r.db.QueryContext(ctx, listDueRemindersQuery, now.UTC())
+	rows, err := r.db.QueryContext(ctx, listDueRemindersQuery, now.UTC(), now.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to query due reminders: %v", ErrDatabaseError, err)
 	}
@@ -261,6 +393,20 @@ func (r *reminderRepository) ListDue(ctx context.Context, now time.Time) ([]*dom
 	return scanReminders(rows)
 }
 
+func (r *reminderRepository) ListFollowUps(ctx context.Context, parentID int64) ([]*domain.Reminder, error) {
+	if parentID <= 0 {
+		return nil, fmt.Errorf("%w: invalid reminder ID", ErrInvalidReminder)
+	}
+
+	rows, err := r.db.QueryContext(ctx, listFollowUpsQuery, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query follow-ups: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	return scanReminders(rows)
+}
+
 // closeRows закрывает набор строк, логируя ошибку: она не влияет на уже прочитанные данные,
 // но её потеря скрыла бы проблемы с соединением.
 func closeRows(rows *sql.Rows) {
@@ -269,7 +415,18 @@ func closeRows(rows *sql.Rows) {
 	}
 }
 
-// serializeRepeatDays сериализует массив дней в строку для хранения в БД
+// nullableTime превращает нулевое время в NULL, а остальное приводит к UTC.
+func nullableTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// serializeRepeatDays сериализует массив дней в строку для хранения в БД.
+// Тем же форматом хранятся времена срабатывания в течение дня (times) и отступы
+// предупреждений (lead_minutes).
 func serializeRepeatDays(days []int) string {
 	if len(days) == 0 {
 		return ""
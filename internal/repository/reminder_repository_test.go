@@ -16,33 +16,11 @@ import (
 
 // setupTestDB создает тестовую базу данных в памяти
 func setupTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
-	require.NoError(t, err)
-
-	// Создаем таблицу reminders
-	_, err = db.Exec(`
-		CREATE TABLE reminders (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			chat_id INTEGER NOT NULL,
-			text TEXT NOT NULL,
-			next_time DATETIME NOT NULL,
-			repeat INTEGER NOT NULL,
-			repeat_days TEXT,
-			repeat_every INTEGER,
-			paused BOOLEAN NOT NULL,
-			created_at DATETIME NOT NULL,
-			updated_at DATETIME NOT NULL
-		)
-	`)
-	require.NoError(t, err)
-
-	_, err = db.Exec(`
-		CREATE TABLE chats (
-			chat_id INTEGER PRIMARY KEY,
-			available BOOLEAN NOT NULL DEFAULT 1
-		)
-	`)
+	db, err := sql.Open("sqlite3", ":memory:?_loc=UTC")
 	require.NoError(t, err)
+	// In-memory база живёт в одном соединении: второе увидело бы пустую схему.
+	db.SetMaxOpenConns(1)
+	require.NoError(t, Migrate(db))
 
 	return db
 }
@@ -177,6 +155,140 @@ func TestReminderRepository_GetByID(t *testing.T) {
 		// UserID removed
 		assert.Equal(t, rem.Text, retrieved.Text)
 		assert.Equal(t, rem.RepeatDays, retrieved.RepeatDays)
+		assert.True(t, retrieved.StartTime.IsZero())
+	})
+
+	t.Run("rrule round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatRRule
+		rem.RRule = "FREQ=MONTHLY;BYDAY=2TU"
+		rem.StartTime = time.Date(2025, time.June, 10, 7, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RepeatRRule, retrieved.Repeat)
+		assert.Equal(t, rem.RRule, retrieved.RRule)
+		assert.True(t, rem.StartTime.Equal(retrieved.StartTime))
+	})
+
+	t.Run("cron round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatCron
+		rem.Cron = "0 9 * * 1-5"
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RepeatCron, retrieved.Repeat)
+		assert.Equal(t, rem.Cron, retrieved.Cron)
+	})
+
+	t.Run("solar round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatSolar
+		rem.SolarEvent = domain.SolarSunset
+		rem.SolarOffset = -30
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RepeatSolar, retrieved.Repeat)
+		assert.Equal(t, domain.SolarSunset, retrieved.SolarEvent)
+		assert.Equal(t, -30, retrieved.SolarOffset)
+	})
+
+	t.Run("timezone round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Timezone = "America/New_York"
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "America/New_York", retrieved.Timezone)
+	})
+
+	t.Run("monthly weekday round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatEveryMonth
+		rem.MonthOrdinal = domain.MonthOrdinalLast
+		rem.RepeatDays = []int{1, 2, 3, 4, 5}
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.MonthOrdinalLast, retrieved.MonthOrdinal)
+		assert.Equal(t, rem.RepeatDays, retrieved.RepeatDays)
+	})
+
+	t.Run("several times per day round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatEveryDay
+		rem.Times = []int{9 * 60, 13 * 60, 21 * 60}
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rem.Times, retrieved.Times)
+	})
+
+	t.Run("interval round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatInterval
+		rem.IntervalMinutes = 120
+		rem.WindowStart, rem.WindowEnd = 10*60, 18*60
+		rem.RepeatDays = []int{1, 2, 3, 4, 5}
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RepeatInterval, retrieved.Repeat)
+		assert.Equal(t, 120, retrieved.IntervalMinutes)
+		assert.Equal(t, 10*60, retrieved.WindowStart)
+		assert.Equal(t, 18*60, retrieved.WindowEnd)
+		assert.Equal(t, rem.RepeatDays, retrieved.RepeatDays)
+	})
+
+	t.Run("end conditions round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatEveryDay
+		rem.EndsAt = time.Date(2026, time.August, 7, 20, 59, 0, 0, time.UTC)
+		rem.RemainingCount = 21
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.True(t, rem.EndsAt.Equal(retrieved.EndsAt), "ends_at %s", retrieved.EndsAt)
+		assert.Equal(t, 21, retrieved.RemainingCount)
+
+		retrieved.EndsAt = time.Time{}
+		retrieved.RemainingCount = 0
+		require.NoError(t, repo.Update(context.Background(), retrieved))
+
+		cleared, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.True(t, cleared.EndsAt.IsZero())
+		assert.Zero(t, cleared.RemainingCount)
+	})
+
+	t.Run("workday policy round trip", func(t *testing.T) {
+		rem := createTestReminder()
+		rem.Repeat = domain.RepeatEveryWeek
+		rem.WorkdayPolicy = domain.WorkdayPrevious
+		rem.ShiftedFrom = time.Date(2026, time.January, 5, 6, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		retrieved, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WorkdayPrevious, retrieved.WorkdayPolicy)
+		assert.True(t, rem.ShiftedFrom.Equal(retrieved.ShiftedFrom), "shifted_from %s", retrieved.ShiftedFrom)
+
+		retrieved.ShiftedFrom = time.Time{}
+		require.NoError(t, repo.Update(context.Background(), retrieved))
+
+		cleared, err := repo.GetByID(context.Background(), rem.ID)
+		require.NoError(t, err)
+		assert.True(t, cleared.ShiftedFrom.IsZero())
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -419,6 +531,31 @@ func TestReminderRepository_ListDue(t *testing.T) {
 		assert.Equal(t, rem1.ID, reminders[0].ID)
 	})
 
+	t.Run("due advance notice", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		repo := NewReminderRepository(db)
+
+		now := time.Now()
+		rem := createTestReminder()
+		rem.NextTime = now.Add(24 * time.Hour)
+		rem.LeadMinutes = []int{domain.MinutesPerDay + 30, 60}
+		rem.NoticeAt = now.Add(-30 * time.Minute)
+		require.NoError(t, repo.Create(context.Background(), rem))
+
+		reminders, err := repo.ListDue(context.Background(), now)
+		require.NoError(t, err)
+		require.Len(t, reminders, 1)
+		assert.Equal(t, []int{domain.MinutesPerDay + 30, 60}, reminders[0].LeadMinutes)
+		assert.WithinDuration(t, rem.NoticeAt, reminders[0].NoticeAt, time.Second)
+
+		rem.NoticeAt = time.Time{}
+		require.NoError(t, repo.Update(context.Background(), rem))
+		reminders, err = repo.ListDue(context.Background(), now)
+		require.NoError(t, err)
+		assert.Empty(t, reminders)
+	})
+
 	t.Run("empty list", func(t *testing.T) {
 		db := setupTestDB(t)
 		defer db.Close()
@@ -821,25 +958,11 @@ func TestReminderRepository_Delete_DatabaseErrors(t *testing.T) {
 func TestReminderRepository_GetByID_DatabaseErrors(t *testing.T) {
 	t.Run("query row context error", func(t *testing.T) {
 		// Используем реальную in-memory базу, чтобы получить sql.ErrNoRows
-		db, err := sql.Open("sqlite3", ":memory:")
-		assert.NoError(t, err)
+		db := setupTestDB(t)
 		defer db.Close()
-		_, err = db.Exec(`CREATE TABLE reminders (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			chat_id INTEGER NOT NULL,
-			text TEXT NOT NULL,
-			next_time DATETIME NOT NULL,
-			repeat INTEGER NOT NULL,
-			repeat_days TEXT,
-			repeat_every INTEGER,
-			paused BOOLEAN NOT NULL,
-			created_at DATETIME NOT NULL,
-			updated_at DATETIME NOT NULL
-		)`)
-		assert.NoError(t, err)
 
 		repo := NewReminderRepository(db)
-		_, err = repo.GetByID(context.Background(), 99999)
+		_, err := repo.GetByID(context.Background(), 99999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
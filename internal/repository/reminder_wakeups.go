@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// Условия совпадают с ListDue, ListDueAcks и ListPendingDeliveries: запись, которую
+// они не выберут, разбудила бы планировщик впустую.
+const (
+	unavailableChat = `EXISTS (SELECT 1 FROM chats c WHERE c.chat_id = t.chat_id AND c.available = 0)`
+
+	wakeupNextTimeQuery = `SELECT id, next_time FROM reminders t
+        WHERE paused = 0 AND waiting = 0 AND NOT ` + unavailableChat + `
+        ORDER BY next_time LIMIT ?`
+
+	wakeupNoticeQuery = `SELECT id, notice_at FROM reminders t
+        WHERE notice_at IS NOT NULL AND paused = 0 AND waiting = 0 AND NOT ` + unavailableChat + `
+        ORDER BY notice_at LIMIT ?`
+
+	wakeupNagQuery = `SELECT id, next_nag_at FROM reminder_acks t
+        WHERE next_nag_at IS NOT NULL AND acked_at IS NULL AND message_id != 0
+            AND NOT ` + unavailableChat + `
+        ORDER BY next_nag_at LIMIT ?`
+
+	wakeupDeliveryQuery = `SELECT id, next_attempt_at FROM reminder_deliveries t
+        WHERE next_attempt_at IS NOT NULL AND NOT ` + unavailableChat + `
+        ORDER BY next_attempt_at LIMIT ?`
+)
+
+func (r *reminderRepository) ListWakeups(ctx context.Context, limit int) ([]domain.Wakeup, error) {
+	sources := []struct {
+		kind  domain.WakeupKind
+		query string
+	}{
+		{domain.WakeReminder, wakeupNextTimeQuery},
+		{domain.WakeReminder, wakeupNoticeQuery},
+		{domain.WakeAck, wakeupNagQuery},
+		{domain.WakeDelivery, wakeupDeliveryQuery},
+	}
+
+	// Первые limit моментов объединения содержатся среди первых limit каждого источника.
+	var wakeups []domain.Wakeup
+	for _, src := range sources {
+		found, err := r.listWakeups(ctx, src.kind, src.query, limit)
+		if err != nil {
+			return nil, err
+		}
+		wakeups = append(wakeups, found...)
+	}
+	slices.SortFunc(wakeups, func(a, b domain.Wakeup) int { return a.At.Compare(b.At) })
+
+	return wakeups[:min(len(wakeups), limit)], nil
+}
+
+func (r *reminderRepository) listWakeups(
+	ctx context.Context,
+	kind domain.WakeupKind,
+	query string,
+	limit int,
+) ([]domain.Wakeup, error) {
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to query wakeups: %v", ErrDatabaseError, err)
+	}
+	defer closeRows(rows)
+
+	var wakeups []domain.Wakeup
+	for rows.Next() {
+		w := domain.Wakeup{Kind: kind}
+		if err := rows.Scan(&w.ID, &w.At); err != nil {
+			return nil, fmt.Errorf("%w: failed to scan wakeup: %v", ErrDatabaseError, err)
+		}
+		w.At = w.At.UTC()
+		wakeups = append(wakeups, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read wakeups: %v", ErrDatabaseError, err)
+	}
+
+	return wakeups, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_ListWakeups(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.June, 2, 8, 0, 0, 0, time.UTC)
+
+	db := setupTestDB(t)
+	defer db.Close()
+	repo := NewReminderRepository(db)
+	chatRepo := NewChatRepository(db)
+
+	create := func(chatID int64, next time.Time, mutate func(*domain.Reminder)) *domain.Reminder {
+		rem := createTestReminder()
+		rem.ChatID, rem.NextTime = chatID, next
+		if mutate != nil {
+			mutate(rem)
+		}
+		require.NoError(t, repo.Create(ctx, rem))
+
+		return rem
+	}
+
+	overdue := create(1, now.Add(-time.Minute), nil)
+	noticed := create(1, now.Add(3*time.Hour), func(r *domain.Reminder) { r.NoticeAt = now.Add(time.Hour) })
+	create(1, now.Add(-2*time.Minute), func(r *domain.Reminder) { r.Paused = true })
+	create(-100, now.Add(-3*time.Minute), nil)
+	require.NoError(t, chatRepo.Upsert(ctx, &domain.Chat{ID: -100, Type: "group", Available: true}))
+	require.NoError(t, chatRepo.SetAvailable(ctx, -100, false))
+
+	ack := &domain.Acknowledgement{ReminderID: overdue.ID, ChatID: 1, Text: "чай", NagEveryMinutes: 15, CreatedAt: now}
+	require.NoError(t, repo.CreateAck(ctx, ack))
+	ack.Delivered(5, now)
+	require.NoError(t, repo.UpdateAck(ctx, ack))
+
+	d := &domain.Delivery{
+		ReminderID: noticed.ID, ChatID: 1, Kind: domain.DeliveryReminder, Occurrence: now, Text: "чай",
+		NextAttemptAt: now.Add(30 * time.Second), Deadline: now.Add(time.Hour), CreatedAt: now,
+	}
+	require.NoError(t, repo.Advance(ctx, noticed, d))
+
+	wakeups, err := repo.ListWakeups(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Wakeup{
+		{Kind: domain.WakeReminder, ID: overdue.ID, At: overdue.NextTime},
+		{Kind: domain.WakeDelivery, ID: d.ID, At: d.NextAttemptAt},
+		{Kind: domain.WakeAck, ID: ack.ID, At: ack.NextNagAt},
+		{Kind: domain.WakeReminder, ID: noticed.ID, At: noticed.NoticeAt},
+		{Kind: domain.WakeReminder, ID: noticed.ID, At: noticed.NextTime},
+	}, wakeups, "paused reminders and unavailable chats do not wake the scheduler")
+
+	wakeups, err = repo.ListWakeups(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, wakeups, 2)
+}
@@ -1,6 +1,11 @@
 package repository
 
-import "github.com/8thgencore/dory-reminder-bot/internal/domain"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
 
 type rowScanner interface {
 	Scan(dest ...any) error
@@ -8,7 +13,8 @@ type rowScanner interface {
 
 func scanReminder(scanner rowScanner) (*domain.Reminder, error) {
 	var reminder domain.Reminder
-	var repeatDays string
+	var repeatDays, times, leadMinutes string
+	var startTime, endsAt, shiftedFrom, noticeAt sql.NullTime
 
 	if err := scanner.Scan(
 		&reminder.ID,
@@ -18,6 +24,29 @@ func scanReminder(scanner rowScanner) (*domain.Reminder, error) {
 		&reminder.Repeat,
 		&repeatDays,
 		&reminder.RepeatEvery,
+		&reminder.MonthOrdinal,
+		&reminder.RRule,
+		&startTime,
+		&reminder.Cron,
+		&reminder.SolarEvent,
+		&reminder.SolarOffset,
+		&reminder.Timezone,
+		&times,
+		&reminder.IntervalMinutes,
+		&reminder.WindowStart,
+		&reminder.WindowEnd,
+		&endsAt,
+		&reminder.RemainingCount,
+		&reminder.WorkdayPolicy,
+		&shiftedFrom,
+		&reminder.NagEveryMinutes,
+		&reminder.NagMax,
+		&leadMinutes,
+		&noticeAt,
+		&reminder.ParentID,
+		&reminder.FollowOffset,
+		&reminder.FollowOn,
+		&reminder.Waiting,
 		&reminder.Paused,
 		&reminder.CreatedAt,
 		&reminder.UpdatedAt,
@@ -26,12 +55,20 @@ func scanReminder(scanner rowScanner) (*domain.Reminder, error) {
 	}
 
 	reminder.RepeatDays = deserializeRepeatDays(repeatDays)
+	reminder.StartTime = startTime.Time
+	reminder.EndsAt = endsAt.Time
+	reminder.ShiftedFrom = shiftedFrom.Time
+	reminder.Times = deserializeRepeatDays(times)
+	reminder.LeadMinutes = deserializeRepeatDays(leadMinutes)
+	reminder.NoticeAt = noticeAt.Time
 
 	return &reminder, nil
 }
 
 func scanChat(scanner rowScanner) (*domain.Chat, error) {
 	var chat domain.Chat
+	var catchUpMinutes int
+	var latitude, longitude sql.NullFloat64
 	if err := scanner.Scan(
 		&chat.ID,
 		&chat.Type,
@@ -39,11 +76,22 @@ func scanChat(scanner rowScanner) (*domain.Chat, error) {
 		&chat.Username,
 		&chat.Timezone,
 		&chat.Available,
+		&chat.CatchUp.Policy,
+		&catchUpMinutes,
+		&chat.Quiet.Start,
+		&chat.Quiet.End,
+		&chat.Quiet.Mode,
+		&latitude,
+		&longitude,
 		&chat.CreatedAt,
 		&chat.UpdatedAt,
 	); err != nil {
 		return nil, err
 	}
+	chat.CatchUp.After = time.Duration(catchUpMinutes) * time.Minute
+	if latitude.Valid && longitude.Valid {
+		chat.Geo = &domain.GeoPoint{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
 
 	return &chat, nil
 }
@@ -19,19 +19,104 @@ const maxAdvanceSteps = 20_000
 //
 // Вся арифметика выполняется в часовом поясе чата с сохранением стенных часов и минут:
 // «каждый день в 9:00» обязано оставаться девятью утра и после перевода часов.
-// Результат возвращается в UTC — в этом виде время хранится в базе.
+// Результат возвращается в UTC — в этом виде время хранится в базе. Когда у серии
+// больше нет срабатываний (COUNT или UNTIL правила RRULE, EndsAt или RemainingCount
+// напоминания), возвращается ErrSeriesEnded. Исключения r.Exceptions учитываются:
+// пропущенные срабатывания перешагиваются, перенесённые срабатывают в новое время.
 func Advance(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	next, err := AdvanceOccurrence(r, after, loc)
+
+	return next.Time, err
+}
+
+// AdvanceOccurrence работает как Advance, но возвращает и время по расписанию
+// перенесённого на рабочий день срабатывания: его нужно сохранить в ShiftedFrom,
+// чтобы следующий шаг серии отсчитывался от него.
+func AdvanceOccurrence(r *domain.Reminder, after time.Time, loc *time.Location) (Occurrence, error) {
+	if err := validateAdvance(r); err != nil {
+		return Occurrence{}, err
+	}
+	if r.RemainingCount == 1 {
+		// NextTime было последним разрешённым срабатыванием.
+		return Occurrence{}, fmt.Errorf("%w: reminder %d has no occurrences left", ErrSeriesEnded, r.ID)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var next Occurrence
+	var err error
+	switch {
+	case r.WorkdayPolicy != domain.WorkdayAny:
+		next, err = nextWithExceptions(r, workdaySeriesAfter(r, after), after, loc)
+	case len(r.Exceptions) > 0 || !r.ShiftedFrom.IsZero():
+		next, err = nextWithExceptions(r, after, after, loc)
+	default:
+		next.Time, err = advanceSeries(r, after, loc)
+	}
+	if err != nil {
+		return Occurrence{}, err
+	}
+	if err := checkEnd(r, next.Time); err != nil {
+		return Occurrence{}, err
+	}
+
+	return next, nil
+}
+
+// validateAdvance отсекает напоминания, для которых следующее время не вычисляется.
+func validateAdvance(r *domain.Reminder) error {
 	if r.Repeat == domain.RepeatNone {
-		return time.Time{}, fmt.Errorf("%w: reminder %d", ErrNotRepeating, r.ID)
+		return fmt.Errorf("%w: reminder %d", ErrNotRepeating, r.ID)
 	}
 	if !r.Repeat.IsValid() {
-		return time.Time{}, fmt.Errorf("%w: unknown repeat type %d", domain.ErrInvalidRepeat, r.Repeat)
+		return fmt.Errorf("%w: unknown repeat type %d", domain.ErrInvalidRepeat, r.Repeat)
 	}
 	if r.Repeat == domain.RepeatEveryNDays && r.RepeatEvery < 1 {
-		return time.Time{}, fmt.Errorf("%w: interval must be at least 1, got %d", ErrInvalidInterval, r.RepeatEvery)
+		return fmt.Errorf("%w: interval must be at least 1, got %d", ErrInvalidInterval, r.RepeatEvery)
 	}
-	if loc == nil {
-		loc = time.UTC
+	if r.Repeat == domain.RepeatEveryMonth && r.MonthOrdinal != 0 {
+		if err := validateMonthWeekday(r.MonthOrdinal, r.RepeatDays); err != nil {
+			return fmt.Errorf("%w: %w", domain.ErrInvalidRepeat, err)
+		}
+	}
+	if !r.WorkdayPolicy.IsValid() {
+		return fmt.Errorf("%w: unknown workday policy %d", domain.ErrInvalidRepeat, r.WorkdayPolicy)
+	}
+
+	return nil
+}
+
+// checkEnd возвращает ErrSeriesEnded, если next позже даты окончания серии.
+func checkEnd(r *domain.Reminder, next time.Time) error {
+	if !r.EndsAt.IsZero() && next.After(r.EndsAt) {
+		return fmt.Errorf("%w: reminder %d ended at %s", ErrSeriesEnded, r.ID, r.EndsAt.UTC())
+	}
+
+	return nil
+}
+
+// advanceSeries выбирает способ сдвига по типу повтора, не учитывая условия окончания.
+func advanceSeries(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	if r.Repeat == domain.RepeatInterval {
+		return advanceInterval(r, after, loc)
+	}
+	if r.Repeat == domain.RepeatCron {
+		// Времена срабатывания целиком задаёт выражение, поэтому шагать от NextTime не нужно.
+		return NextCron(r.Cron, after, loc)
+	}
+	if r.Repeat == domain.RepeatSolar {
+		// Солнечные события от часового пояса не зависят — только от координат.
+		return advanceSolar(r, after)
+	}
+	if r.HasRandomWindow() {
+		return advanceWindow(r, after, loc)
+	}
+	if len(r.Times) > 0 {
+		return advanceTimes(r, after, loc)
+	}
+	if r.Repeat == domain.RepeatRRule {
+		return advanceRRule(r, after, loc)
 	}
 
 	next := r.NextTime.In(loc)
@@ -55,9 +140,14 @@ func advanceOnce(r *domain.Reminder, next time.Time, loc *time.Location) time.Ti
 		return stepDays(next, 1)
 
 	case domain.RepeatEveryWeek:
-		return nextWeekday(next, r.RepeatDays)
+		return nextWeekInSeries(next, r.RepeatDays, r.RepeatStep())
 
 	case domain.RepeatEveryMonth:
+		month := next.Month() + time.Month(r.RepeatStep())
+		if r.MonthOrdinal != 0 {
+			return nthWeekdayInMonth(next.Year(), month, r.MonthOrdinal, r.RepeatDays, next, loc)
+		}
+
 		// RepeatDays хранит исходное число месяца. Опираться на next.Day() нельзя:
 		// оно могло быть обрезано коротким месяцем, и «31-го числа» после февраля
 		// навсегда превратилось бы в «28-го».
@@ -66,7 +156,7 @@ func advanceOnce(r *domain.Reminder, next time.Time, loc *time.Location) time.Ti
 			day = r.RepeatDays[0]
 		}
 
-		return dayInMonth(next.Year(), next.Month()+1, day, next, loc)
+		return dayInMonth(next.Year(), month, day, next, loc)
 
 	case domain.RepeatEveryNDays:
 		return stepDays(next, r.RepeatEvery)
@@ -74,12 +164,70 @@ func advanceOnce(r *domain.Reminder, next time.Time, loc *time.Location) time.Ti
 	case domain.RepeatEveryYear:
 		// 29 февраля в невисокосном году обрезается до 28-го и таким и остаётся:
 		// восстановить исходное число из next уже нельзя.
-		return dayInMonth(next.Year()+1, next.Month(), next.Day(), next, loc)
+		return dayInMonth(next.Year()+r.RepeatStep(), next.Month(), next.Day(), next, loc)
 
-	case domain.RepeatNone:
-		// Отсеивается вызывающим; ветка нужна для полноты switch.
+	case domain.RepeatNone, domain.RepeatRRule, domain.RepeatInterval, domain.RepeatCron, domain.RepeatSolar:
+		// Отсеиваются вызывающим; ветка нужна для полноты switch.
 		return next
 	}
 
 	return next
 }
+
+// advanceTimes ведёт напоминание с несколькими временами в день.
+//
+// Дни повтора перебираются обычным шагом своего типа, привязанным к первому времени дня,
+// а внутри дня выбирается самое раннее время, которое позже и after, и NextTime.
+func advanceTimes(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	current := r.NextTime.In(loc)
+	deadline := after.In(loc)
+	if current.After(deadline) {
+		// Как и в основном цикле Advance: ещё не наступившее срабатывание не сдвигается.
+		deadline = current.Add(-time.Nanosecond)
+	}
+
+	first, last := r.Times[0], r.Times[len(r.Times)-1]
+	day := atMinutes(current, first)
+
+	for steps := 0; !atMinutes(day, last).After(deadline); steps++ {
+		if steps >= maxAdvanceSteps {
+			return time.Time{}, fmt.Errorf("%w: reminder %d did not converge after %d steps",
+				ErrInvalidInterval, r.ID, maxAdvanceSteps)
+		}
+
+		next, err := nextRepeatDay(r, day, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		day = atMinutes(next.In(loc), first)
+	}
+
+	return EarliestTimeInDay(day, deadline, r.Times).UTC(), nil
+}
+
+// nextRepeatDay возвращает следующий после day день повтора с тем же временем суток.
+func nextRepeatDay(r *domain.Reminder, day time.Time, loc *time.Location) (time.Time, error) {
+	if r.Repeat != domain.RepeatRRule {
+		return advanceOnce(r, day, loc), nil
+	}
+
+	start := r.StartTime
+	if start.IsZero() {
+		start = r.NextTime
+	}
+
+	return NextRRule(r.RRule, atMinutes(start.In(loc), minutesOf(day)), day, loc)
+}
+
+// advanceRRule вычисляет следующее срабатывание по правилу RRULE.
+//
+// Шагать от NextTime, как остальные типы, правило не может: COUNT, INTERVAL и BYSETPOS
+// отсчитываются от начала серии, поэтому срабатывания перебираются от StartTime.
+func advanceRRule(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	start := r.StartTime
+	if start.IsZero() {
+		start = r.NextTime
+	}
+
+	return NextRRule(r.RRule, start, after, loc)
+}
@@ -163,6 +163,153 @@ func TestAdvance_MonthlyClampsShortMonths(t *testing.T) {
 	}
 }
 
+func TestAdvance_MonthlyWeekdayAndLastDay(t *testing.T) {
+	loc := berlin(t)
+	workdays := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name    string
+		ordinal int
+		days    []int
+		next    time.Time
+		want    time.Time
+	}{
+		{
+			name:    "второй вторник",
+			ordinal: 2,
+			days:    []int{2},
+			next:    at(loc, 2025, time.June, 10, 9, 0),
+			want:    at(loc, 2025, time.July, 8, 9, 0),
+		},
+		{
+			name:    "последняя пятница",
+			ordinal: domain.MonthOrdinalLast,
+			days:    []int{5},
+			next:    at(loc, 2025, time.June, 27, 9, 0),
+			want:    at(loc, 2025, time.July, 25, 9, 0),
+		},
+		{
+			name:    "последний рабочий день — месяц кончается субботой",
+			ordinal: domain.MonthOrdinalLast,
+			days:    workdays,
+			next:    at(loc, 2025, time.April, 30, 17, 0),
+			want:    at(loc, 2025, time.May, 30, 17, 0),
+		},
+		{
+			name:    "первый рабочий день — месяц начинается воскресеньем",
+			ordinal: 1,
+			days:    workdays,
+			next:    at(loc, 2025, time.May, 1, 9, 0),
+			want:    at(loc, 2025, time.June, 2, 9, 0),
+		},
+		{
+			name:    "последний день месяца",
+			ordinal: 0,
+			days:    []int{domain.LastMonthDay},
+			next:    at(loc, 2025, time.January, 31, 9, 0),
+			want:    at(loc, 2025, time.February, 28, 9, 0),
+		},
+		{
+			name:    "последний день после февраля возвращается к 31-му",
+			ordinal: 0,
+			days:    []int{domain.LastMonthDay},
+			next:    at(loc, 2025, time.February, 28, 9, 0),
+			want:    at(loc, 2025, time.March, 31, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.Reminder{
+				Repeat:       domain.RepeatEveryMonth,
+				RepeatDays:   tt.days,
+				MonthOrdinal: tt.ordinal,
+				NextTime:     tt.next.UTC(),
+			}
+
+			got, err := Advance(r, tt.next, loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got.In(loc))
+		})
+	}
+}
+
+func TestAdvance_SeveralTimesPerDay(t *testing.T) {
+	loc := berlin(t)
+	times := []int{9 * 60, 13 * 60, 21 * 60}
+
+	tests := []struct {
+		name   string
+		repeat domain.RepeatType
+		days   []int
+		rrule  string
+		next   time.Time
+		after  time.Time
+		want   time.Time
+	}{
+		{
+			name:   "следующее время того же дня",
+			repeat: domain.RepeatEveryDay,
+			next:   at(loc, 2025, time.June, 10, 9, 0),
+			after:  at(loc, 2025, time.June, 10, 9, 0),
+			want:   at(loc, 2025, time.June, 10, 13, 0),
+		},
+		{
+			name:   "после последнего времени — первое время следующего дня",
+			repeat: domain.RepeatEveryDay,
+			next:   at(loc, 2025, time.June, 10, 21, 0),
+			after:  at(loc, 2025, time.June, 10, 21, 0),
+			want:   at(loc, 2025, time.June, 11, 9, 0),
+		},
+		{
+			name:   "по будням пятничный вечер переходит на понедельник",
+			repeat: domain.RepeatEveryWeek,
+			days:   []int{1, 2, 3, 4, 5},
+			next:   at(loc, 2025, time.June, 13, 21, 0),
+			after:  at(loc, 2025, time.June, 13, 21, 0),
+			want:   at(loc, 2025, time.June, 16, 9, 0),
+		},
+		{
+			name:   "догоняет простой внутри дня",
+			repeat: domain.RepeatEveryDay,
+			next:   at(loc, 2025, time.June, 1, 9, 0),
+			after:  at(loc, 2025, time.June, 10, 14, 30),
+			want:   at(loc, 2025, time.June, 10, 21, 0),
+		},
+		{
+			name:   "будущее срабатывание не сдвигается",
+			repeat: domain.RepeatEveryDay,
+			next:   at(loc, 2025, time.June, 10, 13, 0),
+			after:  at(loc, 2025, time.June, 10, 8, 0),
+			want:   at(loc, 2025, time.June, 10, 13, 0),
+		},
+		{
+			name:   "правило RRULE",
+			repeat: domain.RepeatRRule,
+			rrule:  "FREQ=WEEKLY;BYDAY=MO,TH",
+			next:   at(loc, 2025, time.June, 2, 21, 0),
+			after:  at(loc, 2025, time.June, 2, 21, 0),
+			want:   at(loc, 2025, time.June, 5, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.Reminder{
+				Repeat:     tt.repeat,
+				RepeatDays: tt.days,
+				RRule:      tt.rrule,
+				Times:      times,
+				NextTime:   tt.next.UTC(),
+			}
+
+			got, err := Advance(r, tt.after, loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got.In(loc))
+		})
+	}
+}
+
 func TestAdvance_Yearly(t *testing.T) {
 	loc := berlin(t)
 
@@ -196,6 +343,143 @@ func TestAdvance_EveryNDays(t *testing.T) {
 	assert.True(t, at(loc, 2025, time.June, 13, 9, 0).Equal(got))
 }
 
+func TestAdvance_EveryNUnits(t *testing.T) {
+	loc := berlin(t)
+
+	tests := []struct {
+		name    string
+		repeat  domain.RepeatType
+		every   int
+		days    []int
+		ordinal int
+		next    time.Time
+		after   time.Time
+		want    time.Time
+	}{
+		{
+			name:   "раз в 2 недели — следующий день той же недели",
+			repeat: domain.RepeatEveryWeek,
+			every:  2,
+			days:   []int{1, 4},
+			next:   at(loc, 2025, time.June, 9, 9, 0),
+			want:   at(loc, 2025, time.June, 12, 9, 0),
+		},
+		{
+			name:   "раз в 2 недели — с четверга через неделю на понедельник",
+			repeat: domain.RepeatEveryWeek,
+			every:  2,
+			days:   []int{1, 4},
+			next:   at(loc, 2025, time.June, 12, 9, 0),
+			want:   at(loc, 2025, time.June, 23, 9, 0),
+		},
+		{
+			name:   "воскресенье завершает неделю",
+			repeat: domain.RepeatEveryWeek,
+			every:  2,
+			days:   []int{0},
+			next:   at(loc, 2025, time.June, 15, 9, 0),
+			want:   at(loc, 2025, time.June, 29, 9, 0),
+		},
+		{
+			name:   "догоняет простой, не сбивая чётность недель",
+			repeat: domain.RepeatEveryWeek,
+			every:  2,
+			days:   []int{1},
+			next:   at(loc, 2025, time.June, 2, 9, 0),
+			after:  at(loc, 2025, time.June, 20, 9, 0),
+			want:   at(loc, 2025, time.June, 30, 9, 0),
+		},
+		{
+			name:   "раз в 3 месяца 15-го числа",
+			repeat: domain.RepeatEveryMonth,
+			every:  3,
+			days:   []int{15},
+			next:   at(loc, 2025, time.January, 15, 9, 0),
+			want:   at(loc, 2025, time.April, 15, 9, 0),
+		},
+		{
+			name:   "раз в 2 месяца 31-го через короткий месяц",
+			repeat: domain.RepeatEveryMonth,
+			every:  2,
+			days:   []int{31},
+			next:   at(loc, 2025, time.January, 31, 9, 0),
+			want:   at(loc, 2025, time.March, 31, 9, 0),
+		},
+		{
+			name:    "раз в 2 месяца во второй вторник",
+			repeat:  domain.RepeatEveryMonth,
+			every:   2,
+			days:    []int{2},
+			ordinal: 2,
+			next:    at(loc, 2025, time.June, 10, 9, 0),
+			want:    at(loc, 2025, time.August, 12, 9, 0),
+		},
+		{
+			name:   "раз в 2 года",
+			repeat: domain.RepeatEveryYear,
+			every:  2,
+			next:   at(loc, 2025, time.June, 10, 9, 0),
+			want:   at(loc, 2027, time.June, 10, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.Reminder{
+				Repeat:       tt.repeat,
+				RepeatEvery:  tt.every,
+				RepeatDays:   tt.days,
+				MonthOrdinal: tt.ordinal,
+				NextTime:     tt.next.UTC(),
+			}
+			after := tt.after
+			if after.IsZero() {
+				after = tt.next
+			}
+
+			got, err := Advance(r, after, loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got.In(loc))
+		})
+	}
+}
+
+func TestAdvance_EndConditions(t *testing.T) {
+	loc := berlin(t)
+	next := at(loc, 2025, time.June, 10, 9, 0)
+
+	t.Run("до даты окончания включительно", func(t *testing.T) {
+		r := &domain.Reminder{
+			Repeat:   domain.RepeatEveryDay,
+			NextTime: next.UTC(),
+			EndsAt:   at(loc, 2025, time.June, 11, 9, 0).UTC(),
+		}
+
+		got, err := Advance(r, next, loc)
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.June, 11, 9, 0).Equal(got))
+
+		r.NextTime = got
+		_, err = Advance(r, got, loc)
+		assert.ErrorIs(t, err, ErrSeriesEnded)
+	})
+
+	t.Run("последнее из оставшихся срабатываний", func(t *testing.T) {
+		r := &domain.Reminder{Repeat: domain.RepeatEveryDay, NextTime: next.UTC(), RemainingCount: 1}
+
+		_, err := Advance(r, next, loc)
+		assert.ErrorIs(t, err, ErrSeriesEnded)
+	})
+
+	t.Run("срабатывания ещё остались", func(t *testing.T) {
+		r := &domain.Reminder{Repeat: domain.RepeatInterval, IntervalMinutes: 8 * 60, NextTime: next.UTC(), RemainingCount: 2}
+
+		got, err := Advance(r, next, loc)
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.June, 10, 17, 0).Equal(got))
+	})
+}
+
 // Бот мог простоять неделю: Advance обязан догнать пропущенные срабатывания за один вызов
 // и вернуть время строго в будущем.
 func TestAdvance_CatchesUpAfterDowntime(t *testing.T) {
@@ -233,6 +517,13 @@ func TestAdvance_Errors(t *testing.T) {
 			reminder: &domain.Reminder{Repeat: domain.RepeatEveryNDays, RepeatEvery: 0, NextTime: now},
 			wantErr:  ErrInvalidInterval,
 		},
+		{
+			name: "N-й день недели без дней недели",
+			reminder: &domain.Reminder{
+				Repeat: domain.RepeatEveryMonth, MonthOrdinal: 2, NextTime: now,
+			},
+			wantErr: domain.ErrInvalidRepeat,
+		},
 		{
 			name:     "неизвестный тип повтора",
 			reminder: &domain.Reminder{Repeat: domain.RepeatType(42), NextTime: now},
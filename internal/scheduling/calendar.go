@@ -2,6 +2,7 @@ package scheduling
 
 import (
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -22,6 +23,16 @@ func atClock(base, clock time.Time) time.Time {
 	)
 }
 
+// atMinutes берёт дату из base и ставит время суток, заданное минутами от полуночи.
+func atMinutes(base time.Time, minutes int) time.Time {
+	return time.Date(base.Year(), base.Month(), base.Day(), minutes/60, minutes%60, 0, 0, base.Location())
+}
+
+// minutesOf возвращает время суток t в минутах от полуночи.
+func minutesOf(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
 // stepDays сдвигает время на n дней вперёд, заново привязывая стенные часы и минуты.
 //
 // Именно из-за этого нельзя складывать с 24*time.Hour: на переходе на летнее время
@@ -40,16 +51,59 @@ func daysInMonth(year int, month time.Month) int {
 //
 // Обрезка, а не перенос: time.Date для 31 февраля вернул бы 3 марта, и ежемесячное
 // напоминание «31-го числа» уезжало бы в следующий месяц. month вне 1..12 нормализуется,
-// поэтому вызывающий может смело передавать now.Month()+1.
+// поэтому вызывающий может смело передавать now.Month()+1. Отрицательное число
+// отсчитывается от конца месяца: -1 — последний день.
 func dayInMonth(year int, month time.Month, day int, clock time.Time, loc *time.Location) time.Time {
 	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
-	if maxDay := daysInMonth(first.Year(), first.Month()); day > maxDay {
+	maxDay := daysInMonth(first.Year(), first.Month())
+	if day < 0 {
+		day = max(1, maxDay+day+1)
+	}
+	if day > maxDay {
 		day = maxDay
 	}
 
 	return time.Date(first.Year(), first.Month(), day, clock.Hour(), clock.Minute(), 0, 0, loc)
 }
 
+// nthWeekdayInMonth возвращает ordinal-й по счёту день месяца, чей день недели входит
+// в weekdays; отрицательный ordinal считает с конца месяца.
+//
+// Каждый день недели встречается в месяце не меньше четырёх раз, поэтому для ordinal
+// в 1..4 и -1 результат есть всегда. Если дней не нашлось (пустой weekdays), возвращается
+// последний день месяца — вызывающий обязан проверить параметры заранее.
+func nthWeekdayInMonth(
+	year int,
+	month time.Month,
+	ordinal int,
+	weekdays []int,
+	clock time.Time,
+	loc *time.Location,
+) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	maxDay := daysInMonth(first.Year(), first.Month())
+
+	var matches []int
+	for day := 1; day <= maxDay; day++ {
+		weekday := int(first.AddDate(0, 0, day-1).Weekday())
+		if slices.Contains(weekdays, weekday) {
+			matches = append(matches, day)
+		}
+	}
+
+	idx := ordinal - 1
+	if ordinal < 0 {
+		idx = len(matches) + ordinal
+	}
+
+	day := maxDay
+	if idx >= 0 && idx < len(matches) {
+		day = matches[idx]
+	}
+
+	return time.Date(first.Year(), first.Month(), day, clock.Hour(), clock.Minute(), 0, 0, loc)
+}
+
 // parseDayMonth разбирает строку ДД.ММ.
 func parseDayMonth(s string) (int, time.Month, error) {
 	if len(s) != len(dayMonthLayout) {
@@ -92,3 +146,25 @@ func nextWeekday(t time.Time, days []int) time.Time {
 
 	return stepDays(t, best)
 }
+
+// nextWeekInSeries — nextWeekday для повтора «раз в step недель».
+//
+// Внутри недели дни идут подряд, а переход на следующую неделю перескакивает ещё
+// step-1 недель. Неделя начинается с понедельника, и серия привязана к неделе t:
+// отдельная точка отсчёта не хранится, потому что каждое срабатывание и так лежит
+// в активной неделе.
+func nextWeekInSeries(t time.Time, days []int, step int) time.Time {
+	next := nextWeekday(t, days)
+	if step <= 1 || weekStart(next).Equal(weekStart(t)) {
+		return next
+	}
+
+	return stepDays(next, 7*(step-1))
+}
+
+// weekStart возвращает полночь понедельника недели, в которую попадает t.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
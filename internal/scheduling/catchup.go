@@ -0,0 +1,35 @@
+package scheduling
+
+import (
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// Missed перечисляет срабатывания, наступившие к now: текущее r.NextTime и все
+// последующие не позже now, — то, что пришлось на простой бота. Возвращает не больше
+// limit времён в UTC; исключения, рабочие дни и условия окончания учитываются так же,
+// как в Advance. Разовое напоминание даёт одно время.
+func Missed(r *domain.Reminder, now time.Time, loc *time.Location, limit int) []time.Time {
+	cur := *r
+	var missed []time.Time
+
+	for len(missed) < limit && !cur.NextTime.After(now) {
+		missed = append(missed, cur.NextTime.UTC())
+		if cur.Repeat == domain.RepeatNone {
+			break
+		}
+
+		next, err := AdvanceOccurrence(&cur, cur.NextTime, loc)
+		if err != nil {
+			// Конец серии или битое расписание: дальше срабатываний нет.
+			break
+		}
+		next.Apply(&cur)
+		if cur.RemainingCount > 0 {
+			cur.RemainingCount--
+		}
+	}
+
+	return missed
+}
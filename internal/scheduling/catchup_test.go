@@ -0,0 +1,45 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissed(t *testing.T) {
+	loc := berlin(t)
+	daily := func() *domain.Reminder {
+		return &domain.Reminder{Repeat: domain.RepeatEveryDay, NextTime: at(loc, 2025, time.June, 10, 9, 0).UTC()}
+	}
+	now := at(loc, 2025, time.June, 12, 12, 0)
+
+	t.Run("все срабатывания простоя", func(t *testing.T) {
+		assert.Equal(t, []time.Time{
+			at(loc, 2025, time.June, 10, 9, 0).UTC(),
+			at(loc, 2025, time.June, 11, 9, 0).UTC(),
+			at(loc, 2025, time.June, 12, 9, 0).UTC(),
+		}, Missed(daily(), now, loc, 10))
+	})
+
+	t.Run("лимит", func(t *testing.T) {
+		assert.Len(t, Missed(daily(), now, loc, 2), 2)
+	})
+
+	t.Run("исключения и остаток серии", func(t *testing.T) {
+		r := daily()
+		r.Exceptions = []domain.OccurrenceException{{Occurrence: at(loc, 2025, time.June, 11, 9, 0).UTC()}}
+		r.RemainingCount = 2
+
+		assert.Equal(t, []time.Time{
+			at(loc, 2025, time.June, 10, 9, 0).UTC(),
+			at(loc, 2025, time.June, 12, 9, 0).UTC(),
+		}, Missed(r, now, loc, 10))
+	})
+
+	t.Run("разовое", func(t *testing.T) {
+		r := &domain.Reminder{Repeat: domain.RepeatNone, NextTime: at(loc, 2025, time.June, 10, 9, 0).UTC()}
+		assert.Equal(t, []time.Time{r.NextTime}, Missed(r, now, loc, 10))
+	})
+}
@@ -0,0 +1,290 @@
+package scheduling
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// cronSearchDays — на сколько дней вперёд ищется срабатывание. Реже всего срабатывает
+// «29 февраля»: между високосными годами бывает восемь лет, как 1896–1904.
+const cronSearchDays = 8*366 + 1
+
+// cronMacros — сокращения, которые понимает crontab.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronField описывает одно поле выражения: допустимый диапазон и имена значений.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonthNames},
+	// Воскресенье можно записать и как 0, и как 7.
+	{name: "day of week", min: 0, max: 7, names: cronWeekdayNames},
+}
+
+// Cron — разобранное выражение cron: минута, час, число месяца, месяц и день недели.
+// Поля хранятся списками подходящих значений по возрастанию.
+//
+// Как в Vixie cron, если ограничены и число месяца, и день недели, день подходит
+// по любому из них: «0 9 1 * 1» — первое число и каждый понедельник.
+type Cron struct {
+	Minutes     []int
+	Hours       []int
+	DaysOfMonth []int // 1..31
+	Months      []int // 1..12
+	Weekdays    []int // 0..6, 0 — воскресенье
+	// AnyDayOfMonth и AnyWeekday — поле начинается со звёздочки и день не ограничивает.
+	AnyDayOfMonth bool
+	AnyWeekday    bool
+}
+
+// ParseCron разбирает выражение из пяти полей через пробел или макрос (@hourly, @daily,
+// @weekly, @monthly, @yearly). Поле — список через запятую из «*», чисел, диапазонов
+// «1-5» и шагов «*/15», «9-17/2»; месяцы и дни недели можно писать по-английски: jan, mon.
+//
+// Срабатывания чаще раза в domain.MinIntervalMinutes минут не допускаются, как и у
+// интервального повтора. Все ошибки оборачивают domain.ErrInvalidRepeat.
+func ParseCron(expr string) (*Cron, error) {
+	s := strings.ToLower(strings.TrimSpace(expr))
+	if macro, ok := cronMacros[s]; ok {
+		s = macro
+	} else if strings.HasPrefix(s, "@") {
+		return nil, fmt.Errorf("%w: unsupported cron macro %q", domain.ErrInvalidRepeat, s)
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w: cron expression must have %d fields, got %d",
+			domain.ErrInvalidRepeat, len(cronFields), len(fields))
+	}
+
+	var values [len(cronFields)][]int
+	for i, field := range cronFields {
+		parsed, err := field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: cron %s: %v", domain.ErrInvalidRepeat, field.name, err)
+		}
+		values[i] = parsed
+	}
+
+	c := &Cron{
+		Minutes:       values[0],
+		Hours:         values[1],
+		DaysOfMonth:   values[2],
+		Months:        values[3],
+		Weekdays:      sundayFirst(values[4]),
+		AnyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		AnyWeekday:    strings.HasPrefix(fields[4], "*"),
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("%w: cron: %v", domain.ErrInvalidRepeat, err)
+	}
+
+	return c, nil
+}
+
+// parse разбирает поле в отсортированный список значений.
+func (f cronField) parse(s string) ([]int, error) {
+	seen := make([]bool, f.max+1)
+	for item := range strings.SplitSeq(s, ",") {
+		span, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 || n > f.max {
+				return nil, fmt.Errorf("step %q is out of range 1..%d", stepText, f.max)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if span != "*" {
+			from, to, isRange := strings.Cut(span, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return nil, err
+			}
+			switch {
+			case isRange:
+				if hi, err = f.value(to); err != nil {
+					return nil, err
+				}
+			case !hasStep:
+				// «5/15» — с пятой минуты до конца часа, просто «5» — только пятая.
+				hi = lo
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("range %q is reversed", span)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			seen[v] = true
+		}
+	}
+
+	var out []int
+	for v, ok := range seen {
+		if ok {
+			out = append(out, v)
+		}
+	}
+
+	return out, nil
+}
+
+// value разбирает одно значение поля: число или имя.
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[s]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %q is out of range %d..%d", s, f.min, f.max)
+	}
+
+	return n, nil
+}
+
+// sundayFirst сводит воскресенье-7 к воскресенью-0.
+func sundayFirst(days []int) []int {
+	if len(days) == 0 || days[len(days)-1] != 7 {
+		return days
+	}
+	out := days[:len(days)-1]
+	if !slices.Contains(out, 0) {
+		out = slices.Insert(out, 0, 0)
+	}
+
+	return out
+}
+
+// validate отсекает выражения, которые срабатывают слишком часто или не срабатывают вовсе.
+func (c *Cron) validate() error {
+	if gap := c.minGap(); gap < domain.MinIntervalMinutes {
+		return fmt.Errorf("fires every %d minutes, at least %d are required", gap, domain.MinIntervalMinutes)
+	}
+	if c.AnyWeekday || c.AnyDayOfMonth {
+		// При ограниченном дне недели день найдётся всегда, а числа месяца вроде
+		// «30 февраля» могут не встретиться никогда.
+		for _, month := range c.Months {
+			if c.DaysOfMonth[0] <= daysInMonth(2024, time.Month(month)) {
+				return nil
+			}
+		}
+
+		return errors.New("day of month never occurs in the selected months")
+	}
+
+	return nil
+}
+
+// minGap — наименьший промежуток между соседними срабатываниями в пределах суток.
+// Переход через границу часа учитывается, только если выбраны соседние часы.
+func (c *Cron) minGap() int {
+	gap := domain.MinutesPerDay
+	for i := 1; i < len(c.Minutes); i++ {
+		gap = min(gap, c.Minutes[i]-c.Minutes[i-1])
+	}
+	for i, h := range c.Hours {
+		next := c.Hours[(i+1)%len(c.Hours)]
+		if next == (h+1)%24 {
+			gap = min(gap, c.Minutes[0]+60-c.Minutes[len(c.Minutes)-1])
+		}
+	}
+
+	return gap
+}
+
+// matchesDay сообщает, есть ли в этот день срабатывания.
+func (c *Cron) matchesDay(day time.Time) bool {
+	if !slices.Contains(c.Months, int(day.Month())) {
+		return false
+	}
+	byMonthDay := slices.Contains(c.DaysOfMonth, day.Day())
+	byWeekday := slices.Contains(c.Weekdays, int(day.Weekday()))
+	if c.AnyDayOfMonth || c.AnyWeekday {
+		return byMonthDay && byWeekday
+	}
+
+	return byMonthDay || byWeekday
+}
+
+// NextCron возвращает первое срабатывание выражения cron строго позже after.
+func NextCron(expr string, after time.Time, loc *time.Location) (time.Time, error) {
+	c, err := ParseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.Next(after, loc)
+}
+
+// Next возвращает первое срабатывание строго позже after в UTC.
+//
+// Выражение читается по стенным часам loc. Время, которого нет из-за перевода часов
+// вперёд, пропускается, а повторившееся при переводе назад срабатывает один раз:
+// стенные минуты перебираются по возрастанию, и каждая даёт не больше одного момента.
+func (c *Cron) Next(after time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := after.In(loc)
+	// Стенная минута after: в его день ищутся только более поздние, иначе повторившийся
+	// час при переводе назад сработал бы дважды.
+	from := local.Hour()*60 + local.Minute()
+	first := civilOf(local)
+
+	for i := range cronSearchDays {
+		day := first.AddDate(0, 0, i)
+		if !c.matchesDay(day) {
+			continue
+		}
+		for _, hour := range c.Hours {
+			for _, minute := range c.Minutes {
+				if i == 0 && hour*60+minute <= from {
+					continue
+				}
+				t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+				if t.Hour() != hour || t.Minute() != minute {
+					// Такого времени в этот день нет: часы переведены вперёд.
+					continue
+				}
+				if t.After(after) {
+					return t.UTC(), nil
+				}
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: cron expression has no occurrences within %d days",
+		domain.ErrInvalidRepeat, cronSearchDays)
+}
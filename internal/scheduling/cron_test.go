@@ -0,0 +1,143 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextCron(t *testing.T) {
+	loc := berlin(t)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "по будням в 9:00, после пятницы — понедельник",
+			expr:  "0 9 * * 1-5",
+			after: at(loc, 2025, time.June, 13, 9, 0),
+			want:  at(loc, 2025, time.June, 16, 9, 0),
+		},
+		{
+			name:  "каждые 15 минут в рабочие часы",
+			expr:  "*/15 9-17 * * *",
+			after: at(loc, 2025, time.June, 13, 17, 45),
+			want:  at(loc, 2025, time.June, 14, 9, 0),
+		},
+		{
+			name:  "число месяца или день недели",
+			expr:  "0 9 1 * mon",
+			after: at(loc, 2025, time.June, 24, 9, 0),
+			want:  at(loc, 2025, time.June, 30, 9, 0),
+		},
+		{
+			name:  "воскресенье как 7",
+			expr:  "30 10 * * 7",
+			after: at(loc, 2025, time.June, 13, 9, 0),
+			want:  at(loc, 2025, time.June, 15, 10, 30),
+		},
+		{
+			name:  "месяцы по именам",
+			expr:  "0 8 1 jan,jul *",
+			after: at(loc, 2025, time.June, 13, 9, 0),
+			want:  at(loc, 2025, time.July, 1, 8, 0),
+		},
+		{
+			name:  "29 февраля ждёт високосного года",
+			expr:  "0 12 29 2 *",
+			after: at(loc, 2025, time.June, 13, 9, 0),
+			want:  at(loc, 2028, time.February, 29, 12, 0),
+		},
+		{
+			name:  "макрос",
+			expr:  "@monthly",
+			after: at(loc, 2025, time.June, 13, 9, 0),
+			want:  at(loc, 2025, time.July, 1, 0, 0),
+		},
+		{
+			name:  "секунды после срабатывания",
+			expr:  "0 9 * * *",
+			after: at(loc, 2025, time.June, 13, 9, 0).Add(30 * time.Second),
+			want:  at(loc, 2025, time.June, 14, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextCron(tt.expr, tt.after, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UTC(), got)
+		})
+	}
+}
+
+func TestNextCron_SkipsNonexistentLocalTime(t *testing.T) {
+	loc := berlin(t)
+
+	// 30 марта 2025 года в Берлине часы переводятся с 02:00 на 03:00.
+	got, err := NextCron("30 2 * * *", at(loc, 2025, time.March, 29, 3, 0), loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2025, time.March, 31, 2, 30).UTC(), got)
+}
+
+func TestNextCron_RepeatedLocalTimeFiresOnce(t *testing.T) {
+	loc := berlin(t)
+
+	// 26 октября 2025 года в Берлине час с 02:00 до 03:00 проходит дважды.
+	after := at(loc, 2025, time.October, 25, 23, 59)
+	end := at(loc, 2025, time.October, 27, 0, 0)
+	seen := make(map[string]bool)
+	for {
+		next, err := NextCron("*/30 * * * *", after, loc)
+		require.NoError(t, err)
+		if !next.Before(end) {
+			break
+		}
+		require.True(t, next.After(after))
+		wall := next.In(loc).Format("15:04")
+		assert.False(t, seen[wall], "%s fired twice", wall)
+		seen[wall] = true
+		after = next
+	}
+	assert.Len(t, seen, 48)
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 9 * *",
+		"0 25 * * *",
+		"0 9 5-1 * *",
+		"0 9 * * */0",
+		"0 9 * foo *",
+		"* * * * *",
+		"0,3 9 * * *",
+		"0 9 30 2 *",
+		"@reboot",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			assert.ErrorIs(t, err, domain.ErrInvalidRepeat)
+		})
+	}
+}
+
+func TestAdvance_Cron(t *testing.T) {
+	loc := berlin(t)
+	r := &domain.Reminder{
+		ID:       1,
+		Repeat:   domain.RepeatCron,
+		Cron:     "0 9 * * 1-5",
+		NextTime: at(loc, 2025, time.June, 13, 9, 0).UTC(),
+	}
+
+	next, err := Advance(r, r.NextTime, loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2025, time.June, 16, 9, 0).UTC(), next)
+}
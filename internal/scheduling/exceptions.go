@@ -0,0 +1,169 @@
+package scheduling
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// SeriesTime возвращает время по расписанию серии, которому соответствует NextTime.
+//
+// Если ближайшее срабатывание перенесено исключением или на рабочий день, NextTime
+// хранит новое время, а шагать по расписанию нужно от исходного: иначе перенос одной
+// встречи сдвинул бы всю серию.
+func SeriesTime(r *domain.Reminder) time.Time {
+	for _, e := range r.Exceptions {
+		if !e.IsSkip() && e.MovedTo.Equal(r.NextTime) {
+			return e.Occurrence
+		}
+	}
+	if !r.ShiftedFrom.IsZero() {
+		return r.ShiftedFrom
+	}
+
+	return r.NextTime
+}
+
+// NextOccurrence пересчитывает NextTime после изменения исключений.
+//
+// В отличие от Advance, текущее срабатывание серии не считается прошедшим: результатом
+// будет оно само, если его не пропустили и не перенесли. Перенесённые срабатывания
+// и срабатывания в режиме «только по рабочим дням» учитываются, только если они позже
+// now. RemainingCount не проверяется — исключение не расходует срабатываний.
+//
+// NextTime должен указывать на время серии или на перенос из r.Exceptions: заменяя
+// исключение для текущего срабатывания, вызывающий сначала возвращает NextTime
+// к SeriesTime.
+//
+// Тем же способом выравнивается на рабочий день первое срабатывание нового напоминания.
+func NextOccurrence(r *domain.Reminder, now time.Time, loc *time.Location) (Occurrence, error) {
+	if err := validateAdvance(r); err != nil {
+		return Occurrence{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	next, err := nextWithExceptions(r, SeriesTime(r).Add(-time.Nanosecond), now, loc)
+	if err != nil {
+		return Occurrence{}, err
+	}
+	if err := checkEnd(r, next.Time); err != nil {
+		return Occurrence{}, err
+	}
+
+	return next, nil
+}
+
+// IsOccurrence сообщает, входит ли t в расписание серии и не раньше ли оно текущего
+// срабатывания. Исключения при проверке не учитываются: перенести можно и уже
+// пропущенное срабатывание.
+func IsOccurrence(r *domain.Reminder, t time.Time, loc *time.Location) bool {
+	if validateAdvance(r) != nil {
+		return false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	current := SeriesTime(r)
+	if t.Before(current) || checkEnd(r, t) != nil {
+		return false
+	}
+
+	probe := *r
+	probe.NextTime = current
+	probe.Exceptions = nil
+	next, err := advanceSeries(&probe, t.Add(-time.Nanosecond), loc)
+
+	return err == nil && next.Equal(t)
+}
+
+// nextWithExceptions ищет ближайшее срабатывание с учётом исключений и календаря: первое
+// время серии позже after, которое не пропущено и не перенесено, или самое раннее
+// перенесённое позже movedAfter — что наступит раньше. В режиме «только по рабочим
+// дням» время серии переносится или пропускается по WorkdayPolicy и тоже должно
+// оказаться позже movedAfter.
+func nextWithExceptions(r *domain.Reminder, after, movedAfter time.Time, loc *time.Location) (Occurrence, error) {
+	probe := *r
+	probe.NextTime = SeriesTime(r)
+
+	// Каждое исключение отменяет не больше одного времени серии, поэтому без календаря
+	// len(r.Exceptions)+1 шагов всегда хватает. Нерабочие дни календаря так не
+	// ограничить — для них действует общий предел догоняющего цикла.
+	limit := len(r.Exceptions) + 1
+	if r.WorkdayPolicy != domain.WorkdayAny {
+		limit = maxAdvanceSteps
+	}
+
+	var regular Occurrence
+	exhausted := true
+	for range limit {
+		next, err := advanceSeries(&probe, after, loc)
+		if errors.Is(err, ErrSeriesEnded) {
+			// Серия исчерпана, но перенесённое срабатывание ещё может быть впереди.
+			exhausted = false
+			break
+		}
+		if err != nil {
+			return Occurrence{}, err
+		}
+		probe.NextTime, after = next, next
+		if hasException(r.Exceptions, next) {
+			continue
+		}
+
+		fire, ok := onWorkday(r, next, loc)
+		if !ok || (r.WorkdayPolicy != domain.WorkdayAny && !fire.After(movedAfter)) {
+			// Перенос мог совпасть с уже прошедшим срабатыванием — тогда оно не повторяется.
+			continue
+		}
+		regular = Occurrence{Time: fire}
+		if !fire.Equal(next) {
+			regular.ShiftedFrom = next
+		}
+		exhausted = false
+		break
+	}
+	if exhausted && r.WorkdayPolicy != domain.WorkdayAny {
+		return Occurrence{}, fmt.Errorf("%w: reminder %d found none in %d occurrences", ErrNoWorkdays, r.ID, limit)
+	}
+
+	moved := earliestMove(r.Exceptions, movedAfter)
+	switch {
+	case regular.Time.IsZero() && moved.IsZero():
+		return Occurrence{}, fmt.Errorf("%w: reminder %d has no occurrences left after exceptions", ErrSeriesEnded, r.ID)
+	case regular.Time.IsZero(), !moved.IsZero() && moved.Before(regular.Time):
+		return Occurrence{Time: moved}, nil
+	}
+
+	return regular, nil
+}
+
+// hasException сообщает, есть ли исключение для времени серии t.
+func hasException(exceptions []domain.OccurrenceException, t time.Time) bool {
+	for _, e := range exceptions {
+		if e.Occurrence.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// earliestMove возвращает самое раннее перенесённое время позже after или нулевое.
+func earliestMove(exceptions []domain.OccurrenceException, after time.Time) time.Time {
+	var moved time.Time
+	for _, e := range exceptions {
+		if e.IsSkip() || !e.MovedTo.After(after) {
+			continue
+		}
+		if moved.IsZero() || e.MovedTo.Before(moved) {
+			moved = e.MovedTo
+		}
+	}
+
+	return moved.UTC()
+}
@@ -0,0 +1,169 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weeklyMeeting — встреча по вторникам в 10:00; 3 июня 2025 года — вторник.
+func weeklyMeeting(next time.Time, exceptions ...domain.OccurrenceException) *domain.Reminder {
+	return &domain.Reminder{
+		Repeat:     domain.RepeatEveryWeek,
+		RepeatDays: []int{int(time.Tuesday)},
+		NextTime:   next.UTC(),
+		Exceptions: exceptions,
+	}
+}
+
+func skipAt(t time.Time) domain.OccurrenceException {
+	return domain.OccurrenceException{Occurrence: t.UTC()}
+}
+
+func moveAt(from, to time.Time) domain.OccurrenceException {
+	return domain.OccurrenceException{Occurrence: from.UTC(), MovedTo: to.UTC()}
+}
+
+func TestAdvance_Exceptions(t *testing.T) {
+	loc := berlin(t)
+	jun3 := at(loc, 2025, time.June, 3, 10, 0)
+	jun10 := at(loc, 2025, time.June, 10, 10, 0)
+	jun17 := at(loc, 2025, time.June, 17, 10, 0)
+
+	tests := []struct {
+		name string
+		r    *domain.Reminder
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "пропущенное срабатывание перешагивается",
+			r:    weeklyMeeting(jun3, skipAt(jun10)),
+			now:  jun3,
+			want: jun17,
+		},
+		{
+			name: "перенос на более позднее время",
+			r:    weeklyMeeting(jun3, moveAt(jun10, at(loc, 2025, time.June, 11, 15, 0))),
+			now:  jun3,
+			want: at(loc, 2025, time.June, 11, 15, 0),
+		},
+		{
+			name: "после перенесённого серия идёт по расписанию",
+			r: weeklyMeeting(at(loc, 2025, time.June, 11, 15, 0),
+				moveAt(jun10, at(loc, 2025, time.June, 11, 15, 0))),
+			now:  at(loc, 2025, time.June, 11, 15, 0),
+			want: jun17,
+		},
+		{
+			name: "перенос на более раннее время",
+			r:    weeklyMeeting(jun3, moveAt(jun10, at(loc, 2025, time.June, 9, 10, 0))),
+			now:  jun3,
+			want: at(loc, 2025, time.June, 9, 10, 0),
+		},
+		{
+			name: "исходное время перенесённого на раньше не срабатывает",
+			r: weeklyMeeting(at(loc, 2025, time.June, 9, 10, 0),
+				moveAt(jun10, at(loc, 2025, time.June, 9, 10, 0))),
+			now:  at(loc, 2025, time.June, 9, 10, 0),
+			want: jun17,
+		},
+		{
+			name: "несколько пропусков подряд",
+			r:    weeklyMeeting(jun3, skipAt(jun10), skipAt(jun17)),
+			now:  jun3,
+			want: at(loc, 2025, time.June, 24, 10, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Advance(tt.r, tt.now, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UTC(), got)
+		})
+	}
+}
+
+func TestAdvance_ExceptionOutlivesRRuleCount(t *testing.T) {
+	loc := berlin(t)
+	jun3 := at(loc, 2025, time.June, 3, 10, 0)
+	jun12 := at(loc, 2025, time.June, 12, 10, 0)
+	r := &domain.Reminder{
+		Repeat:     domain.RepeatRRule,
+		RRule:      "FREQ=WEEKLY;COUNT=2",
+		StartTime:  jun3.UTC(),
+		NextTime:   jun3.UTC(),
+		Exceptions: []domain.OccurrenceException{moveAt(at(loc, 2025, time.June, 10, 10, 0), jun12)},
+	}
+
+	got, err := Advance(r, jun3, loc)
+	require.NoError(t, err)
+	assert.Equal(t, jun12.UTC(), got)
+
+	r.NextTime = got
+	_, err = Advance(r, jun12, loc)
+	assert.ErrorIs(t, err, ErrSeriesEnded)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	loc := berlin(t)
+	now := at(loc, 2025, time.June, 5, 12, 0)
+	jun10 := at(loc, 2025, time.June, 10, 10, 0)
+	jun17 := at(loc, 2025, time.June, 17, 10, 0)
+
+	tests := []struct {
+		name string
+		r    *domain.Reminder
+		want time.Time
+	}{
+		{"без исключений остаётся текущее", weeklyMeeting(jun10), jun10},
+		{"пропуск ближайшего", weeklyMeeting(jun10, skipAt(jun10)), jun17},
+		{
+			name: "перенос ближайшего",
+			r:    weeklyMeeting(jun10, moveAt(jun10, at(loc, 2025, time.June, 6, 9, 0))),
+			want: at(loc, 2025, time.June, 6, 9, 0),
+		},
+		{
+			name: "пропуск ближайшего, когда следующее перенесено",
+			r: weeklyMeeting(jun10,
+				moveAt(jun17, at(loc, 2025, time.June, 18, 9, 0)), skipAt(jun10)),
+			want: at(loc, 2025, time.June, 18, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextOccurrence(tt.r, now, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UTC(), got.Time)
+		})
+	}
+}
+
+func TestNextOccurrence_SkippingLastOccurrenceEndsSeries(t *testing.T) {
+	loc := berlin(t)
+	jun10 := at(loc, 2025, time.June, 10, 10, 0)
+	r := weeklyMeeting(jun10, skipAt(jun10))
+	r.EndsAt = at(loc, 2025, time.June, 15, 23, 59).UTC()
+
+	_, err := NextOccurrence(r, at(loc, 2025, time.June, 5, 12, 0), loc)
+	assert.ErrorIs(t, err, ErrSeriesEnded)
+}
+
+func TestIsOccurrence(t *testing.T) {
+	loc := berlin(t)
+	r := weeklyMeeting(at(loc, 2025, time.June, 10, 10, 0))
+
+	assert.True(t, IsOccurrence(r, at(loc, 2025, time.June, 10, 10, 0).UTC(), loc))
+	assert.True(t, IsOccurrence(r, at(loc, 2025, time.June, 24, 10, 0).UTC(), loc))
+	assert.False(t, IsOccurrence(r, at(loc, 2025, time.June, 24, 11, 0).UTC(), loc), "не то время")
+	assert.False(t, IsOccurrence(r, at(loc, 2025, time.June, 25, 10, 0).UTC(), loc), "не тот день")
+	assert.False(t, IsOccurrence(r, at(loc, 2025, time.June, 3, 10, 0).UTC(), loc), "уже прошло")
+
+	r.Repeat = domain.RepeatNone
+	assert.False(t, IsOccurrence(r, at(loc, 2025, time.June, 10, 10, 0).UTC(), loc))
+}
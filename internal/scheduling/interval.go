@@ -0,0 +1,108 @@
+package scheduling
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// NextInterval вычисляет первое срабатывание интервального повтора после now.
+//
+// Без окна отсчёт идёт от now с точностью до минуты: «каждые 30 минут», созданное
+// в 10:07, впервые сработает в 10:37. С окном срабатывания привязаны к его началу.
+func NextInterval(now time.Time, r *domain.Reminder) (time.Time, error) {
+	if err := validateInterval(r); err != nil {
+		return time.Time{}, err
+	}
+
+	return nextIntervalSlot(r, now.Truncate(time.Minute), now, now.Location())
+}
+
+// advanceInterval сдвигает интервальный повтор на первое срабатывание строго позже after.
+//
+// Шаги не перебираются по одному, как у остальных типов: при интервале в пять минут
+// неделя простоя — это две тысячи шагов. Номер нужного шага считается делением,
+// поэтому maxAdvanceSteps ограничивает лишь перебор дней в поисках разрешённого.
+func advanceInterval(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	if err := validateInterval(r); err != nil {
+		return time.Time{}, err
+	}
+
+	next, err := nextIntervalSlot(r, r.NextTime.In(loc), after.In(loc), loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return next.UTC(), nil
+}
+
+// validateInterval отсекает параметры, на которых поиск срабатывания не сходится.
+func validateInterval(r *domain.Reminder) error {
+	if r.IntervalMinutes < 1 {
+		return fmt.Errorf("%w: interval must be at least 1 minute, got %d", ErrInvalidInterval, r.IntervalMinutes)
+	}
+	if r.HasWindow() && (r.WindowStart < 0 || r.WindowStart >= r.WindowEnd || r.WindowEnd >= domain.MinutesPerDay) {
+		return fmt.Errorf("%w: window %d..%d must lie within one day", ErrInvalidInterval, r.WindowStart, r.WindowEnd)
+	}
+
+	return nil
+}
+
+// nextIntervalSlot ищет первое срабатывание позже after, но не раньше anchor.
+//
+// Без окна срабатывания идут от anchor ровными отрезками реального времени: «каждые
+// 2 часа» остаются двумя часами и в ночь перевода часов, хотя стенное время сдвигается.
+// С окном срабатывания — это стенные времена начала окна плюс кратные шагу, поэтому
+// «с 10:00 до 18:00» остаётся таким и после перевода часов.
+func nextIntervalSlot(r *domain.Reminder, anchor, after time.Time, loc *time.Location) (time.Time, error) {
+	if anchor.After(after) {
+		// Как и в Advance: ещё не наступившее срабатывание не сдвигается.
+		after = anchor.Add(-time.Nanosecond)
+	}
+	if r.HasWindow() {
+		return nextWindowSlot(r, after, loc)
+	}
+
+	step := time.Duration(r.IntervalMinutes) * time.Minute
+	next := anchor.Add((after.Sub(anchor)/step + 1) * step)
+
+	for steps := 0; !activeOn(r, next); steps++ {
+		if steps >= maxAdvanceSteps {
+			return time.Time{}, fmt.Errorf("%w: reminder %d has no active weekday", ErrInvalidInterval, r.ID)
+		}
+		// Первое срабатывание сетки от anchor, попадающее в следующие сутки.
+		midnight := time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+		n := midnight.Sub(anchor) / step
+		if midnight.Sub(anchor)%step != 0 {
+			n++
+		}
+		next = anchor.Add(n * step)
+	}
+
+	return next, nil
+}
+
+// nextWindowSlot ищет первое срабатывание в окне позже after, перебирая дни от дня after.
+func nextWindowSlot(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+
+	for steps := 0; steps < maxAdvanceSteps; steps++ {
+		if activeOn(r, day) {
+			for m := r.WindowStart; m <= r.WindowEnd; m += r.IntervalMinutes {
+				if candidate := atMinutes(day, m); candidate.After(after) {
+					return candidate, nil
+				}
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Time{}, fmt.Errorf("%w: reminder %d has no active weekday", ErrInvalidInterval, r.ID)
+}
+
+// activeOn сообщает, разрешён ли день t маской дней недели интервального повтора.
+func activeOn(r *domain.Reminder, t time.Time) bool {
+	return len(r.RepeatDays) == 0 || slices.Contains(r.RepeatDays, int(t.Weekday()))
+}
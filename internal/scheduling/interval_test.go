@@ -0,0 +1,166 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvance_Interval(t *testing.T) {
+	loc := berlin(t)
+	workdays := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name   string
+		every  int
+		window [2]int
+		days   []int
+		next   time.Time
+		after  time.Time
+		want   time.Time
+	}{
+		{
+			name:  "каждые 30 минут",
+			every: 30,
+			next:  at(loc, 2025, time.June, 10, 10, 7),
+			after: at(loc, 2025, time.June, 10, 10, 7),
+			want:  at(loc, 2025, time.June, 10, 10, 37),
+		},
+		{
+			name:  "догоняет простой делением, а не перебором",
+			every: 5,
+			next:  at(loc, 2025, time.January, 1, 0, 0),
+			after: at(loc, 2025, time.June, 10, 12, 3),
+			want:  at(loc, 2025, time.June, 10, 12, 5),
+		},
+		{
+			name:  "без окна шаг — реальное время и на переводе часов",
+			every: 120,
+			next:  at(loc, 2025, time.March, 30, 1, 0),
+			after: at(loc, 2025, time.March, 30, 1, 0),
+			want:  at(loc, 2025, time.March, 30, 4, 0),
+		},
+		{
+			name:   "внутри окна",
+			every:  120,
+			window: [2]int{10 * 60, 18 * 60},
+			next:   at(loc, 2025, time.June, 10, 12, 0),
+			after:  at(loc, 2025, time.June, 10, 12, 0),
+			want:   at(loc, 2025, time.June, 10, 14, 0),
+		},
+		{
+			name:   "конец окна включительно",
+			every:  120,
+			window: [2]int{10 * 60, 18 * 60},
+			next:   at(loc, 2025, time.June, 10, 16, 0),
+			after:  at(loc, 2025, time.June, 10, 16, 0),
+			want:   at(loc, 2025, time.June, 10, 18, 0),
+		},
+		{
+			name:   "после окна — начало окна следующего дня",
+			every:  120,
+			window: [2]int{10 * 60, 18 * 60},
+			next:   at(loc, 2025, time.June, 10, 18, 0),
+			after:  at(loc, 2025, time.June, 10, 18, 0),
+			want:   at(loc, 2025, time.June, 11, 10, 0),
+		},
+		{
+			name:   "по будням пятница переходит на понедельник",
+			every:  120,
+			window: [2]int{10 * 60, 18 * 60},
+			days:   workdays,
+			next:   at(loc, 2025, time.June, 13, 18, 0),
+			after:  at(loc, 2025, time.June, 13, 18, 0),
+			want:   at(loc, 2025, time.June, 16, 10, 0),
+		},
+		{
+			name:   "окно сохраняет стенное время после перевода часов",
+			every:  60,
+			window: [2]int{9 * 60, 11 * 60},
+			next:   at(loc, 2025, time.March, 29, 11, 0),
+			after:  at(loc, 2025, time.March, 29, 11, 0),
+			want:   at(loc, 2025, time.March, 30, 9, 0),
+		},
+		{
+			name:  "без окна маска дней пропускает выходные",
+			every: 90,
+			days:  workdays,
+			next:  at(loc, 2025, time.June, 13, 23, 0),
+			after: at(loc, 2025, time.June, 13, 23, 0),
+			want:  at(loc, 2025, time.June, 16, 0, 30),
+		},
+		{
+			name:   "будущее срабатывание не сдвигается",
+			every:  120,
+			window: [2]int{10 * 60, 18 * 60},
+			next:   at(loc, 2025, time.June, 10, 14, 0),
+			after:  at(loc, 2025, time.June, 10, 9, 0),
+			want:   at(loc, 2025, time.June, 10, 14, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.Reminder{
+				Repeat:          domain.RepeatInterval,
+				IntervalMinutes: tt.every,
+				WindowStart:     tt.window[0],
+				WindowEnd:       tt.window[1],
+				RepeatDays:      tt.days,
+				NextTime:        tt.next.UTC(),
+			}
+
+			got, err := Advance(r, tt.after, loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got.In(loc))
+		})
+	}
+}
+
+func TestAdvance_IntervalErrors(t *testing.T) {
+	loc := time.UTC
+	now := at(loc, 2025, time.June, 10, 12, 0)
+
+	tests := []struct {
+		name   string
+		every  int
+		window [2]int
+		days   []int
+	}{
+		{name: "нулевой шаг"},
+		{name: "окно наоборот", every: 60, window: [2]int{600, 60}},
+		{name: "нет разрешённых дней", every: 60, days: []int{9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.Reminder{
+				Repeat:          domain.RepeatInterval,
+				IntervalMinutes: tt.every,
+				WindowStart:     tt.window[0],
+				WindowEnd:       tt.window[1],
+				RepeatDays:      tt.days,
+				NextTime:        now,
+			}
+
+			_, err := Advance(r, now, loc)
+			assert.ErrorIs(t, err, ErrInvalidInterval)
+		})
+	}
+}
+
+func TestNextInterval(t *testing.T) {
+	loc := berlin(t)
+	now := at(loc, 2025, time.June, 10, 10, 7).Add(42 * time.Second)
+
+	got, err := NextInterval(now, &domain.Reminder{IntervalMinutes: 30})
+	require.NoError(t, err)
+	assert.True(t, at(loc, 2025, time.June, 10, 10, 37).Equal(got), "got %s", got)
+
+	got, err = NextInterval(now, &domain.Reminder{IntervalMinutes: 120, WindowStart: 9 * 60, WindowEnd: 17 * 60})
+	require.NoError(t, err)
+	assert.True(t, at(loc, 2025, time.June, 10, 11, 0).Equal(got), "got %s", got)
+}
@@ -0,0 +1,49 @@
+package scheduling
+
+import (
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// NextNotice возвращает время ближайшего предупреждения о срабатывании r.NextTime,
+// наступающего позже after, в UTC. Нулевое время — до срабатывания предупреждений
+// больше нет: все уже отправлены или их время прошло, пока напоминание стояло на паузе.
+// У продолжения, ждущего родителя, срабатывания ещё нет — предупреждать не о чем.
+func NextNotice(r *domain.Reminder, after time.Time) time.Time {
+	if r.Waiting {
+		return time.Time{}
+	}
+
+	var next time.Time
+	for _, lead := range r.LeadMinutes {
+		at := noticeTime(r, lead)
+		if at.After(after) && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+
+	return next.UTC()
+}
+
+// DueNotice выбирает предупреждение, которое пора отправить в момент now: из наступивших —
+// ближайшее к срабатыванию, чтобы после простоя не прислать устаревшее «через 3 дня».
+// at — время этого предупреждения по расписанию; ok=false — отправлять нечего.
+func DueNotice(r *domain.Reminder, now time.Time) (lead int, at time.Time, ok bool) {
+	if !r.NextTime.After(now) {
+		// Срабатывание уже наступило — вместо предупреждения приходит оно само.
+		return 0, time.Time{}, false
+	}
+	for _, l := range r.LeadMinutes {
+		t := noticeTime(r, l)
+		if !t.After(now) && (!ok || l < lead) {
+			lead, at, ok = l, t, true
+		}
+	}
+
+	return lead, at, ok
+}
+
+func noticeTime(r *domain.Reminder, lead int) time.Time {
+	return r.NextTime.Add(-time.Duration(lead) * time.Minute)
+}
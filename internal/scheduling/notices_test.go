@@ -0,0 +1,38 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotices(t *testing.T) {
+	loc := berlin(t)
+	r := &domain.Reminder{
+		NextTime:    at(loc, 2025, time.June, 20, 9, 0).UTC(),
+		LeadMinutes: []int{3 * 24 * 60, 24 * 60, 30},
+	}
+
+	t.Run("ближайшее после момента", func(t *testing.T) {
+		assert.Equal(t, at(loc, 2025, time.June, 17, 9, 0).UTC(), NextNotice(r, at(loc, 2025, time.June, 10, 9, 0)))
+		assert.Equal(t, at(loc, 2025, time.June, 19, 9, 0).UTC(), NextNotice(r, at(loc, 2025, time.June, 17, 9, 0)))
+		assert.Equal(t, at(loc, 2025, time.June, 20, 8, 30).UTC(), NextNotice(r, at(loc, 2025, time.June, 19, 9, 0)))
+		assert.True(t, NextNotice(r, at(loc, 2025, time.June, 20, 8, 30)).IsZero())
+	})
+
+	t.Run("после простоя — ближайшее к срабатыванию", func(t *testing.T) {
+		lead, noticeAt, ok := DueNotice(r, at(loc, 2025, time.June, 19, 12, 0))
+		assert.True(t, ok)
+		assert.Equal(t, 24*60, lead)
+		assert.Equal(t, at(loc, 2025, time.June, 19, 9, 0).UTC(), noticeAt)
+	})
+
+	t.Run("рано или уже поздно", func(t *testing.T) {
+		_, _, ok := DueNotice(r, at(loc, 2025, time.June, 16, 9, 0))
+		assert.False(t, ok)
+		_, _, ok = DueNotice(r, at(loc, 2025, time.June, 20, 9, 0))
+		assert.False(t, ok, "the occurrence itself is due")
+	})
+}
@@ -0,0 +1,72 @@
+package scheduling
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// WindowTime возвращает срабатывание напоминания со случайным окном в сутки day по часам
+// loc: минуту, равномерно выбранную в окне WindowStart..WindowEnd (оба конца включительно).
+//
+// Генератор засевается чатом и временем создания напоминания вместе с датой, а не временем
+// запуска: тот же день всегда даёт ту же минуту. Иначе пересчёт серии — в IsOccurrence,
+// после перезапуска бота или в тестах — выдавал бы другое время, и исключения теряли бы
+// свои срабатывания. Идентификатор для этого не годится: первое срабатывание выбирается
+// до вставки, пока он ещё нулевой.
+func WindowTime(r *domain.Reminder, day time.Time, loc *time.Location) time.Time {
+	return windowTimeFrom(r, day.In(loc), r.WindowStart)
+}
+
+// FirstWindowTime выбирает первое срабатывание в сутки day. Если окно уже началось
+// к моменту now, минута выбирается из оставшейся части окна: напоминание, созданное
+// в 11:00 с окном 10:00–12:00, ещё успевает сработать сегодня. Окно суток day
+// не должно к now закончиться: такой день вызывающий пропускает.
+//
+// У нового напоминания CreatedAt становится равным now: время создания засевает
+// генератор, и со вставкой оно не должно меняться.
+func FirstWindowTime(r *domain.Reminder, day, now time.Time, loc *time.Location) time.Time {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	local, current := day.In(loc), now.In(loc)
+	from := r.WindowStart
+	if civilOf(local).Equal(civilOf(current)) {
+		from = min(max(from, minutesOf(current)+1), r.WindowEnd)
+	}
+
+	return windowTimeFrom(r, local, from)
+}
+
+// windowTimeFrom выбирает минуту в части окна from..WindowEnd суток day.
+func windowTimeFrom(r *domain.Reminder, day time.Time, from int) time.Time {
+	seed := fnv.New64a()
+	fmt.Fprintf(seed, "%d/%d/%s", r.ChatID, r.CreatedAt.Unix(), day.Format(time.DateOnly))
+	rng := rand.New(rand.NewPCG(seed.Sum64(), 0)) //nolint:gosec // нужен воспроизводимый генератор
+
+	return atMinutes(day, from+rng.IntN(r.WindowEnd-from+1)).UTC()
+}
+
+// advanceWindow ведёт напоминание со случайным окном. Дни серии перебираются обычным
+// шагом своего типа от начала окна: случайная минута прошлого срабатывания на шаг
+// не влияет. В каждом дне срабатывание одно, поэтому день NextTime считается отработанным.
+func advanceWindow(r *domain.Reminder, after time.Time, loc *time.Location) (time.Time, error) {
+	if r.NextTime.After(after) {
+		// Как и в основном цикле Advance: ещё не наступившее срабатывание не сдвигается.
+		return r.NextTime.UTC(), nil
+	}
+
+	day := atMinutes(r.NextTime.In(loc), r.WindowStart)
+	for range maxAdvanceSteps {
+		day = advanceOnce(r, day, loc)
+		if next := WindowTime(r, day, loc); next.After(after) {
+			return next, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: reminder %d did not converge after %d steps",
+		ErrInvalidInterval, r.ID, maxAdvanceSteps)
+}
@@ -0,0 +1,126 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func windowReminder(repeat domain.RepeatType, next time.Time) *domain.Reminder {
+	return &domain.Reminder{
+		ID:          7,
+		ChatID:      42,
+		CreatedAt:   time.Date(2026, time.March, 1, 8, 15, 0, 0, time.UTC),
+		Repeat:      repeat,
+		NextTime:    next,
+		WindowStart: 10 * 60,
+		WindowEnd:   12 * 60,
+	}
+}
+
+func TestWindowTime(t *testing.T) {
+	loc := berlin(t)
+	r := windowReminder(domain.RepeatEveryDay, time.Time{})
+	day := at(loc, 2026, time.March, 2, 0, 0)
+
+	first := WindowTime(r, day, loc)
+	assert.Equal(t, first, WindowTime(r, day.Add(15*time.Hour), loc), "время дня не влияет на выбор")
+
+	minutes := make(map[int]bool)
+	for i := range 200 {
+		got := WindowTime(r, day.AddDate(0, 0, i), loc).In(loc)
+		m := minutesOf(got)
+		require.GreaterOrEqual(t, m, r.WindowStart)
+		require.LessOrEqual(t, m, r.WindowEnd)
+		minutes[m] = true
+	}
+	// 200 равномерных выборок из 121 минуты почти наверняка покрывают обе трети окна.
+	assert.Greater(t, len(minutes), 60)
+
+	other := *r
+	other.CreatedAt = r.CreatedAt.Add(time.Minute)
+	same := 0
+	for i := range 30 {
+		d := day.AddDate(0, 0, i)
+		if WindowTime(r, d, loc).Equal(WindowTime(&other, d, loc)) {
+			same++
+		}
+	}
+	assert.Less(t, same, 5, "у разных напоминаний свои случайные минуты")
+}
+
+func TestFirstWindowTime(t *testing.T) {
+	loc := berlin(t)
+	r := windowReminder(domain.RepeatEveryDay, time.Time{})
+	now := at(loc, 2026, time.March, 2, 11, 30)
+
+	got := FirstWindowTime(r, now, now, loc).In(loc)
+	assert.True(t, got.After(now), "минута выбирается из оставшейся части окна")
+	assert.LessOrEqual(t, minutesOf(got), r.WindowEnd)
+
+	tomorrow := FirstWindowTime(r, now.AddDate(0, 0, 1), now, loc)
+	assert.Equal(t, WindowTime(r, now.AddDate(0, 0, 1), loc), tomorrow)
+
+	t.Run("не зависит от идентификатора, выданного при вставке", func(t *testing.T) {
+		fresh := windowReminder(domain.RepeatEveryDay, time.Time{})
+		fresh.ID, fresh.CreatedAt = 0, time.Time{}
+		day := now.AddDate(0, 0, 1)
+
+		first := FirstWindowTime(fresh, day, now, loc)
+		assert.True(t, fresh.CreatedAt.Equal(now), "время создания засевает генератор")
+
+		fresh.ID, fresh.NextTime = 15, first
+		assert.Equal(t, first, WindowTime(fresh, day, loc))
+		assert.True(t, IsOccurrence(fresh, first, loc))
+	})
+}
+
+func TestAdvance_RandomWindow(t *testing.T) {
+	loc := berlin(t)
+
+	t.Run("каждый день — новая минута в окне", func(t *testing.T) {
+		r := windowReminder(domain.RepeatEveryDay, at(loc, 2026, time.March, 2, 10, 47))
+
+		next, err := Advance(r, r.NextTime, loc)
+		require.NoError(t, err)
+		assert.Equal(t, WindowTime(r, at(loc, 2026, time.March, 3, 0, 0), loc), next)
+
+		again, err := Advance(r, r.NextTime, loc)
+		require.NoError(t, err)
+		assert.Equal(t, next, again, "пересчёт даёт то же время")
+		assert.True(t, IsOccurrence(r, next, loc))
+	})
+
+	t.Run("ещё не наступившее срабатывание не сдвигается", func(t *testing.T) {
+		r := windowReminder(domain.RepeatEveryDay, at(loc, 2026, time.March, 2, 10, 47))
+
+		next, err := Advance(r, r.NextTime.Add(-time.Hour), loc)
+		require.NoError(t, err)
+		assert.True(t, r.NextTime.Equal(next))
+	})
+
+	t.Run("в тот же день второй раз не срабатывает", func(t *testing.T) {
+		// Сработало в 10:01, а выбор для того же дня почти наверняка дал бы минуту позже.
+		r := windowReminder(domain.RepeatEveryDay, at(loc, 2026, time.March, 2, 10, 1))
+
+		next, err := Advance(r, r.NextTime, loc)
+		require.NoError(t, err)
+		assert.Equal(t, 3, next.In(loc).Day())
+	})
+
+	t.Run("по дням недели после простоя", func(t *testing.T) {
+		r := windowReminder(domain.RepeatEveryWeek, at(loc, 2026, time.March, 2, 11, 5)) // понедельник
+		r.RepeatDays = []int{int(time.Monday), int(time.Thursday)}
+
+		next, err := Advance(r, at(loc, 2026, time.March, 10, 0, 0), loc)
+		require.NoError(t, err)
+		local := next.In(loc)
+		assert.Equal(t, time.Thursday, local.Weekday())
+		assert.Equal(t, 12, local.Day())
+		assert.GreaterOrEqual(t, minutesOf(local), r.WindowStart)
+		assert.LessOrEqual(t, minutesOf(local), r.WindowEnd)
+	})
+}
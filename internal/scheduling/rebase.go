@@ -0,0 +1,38 @@
+package scheduling
+
+import (
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// Rebase переносит напоминание из пояса from в пояс to с сохранением времени по часам:
+// срабатывание в 09:00 по from становится срабатыванием в 09:00 по to.
+//
+// Вместе с NextTime переносятся и времена, которые должны с ним совпадать: DTSTART
+// правила, время по расписанию перенесённого срабатывания, дата окончания и исключения.
+// Иначе исключение перестало бы находить своё срабатывание, а серия RRULE отсчитывалась
+// бы от прежнего времени. Предупреждение вызывающий планирует заново.
+func Rebase(r *domain.Reminder, from, to *time.Location) {
+	r.NextTime = rebaseTime(r.NextTime, from, to)
+	r.StartTime = rebaseTime(r.StartTime, from, to)
+	r.ShiftedFrom = rebaseTime(r.ShiftedFrom, from, to)
+	r.EndsAt = rebaseTime(r.EndsAt, from, to)
+	for i := range r.Exceptions {
+		e := &r.Exceptions[i]
+		e.Occurrence = rebaseTime(e.Occurrence, from, to)
+		e.MovedTo = rebaseTime(e.MovedTo, from, to)
+	}
+}
+
+// rebaseTime переносит момент t на то же время по часам в поясе to. Время, которого
+// в поясе to нет из-за перевода часов, сдвигается вперёд, как это делает time.Date.
+func rebaseTime(t time.Time, from, to *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	local := t.In(from)
+
+	return time.Date(local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), to).UTC()
+}
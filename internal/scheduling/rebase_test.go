@@ -0,0 +1,42 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebase_KeepsWallClock(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	loc := berlin(t)
+
+	r := &domain.Reminder{
+		Repeat:   domain.RepeatEveryDay,
+		NextTime: at(moscow, 2026, time.June, 12, 9, 0).UTC(),
+		EndsAt:   at(moscow, 2026, time.June, 30, 23, 59).UTC(),
+		Exceptions: []domain.OccurrenceException{
+			{Occurrence: at(moscow, 2026, time.June, 13, 9, 0).UTC()},
+			{
+				Occurrence: at(moscow, 2026, time.June, 14, 9, 0).UTC(),
+				MovedTo:    at(moscow, 2026, time.June, 14, 11, 0).UTC(),
+			},
+		},
+	}
+
+	Rebase(r, moscow, loc)
+
+	assert.Equal(t, at(loc, 2026, time.June, 12, 9, 0).UTC(), r.NextTime)
+	assert.Equal(t, at(loc, 2026, time.June, 30, 23, 59).UTC(), r.EndsAt)
+	assert.True(t, r.ShiftedFrom.IsZero(), "zero times stay zero")
+	assert.Equal(t, at(loc, 2026, time.June, 13, 9, 0).UTC(), r.Exceptions[0].Occurrence)
+	assert.True(t, r.Exceptions[0].IsSkip())
+	assert.Equal(t, at(loc, 2026, time.June, 14, 11, 0).UTC(), r.Exceptions[1].MovedTo)
+
+	next, err := Advance(r, r.NextTime, loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2026, time.June, 14, 11, 0).UTC(), next, "the series keeps its exceptions")
+}
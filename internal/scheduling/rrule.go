@@ -0,0 +1,565 @@
+package scheduling
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// Frequency — базовый период правила RRULE.
+type Frequency int
+
+// Поддерживаемые значения FREQ. Повторы чаще раза в сутки правилом не задаются:
+// напоминание привязано к стенному времени DTSTART.
+const (
+	FreqDaily Frequency = iota + 1
+	FreqWeekly
+	FreqMonthly
+	FreqYearly
+)
+
+// Ограничения на параметры правила: без них COUNT=1000000 заставил бы планировщик
+// перебирать миллион срабатываний на каждом тике.
+const (
+	maxRRuleInterval = 1000
+	maxRRuleCount    = 10_000
+)
+
+var rruleFrequencies = map[string]Frequency{
+	"DAILY":   FreqDaily,
+	"WEEKLY":  FreqWeekly,
+	"MONTHLY": FreqMonthly,
+	"YEARLY":  FreqYearly,
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum — элемент BYDAY: день недели с необязательным порядковым номером
+// («2TU» — второй вторник, «-1FR» — последняя пятница, «MO» — каждый понедельник).
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// RRule — разобранное правило повторения RFC 5545.
+//
+// Поддерживается подмножество, достаточное для напоминаний: FREQ от DAILY до YEARLY,
+// INTERVAL, BYDAY (с порядковыми номерами), BYMONTHDAY (в том числе отрицательные),
+// BYMONTH, BYSETPOS, COUNT, UNTIL и WKST. Остальные части отвергаются, а не игнорируются:
+// молча выброшенный BYHOUR дал бы расписание, которого пользователь не задавал.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	Count      int
+	Until      time.Time
+	WeekStart  time.Weekday
+}
+
+// ParseRRule разбирает строку правила вида «FREQ=MONTHLY;BYDAY=2TU».
+//
+// Префикс «RRULE:» допускается. UNTIL без «Z» и UNTIL-дата трактуются в поясе loc,
+// дата включается целиком. Все ошибки оборачивают domain.ErrInvalidRepeat.
+func ParseRRule(s string, loc *time.Location) (*RRule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rrule", domain.ErrInvalidRepeat)
+	}
+
+	rule := &RRule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for part := range strings.SplitSeq(s, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed rrule part %q", domain.ErrInvalidRepeat, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate rrule part %s", domain.ErrInvalidRepeat, name)
+		}
+		seen[name] = true
+
+		if err := rule.setPart(name, value, loc); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidRepeat, name, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRepeat, err)
+	}
+
+	return rule, nil
+}
+
+// setPart применяет одну пару NAME=VALUE.
+func (r *RRule) setPart(name, value string, loc *time.Location) error {
+	var err error
+
+	switch name {
+	case "FREQ":
+		freq, ok := rruleFrequencies[value]
+		if !ok {
+			return fmt.Errorf("unsupported frequency %q", value)
+		}
+		r.Freq = freq
+	case "INTERVAL":
+		r.Interval, err = parseRRuleInt(value, 1, maxRRuleInterval)
+	case "COUNT":
+		r.Count, err = parseRRuleInt(value, 1, maxRRuleCount)
+	case "UNTIL":
+		r.Until, err = parseRRuleUntil(value, loc)
+	case "WKST":
+		day, ok := rruleWeekdays[value]
+		if !ok {
+			return fmt.Errorf("unknown weekday %q", value)
+		}
+		r.WeekStart = day
+	case "BYDAY":
+		r.ByDay, err = parseByDay(value)
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseRRuleList(value, -31, 31)
+	case "BYMONTH":
+		var months []int
+		months, err = parseRRuleList(value, 1, 12)
+		for _, m := range months {
+			r.ByMonth = append(r.ByMonth, time.Month(m))
+		}
+	case "BYSETPOS":
+		r.BySetPos, err = parseRRuleList(value, -366, 366)
+	default:
+		return fmt.Errorf("unsupported part")
+	}
+
+	return err
+}
+
+// validate проверяет сочетания частей, которые RFC 5545 запрещает или которые
+// в этой реализации не имеют смысла.
+func (r *RRule) validate() error {
+	if r.Freq == 0 {
+		return fmt.Errorf("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	if r.Freq == FreqDaily || r.Freq == FreqWeekly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return fmt.Errorf("ordinal BYDAY is only allowed with FREQ=MONTHLY or FREQ=YEARLY")
+			}
+		}
+	}
+	if r.Freq == FreqYearly && len(r.ByMonth) == 0 {
+		for _, d := range r.ByDay {
+			if d.N > 53 || d.N < -53 {
+				return fmt.Errorf("BYDAY ordinal %d is out of range", d.N)
+			}
+		}
+	} else {
+		for _, d := range r.ByDay {
+			if d.N > 5 || d.N < -5 {
+				return fmt.Errorf("BYDAY ordinal %d is out of range", d.N)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseRRuleInt(value string, minValue, maxValue int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minValue || n > maxValue {
+		return 0, fmt.Errorf("%q is out of range %d..%d", value, minValue, maxValue)
+	}
+
+	return n, nil
+}
+
+// parseRRuleList разбирает список чисел через запятую; ноль RFC 5545 не допускает.
+func parseRRuleList(value string, minValue, maxValue int) ([]int, error) {
+	var out []int
+	for item := range strings.SplitSeq(value, ",") {
+		n, err := parseRRuleInt(strings.TrimPrefix(item, "+"), minValue, maxValue)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("zero is not allowed")
+		}
+		out = append(out, n)
+	}
+
+	return out, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var out []WeekdayNum
+	for item := range strings.SplitSeq(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("malformed weekday %q", item)
+		}
+
+		code := item[len(item)-2:]
+		day, ok := rruleWeekdays[code]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", code)
+		}
+
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(strings.TrimPrefix(ordinal, "+"))
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("malformed ordinal in %q", item)
+			}
+		}
+		out = append(out, WeekdayNum{N: n, Weekday: day})
+	}
+
+	return out, nil
+}
+
+// parseRRuleUntil принимает три формы UNTIL из RFC 5545: UTC («…Z»), плавающее
+// локальное время и дату.
+func parseRRuleUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		// Дата в UNTIL включается целиком.
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a valid date", value)
+}
+
+// NextRRule возвращает первое срабатывание правила строго позже after.
+//
+// start задаёт DTSTART: от него отсчитываются INTERVAL и COUNT, его стенное время
+// становится временем каждого срабатывания. Срабатывания раньше start не учитываются.
+// Несуществующие даты (30 февраля, пятый вторник) пропускаются, как требует RFC 5545.
+// Если серия исчерпана COUNT или UNTIL, возвращается ErrSeriesEnded.
+func NextRRule(rule string, start, after time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	parsed, err := ParseRRule(rule, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsed.Next(start, after, loc)
+}
+
+// Next — то же, что NextRRule, для уже разобранного правила.
+func (r *RRule) Next(start, after time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start = start.In(loc)
+	after = after.In(loc)
+
+	first := 0
+	if r.Count == 0 && after.After(start) {
+		// Без COUNT нумерация срабатываний не важна, и можно сразу перейти к периоду,
+		// в котором лежит after. Период раньше берём с запасом: BYSETPOS и границы
+		// недель могут сдвинуть срабатывание через границу периода.
+		first = max(0, r.periodsBetween(start, after)/r.Interval-1)
+	}
+
+	seen := 0
+	for k := first; k < first+maxAdvanceSteps; k++ {
+		for _, day := range r.expand(r.periodStart(start, k*r.Interval), start) {
+			occurrence := time.Date(day.Year(), day.Month(), day.Day(),
+				start.Hour(), start.Minute(), 0, 0, loc)
+			if occurrence.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return time.Time{}, fmt.Errorf("%w: UNTIL %s reached", ErrSeriesEnded, r.Until.UTC())
+			}
+			seen++
+			if r.Count > 0 && seen > r.Count {
+				return time.Time{}, fmt.Errorf("%w: COUNT %d reached", ErrSeriesEnded, r.Count)
+			}
+			if occurrence.After(after) {
+				return occurrence.UTC(), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: rule produces no occurrences within %d periods",
+		domain.ErrInvalidRepeat, maxAdvanceSteps)
+}
+
+// Дни внутри правила считаются гражданскими датами в UTC: часовой пояс влияет только
+// на итоговое время срабатывания, а сутки в UTC всегда длятся ровно 24 часа.
+
+func civilDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func civilOf(t time.Time) time.Time {
+	return civilDate(t.Year(), t.Month(), t.Day())
+}
+
+// weekStartOf возвращает начало недели (по WKST), в которую попадает день.
+func (r *RRule) weekStartOf(day time.Time) time.Time {
+	delta := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+
+	return day.AddDate(0, 0, -delta)
+}
+
+// periodsBetween считает, сколько базовых периодов FREQ отделяют after от start.
+func (r *RRule) periodsBetween(start, after time.Time) int {
+	from, to := civilOf(start), civilOf(after)
+
+	switch r.Freq {
+	case FreqDaily:
+		return int(to.Sub(from).Hours() / 24)
+	case FreqWeekly:
+		return int(r.weekStartOf(to).Sub(r.weekStartOf(from)).Hours() / (24 * 7))
+	case FreqMonthly:
+		return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	case FreqYearly:
+		return to.Year() - from.Year()
+	}
+
+	return 0
+}
+
+// periodStart возвращает первый день периода, отстоящего от периода start на n.
+func (r *RRule) periodStart(start time.Time, n int) time.Time {
+	day := civilOf(start)
+
+	switch r.Freq {
+	case FreqDaily:
+		return day.AddDate(0, 0, n)
+	case FreqWeekly:
+		return r.weekStartOf(day).AddDate(0, 0, 7*n)
+	case FreqMonthly:
+		return civilDate(day.Year(), day.Month()+time.Month(n), 1)
+	case FreqYearly:
+		return civilDate(day.Year()+n, time.January, 1)
+	}
+
+	return day
+}
+
+// expand возвращает отсортированные дни срабатываний внутри периода.
+func (r *RRule) expand(period, start time.Time) []time.Time {
+	var days []time.Time
+
+	switch r.Freq {
+	case FreqDaily:
+		if r.matchesMonth(period) && r.matchesMonthDay(period) && r.matchesWeekday(period) {
+			days = []time.Time{period}
+		}
+
+	case FreqWeekly:
+		for i := range 7 {
+			day := period.AddDate(0, 0, i)
+			if !r.matchesMonth(day) {
+				continue
+			}
+			if len(r.ByDay) > 0 && r.matchesWeekday(day) ||
+				len(r.ByDay) == 0 && day.Weekday() == start.Weekday() {
+				days = append(days, day)
+			}
+		}
+
+	case FreqMonthly:
+		if r.matchesMonth(period) {
+			days = r.expandMonth(period.Year(), period.Month(), start.Day())
+		}
+
+	case FreqYearly:
+		days = r.expandYear(period.Year(), start)
+	}
+
+	return r.applySetPos(days)
+}
+
+// expandMonth раскрывает BYMONTHDAY и BYDAY внутри месяца. Без них срабатывание
+// приходится на число DTSTART, а в месяцах, где такого числа нет, пропускается.
+func (r *RRule) expandMonth(year int, month time.Month, defaultDay int) []time.Time {
+	first := civilDate(year, month, 1)
+	last := civilDate(year, month, daysInMonth(year, month))
+
+	var days []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			day := md
+			if md < 0 {
+				day = last.Day() + md + 1
+			}
+			if day < 1 || day > last.Day() {
+				continue
+			}
+			date := civilDate(year, month, day)
+			// BYDAY вместе с BYMONTHDAY только сужает выборку.
+			if len(r.ByDay) == 0 || matchesByDay(date, r.ByDay, first, last) {
+				days = append(days, date)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+			if matchesByDay(date, r.ByDay, first, last) {
+				days = append(days, date)
+			}
+		}
+	default:
+		if defaultDay <= last.Day() {
+			days = append(days, civilDate(year, month, defaultDay))
+		}
+	}
+
+	return sortedUnique(days)
+}
+
+// expandYear раскрывает правило с FREQ=YEARLY. BYDAY без BYMONTH отсчитывает
+// порядковые номера от начала года («20MO» — двадцатый понедельник года), а BYMONTHDAY
+// без BYMONTH, как в RFC 5545, берёт это число в каждом месяце года.
+func (r *RRule) expandYear(year int, start time.Time) []time.Time {
+	if len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+		first := civilDate(year, time.January, 1)
+		last := civilDate(year, time.December, 31)
+
+		var days []time.Time
+		for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+			if matchesByDay(date, r.ByDay, first, last) {
+				days = append(days, date)
+			}
+		}
+
+		return days
+	}
+
+	months := r.ByMonth
+	switch {
+	case len(months) > 0:
+	case len(r.ByMonthDay) > 0:
+		for m := time.January; m <= time.December; m++ {
+			months = append(months, m)
+		}
+	default:
+		months = []time.Month{start.Month()}
+	}
+
+	var days []time.Time
+	for _, month := range months {
+		days = append(days, r.expandMonth(year, month, start.Day())...)
+	}
+
+	return sortedUnique(days)
+}
+
+// applySetPos оставляет из дней периода только позиции BYSETPOS.
+func (r *RRule) applySetPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 || len(days) == 0 {
+		return days
+	}
+
+	var out []time.Time
+	for _, pos := range r.BySetPos {
+		idx := pos - 1
+		if pos < 0 {
+			idx = len(days) + pos
+		}
+		if idx >= 0 && idx < len(days) {
+			out = append(out, days[idx])
+		}
+	}
+
+	return sortedUnique(out)
+}
+
+func (r *RRule) matchesMonth(day time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, day.Month())
+}
+
+func (r *RRule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	last := daysInMonth(day.Year(), day.Month())
+	for _, md := range r.ByMonthDay {
+		if md == day.Day() || md < 0 && last+md+1 == day.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RRule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, d := range r.ByDay {
+		if d.Weekday == day.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesByDay проверяет день по списку BYDAY с учётом порядковых номеров внутри
+// диапазона [first, last] — месяца или года.
+func matchesByDay(day time.Time, byDay []WeekdayNum, first, last time.Time) bool {
+	for _, d := range byDay {
+		if d.Weekday != day.Weekday() {
+			continue
+		}
+		if d.N == 0 {
+			return true
+		}
+
+		fromStart := int(day.Sub(first).Hours()/24)/7 + 1
+		fromEnd := -(int(last.Sub(day).Hours()/24)/7 + 1)
+		if d.N == fromStart || d.N == fromEnd {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedUnique(days []time.Time) []time.Time {
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+
+	return slices.CompactFunc(days, func(a, b time.Time) bool { return a.Equal(b) })
+}
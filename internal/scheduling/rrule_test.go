@@ -0,0 +1,174 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRRule(t *testing.T) {
+	loc := berlin(t)
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "второй вторник месяца",
+			rule:  "FREQ=MONTHLY;BYDAY=2TU",
+			start: at(loc, 2025, time.June, 10, 9, 0),
+			after: at(loc, 2025, time.June, 10, 9, 0),
+			want:  at(loc, 2025, time.July, 8, 9, 0),
+		},
+		{
+			name:  "последняя пятница месяца",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: at(loc, 2025, time.June, 27, 18, 0),
+			after: at(loc, 2025, time.June, 27, 18, 0),
+			want:  at(loc, 2025, time.July, 25, 18, 0),
+		},
+		{
+			name:  "последний день месяца",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: at(loc, 2025, time.January, 31, 9, 0),
+			after: at(loc, 2025, time.January, 31, 9, 0),
+			want:  at(loc, 2025, time.February, 28, 9, 0),
+		},
+		{
+			name:  "последний рабочий день через BYSETPOS",
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start: at(loc, 2025, time.May, 30, 17, 0),
+			after: at(loc, 2025, time.May, 30, 17, 0),
+			want:  at(loc, 2025, time.June, 30, 17, 0),
+		},
+		{
+			name:  "раз в две недели по понедельникам и средам",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: at(loc, 2025, time.June, 2, 9, 0),
+			after: at(loc, 2025, time.June, 4, 9, 0),
+			want:  at(loc, 2025, time.June, 16, 9, 0),
+		},
+		{
+			name:  "31-е число пропускает короткие месяцы",
+			rule:  "FREQ=MONTHLY",
+			start: at(loc, 2025, time.January, 31, 9, 0),
+			after: at(loc, 2025, time.January, 31, 9, 0),
+			want:  at(loc, 2025, time.March, 31, 9, 0),
+		},
+		{
+			name:  "стенное время сохраняется на переходе на летнее время",
+			rule:  "FREQ=DAILY",
+			start: at(loc, 2025, time.March, 1, 9, 0),
+			after: at(loc, 2025, time.March, 29, 9, 0),
+			want:  at(loc, 2025, time.March, 30, 9, 0),
+		},
+		{
+			name:  "ежегодно в последнее воскресенье марта",
+			rule:  "FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+			start: at(loc, 2025, time.March, 30, 9, 0),
+			after: at(loc, 2025, time.March, 30, 9, 0),
+			want:  at(loc, 2026, time.March, 29, 9, 0),
+		},
+		{
+			name:  "ежегодно по BYMONTHDAY без BYMONTH — в каждом месяце",
+			rule:  "FREQ=YEARLY;BYMONTHDAY=1",
+			start: at(loc, 2026, time.January, 13, 9, 0),
+			after: at(loc, 2026, time.January, 13, 9, 0),
+			want:  at(loc, 2026, time.February, 1, 9, 0),
+		},
+		{
+			name:  "ежегодно по BYMONTHDAY без BYMONTH — следующий месяц",
+			rule:  "FREQ=YEARLY;BYMONTHDAY=1",
+			start: at(loc, 2026, time.January, 13, 9, 0),
+			after: at(loc, 2026, time.February, 1, 9, 0),
+			want:  at(loc, 2026, time.March, 1, 9, 0),
+		},
+		{
+			name:  "далёкое будущее без перебора с начала серии",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: at(loc, 2000, time.January, 1, 9, 0),
+			after: at(loc, 2025, time.June, 10, 12, 0),
+			want:  at(loc, 2025, time.June, 12, 9, 0),
+		},
+		{
+			name:  "срабатывания раньше DTSTART не учитываются",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=1,15",
+			start: at(loc, 2025, time.June, 10, 9, 0),
+			after: at(loc, 2025, time.June, 1, 0, 0),
+			want:  at(loc, 2025, time.June, 15, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextRRule(tt.rule, tt.start, tt.after, loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got.In(loc))
+		})
+	}
+}
+
+func TestNextRRule_SeriesEnds(t *testing.T) {
+	loc := time.UTC
+	start := at(loc, 2025, time.June, 10, 9, 0)
+
+	t.Run("COUNT", func(t *testing.T) {
+		got, err := NextRRule("FREQ=DAILY;COUNT=3", start, at(loc, 2025, time.June, 11, 9, 0), loc)
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.June, 12, 9, 0).Equal(got))
+
+		_, err = NextRRule("FREQ=DAILY;COUNT=3", start, got, loc)
+		assert.ErrorIs(t, err, ErrSeriesEnded)
+	})
+
+	t.Run("UNTIL включает дату целиком", func(t *testing.T) {
+		got, err := NextRRule("FREQ=DAILY;UNTIL=20250611", start, start, loc)
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.June, 11, 9, 0).Equal(got))
+
+		_, err = NextRRule("FREQ=DAILY;UNTIL=20250611", start, got, loc)
+		assert.ErrorIs(t, err, ErrSeriesEnded)
+	})
+}
+
+func TestParseRRule_Errors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;FREQ=DAILY",
+		"FREQ=MONTHLY;UNTIL=tomorrow",
+	} {
+		_, err := ParseRRule(rule, time.UTC)
+		assert.ErrorIs(t, err, domain.ErrInvalidRepeat, "rule %q", rule)
+	}
+}
+
+func TestAdvance_RRuleCountsFromStartTime(t *testing.T) {
+	loc := berlin(t)
+	start := at(loc, 2025, time.June, 2, 9, 0)
+
+	r := &domain.Reminder{
+		Repeat:    domain.RepeatRRule,
+		RRule:     "FREQ=WEEKLY;INTERVAL=2",
+		StartTime: start.UTC(),
+		NextTime:  at(loc, 2025, time.June, 16, 9, 0).UTC(),
+	}
+
+	got, err := Advance(r, r.NextTime, loc)
+	require.NoError(t, err)
+	assert.True(t, at(loc, 2025, time.June, 30, 9, 0).Equal(got), "got %s", got.In(loc))
+}
@@ -0,0 +1,109 @@
+package scheduling
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// solarSearchDays — на сколько дней вперёд ищется событие. За полярным кругом солнце
+// месяцами не заходит или не всходит, но за год каждое событие хотя бы раз случается.
+const solarSearchDays = 367
+
+// j2000 — эпоха J2000.0, от которой отсчитываются юлианские дни в формулах ниже.
+var j2000 = time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// solarAltitude — высота центра солнца над горизонтом в градусах, при которой наступает
+// событие. Для восхода и заката учтены рефракция и видимый радиус диска.
+func solarAltitude(event domain.SolarEvent) float64 {
+	if event == domain.SolarDawn || event == domain.SolarDusk {
+		return -6
+	}
+
+	return -0.833
+}
+
+// SolarTime возвращает момент события в солнечные сутки, полдень которых ближе всего
+// к полудню гражданской даты day (берутся только год, месяц и число) на долготе geo.
+// ok == false, если в эти сутки солнце не пересекает нужную высоту: полярный день
+// или полярная ночь.
+//
+// Расчёт идёт по уравнению восхода без сетевых запросов; точность — около минуты,
+// чего для напоминаний достаточно.
+func SolarTime(event domain.SolarEvent, geo domain.GeoPoint, day time.Time) (time.Time, bool) {
+	transit, cosHourAngle := solarDay(geo, day, solarAltitude(event))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+
+	halfDay := math.Acos(cosHourAngle) / solarRad / 360
+	at := transit - halfDay
+	if event == domain.SolarSunset || event == domain.SolarDusk {
+		at = transit + halfDay
+	}
+
+	return j2000.Add(time.Duration(at * 24 * float64(time.Hour))).Round(time.Minute), true
+}
+
+// PolarDay сообщает, что в сутки day солнце в точке geo не заходит за горизонт.
+// Если при этом нет и восхода, значит, наступила полярная ночь.
+func PolarDay(geo domain.GeoPoint, day time.Time) bool {
+	_, cosHourAngle := solarDay(geo, day, solarAltitude(domain.SolarSunset))
+
+	return cosHourAngle < -1
+}
+
+// solarRad переводит градусы в радианы.
+const solarRad = math.Pi / 180
+
+// solarDay возвращает момент солнечного полудня в сутки day — в юлианских днях от J2000 —
+// и косинус часового угла, на котором солнце проходит высоту altitude. Косинус вне
+// [-1, 1] означает, что солнце весь день выше (меньше -1) или ниже (больше 1) этой высоты.
+func solarDay(geo domain.GeoPoint, day time.Time, altitude float64) (transit, cosHourAngle float64) {
+	// Номер суток от J2000 и средний солнечный полдень на долготе geo.
+	date := civilDate(day.Year(), day.Month(), day.Day()).Add(12 * time.Hour)
+	n := math.Round(date.Sub(j2000).Hours() / 24)
+	noon := n - geo.Longitude/360
+
+	anomaly := math.Mod(357.5291+0.98560028*noon, 360) * solarRad
+	center := 1.9148*math.Sin(anomaly) + 0.0200*math.Sin(2*anomaly) + 0.0003*math.Sin(3*anomaly)
+	ecliptic := math.Mod(anomaly/solarRad+center+180+102.9372, 360) * solarRad
+	transit = noon + 0.0053*math.Sin(anomaly) - 0.0069*math.Sin(2*ecliptic)
+
+	declination := math.Asin(math.Sin(ecliptic) * math.Sin(23.4397*solarRad))
+	latitude := geo.Latitude * solarRad
+	cosHourAngle = (math.Sin(altitude*solarRad) - math.Sin(latitude)*math.Sin(declination)) /
+		(math.Cos(latitude) * math.Cos(declination))
+
+	return transit, cosHourAngle
+}
+
+// NextSolar возвращает первое срабатывание солнечного повтора строго позже after в UTC:
+// событие плюс offset минут. Дни без события пропускаются.
+func NextSolar(event domain.SolarEvent, offset int, geo domain.GeoPoint, after time.Time) (time.Time, error) {
+	// Начинаем с предыдущих суток: со сдвигом вперёд их событие может быть ещё впереди.
+	first := civilOf(after.UTC()).AddDate(0, 0, -1)
+	for i := range solarSearchDays {
+		at, ok := SolarTime(event, geo, first.AddDate(0, 0, i))
+		if !ok {
+			continue
+		}
+		if at = at.Add(time.Duration(offset) * time.Minute); at.After(after) {
+			return at, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: solar event %d does not occur within %d days",
+		domain.ErrInvalidRepeat, event, solarSearchDays)
+}
+
+// advanceSolar вычисляет следующее срабатывание по координатам чата.
+func advanceSolar(r *domain.Reminder, after time.Time) (time.Time, error) {
+	if r.Geo == nil {
+		return time.Time{}, fmt.Errorf("%w: reminder %d", domain.ErrNoLocation, r.ID)
+	}
+
+	return NextSolar(r.SolarEvent, r.SolarOffset, *r.Geo, after)
+}
@@ -0,0 +1,105 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	moscowGeo = domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	tromsoGeo = domain.GeoPoint{Latitude: 69.6492, Longitude: 18.9553}
+)
+
+func TestSolarTime(t *testing.T) {
+	solstice := at(time.UTC, 2025, time.June, 21, 0, 0)
+
+	tests := []struct {
+		name  string
+		event domain.SolarEvent
+		geo   domain.GeoPoint
+		want  time.Time
+	}{
+		// Опубликованные времена: восход в Москве в 03:44, закат в 21:18 по Москве.
+		{"восход в Москве", domain.SolarSunrise, moscowGeo, at(time.UTC, 2025, time.June, 21, 0, 45)},
+		{"закат в Москве", domain.SolarSunset, moscowGeo, at(time.UTC, 2025, time.June, 21, 18, 18)},
+		{"утренние сумерки раньше восхода", domain.SolarDawn, moscowGeo, at(time.UTC, 2025, time.June, 20, 23, 43)},
+		{"вечерние сумерки позже заката", domain.SolarDusk, moscowGeo, at(time.UTC, 2025, time.June, 21, 19, 20)},
+		// В Гонолулу закат по местному времени 21 июня приходится на следующие сутки UTC.
+		{"западная долгота", domain.SolarSunset, domain.GeoPoint{Latitude: 21.3069, Longitude: -157.8583},
+			at(time.UTC, 2025, time.June, 22, 5, 16)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SolarTime(tt.event, tt.geo, solstice)
+			require.True(t, ok)
+			assert.WithinDuration(t, tt.want, got, 2*time.Minute)
+		})
+	}
+}
+
+func TestSolarTime_PolarDayAndNight(t *testing.T) {
+	summer, winter := at(time.UTC, 2025, time.June, 21, 0, 0), at(time.UTC, 2025, time.December, 21, 0, 0)
+
+	_, ok := SolarTime(domain.SolarSunset, tromsoGeo, summer)
+	assert.False(t, ok, "полярный день: солнце не заходит")
+	assert.True(t, PolarDay(tromsoGeo, summer))
+
+	_, ok = SolarTime(domain.SolarSunrise, tromsoGeo, winter)
+	assert.False(t, ok, "полярная ночь: солнце не всходит")
+	assert.False(t, PolarDay(tromsoGeo, winter))
+
+	_, ok = SolarTime(domain.SolarDawn, tromsoGeo, winter)
+	assert.True(t, ok, "гражданские сумерки в полярную ночь бывают")
+}
+
+func TestNextSolar(t *testing.T) {
+	t.Run("за полчаса до заката", func(t *testing.T) {
+		got, err := NextSolar(domain.SolarSunset, -30, moscowGeo, at(time.UTC, 2025, time.June, 21, 18, 0))
+		require.NoError(t, err)
+		assert.WithinDuration(t, at(time.UTC, 2025, time.June, 22, 17, 48), got, 2*time.Minute)
+	})
+
+	t.Run("сдвиг вперёд переходит на следующие сутки", func(t *testing.T) {
+		// Закат 20 июня плюс шесть часов — 00:18 UTC 21 июня, ещё впереди.
+		got, err := NextSolar(domain.SolarSunset, 6*60, moscowGeo, at(time.UTC, 2025, time.June, 21, 0, 0))
+		require.NoError(t, err)
+		assert.WithinDuration(t, at(time.UTC, 2025, time.June, 21, 0, 18), got, 2*time.Minute)
+	})
+
+	t.Run("полярный день пропускается", func(t *testing.T) {
+		// В Тромсё солнце снова заходит только в конце июля.
+		got, err := NextSolar(domain.SolarSunset, 0, tromsoGeo, at(time.UTC, 2025, time.June, 21, 0, 0))
+		require.NoError(t, err)
+		assert.Equal(t, time.July, got.Month())
+		assert.GreaterOrEqual(t, got.Day(), 24)
+	})
+
+	t.Run("событие, которого не бывает", func(t *testing.T) {
+		pole := domain.GeoPoint{Latitude: 90}
+		_, err := NextSolar(domain.SolarSunrise, 0, pole, at(time.UTC, 2025, time.June, 1, 0, 0))
+		assert.ErrorIs(t, err, domain.ErrInvalidRepeat)
+	})
+}
+
+func TestAdvance_Solar(t *testing.T) {
+	r := &domain.Reminder{
+		ID:          1,
+		Repeat:      domain.RepeatSolar,
+		SolarEvent:  domain.SolarSunset,
+		SolarOffset: -30,
+		NextTime:    at(time.UTC, 2025, time.June, 21, 17, 48),
+	}
+
+	_, err := Advance(r, r.NextTime, berlin(t))
+	require.ErrorIs(t, err, domain.ErrNoLocation)
+
+	r.Geo = &moscowGeo
+	next, err := Advance(r, r.NextTime, berlin(t))
+	require.NoError(t, err)
+	assert.WithinDuration(t, at(time.UTC, 2025, time.June, 22, 17, 48), next, 2*time.Minute)
+}
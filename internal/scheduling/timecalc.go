@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 )
 
 // Ошибки расчёта времени.
@@ -18,6 +20,12 @@ var (
 	ErrInvalidInterval = errors.New("invalid interval")
 	// ErrNotRepeating возвращается при попытке сдвинуть неповторяющееся напоминание.
 	ErrNotRepeating = errors.New("reminder does not repeat")
+	// ErrSeriesEnded возвращается, когда у повтора больше нет срабатываний
+	// (правило исчерпано COUNT или UNTIL).
+	ErrSeriesEnded = errors.New("repeat series has ended")
+	// ErrNoWorkdays возвращается, когда режим «только по рабочим дням» не находит
+	// ни одного рабочего дня для срабатывания.
+	ErrNoWorkdays = errors.New("no working days for the series")
 )
 
 // Функции расчёта принимают now уже в часовом поясе чата и возвращают время
@@ -34,6 +42,22 @@ func NextToday(now, t time.Time) time.Time {
 	return nextTime
 }
 
+// EarliestTimeInDay выбирает в дне day самое раннее из времён times (минуты от полуночи),
+// наступающее строго после now. Если такого нет, day возвращается без изменений.
+//
+// Так первое срабатывание напоминания с несколькими временами в день считается в два шага:
+// ближайший подходящий день ищется по последнему времени дня, а уже в нём выбирается
+// самое раннее из ещё не прошедших.
+func EarliestTimeInDay(day, now time.Time, times []int) time.Time {
+	for _, m := range times {
+		if candidate := atMinutes(day, m); candidate.After(now) {
+			return candidate
+		}
+	}
+
+	return day
+}
+
 // NextTomorrow вычисляет время для напоминания на завтра.
 func NextTomorrow(now, t time.Time) time.Time {
 	return atClock(now, t).AddDate(0, 0, 1)
@@ -56,8 +80,9 @@ func NextWeekday(now, t time.Time, weekday int) (time.Time, error) {
 }
 
 // NextMonthDay вычисляет время ближайшего срабатывания в заданное число месяца.
+// domain.LastMonthDay означает последний день месяца.
 func NextMonthDay(now, t time.Time, dayOfMonth int) (time.Time, error) {
-	if dayOfMonth < 1 || dayOfMonth > 31 {
+	if (dayOfMonth < 1 || dayOfMonth > 31) && dayOfMonth != domain.LastMonthDay {
 		return time.Time{}, fmt.Errorf("%w: day of month %d is out of range 1..31", ErrInvalidDate, dayOfMonth)
 	}
 
@@ -69,6 +94,41 @@ func NextMonthDay(now, t time.Time, dayOfMonth int) (time.Time, error) {
 	return candidate, nil
 }
 
+// NextMonthWeekday вычисляет ближайшее срабатывание «N-й день недели месяца».
+//
+// ordinal — 1..domain.MaxMonthOrdinal или domain.MonthOrdinalLast, weekdays — дни недели
+// (0 — воскресенье). Несколько дней означают N-й из подходящих дней: «последний из
+// пн–пт» — последний рабочий день месяца.
+func NextMonthWeekday(now, t time.Time, ordinal int, weekdays []int) (time.Time, error) {
+	if err := validateMonthWeekday(ordinal, weekdays); err != nil {
+		return time.Time{}, err
+	}
+
+	candidate := nthWeekdayInMonth(now.Year(), now.Month(), ordinal, weekdays, t, now.Location())
+	if !candidate.After(now) {
+		candidate = nthWeekdayInMonth(now.Year(), now.Month()+1, ordinal, weekdays, t, now.Location())
+	}
+
+	return candidate, nil
+}
+
+// validateMonthWeekday проверяет параметры режима «N-й день недели месяца».
+func validateMonthWeekday(ordinal int, weekdays []int) error {
+	if (ordinal < 1 || ordinal > domain.MaxMonthOrdinal) && ordinal != domain.MonthOrdinalLast {
+		return fmt.Errorf("%w: month ordinal %d is out of range", ErrInvalidDate, ordinal)
+	}
+	if len(weekdays) == 0 {
+		return fmt.Errorf("%w: at least one weekday is required", ErrInvalidDate)
+	}
+	for _, d := range weekdays {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("%w: weekday %d is out of range 0..6", ErrInvalidDate, d)
+		}
+	}
+
+	return nil
+}
+
 // NextYearDay вычисляет время ближайшего срабатывания в заданный день и месяц.
 // date — строка в формате ДД.ММ.
 func NextYearDay(now, t time.Time, date string) (time.Time, error) {
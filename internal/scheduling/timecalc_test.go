@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -101,6 +102,12 @@ func TestNextMonthDay(t *testing.T) {
 			day:  5,
 			want: at(loc, 2026, time.January, 5, 9, 0),
 		},
+		{
+			name: "последний день месяца",
+			now:  at(loc, 2025, time.February, 10, 8, 0),
+			day:  domain.LastMonthDay,
+			want: at(loc, 2025, time.February, 28, 9, 0),
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +124,30 @@ func TestNextMonthDay(t *testing.T) {
 	})
 }
 
+func TestNextMonthWeekday(t *testing.T) {
+	loc := time.UTC
+
+	t.Run("второй вторник этого месяца ещё впереди", func(t *testing.T) {
+		got, err := NextMonthWeekday(at(loc, 2025, time.June, 2, 8, 0), clock(9, 0), 2, []int{2})
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.June, 10, 9, 0).Equal(got), "got %s", got)
+	})
+
+	t.Run("второй вторник уже прошёл", func(t *testing.T) {
+		got, err := NextMonthWeekday(at(loc, 2025, time.June, 10, 10, 0), clock(9, 0), 2, []int{2})
+		require.NoError(t, err)
+		assert.True(t, at(loc, 2025, time.July, 8, 9, 0).Equal(got), "got %s", got)
+	})
+
+	t.Run("некорректные параметры", func(t *testing.T) {
+		now := at(loc, 2025, time.June, 2, 8, 0)
+		_, err := NextMonthWeekday(now, clock(9, 0), 5, []int{2})
+		assert.ErrorIs(t, err, ErrInvalidDate)
+		_, err = NextMonthWeekday(now, clock(9, 0), 1, nil)
+		assert.ErrorIs(t, err, ErrInvalidDate)
+	})
+}
+
 func TestNextYearDay(t *testing.T) {
 	loc := time.UTC
 
@@ -196,3 +227,37 @@ func TestNextNDays(t *testing.T) {
 		assert.ErrorIs(t, err, ErrInvalidInterval)
 	})
 }
+
+func TestEarliestTimeInDay(t *testing.T) {
+	loc := time.UTC
+	times := []int{9 * 60, 13 * 60, 21 * 60}
+	day := time.Date(2025, time.June, 10, 21, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "утро — первое время",
+			now:  time.Date(2025, time.June, 10, 7, 0, 0, 0, loc),
+			want: time.Date(2025, time.June, 10, 9, 0, 0, 0, loc),
+		},
+		{
+			name: "днём — ближайшее не прошедшее",
+			now:  time.Date(2025, time.June, 10, 9, 0, 0, 0, loc),
+			want: time.Date(2025, time.June, 10, 13, 0, 0, 0, loc),
+		},
+		{
+			name: "предыдущий день — первое время",
+			now:  time.Date(2025, time.June, 9, 22, 0, 0, 0, loc),
+			want: time.Date(2025, time.June, 10, 9, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EarliestTimeInDay(day, tt.now, times))
+		})
+	}
+}
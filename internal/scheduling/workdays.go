@@ -0,0 +1,75 @@
+package scheduling
+
+import (
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+)
+
+// maxWorkdayShift — на сколько дней ищется рабочий день при переносе. Самые длинные
+// новогодние каникулы короче двух недель; месяц без рабочих дней означает битый календарь.
+const maxWorkdayShift = 31
+
+// Occurrence — ближайшее срабатывание серии.
+type Occurrence struct {
+	Time time.Time // когда сработать, UTC
+	// ShiftedFrom — время по расписанию, если срабатывание перенесено с нерабочего дня;
+	// нулевое, если не перенесено.
+	ShiftedFrom time.Time
+}
+
+// Apply записывает срабатывание в напоминание.
+func (o Occurrence) Apply(r *domain.Reminder) {
+	r.NextTime = o.Time
+	r.ShiftedFrom = o.ShiftedFrom
+}
+
+// onWorkday применяет WorkdayPolicy к времени серии t: возвращает время, в которое
+// срабатывание случится, или false, если оно пропускается. Перенос сохраняет
+// стенные часы и минуты в поясе чата.
+func onWorkday(r *domain.Reminder, t time.Time, loc *time.Location) (time.Time, bool) {
+	local := t.In(loc)
+	if r.WorkdayPolicy == domain.WorkdayAny || r.Calendar.IsWorkday(local) {
+		return t, true
+	}
+
+	var step int
+	switch r.WorkdayPolicy {
+	case domain.WorkdayPrevious:
+		step = -1
+	case domain.WorkdayNext:
+		step = 1
+	case domain.WorkdayAny, domain.WorkdaySkip:
+		return time.Time{}, false
+	}
+
+	for range maxWorkdayShift {
+		local = stepDays(local, step)
+		if r.Calendar.IsWorkday(local) {
+			return local.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// workdaySeriesAfter возвращает, после какого времени серии искать следующее
+// срабатывание в режиме «только по рабочим дням».
+//
+// Перенос на предыдущий рабочий день срабатывает раньше своего времени по расписанию,
+// на следующий — позже. Поэтому кандидаты перебираются не от after, а от текущего
+// срабатывания серии, но не дальше maxWorkdayShift дней назад: иначе после долгого
+// простоя пришлось бы перебрать всю пропущенную историю.
+func workdaySeriesAfter(r *domain.Reminder, after time.Time) time.Time {
+	if r.WorkdayPolicy == domain.WorkdaySkip {
+		return after
+	}
+
+	from := after.AddDate(0, 0, -maxWorkdayShift)
+	// Ещё не наступившее срабатывание остаётся кандидатом, как и в основном цикле Advance.
+	if current := SeriesTime(r); current.After(from) && !r.NextTime.After(after) {
+		from = current
+	}
+
+	return from
+}
@@ -0,0 +1,160 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8thgencore/dory-reminder-bot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// whitMonday — календарь с одним праздником: 9 июня 2025 года, понедельник.
+var whitMonday = &domain.BusinessCalendar{Days: map[string]bool{"2025-06-09": false}}
+
+func workdayReminder(repeat domain.RepeatType, days []int, next time.Time, policy domain.WorkdayPolicy) *domain.Reminder {
+	return &domain.Reminder{
+		Repeat:        repeat,
+		RepeatDays:    days,
+		NextTime:      next.UTC(),
+		WorkdayPolicy: policy,
+		Calendar:      whitMonday,
+	}
+}
+
+func TestAdvanceOccurrence_Workdays(t *testing.T) {
+	loc := berlin(t)
+	monday := []int{int(time.Monday)}
+
+	tests := []struct {
+		name        string
+		r           *domain.Reminder
+		now         time.Time
+		want        time.Time
+		wantShifted time.Time
+	}{
+		{
+			name: "пропуск выходных и праздника",
+			r:    workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 6, 9, 0), domain.WorkdaySkip),
+			now:  at(loc, 2025, time.June, 6, 9, 0),
+			want: at(loc, 2025, time.June, 10, 9, 0),
+		},
+		{
+			name:        "перенос на следующий рабочий день",
+			r:           workdayReminder(domain.RepeatEveryWeek, monday, at(loc, 2025, time.June, 2, 9, 0), domain.WorkdayNext),
+			now:         at(loc, 2025, time.June, 2, 9, 0),
+			want:        at(loc, 2025, time.June, 10, 9, 0),
+			wantShifted: at(loc, 2025, time.June, 9, 9, 0),
+		},
+		{
+			name:        "перенос на предыдущий рабочий день",
+			r:           workdayReminder(domain.RepeatEveryWeek, monday, at(loc, 2025, time.June, 2, 9, 0), domain.WorkdayPrevious),
+			now:         at(loc, 2025, time.June, 2, 9, 0),
+			want:        at(loc, 2025, time.June, 6, 9, 0),
+			wantShifted: at(loc, 2025, time.June, 9, 9, 0),
+		},
+		{
+			name:        "сдвиги на один день сливаются в одно срабатывание",
+			r:           workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 6, 9, 0), domain.WorkdayNext),
+			now:         at(loc, 2025, time.June, 6, 9, 0),
+			want:        at(loc, 2025, time.June, 10, 9, 0),
+			wantShifted: at(loc, 2025, time.June, 7, 9, 0),
+		},
+		{
+			name: "рабочий день не сдвигается",
+			r:    workdayReminder(domain.RepeatEveryWeek, monday, at(loc, 2025, time.June, 9, 9, 0), domain.WorkdayNext),
+			now:  at(loc, 2025, time.June, 10, 9, 0),
+			want: at(loc, 2025, time.June, 16, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AdvanceOccurrence(tt.r, tt.now, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UTC(), got.Time)
+			if tt.wantShifted.IsZero() {
+				assert.True(t, got.ShiftedFrom.IsZero())
+			} else {
+				assert.Equal(t, tt.wantShifted.UTC(), got.ShiftedFrom)
+			}
+		})
+	}
+}
+
+func TestAdvanceOccurrence_SeriesContinuesFromShiftedTime(t *testing.T) {
+	loc := berlin(t)
+
+	// Ежедневное «по рабочим, с переносом вперёд»: суббота, воскресенье и праздничный
+	// понедельник ушли во вторник. Следующим должна быть среда, а не второй вторник.
+	r := workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 10, 9, 0), domain.WorkdayNext)
+	r.ShiftedFrom = at(loc, 2025, time.June, 7, 9, 0).UTC()
+
+	got, err := AdvanceOccurrence(r, at(loc, 2025, time.June, 10, 9, 0), loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2025, time.June, 11, 9, 0).UTC(), got.Time)
+	assert.True(t, got.ShiftedFrom.IsZero())
+
+	// «Каждые 3 дня» не должно дрейфовать из-за переноса: шаг идёт от времени серии.
+	r = workdayReminder(domain.RepeatEveryNDays, nil, at(loc, 2025, time.June, 6, 9, 0), domain.WorkdayPrevious)
+	r.RepeatEvery = 3
+	r.ShiftedFrom = at(loc, 2025, time.June, 8, 9, 0).UTC()
+
+	got, err = AdvanceOccurrence(r, at(loc, 2025, time.June, 6, 9, 0), loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2025, time.June, 11, 9, 0).UTC(), got.Time)
+}
+
+func TestAdvanceOccurrence_CalendarOverrides(t *testing.T) {
+	loc := berlin(t)
+
+	t.Run("перенесённая рабочая суббота", func(t *testing.T) {
+		r := workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 13, 9, 0), domain.WorkdaySkip)
+		r.Calendar = &domain.BusinessCalendar{Days: map[string]bool{"2025-06-14": true}}
+
+		got, err := Advance(r, at(loc, 2025, time.June, 13, 9, 0), loc)
+		require.NoError(t, err)
+		assert.Equal(t, at(loc, 2025, time.June, 14, 9, 0).UTC(), got)
+	})
+
+	t.Run("своя рабочая неделя", func(t *testing.T) {
+		r := workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 12, 9, 0), domain.WorkdaySkip)
+		r.Calendar = &domain.BusinessCalendar{Workweek: []int{0, 1, 2, 3, 4}}
+
+		got, err := Advance(r, at(loc, 2025, time.June, 12, 9, 0), loc)
+		require.NoError(t, err)
+		assert.Equal(t, at(loc, 2025, time.June, 15, 9, 0).UTC(), got)
+	})
+
+	t.Run("без календаря — пятидневка", func(t *testing.T) {
+		r := workdayReminder(domain.RepeatEveryDay, nil, at(loc, 2025, time.June, 13, 9, 0), domain.WorkdaySkip)
+		r.Calendar = nil
+
+		got, err := Advance(r, at(loc, 2025, time.June, 13, 9, 0), loc)
+		require.NoError(t, err)
+		assert.Equal(t, at(loc, 2025, time.June, 16, 9, 0).UTC(), got)
+	})
+}
+
+func TestAdvanceOccurrence_NoWorkdays(t *testing.T) {
+	loc := berlin(t)
+	sunday := []int{int(time.Sunday)}
+	r := workdayReminder(domain.RepeatEveryWeek, sunday, at(loc, 2025, time.June, 8, 9, 0), domain.WorkdaySkip)
+
+	_, err := Advance(r, at(loc, 2025, time.June, 8, 9, 0), loc)
+	assert.ErrorIs(t, err, ErrNoWorkdays)
+}
+
+func TestNextOccurrence_AlignsFirstOccurrenceToWorkday(t *testing.T) {
+	loc := berlin(t)
+	r := workdayReminder(domain.RepeatEveryWeek, []int{int(time.Monday)},
+		at(loc, 2025, time.June, 9, 9, 0), domain.WorkdayPrevious)
+
+	got, err := NextOccurrence(r, at(loc, 2025, time.June, 5, 12, 0), loc)
+	require.NoError(t, err)
+	assert.Equal(t, at(loc, 2025, time.June, 6, 9, 0).UTC(), got.Time)
+	assert.Equal(t, at(loc, 2025, time.June, 9, 9, 0).UTC(), got.ShiftedFrom)
+
+	got.Apply(r)
+	assert.Equal(t, at(loc, 2025, time.June, 9, 9, 0).UTC(), SeriesTime(r))
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"time"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
@@ -12,13 +14,16 @@ import (
 )
 
 // ErrInvalidTimezone возвращается при попытке установить неизвестную IANA-зону.
-var ErrInvalidTimezone = errors.New("invalid timezone")
+// Это та же ошибка, что и у зоны напоминания.
+var ErrInvalidTimezone = domain.ErrInvalidTimezone
 
 // ChatUsecase описывает бизнес-логику работы с чатами
 type ChatUsecase interface {
 	GetOrCreateChat(ctx context.Context, chatID int64, chatType, title, username string) (*domain.Chat, error)
 	HasTimezone(ctx context.Context, chatID int64) (bool, error)
-	SetTimezone(ctx context.Context, chatID int64, timezone string) error
+	// SetTimezone меняет часовой пояс чата и пересчитывает его напоминания по режиму mode
+	// в той же транзакции. Возвращает, сколько напоминаний пересчитано.
+	SetTimezone(ctx context.Context, chatID int64, timezone string, mode domain.TimezoneRebase) (int, error)
 	ResolveChatID(ctx context.Context, chatID int64) (int64, error)
 	MigrateChat(ctx context.Context, oldChatID, newChatID int64) error
 	SetAvailable(ctx context.Context, chatID int64, available bool) error
@@ -27,15 +32,42 @@ type ChatUsecase interface {
 	Get(ctx context.Context, chatID int64) (*domain.Chat, error)
 	// Location возвращает часовой пояс чата, откатываясь к UTC, если он не задан или не читается.
 	Location(ctx context.Context, chatID int64) *time.Location
+	// CatchUp возвращает политику для опоздавших напоминаний, откатываясь к настройкам
+	// по умолчанию, если чат не найден или не читается.
+	CatchUp(ctx context.Context, chatID int64) domain.CatchUp
+	SetCatchUp(ctx context.Context, chatID int64, catchUp domain.CatchUp) error
+	// QuietHours возвращает тихие часы чата; если чат не найден или не читается — их нет.
+	QuietHours(ctx context.Context, chatID int64) domain.QuietHours
+	SetQuietHours(ctx context.Context, chatID int64, quiet domain.QuietHours) error
+
+	// Координаты чата для солнечных напоминаний. Geo возвращает nil, если чат
+	// геопозицией не делился.
+	Geo(ctx context.Context, chatID int64) (*domain.GeoPoint, error)
+	SetGeo(ctx context.Context, chatID int64, geo domain.GeoPoint) error
+
+	// Производственный календарь чата для напоминаний «только по рабочим дням».
+	Calendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error)
+	SetCalendar(ctx context.Context, cal *domain.BusinessCalendar) error
+	// ImportCalendar добавляет к календарю особые дни из файла; даты из файла
+	// заменяют уже заданные.
+	ImportCalendar(ctx context.Context, chatID int64, data []byte) (*domain.BusinessCalendar, error)
+}
+
+// ReminderRebaser пересчитывает напоминания чата при смене его часового пояса;
+// это делает ReminderUsecase.
+type ReminderRebaser interface {
+	RebaseChat(ctx context.Context, chatID int64, timezone string, from, to *time.Location,
+		mode domain.TimezoneRebase) (int, error)
 }
 
 type chatUsecase struct {
-	chatRepo repository.ChatRepository
+	chatRepo  repository.ChatRepository
+	reminders ReminderRebaser
 }
 
 // NewChatUsecase создает новый ChatUsecase.
-func NewChatUsecase(chatRepo repository.ChatRepository) ChatUsecase {
-	return &chatUsecase{chatRepo: chatRepo}
+func NewChatUsecase(chatRepo repository.ChatRepository, reminders ReminderRebaser) ChatUsecase {
+	return &chatUsecase{chatRepo: chatRepo, reminders: reminders}
 }
 
 func (u *chatUsecase) GetOrCreateChat(
@@ -85,12 +117,22 @@ func (u *chatUsecase) HasTimezone(ctx context.Context, chatID int64) (bool, erro
 	return ch.Timezone != "", nil
 }
 
-func (u *chatUsecase) SetTimezone(ctx context.Context, chatID int64, tz string) error {
+func (u *chatUsecase) SetTimezone(
+	ctx context.Context,
+	chatID int64,
+	tz string,
+	mode domain.TimezoneRebase,
+) (int, error) {
 	if !timezone.IsValidTimezone(tz) {
-		return ErrInvalidTimezone
+		return 0, ErrInvalidTimezone
+	}
+	to, err := time.LoadLocation(tz)
+	if err != nil {
+		return 0, fmt.Errorf("load timezone %q: %w", tz, err)
 	}
 
-	return u.chatRepo.UpdateTimezone(ctx, chatID, tz)
+	// Пока пояс не задан, напоминания чата считались в UTC — от него и пересчитываем.
+	return u.reminders.RebaseChat(ctx, chatID, tz, u.Location(ctx, chatID), to, mode)
 }
 
 func (u *chatUsecase) Get(ctx context.Context, chatID int64) (*domain.Chat, error) {
@@ -141,3 +183,101 @@ func (u *chatUsecase) Location(ctx context.Context, chatID int64) *time.Location
 
 	return loc
 }
+
+func (u *chatUsecase) CatchUp(ctx context.Context, chatID int64) domain.CatchUp {
+	ch, err := u.chatRepo.GetByID(ctx, chatID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrChatNotFound) {
+			slog.Error("Failed to load chat catch-up policy, using default",
+				"chatID", chatID, "error", err)
+		}
+
+		return domain.CatchUp{}
+	}
+
+	return ch.CatchUp
+}
+
+func (u *chatUsecase) SetCatchUp(ctx context.Context, chatID int64, catchUp domain.CatchUp) error {
+	if err := catchUp.Validate(); err != nil {
+		return err
+	}
+
+	return u.chatRepo.UpdateCatchUp(ctx, chatID, catchUp)
+}
+
+func (u *chatUsecase) QuietHours(ctx context.Context, chatID int64) domain.QuietHours {
+	ch, err := u.chatRepo.GetByID(ctx, chatID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrChatNotFound) {
+			slog.Error("Failed to load chat quiet hours, delivering as usual",
+				"chatID", chatID, "error", err)
+		}
+
+		return domain.QuietHours{}
+	}
+
+	return ch.Quiet
+}
+
+func (u *chatUsecase) SetQuietHours(ctx context.Context, chatID int64, quiet domain.QuietHours) error {
+	if err := quiet.Validate(); err != nil {
+		return err
+	}
+
+	return u.chatRepo.UpdateQuietHours(ctx, chatID, quiet)
+}
+
+func (u *chatUsecase) Geo(ctx context.Context, chatID int64) (*domain.GeoPoint, error) {
+	ch, err := u.chatRepo.GetByID(ctx, chatID)
+	if errors.Is(err, repository.ErrChatNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.Geo, nil
+}
+
+func (u *chatUsecase) SetGeo(ctx context.Context, chatID int64, geo domain.GeoPoint) error {
+	if err := geo.Validate(); err != nil {
+		return err
+	}
+
+	return u.chatRepo.UpdateLocation(ctx, chatID, &geo)
+}
+
+func (u *chatUsecase) Calendar(ctx context.Context, chatID int64) (*domain.BusinessCalendar, error) {
+	return u.chatRepo.GetCalendar(ctx, chatID)
+}
+
+func (u *chatUsecase) SetCalendar(ctx context.Context, cal *domain.BusinessCalendar) error {
+	cal.Normalize()
+	if err := cal.Validate(); err != nil {
+		return err
+	}
+
+	return u.chatRepo.SaveCalendar(ctx, cal)
+}
+
+func (u *chatUsecase) ImportCalendar(ctx context.Context, chatID int64, data []byte) (*domain.BusinessCalendar, error) {
+	days, err := domain.ParseCalendarDays(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := u.chatRepo.GetCalendar(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	// Файл обычно покрывает один год: календарь следующего года дополняет прежний,
+	// а не стирает его.
+	maps.Copy(cal.Days, days)
+
+	if err := u.SetCalendar(ctx, cal); err != nil {
+		return nil, err
+	}
+
+	return cal, nil
+}
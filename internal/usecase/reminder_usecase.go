@@ -2,13 +2,23 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 )
 
+// Waker узнаёт об изменениях расписания. Планировщик держит ближайшие срабатывания
+// в памяти и без этого узнал бы о новом времени напоминания только при сверке с базой.
+type Waker interface {
+	// WakeAt сообщает, что напоминание id нужно обработать в момент at; нулевое at —
+	// напоминание удалено или поставлено на паузу.
+	WakeAt(id int64, at time.Time)
+}
+
 // ReminderUsecase определяет бизнес-логику для работы с напоминаниями.
 //
 // Методы с суффиксом Owned принимают chatID и обязаны использоваться всюду, где
@@ -30,19 +40,131 @@ type ReminderUsecase interface {
 	UpdateOwned(ctx context.Context, r *domain.Reminder, chatID int64) error
 	DeleteOwned(ctx context.Context, id, chatID int64) error
 	SetPausedOwned(ctx context.Context, id, chatID int64, paused bool) error
+
+	// Исключения из серии. r — напоминание, уже прочитанное и авторизованное вызывающим;
+	// после успешного вызова его NextTime пересчитан и сохранён.
+	ListExceptions(ctx context.Context, reminderID int64) ([]domain.OccurrenceException, error)
+	AddException(ctx context.Context, r *domain.Reminder, e domain.OccurrenceException, now time.Time,
+		loc *time.Location) error
+	SkipNext(ctx context.Context, r *domain.Reminder, now time.Time, loc *time.Location) error
+
+	// ArmFollowUps назначает продолжения напоминания parentID, ждущие события trigger,
+	// которое случилось в момент at. Возвращает, сколько продолжений назначено.
+	ArmFollowUps(ctx context.Context, parentID int64, trigger domain.FollowTrigger, at time.Time) (int, error)
+
+	// RebaseChat записывает новый часовой пояс чата timezone и в той же транзакции
+	// пересчитывает напоминания чата из пояса from в пояс to по режиму mode.
+	// Возвращает, сколько напоминаний пересчитано. Режим RebaseInstant отклоняется
+	// с domain.ErrInstantRebase, если у напоминания есть время по часам.
+	RebaseChat(ctx context.Context, chatID int64, timezone string, from, to *time.Location,
+		mode domain.TimezoneRebase) (int, error)
+
+	// Подтверждения доставок в режиме «до подтверждения».
+	CreateAck(ctx context.Context, a *domain.Acknowledgement) error
+	UpdateAck(ctx context.Context, a *domain.Acknowledgement) error
+	ListDueAcks(ctx context.Context, now time.Time) ([]*domain.Acknowledgement, error)
+	// Acknowledge отмечает доставку id чата chatID подтверждённой пользователем userID.
+	// Повторное подтверждение возвращает запись вместе с domain.ErrAlreadyAcknowledged.
+	Acknowledge(ctx context.Context, id, chatID, userID int64, userName string,
+		at time.Time) (*domain.Acknowledgement, error)
+	GetAck(ctx context.Context, id, chatID int64) (*domain.Acknowledgement, error)
+
+	// Очередь доставок. AdvanceReminder сохраняет перенесённое напоминание, а
+	// FinishReminder удаляет отработавшее — вместе с постановкой d в очередь, атомарно;
+	// d == nil — отправлять нечего. Уже стоящее в очереди срабатывание не дублируется,
+	// d.ID тогда остаётся нулевым.
+	AdvanceReminder(ctx context.Context, r *domain.Reminder, d *domain.Delivery) error
+	FinishReminder(ctx context.Context, id int64, d *domain.Delivery) error
+	UpdateDelivery(ctx context.Context, d *domain.Delivery) error
+	ListPendingDeliveries(ctx context.Context, now time.Time) ([]*domain.Delivery, error)
+
+	// История доставок. ListHistory отдаёт последние limit попыток чата, но не больше
+	// domain.MaxHistoryEntries; PruneHistory удаляет попытки старше before.
+	RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt) error
+	ListHistory(ctx context.Context, chatID int64, limit int) ([]*domain.DeliveryAttempt, error)
+	PruneHistory(ctx context.Context, before time.Time) (int64, error)
+
+	// ListWakeups возвращает limit ближайших моментов, когда у планировщика есть работа.
+	ListWakeups(ctx context.Context, limit int) ([]domain.Wakeup, error)
+	// SetWaker подключает планировщик: создание, правка, пауза и удаление напоминаний
+	// будят его. Вызывается один раз до начала работы.
+	SetWaker(w Waker)
 }
 
 type reminderUsecase struct {
-	repo repository.ReminderRepository
+	repo  repository.ReminderRepository
+	waker Waker
 }
 
 // NewReminderUsecase создает новый ReminderUsecase.
 func NewReminderUsecase(repo repository.ReminderRepository) ReminderUsecase {
-	return &reminderUsecase{repo: repo}
+	return &reminderUsecase{repo: repo, waker: noWaker{}}
+}
+
+// noWaker — Waker, пока планировщик не подключён.
+type noWaker struct{}
+
+func (noWaker) WakeAt(int64, time.Time) {}
+
+func (u *reminderUsecase) SetWaker(w Waker) {
+	u.waker = w
+}
+
+// update сохраняет напоминание и будит планировщик к его новому времени.
+func (u *reminderUsecase) update(ctx context.Context, r *domain.Reminder) error {
+	if err := u.repo.Update(ctx, r); err != nil {
+		return err
+	}
+	u.waker.WakeAt(r.ID, r.WakeAt())
+
+	return nil
+}
+
+// delete удаляет напоминание и снимает его с расписания планировщика. Продолжения,
+// которые ждут его, уже никогда не сработали бы и удаляются вместе с ним, а уже
+// назначенные становятся обычными разовыми.
+func (u *reminderUsecase) delete(ctx context.Context, id int64) error {
+	return u.deleteChain(ctx, id, 0)
+}
+
+// deleteChain удаляет напоминание id, находящееся на глубине depth цепочки.
+func (u *reminderUsecase) deleteChain(ctx context.Context, id int64, depth int) error {
+	if depth < domain.MaxChainDepth {
+		followUps, err := u.repo.ListFollowUps(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, f := range followUps {
+			if f.Waiting {
+				if err := u.deleteChain(ctx, f.ID, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			f.ParentID = 0
+			if err := u.EditReminder(ctx, f); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	u.waker.WakeAt(id, time.Time{})
+
+	return nil
 }
 
 func (u *reminderUsecase) AddReminder(ctx context.Context, r *domain.Reminder) error {
 	r.Normalize()
+	if r.IsFollowUp() {
+		if err := u.checkChain(ctx, r); err != nil {
+			return err
+		}
+		// Родитель ещё не срабатывал после создания продолжения.
+		r.Waiting = true
+	}
 	if err := r.Validate(); err != nil {
 		return err
 	}
@@ -54,8 +176,14 @@ func (u *reminderUsecase) AddReminder(ctx context.Context, r *domain.Reminder) e
 	if len(existing) >= domain.MaxRemindersPerChat {
 		return domain.ErrTooManyReminders
 	}
+	planNotice(r)
 
-	return u.repo.Create(ctx, r)
+	if err := u.repo.Create(ctx, r); err != nil {
+		return err
+	}
+	u.waker.WakeAt(r.ID, r.WakeAt())
+
+	return nil
 }
 
 func (u *reminderUsecase) EditReminder(ctx context.Context, r *domain.Reminder) error {
@@ -63,12 +191,13 @@ func (u *reminderUsecase) EditReminder(ctx context.Context, r *domain.Reminder)
 	if err := r.Validate(); err != nil {
 		return err
 	}
+	planNotice(r)
 
-	return u.repo.Update(ctx, r)
+	return u.update(ctx, r)
 }
 
 func (u *reminderUsecase) DeleteReminder(ctx context.Context, id int64) error {
-	return u.repo.Delete(ctx, id)
+	return u.delete(ctx, id)
 }
 
 func (u *reminderUsecase) PauseReminder(ctx context.Context, id int64) error {
@@ -85,8 +214,9 @@ func (u *reminderUsecase) setPaused(ctx context.Context, id int64, paused bool)
 		return err
 	}
 	r.Paused = paused
+	planNotice(r)
 
-	return u.repo.Update(ctx, r)
+	return u.update(ctx, r)
 }
 
 func (u *reminderUsecase) ListReminders(ctx context.Context, chatID int64) ([]*domain.Reminder, error) {
@@ -127,15 +257,99 @@ func (u *reminderUsecase) UpdateOwned(ctx context.Context, r *domain.Reminder, c
 	r.ChatID = existing.ChatID
 	r.CreatedAt = existing.CreatedAt
 
+	if r.IsFollowUp() && (r.ParentID != existing.ParentID || r.FollowOn != existing.FollowOn) {
+		if err := u.checkChain(ctx, r); err != nil {
+			return err
+		}
+	}
+	if r.IsFollowUp() && r.ParentID != existing.ParentID {
+		r.Waiting = true
+	}
+
 	return u.EditReminder(ctx, r)
 }
 
+// checkChain проверяет родителя продолжения r: он из того же чата, ждёт подтверждения,
+// если продолжение назначается по нему, а цепочка над ним не возвращается к r
+// и не длиннее domain.MaxChainDepth.
+//
+// Родитель из чужого чата неотличим от несуществующего, как и в GetOwned.
+func (u *reminderUsecase) checkChain(ctx context.Context, r *domain.Reminder) error {
+	id := r.ParentID
+	for depth := 0; id != 0; depth++ {
+		if id == r.ID {
+			return fmt.Errorf("%w: reminder %d already precedes %d", domain.ErrFollowUpCycle, r.ID, r.ParentID)
+		}
+		if depth == domain.MaxChainDepth {
+			return fmt.Errorf("%w: chain cannot be longer than %d reminders",
+				domain.ErrInvalidFollowUp, domain.MaxChainDepth)
+		}
+
+		parent, err := u.repo.GetByID(ctx, id)
+		if errors.Is(err, repository.ErrReminderNotFound) {
+			parent, err = &domain.Reminder{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		if parent.ChatID != r.ChatID {
+			return fmt.Errorf("%w: parent reminder %d not found", domain.ErrInvalidFollowUp, id)
+		}
+		if depth == 0 && r.FollowOn == domain.FollowOnAck && parent.NagEveryMinutes == 0 {
+			return fmt.Errorf("%w: parent reminder %d does not wait for acknowledgement",
+				domain.ErrInvalidFollowUp, id)
+		}
+		id = parent.ParentID
+	}
+
+	return nil
+}
+
+// ArmFollowUps назначает продолжения. Уже назначенное продолжение не сдвигается:
+// родитель, срабатывающий чаще сдвига, иначе откладывал бы его бесконечно.
+//
+// Разовый родитель к этому моменту уже удалён планировщиком. Его продолжения
+// срабатывают в последний раз и становятся обычными разовыми напоминаниями.
+func (u *reminderUsecase) ArmFollowUps(
+	ctx context.Context,
+	parentID int64,
+	trigger domain.FollowTrigger,
+	at time.Time,
+) (int, error) {
+	followUps, err := u.repo.ListFollowUps(ctx, parentID)
+	if err != nil || len(followUps) == 0 {
+		return 0, err
+	}
+	_, err = u.repo.GetByID(ctx, parentID)
+	orphaned := errors.Is(err, repository.ErrReminderNotFound)
+	if err != nil && !orphaned {
+		return 0, err
+	}
+
+	armed := 0
+	for _, f := range followUps {
+		if !f.Waiting || f.FollowOn != trigger {
+			continue
+		}
+		f.Arm(at)
+		if orphaned {
+			f.ParentID = 0
+		}
+		if err := u.EditReminder(ctx, f); err != nil {
+			return armed, err
+		}
+		armed++
+	}
+
+	return armed, nil
+}
+
 func (u *reminderUsecase) DeleteOwned(ctx context.Context, id, chatID int64) error {
 	if _, err := u.GetOwned(ctx, id, chatID); err != nil {
 		return err
 	}
 
-	return u.repo.Delete(ctx, id)
+	return u.delete(ctx, id)
 }
 
 func (u *reminderUsecase) SetPausedOwned(ctx context.Context, id, chatID int64, paused bool) error {
@@ -144,6 +358,266 @@ func (u *reminderUsecase) SetPausedOwned(ctx context.Context, id, chatID int64,
 		return err
 	}
 	r.Paused = paused
+	planNotice(r)
+
+	return u.update(ctx, r)
+}
+
+func (u *reminderUsecase) ListExceptions(ctx context.Context, reminderID int64) ([]domain.OccurrenceException, error) {
+	return u.repo.ListExceptions(ctx, reminderID)
+}
+
+// SkipNext пропускает ближайшее срабатывание серии. Если оно было перенесено,
+// пропускается перенесённое.
+func (u *reminderUsecase) SkipNext(ctx context.Context, r *domain.Reminder, now time.Time, loc *time.Location) error {
+	return u.applyException(ctx, r, domain.OccurrenceException{}, true, now, loc)
+}
+
+// AddException пропускает или переносит одно срабатывание серии и пересчитывает NextTime.
+//
+// Если после исключения у серии не остаётся срабатываний, возвращается
+// scheduling.ErrSeriesEnded и ничего не сохраняется: такое напоминание нужно удалять,
+// а не пропускать.
+func (u *reminderUsecase) AddException(
+	ctx context.Context,
+	r *domain.Reminder,
+	e domain.OccurrenceException,
+	now time.Time,
+	loc *time.Location,
+) error {
+	return u.applyException(ctx, r, e, false, now, loc)
+}
+
+// applyException сохраняет исключение e одной транзакцией с пересчётом напоминания:
+// напоминание перечитывается в ней же, так что перенос, который планировщик записал
+// между чтением и записью, не затирается. nearest — исключение для ближайшего
+// срабатывания, каким оно окажется в транзакции. При успехе r заменяется сохранённым.
+func (u *reminderUsecase) applyException(
+	ctx context.Context,
+	r *domain.Reminder,
+	e domain.OccurrenceException,
+	nearest bool,
+	now time.Time,
+	loc *time.Location,
+) error {
+	if r.Repeat == domain.RepeatNone {
+		return fmt.Errorf("%w: reminder %d is not repeating", domain.ErrInvalidException, r.ID)
+	}
+
+	var saved *domain.Reminder
+	e.ReminderID = r.ID
+	err := u.repo.ApplyException(ctx, &e, func(
+		stored *domain.Reminder,
+		e *domain.OccurrenceException,
+	) (time.Time, error) {
+		// Календарь и координаты чата в таблице reminders не хранятся: их загрузил вызывающий.
+		stored.Calendar, stored.Geo = r.Calendar, r.Geo
+		if stored.Repeat == domain.RepeatNone {
+			return time.Time{}, fmt.Errorf("%w: reminder %d is not repeating", domain.ErrInvalidException, r.ID)
+		}
+		if nearest {
+			e.Occurrence = scheduling.SeriesTime(stored)
+		}
+		e.Normalize()
+		if err := e.Validate(); err != nil {
+			return time.Time{}, err
+		}
+
+		if !scheduling.IsOccurrence(stored, e.Occurrence, loc) {
+			return time.Time{}, fmt.Errorf("%w: %s is not an upcoming occurrence of reminder %d",
+				domain.ErrInvalidException, e.Occurrence, r.ID)
+		}
+		if !e.IsSkip() && !e.MovedTo.After(now) {
+			return time.Time{}, fmt.Errorf("%w: cannot move an occurrence into the past", domain.ErrInvalidException)
+		}
+		if !e.IsSkip() && !stored.EndsAt.IsZero() && e.MovedTo.After(stored.EndsAt) {
+			return time.Time{}, fmt.Errorf("%w: cannot move an occurrence past the end of the series",
+				domain.ErrInvalidException)
+		}
+
+		// Отжившие исключения больше не влияют на расчёт и только занимают лимит. Граница —
+		// не позже NextTime: ещё не отправленный перенос должен дожить до отправки.
+		before := now
+		if stored.NextTime.Before(before) {
+			before = stored.NextTime
+		}
+
+		// Шагать по серии нужно от исходного времени ближайшего срабатывания: если прежнее
+		// исключение для него заменяется, NextTime с ним больше не совпадёт.
+		probe := *stored
+		probe.NextTime = scheduling.SeriesTime(stored)
+		probe.Exceptions = withException(activeExceptions(stored.Exceptions, before), *e)
+		if len(probe.Exceptions) > domain.MaxExceptionsPerReminder {
+			return time.Time{}, domain.ErrTooManyExceptions
+		}
+
+		next, err := scheduling.NextOccurrence(&probe, now, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next.Apply(stored)
+		stored.Exceptions = probe.Exceptions
+		planNotice(stored)
+		saved = stored
+
+		return before, nil
+	})
+	if err != nil {
+		return err
+	}
+	*r = *saved
+	u.waker.WakeAt(r.ID, r.WakeAt())
+
+	return nil
+}
+
+func (u *reminderUsecase) RebaseChat(
+	ctx context.Context,
+	chatID int64,
+	timezone string,
+	from, to *time.Location,
+	mode domain.TimezoneRebase,
+) (int, error) {
+	if !mode.IsValid() {
+		return 0, fmt.Errorf("%w: unknown rebase mode %d", domain.ErrInvalidTimezone, mode)
+	}
+
+	var rebased []*domain.Reminder
+	shift := from.String() != to.String()
+	err := u.repo.RebaseChat(ctx, chatID, timezone, func(r *domain.Reminder) (bool, error) {
+		if !shift || r.Timezone != "" || r.Repeat == domain.RepeatSolar || r.IsFollowUp() {
+			// Напоминание живёт по своему поясу, по солнцу или по родителю — смена
+			// пояса чата его не касается.
+			return false, nil
+		}
+		if mode == domain.RebaseInstant {
+			if len(r.Times) > 0 || r.HasWindow() || r.Repeat == domain.RepeatCron {
+				return false, fmt.Errorf("%w: reminder %d", domain.ErrInstantRebase, r.ID)
+			}
+			// NextTime хранится моментом, а следующие срабатывания отсчитываются от него.
+			return false, nil
+		}
+		scheduling.Rebase(r, from, to)
+		planNotice(r)
+		rebased = append(rebased, r)
+
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range rebased {
+		u.waker.WakeAt(r.ID, r.WakeAt())
+	}
+
+	return len(rebased), nil
+}
+
+func (u *reminderUsecase) CreateAck(ctx context.Context, a *domain.Acknowledgement) error {
+	return u.repo.CreateAck(ctx, a)
+}
+
+func (u *reminderUsecase) UpdateAck(ctx context.Context, a *domain.Acknowledgement) error {
+	return u.repo.UpdateAck(ctx, a)
+}
+
+func (u *reminderUsecase) ListDueAcks(ctx context.Context, now time.Time) ([]*domain.Acknowledgement, error) {
+	return u.repo.ListDueAcks(ctx, now)
+}
+
+func (u *reminderUsecase) Acknowledge(
+	ctx context.Context,
+	id, chatID, userID int64,
+	userName string,
+	at time.Time,
+) (*domain.Acknowledgement, error) {
+	return u.repo.Acknowledge(ctx, id, chatID, userID, userName, at)
+}
+
+func (u *reminderUsecase) GetAck(ctx context.Context, id, chatID int64) (*domain.Acknowledgement, error) {
+	return u.repo.GetAck(ctx, id, chatID)
+}
+
+func (u *reminderUsecase) AdvanceReminder(ctx context.Context, r *domain.Reminder, d *domain.Delivery) error {
+	r.Normalize()
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	planNotice(r)
+
+	if err := u.repo.Advance(ctx, r, d); err != nil {
+		return err
+	}
+	u.waker.WakeAt(r.ID, r.WakeAt())
+
+	return nil
+}
+
+func (u *reminderUsecase) FinishReminder(ctx context.Context, id int64, d *domain.Delivery) error {
+	if err := u.repo.Finish(ctx, id, d); err != nil {
+		return err
+	}
+	u.waker.WakeAt(id, time.Time{})
+
+	return nil
+}
+
+func (u *reminderUsecase) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	return u.repo.UpdateDelivery(ctx, d)
+}
+
+func (u *reminderUsecase) ListPendingDeliveries(ctx context.Context, now time.Time) ([]*domain.Delivery, error) {
+	return u.repo.ListPendingDeliveries(ctx, now)
+}
+
+func (u *reminderUsecase) RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt) error {
+	return u.repo.RecordAttempt(ctx, a)
+}
+
+func (u *reminderUsecase) ListHistory(ctx context.Context, chatID int64, limit int) ([]*domain.DeliveryAttempt, error) {
+	if limit <= 0 || limit > domain.MaxHistoryEntries {
+		limit = domain.MaxHistoryEntries
+	}
+
+	return u.repo.ListAttempts(ctx, chatID, limit)
+}
+
+func (u *reminderUsecase) PruneHistory(ctx context.Context, before time.Time) (int64, error) {
+	return u.repo.DeleteAttemptsBefore(ctx, before)
+}
+
+func (u *reminderUsecase) ListWakeups(ctx context.Context, limit int) ([]domain.Wakeup, error) {
+	return u.repo.ListWakeups(ctx, limit)
+}
+
+// planNotice назначает ближайшее предупреждение перед срабатыванием. Отсчёт идёт от
+// текущего момента: предупреждения, время которых прошло до правки или на паузе,
+// не досылаются.
+func planNotice(r *domain.Reminder) {
+	r.NoticeAt = scheduling.NextNotice(r, time.Now())
+}
+
+// activeExceptions отбрасывает исключения, которые удаляет DeleteExceptionsBefore.
+func activeExceptions(exceptions []domain.OccurrenceException, before time.Time) []domain.OccurrenceException {
+	active := make([]domain.OccurrenceException, 0, len(exceptions))
+	for _, e := range exceptions {
+		if e.Occurrence.Before(before) && (e.IsSkip() || e.MovedTo.Before(before)) {
+			continue
+		}
+		active = append(active, e)
+	}
+
+	return active
+}
+
+// withException добавляет исключение, заменяя прежнее для того же срабатывания.
+func withException(exceptions []domain.OccurrenceException, e domain.OccurrenceException) []domain.OccurrenceException {
+	for i := range exceptions {
+		if exceptions[i].Occurrence.Equal(e.Occurrence) {
+			exceptions[i] = e
+			return exceptions
+		}
+	}
 
-	return u.repo.Update(ctx, r)
+	return append(exceptions, e)
 }
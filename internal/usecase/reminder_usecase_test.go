@@ -8,6 +8,7 @@ import (
 
 	"github.com/8thgencore/dory-reminder-bot/internal/domain"
 	"github.com/8thgencore/dory-reminder-bot/internal/repository"
+	"github.com/8thgencore/dory-reminder-bot/internal/scheduling"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +23,21 @@ type reminderRepositoryStub struct {
 	updated   *domain.Reminder
 	deletedID int64
 	listCalls int
+
+	exceptions      []domain.OccurrenceException
+	addedException  *domain.OccurrenceException
+	exceptionsPrune time.Time
+
+	historyLimit int
+
+	rebasedTimezone string
+	rebased         []*domain.Reminder
+
+	// byID и followUps описывают цепочки: с byID GetByID ищет напоминание в нём,
+	// а не отдаёт reminder. deleted — все удалённые напоминания по порядку.
+	byID      map[int64]*domain.Reminder
+	followUps map[int64][]*domain.Reminder
+	deleted   []int64
 }
 
 func (s *reminderRepositoryStub) Create(_ context.Context, reminder *domain.Reminder) error {
@@ -38,12 +54,26 @@ func (s *reminderRepositoryStub) Update(_ context.Context, reminder *domain.Remi
 
 func (s *reminderRepositoryStub) Delete(_ context.Context, id int64) error {
 	s.deletedID = id
+	s.deleted = append(s.deleted, id)
 
 	return s.err
 }
 
-func (s *reminderRepositoryStub) GetByID(_ context.Context, _ int64) (*domain.Reminder, error) {
-	return s.reminder, s.err
+func (s *reminderRepositoryStub) GetByID(_ context.Context, id int64) (*domain.Reminder, error) {
+	if s.byID == nil {
+		return s.reminder, s.err
+	}
+	r, ok := s.byID[id]
+	if !ok {
+		return nil, repository.ErrReminderNotFound
+	}
+	copied := *r
+
+	return &copied, nil
+}
+
+func (s *reminderRepositoryStub) ListFollowUps(_ context.Context, parentID int64) ([]*domain.Reminder, error) {
+	return s.followUps[parentID], s.err
 }
 
 func (s *reminderRepositoryStub) ListByChat(_ context.Context, _ int64) ([]*domain.Reminder, error) {
@@ -56,6 +86,140 @@ func (s *reminderRepositoryStub) ListDue(_ context.Context, _ time.Time) ([]*dom
 	return s.reminders, s.err
 }
 
+func (s *reminderRepositoryStub) AddException(_ context.Context, e *domain.OccurrenceException) error {
+	s.addedException = e
+
+	return s.err
+}
+
+// ApplyException пересчитывает копию reminder, как репозиторий — прочитанное
+// в транзакции напоминание. С err запись не удаётся, и ничего не сохраняется.
+func (s *reminderRepositoryStub) ApplyException(
+	_ context.Context,
+	e *domain.OccurrenceException,
+	apply repository.ExceptionFunc,
+) error {
+	stored := *s.reminder
+	stored.Exceptions = s.exceptions
+	before, err := apply(&stored, e)
+	if err != nil {
+		return err
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.exceptionsPrune, s.addedException, s.updated = before, e, &stored
+
+	return nil
+}
+
+func (s *reminderRepositoryStub) ListExceptions(_ context.Context, _ int64) ([]domain.OccurrenceException, error) {
+	return s.exceptions, s.err
+}
+
+func (s *reminderRepositoryStub) DeleteExceptionsBefore(_ context.Context, _ int64, before time.Time) error {
+	s.exceptionsPrune = before
+
+	return s.err
+}
+
+func (s *reminderRepositoryStub) CreateAck(context.Context, *domain.Acknowledgement) error {
+	return s.err
+}
+
+func (s *reminderRepositoryStub) UpdateAck(context.Context, *domain.Acknowledgement) error {
+	return s.err
+}
+
+func (s *reminderRepositoryStub) ListDueAcks(context.Context, time.Time) ([]*domain.Acknowledgement, error) {
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) Acknowledge(
+	context.Context, int64, int64, int64, string, time.Time,
+) (*domain.Acknowledgement, error) {
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) GetAck(context.Context, int64, int64) (*domain.Acknowledgement, error) {
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) Advance(_ context.Context, reminder *domain.Reminder, _ *domain.Delivery) error {
+	s.updated = reminder
+
+	return s.err
+}
+
+func (s *reminderRepositoryStub) Finish(_ context.Context, id int64, _ *domain.Delivery) error {
+	s.deletedID = id
+
+	return s.err
+}
+
+func (s *reminderRepositoryStub) UpdateDelivery(context.Context, *domain.Delivery) error {
+	return s.err
+}
+
+func (s *reminderRepositoryStub) ListPendingDeliveries(context.Context, time.Time) ([]*domain.Delivery, error) {
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) RecordAttempt(context.Context, *domain.DeliveryAttempt) error {
+	return s.err
+}
+
+func (s *reminderRepositoryStub) ListAttempts(
+	_ context.Context,
+	_ int64,
+	limit int,
+) ([]*domain.DeliveryAttempt, error) {
+	s.historyLimit = limit
+
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) DeleteAttemptsBefore(context.Context, time.Time) (int64, error) {
+	return 0, s.err
+}
+
+func (s *reminderRepositoryStub) ListWakeups(context.Context, int) ([]domain.Wakeup, error) {
+	return nil, s.err
+}
+
+func (s *reminderRepositoryStub) RebaseChat(
+	_ context.Context,
+	_ int64,
+	timezone string,
+	rebase repository.RebaseFunc,
+) error {
+	if s.err != nil {
+		return s.err
+	}
+	var rebased []*domain.Reminder
+	for _, r := range s.reminders {
+		changed, err := rebase(r)
+		if err != nil {
+			return err
+		}
+		if changed {
+			rebased = append(rebased, r)
+		}
+	}
+	s.rebasedTimezone, s.rebased = timezone, rebased
+
+	return nil
+}
+
+// wakerStub запоминает последнее время, к которому usecase будил планировщик.
+type wakerStub struct {
+	wakes map[int64]time.Time
+}
+
+func (w *wakerStub) WakeAt(id int64, at time.Time) {
+	w.wakes[id] = at
+}
+
 func validReminder() *domain.Reminder {
 	return &domain.Reminder{
 		ID:       7,
@@ -81,6 +245,18 @@ func TestReminderUsecaseAddReminder(t *testing.T) {
 		assert.Equal(t, 1, repo.listCalls)
 	})
 
+	t.Run("plans the nearest advance notice", func(t *testing.T) {
+		repo := &reminderRepositoryStub{}
+		reminder := validReminder()
+		reminder.NextTime = time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+		reminder.LeadMinutes = []int{60, 3 * domain.MinutesPerDay, domain.MinutesPerDay}
+
+		require.NoError(t, NewReminderUsecase(repo).AddReminder(t.Context(), reminder))
+
+		// Предупреждение за три дня опоздало — ближайшим остаётся за сутки.
+		assert.Equal(t, reminder.NextTime.Add(-24*time.Hour), repo.created.NoticeAt)
+	})
+
 	t.Run("rejects invalid data before querying the repository", func(t *testing.T) {
 		repo := &reminderRepositoryStub{}
 		reminder := validReminder()
@@ -163,3 +339,368 @@ func TestReminderUsecaseOwnedOperations(t *testing.T) {
 		assert.Same(t, reminder, repo.updated)
 	})
 }
+
+func TestReminderUsecaseExceptions(t *testing.T) {
+	// Еженедельно по средам в 12:00 UTC; 29 июля 2026 года — среда.
+	weekly := func() *domain.Reminder {
+		return &domain.Reminder{
+			ID:         7,
+			ChatID:     42,
+			Text:       "reminder",
+			NextTime:   time.Date(2026, time.July, 29, 12, 0, 0, 0, time.UTC),
+			Repeat:     domain.RepeatEveryWeek,
+			RepeatDays: []int{int(time.Wednesday)},
+		}
+	}
+	now := time.Date(2026, time.July, 27, 9, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	t.Run("skips the next occurrence", func(t *testing.T) {
+		repo := &reminderRepositoryStub{reminder: weekly()}
+		reminder := weekly()
+		occurrence := reminder.NextTime
+
+		err := NewReminderUsecase(repo).SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.NoError(t, err)
+		require.NotNil(t, repo.addedException)
+		assert.Equal(t, occurrence, repo.addedException.Occurrence)
+		assert.True(t, repo.addedException.IsSkip())
+		assert.Equal(t, occurrence.Add(week), reminder.NextTime)
+		require.NotNil(t, repo.updated)
+		assert.Equal(t, reminder.NextTime, repo.updated.NextTime)
+		assert.Equal(t, now, repo.exceptionsPrune)
+	})
+
+	t.Run("uses the reminder as stored, not the caller's copy", func(t *testing.T) {
+		// Планировщик уже перенёс серию на неделю вперёд, а вызывающий видит прежнее время.
+		stored := weekly()
+		stored.NextTime = stored.NextTime.Add(week)
+		repo := &reminderRepositoryStub{reminder: stored}
+		reminder := weekly()
+
+		err := NewReminderUsecase(repo).SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.NoError(t, err)
+		assert.Equal(t, stored.NextTime, repo.addedException.Occurrence)
+		assert.Equal(t, stored.NextTime.Add(week), reminder.NextTime)
+	})
+
+	t.Run("a failed write leaves the reminder untouched", func(t *testing.T) {
+		repo := &reminderRepositoryStub{reminder: weekly(), err: errRepository}
+		waker := &wakerStub{wakes: map[int64]time.Time{}}
+		uc := NewReminderUsecase(repo)
+		uc.SetWaker(waker)
+		reminder := weekly()
+		next := reminder.NextTime
+
+		err := uc.SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.ErrorIs(t, err, errRepository)
+		assert.Equal(t, next, reminder.NextTime)
+		assert.Nil(t, repo.addedException)
+		assert.Nil(t, repo.updated)
+		assert.True(t, waker.wakes[7].IsZero(), "a failed write does not wake the scheduler")
+	})
+
+	t.Run("skips a moved occurrence by its original time", func(t *testing.T) {
+		reminder := weekly()
+		occurrence := reminder.NextTime
+		reminder.NextTime = occurrence.Add(2 * time.Hour)
+		repo := &reminderRepositoryStub{reminder: reminder, exceptions: []domain.OccurrenceException{
+			{ReminderID: 7, Occurrence: occurrence, MovedTo: reminder.NextTime},
+		}}
+
+		err := NewReminderUsecase(repo).SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.NoError(t, err)
+		assert.Equal(t, occurrence, repo.addedException.Occurrence)
+		assert.Equal(t, occurrence.Add(week), reminder.NextTime)
+		require.Len(t, reminder.Exceptions, 1)
+		assert.True(t, reminder.Exceptions[0].IsSkip())
+	})
+
+	t.Run("moves a later occurrence without touching the next one", func(t *testing.T) {
+		reminder := weekly()
+		repo := &reminderRepositoryStub{reminder: weekly()}
+		next := reminder.NextTime
+		e := domain.OccurrenceException{Occurrence: next.Add(week), MovedTo: next.Add(week + 24*time.Hour)}
+
+		err := NewReminderUsecase(repo).AddException(t.Context(), reminder, e, now, time.UTC)
+
+		require.NoError(t, err)
+		assert.Equal(t, next, reminder.NextTime)
+		assert.Equal(t, int64(7), repo.addedException.ReminderID)
+	})
+
+	t.Run("rejects invalid exceptions", func(t *testing.T) {
+		next := weekly().NextTime
+		tests := []struct {
+			name      string
+			reminder  func() *domain.Reminder
+			exception domain.OccurrenceException
+		}{
+			{
+				name: "one-time reminder",
+				reminder: func() *domain.Reminder {
+					r := weekly()
+					r.Repeat = domain.RepeatNone
+					return r
+				},
+				exception: domain.OccurrenceException{Occurrence: next},
+			},
+			{"not an occurrence", weekly, domain.OccurrenceException{Occurrence: next.Add(time.Hour)}},
+			{"past occurrence", weekly, domain.OccurrenceException{Occurrence: next.Add(-week)}},
+			{"move into the past", weekly, domain.OccurrenceException{Occurrence: next, MovedTo: now.Add(-time.Hour)}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := &reminderRepositoryStub{reminder: tt.reminder()}
+
+				err := NewReminderUsecase(repo).AddException(t.Context(), tt.reminder(), tt.exception, now, time.UTC)
+
+				require.ErrorIs(t, err, domain.ErrInvalidException)
+				assert.Nil(t, repo.addedException)
+				assert.Nil(t, repo.updated)
+			})
+		}
+	})
+
+	t.Run("refuses to skip the last occurrence", func(t *testing.T) {
+		reminder := weekly()
+		next := reminder.NextTime
+		reminder.EndsAt = next.Add(time.Hour)
+		stored := *reminder
+		repo := &reminderRepositoryStub{reminder: &stored}
+
+		err := NewReminderUsecase(repo).SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.ErrorIs(t, err, scheduling.ErrSeriesEnded)
+		assert.Nil(t, repo.addedException)
+		assert.Equal(t, next, reminder.NextTime)
+	})
+
+	t.Run("enforces the per-reminder limit", func(t *testing.T) {
+		reminder := weekly()
+		exceptions := make([]domain.OccurrenceException, domain.MaxExceptionsPerReminder)
+		for i := range exceptions {
+			exceptions[i] = domain.OccurrenceException{Occurrence: reminder.NextTime.Add(time.Duration(i+1) * week)}
+		}
+		repo := &reminderRepositoryStub{reminder: weekly(), exceptions: exceptions}
+
+		err := NewReminderUsecase(repo).SkipNext(t.Context(), reminder, now, time.UTC)
+
+		require.ErrorIs(t, err, domain.ErrTooManyExceptions)
+		assert.Nil(t, repo.addedException)
+	})
+}
+
+func TestListHistory_LimitsEntries(t *testing.T) {
+	for limit, want := range map[int]int{0: domain.MaxHistoryEntries, 10: 10, 1000: domain.MaxHistoryEntries} {
+		repo := &reminderRepositoryStub{}
+		_, err := NewReminderUsecase(repo).ListHistory(context.Background(), 42, limit)
+		require.NoError(t, err)
+		assert.Equal(t, want, repo.historyLimit, "limit %d", limit)
+	}
+}
+
+func TestReminderUsecase_WakesScheduler(t *testing.T) {
+	next := time.Now().Add(time.Hour).UTC()
+	repo := &reminderRepositoryStub{reminder: &domain.Reminder{ID: 7, ChatID: 42, Text: "чай", NextTime: next}}
+	waker := &wakerStub{wakes: map[int64]time.Time{}}
+	uc := NewReminderUsecase(repo)
+	uc.SetWaker(waker)
+
+	r := validReminder()
+	r.NextTime = next
+	require.NoError(t, uc.EditReminder(t.Context(), r))
+	assert.Equal(t, next, waker.wakes[r.ID])
+
+	require.NoError(t, uc.PauseReminder(t.Context(), 7))
+	assert.True(t, waker.wakes[7].IsZero(), "a paused reminder is not scheduled")
+
+	waker.wakes[7] = next
+	require.NoError(t, uc.DeleteReminder(t.Context(), 7))
+	assert.True(t, waker.wakes[7].IsZero())
+
+	repo.err = errRepository
+	require.Error(t, uc.ResumeReminder(t.Context(), 7))
+	assert.True(t, waker.wakes[7].IsZero(), "a failed write does not wake the scheduler")
+}
+
+func TestReminderUsecase_RebaseChat(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	newRepo := func() *reminderRepositoryStub {
+		return &reminderRepositoryStub{reminders: []*domain.Reminder{
+			{ID: 1, ChatID: 42, Text: "чай", Repeat: domain.RepeatEveryDay,
+				NextTime: time.Date(2026, time.June, 10, 9, 0, 0, 0, moscow).UTC()},
+			{ID: 2, ChatID: 42, Text: "созвон", Repeat: domain.RepeatEveryDay, Timezone: "America/New_York",
+				NextTime: time.Date(2026, time.June, 10, 13, 0, 0, 0, time.UTC)},
+		}}
+	}
+
+	t.Run("keeps wall clock of reminders without own zone", func(t *testing.T) {
+		repo := newRepo()
+		waker := &wakerStub{wakes: map[int64]time.Time{}}
+		uc := NewReminderUsecase(repo)
+		uc.SetWaker(waker)
+
+		n, err := uc.RebaseChat(t.Context(), 42, "Asia/Tokyo", moscow, tokyo, domain.RebaseWallClock)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, "Asia/Tokyo", repo.rebasedTimezone)
+		require.Len(t, repo.rebased, 1)
+		want := time.Date(2026, time.June, 10, 9, 0, 0, 0, tokyo).UTC()
+		assert.Equal(t, want, repo.rebased[0].NextTime)
+		assert.Equal(t, want, waker.wakes[1])
+	})
+
+	t.Run("instant mode only changes the zone", func(t *testing.T) {
+		repo := newRepo()
+
+		uc := NewReminderUsecase(repo)
+
+		n, err := uc.RebaseChat(t.Context(), 42, "Asia/Tokyo", moscow, tokyo, domain.RebaseInstant)
+
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Equal(t, "Asia/Tokyo", repo.rebasedTimezone)
+		assert.Empty(t, repo.rebased)
+	})
+
+	t.Run("instant mode refuses wall-clock times", func(t *testing.T) {
+		for name, patch := range map[string]func(r *domain.Reminder){
+			"times":  func(r *domain.Reminder) { r.Times = []int{9 * 60, 18 * 60} },
+			"window": func(r *domain.Reminder) { r.WindowStart, r.WindowEnd = 10*60, 12*60 },
+			"cron":   func(r *domain.Reminder) { r.Repeat, r.Cron = domain.RepeatCron, "0 9 * * *" },
+		} {
+			t.Run(name, func(t *testing.T) {
+				repo := newRepo()
+				patch(repo.reminders[0])
+
+				_, err := NewReminderUsecase(repo).RebaseChat(t.Context(), 42, "Asia/Tokyo", moscow, tokyo,
+					domain.RebaseInstant)
+
+				require.ErrorIs(t, err, domain.ErrInstantRebase)
+				assert.Empty(t, repo.rebasedTimezone, "the zone is not changed")
+			})
+		}
+	})
+
+	t.Run("rejects unknown mode", func(t *testing.T) {
+		_, err := NewReminderUsecase(newRepo()).RebaseChat(t.Context(), 42, "Asia/Tokyo", moscow, tokyo, 7)
+
+		require.ErrorIs(t, err, domain.ErrInvalidTimezone)
+	})
+}
+
+func TestReminderUsecase_FollowUpChain(t *testing.T) {
+	newRepo := func() *reminderRepositoryStub {
+		return &reminderRepositoryStub{byID: map[int64]*domain.Reminder{
+			1: {ID: 1, ChatID: 42, Text: "полить рассаду", Repeat: domain.RepeatEveryWeek},
+			2: {ID: 2, ChatID: 42, Text: "подкормить", ParentID: 1, FollowOffset: 3 * domain.MinutesPerDay},
+			3: {ID: 3, ChatID: 99, Text: "чужое"},
+		}}
+	}
+	followUp := func(parentID int64) *domain.Reminder {
+		return &domain.Reminder{ChatID: 42, Text: "проверить всходы", NextTime: time.Now(), ParentID: parentID}
+	}
+
+	t.Run("new follow-up waits for its parent", func(t *testing.T) {
+		repo := newRepo()
+		r := followUp(2)
+
+		require.NoError(t, NewReminderUsecase(repo).AddReminder(t.Context(), r))
+		assert.True(t, repo.created.Waiting)
+		assert.True(t, repo.created.WakeAt().IsZero())
+	})
+
+	t.Run("rejects a cycle", func(t *testing.T) {
+		repo := newRepo()
+		first := *repo.byID[1]
+		first.ParentID = 2
+
+		err := NewReminderUsecase(repo).UpdateOwned(t.Context(), &first, 42)
+
+		require.ErrorIs(t, err, domain.ErrFollowUpCycle)
+		assert.Nil(t, repo.updated)
+	})
+
+	t.Run("parent from another chat is not found", func(t *testing.T) {
+		err := NewReminderUsecase(newRepo()).AddReminder(t.Context(), followUp(3))
+
+		require.ErrorIs(t, err, domain.ErrInvalidFollowUp)
+	})
+
+	t.Run("acknowledgement trigger needs a parent that waits for it", func(t *testing.T) {
+		repo := newRepo()
+		r := followUp(1)
+		r.FollowOn = domain.FollowOnAck
+		require.ErrorIs(t, NewReminderUsecase(repo).AddReminder(t.Context(), r), domain.ErrInvalidFollowUp)
+
+		repo.byID[1].NagEveryMinutes, repo.byID[1].NagMax = 15, 3
+		require.NoError(t, NewReminderUsecase(repo).AddReminder(t.Context(), followUp(1)))
+	})
+}
+
+func TestReminderUsecase_ArmFollowUps(t *testing.T) {
+	ackedAt := time.Date(2026, time.April, 6, 8, 30, 0, 0, time.UTC)
+	newRepo := func() *reminderRepositoryStub {
+		return &reminderRepositoryStub{
+			byID: map[int64]*domain.Reminder{1: {ID: 1, ChatID: 42, Text: "полить рассаду"}},
+			followUps: map[int64][]*domain.Reminder{1: {
+				{ID: 2, ChatID: 42, Text: "подкормить", NextTime: ackedAt, ParentID: 1,
+					FollowOffset: 3 * domain.MinutesPerDay, FollowOn: domain.FollowOnAck, Waiting: true},
+				{ID: 3, ChatID: 42, Text: "по доставке", NextTime: ackedAt, ParentID: 1, Waiting: true},
+				{ID: 4, ChatID: 42, Text: "уже назначено", NextTime: ackedAt.Add(time.Hour), ParentID: 1,
+					FollowOn: domain.FollowOnAck},
+			}},
+		}
+	}
+
+	t.Run("arms waiting follow-ups of the trigger", func(t *testing.T) {
+		repo := newRepo()
+		waker := &wakerStub{wakes: map[int64]time.Time{}}
+		uc := NewReminderUsecase(repo)
+		uc.SetWaker(waker)
+
+		n, err := uc.ArmFollowUps(t.Context(), 1, domain.FollowOnAck, ackedAt)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		armed := repo.followUps[1][0]
+		assert.False(t, armed.Waiting)
+		assert.Equal(t, ackedAt.AddDate(0, 0, 3), armed.NextTime)
+		assert.Equal(t, int64(1), armed.ParentID)
+		assert.Equal(t, armed.NextTime, waker.wakes[2])
+		assert.True(t, repo.followUps[1][1].Waiting, "другое событие продолжение не назначает")
+		assert.Equal(t, ackedAt.Add(time.Hour), repo.followUps[1][2].NextTime, "назначенное не сдвигается")
+	})
+
+	t.Run("follow-ups of a finished parent become one-time reminders", func(t *testing.T) {
+		repo := newRepo()
+		delete(repo.byID, 1)
+
+		n, err := NewReminderUsecase(repo).ArmFollowUps(t.Context(), 1, domain.FollowOnDelivery, ackedAt)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.False(t, repo.followUps[1][1].IsFollowUp())
+		assert.Equal(t, ackedAt, repo.followUps[1][1].NextTime)
+	})
+
+	t.Run("deleting the parent drops waiting follow-ups and detaches armed ones", func(t *testing.T) {
+		repo := newRepo()
+
+		require.NoError(t, NewReminderUsecase(repo).DeleteReminder(t.Context(), 1))
+
+		assert.Equal(t, []int64{2, 3, 1}, repo.deleted)
+		assert.False(t, repo.followUps[1][2].IsFollowUp())
+	})
+}
@@ -90,3 +90,14 @@ func TestIsTime(t *testing.T) {
 		assert.False(t, IsTime(s), "expected %q to be invalid", s)
 	}
 }
+
+func TestParseTimes(t *testing.T) {
+	got, ok := ParseTimes("21:00, 09:00,13:30 09:00")
+	assert.True(t, ok)
+	assert.Equal(t, []int{9 * 60, 13*60 + 30, 21 * 60}, got)
+
+	for _, s := range []string{"", " , ", "09:00, 25:00", "09:00 и 13:00"} {
+		_, ok := ParseTimes(s)
+		assert.False(t, ok, "expected %q to be invalid", s)
+	}
+}
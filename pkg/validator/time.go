@@ -1,11 +1,18 @@
 package validator
 
 import (
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 )
 
 const timeLayout = "15:04"
 
+// MaxTimesPerDay — наибольшее число времён в одном списке.
+// Дублирует domain.MaxTimesPerDay, чтобы pkg/ оставался независимым от internal/.
+const MaxTimesPerDay = 24
+
 // IsTime проверяет, что строка соответствует формату HH:MM
 func IsTime(s string) bool {
 	if len(s) != len(timeLayout) {
@@ -15,3 +22,26 @@ func IsTime(s string) bool {
 
 	return err == nil
 }
+
+// ParseTimes разбирает список времён HH:MM через запятую или пробел («09:00, 13:00»)
+// в минуты от полуночи, отсортированные по возрастанию и без повторов.
+func ParseTimes(s string) ([]int, bool) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 || len(fields) > MaxTimesPerDay {
+		return nil, false
+	}
+
+	minutes := make([]int, 0, len(fields))
+	for _, f := range fields {
+		if !IsTime(f) {
+			return nil, false
+		}
+		t, _ := time.Parse(timeLayout, f)
+		minutes = append(minutes, t.Hour()*60+t.Minute())
+	}
+	slices.Sort(minutes)
+
+	return slices.Compact(minutes), true
+}